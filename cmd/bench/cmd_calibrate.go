@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/bench/runner"
+	"github.com/DjordjeVuckovic/news-hunter/internal/bench/spec"
+	"github.com/DjordjeVuckovic/news-hunter/internal/bench/trackctx"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
+	"github.com/spf13/cobra"
+)
+
+const calibrationFile = "calibration.json"
+
+type calibrateFlags struct {
+	trackArg  string
+	specPath  string
+	judgments string
+	engine    string
+	kind      string
+	output    string
+	depth     int
+	minGrade  int
+}
+
+func newCalibrateCmd() *cobra.Command {
+	var f calibrateFlags
+	cmd := &cobra.Command{
+		Use:   "calibrate [track]",
+		Short: "Fit the sigmoid of sigmoid score normalization on judged hits",
+		Long: `Runs the track's queries through one engine, pairs the raw score of every
+judged hit with its grade and fits a sigmoid (Platt scaling) estimating the
+probability that a hit with a given score is relevant.
+
+The sigmoid is stored under --kind (the query kind it applies to: match,
+multi_match, phrase, semantic, ... or "default" for every kind without its own
+sigmoid) in a calibration file, merged with the sigmoids already there. Point
+the API at the file with RANKING_CALIBRATION_FILE to enable
+"normalization": "sigmoid".
+
+Raw scores depend on the backend: calibrate against the engine the API serves
+from. Only engines reporting scores (type api or elasticsearch) can be calibrated.`,
+		Example: `  bench calibrate fts_quality --engine api --kind match
+  bench calibrate news/semantic --engine api --kind semantic --min-grade 2
+  bench calibrate fts_quality --engine es --output /etc/news-hunter/calibration.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return executeCalibrate(cmd, f, args)
+		},
+	}
+	cmd.Flags().StringVar(&f.trackArg, "track", "", "Track name or path")
+	cmd.Flags().StringVar(&f.specPath, "spec", "", "Override spec.yaml path")
+	cmd.Flags().StringVar(&f.judgments, "judgments", "", "Strategy name (e.g. lexical) or annotations YAML path (default: spec.defaults.judgments)")
+	cmd.Flags().StringVar(&f.engine, "engine", "", "Engine whose scores are calibrated (required)")
+	cmd.Flags().StringVar(&f.kind, "kind", ranking.DefaultCalibration, "Query kind the sigmoid applies to")
+	cmd.Flags().StringVar(&f.output, "output", "", "Calibration file (default: tracks/<name>/calibration.json)")
+	cmd.Flags().IntVar(&f.depth, "depth", 0, "Top-K per query (0 = spec.metrics.max_k or 100)")
+	cmd.Flags().IntVar(&f.minGrade, "min-grade", 0, "Lowest relevant grade (0 = spec.metrics.relevance_threshold or 1)")
+	_ = cmd.MarkFlagRequired("engine")
+	return cmd
+}
+
+func executeCalibrate(cmd *cobra.Command, f calibrateFlags, args []string) error {
+	tr, err := trackctx.Resolve(trackctx.Inputs{
+		TrackArg:   trackArg(f.trackArg, args),
+		SpecPath:   f.specPath,
+		OutputPath: f.output,
+	})
+	if err != nil {
+		return err
+	}
+
+	bs, err := spec.LoadFromFile(tr.Spec)
+	if err != nil {
+		return fmt.Errorf("load spec: %w", err)
+	}
+
+	judgmentsValue := f.judgments
+	explicit := judgmentsValue != ""
+	if !explicit {
+		judgmentsValue = bs.Defaults.Judgments
+	}
+	judgments, err := loadJudgmentsMap(tr.JudgmentsPath(judgmentsValue), explicit)
+	if err != nil {
+		return err
+	}
+	if judgments == nil {
+		return fmt.Errorf("calibration needs judgments: judge the track first or pass --judgments")
+	}
+
+	depth := firstNonZero(f.depth, bs.Metrics.MaxK)
+	if depth == 0 {
+		depth = 100
+	}
+	minGrade := firstNonZero(f.minGrade, bs.Metrics.RelevanceThreshold)
+	if minGrade == 0 {
+		minGrade = 1
+	}
+
+	vectorStore, err := buildQueryVectorStore(cmd.Context(), bs)
+	if err != nil {
+		return fmt.Errorf("build vector store: %w", err)
+	}
+
+	executors, cleanup, err := createExecutors(cmd.Context(), bs)
+	if err != nil {
+		return fmt.Errorf("create executors: %w", err)
+	}
+	defer cleanup()
+	if _, ok := executors[f.engine]; !ok {
+		return fmt.Errorf("engine %q is not defined in %s", f.engine, tr.Spec)
+	}
+
+	r := runner.New(runner.Config{
+		KValues:          []int{depth},
+		MaxK:             depth,
+		Runs:             1,
+		QueryParallelism: runner.QueryParallelismUnlimited,
+		VectorStore:      vectorStore,
+	})
+	sp := startSpinner("Calibrating " + tr.Name() + "…")
+	result, err := r.RunAll(cmd.Context(), bs, executors)
+	sp.Stop()
+	if err != nil {
+		return fmt.Errorf("calibration run: %w", err)
+	}
+
+	samples := calibrationSamples(result, f.engine, judgments, minGrade)
+	sigmoid, err := ranking.FitSigmoid(samples)
+	if err != nil {
+		return fmt.Errorf("fit sigmoid on %d judged hits of %s: %w", len(samples), f.engine, err)
+	}
+
+	outPath := f.output
+	if outPath == "" {
+		outPath = filepath.Join(tr.Root, calibrationFile)
+	}
+	calibrations := ranking.Calibrations{}
+	if _, err := os.Stat(outPath); err == nil {
+		if calibrations, err = ranking.ReadCalibrations(outPath); err != nil {
+			return err
+		}
+	}
+	calibrations[f.kind] = sigmoid
+	if err := ranking.WriteCalibrations(outPath, calibrations); err != nil {
+		return err
+	}
+
+	printDone(cmd.OutOrStdout(), fmt.Sprintf("Calibration written: %s  (kind=%s  a=%.4f  b=%.4f  samples=%d)",
+		outPath, f.kind, sigmoid.A, sigmoid.B, len(samples)))
+	return nil
+}
+
+// calibrationSamples pairs the raw score of every judged hit of the engine with its
+// relevance. A query shared by several jobs is sampled once; unjudged hits are skipped.
+func calibrationSamples(result *runner.BenchmarkResult, engineName string, judgments map[string]map[string]int, minGrade int) []ranking.Sample {
+	var samples []ranking.Sample
+	seen := make(map[string]bool)
+	for _, jr := range result.Jobs {
+		for _, qID := range jr.QueryOrder {
+			qr, ok := jr.Results[qID][engineName]
+			if !ok || qr.Error != nil || seen[qID] || len(qr.RawScores) != len(qr.RankedDocIDs) {
+				continue
+			}
+			seen[qID] = true
+
+			grades := judgments[qID]
+			for i, id := range qr.RankedDocIDs {
+				grade, judged := grades[id.String()]
+				if !judged {
+					continue
+				}
+				samples = append(samples, ranking.Sample{Score: qr.RawScores[i], Relevant: grade >= minGrade})
+			}
+		}
+	}
+	return samples
+}
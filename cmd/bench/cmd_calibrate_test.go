@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/bench/runner"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalibrationSamples(t *testing.T) {
+	relevant, marginal, unjudged := uuid.New(), uuid.New(), uuid.New()
+	qr := runner.QueryResult{
+		RankedDocIDs: []uuid.UUID{relevant, marginal, unjudged},
+		RawScores:    []float64{7.5, 3.2, 1.1},
+	}
+	job := &runner.JobResult{
+		QueryOrder: []string{"q1"},
+		Results:    map[string]map[string]runner.QueryResult{"q1": {"api": qr, "pg": {RankedDocIDs: qr.RankedDocIDs}}},
+	}
+	// The same suite in a second job must not double the samples
+	result := &runner.BenchmarkResult{Jobs: []*runner.JobResult{job, job}}
+	judgments := map[string]map[string]int{"q1": {relevant.String(): 3, marginal.String(): 1}}
+
+	samples := calibrationSamples(result, "api", judgments, 2)
+	assert.Equal(t, []ranking.Sample{{Score: 7.5, Relevant: true}, {Score: 3.2}}, samples)
+
+	assert.Empty(t, calibrationSamples(result, "pg", judgments, 2), "engines without scores yield no samples")
+}
@@ -35,6 +35,8 @@ Typical pipeline (pass the track name as a positional arg):
   bench status <name>               see where you left off
   bench diff   <name>               compare latest two runs
   bench clean  <name>               remove old report files (keep N newest)
+  bench calibrate <name> --engine api --kind match
+                                    fit sigmoid score normalization on judgments
 
 Tracks live under ./tracks as either a flat folder (fts_quality) or nested as
 <dataset>/<paradigm> (news/fts). validate/pool/judge/run/status accept a glob
@@ -61,6 +63,7 @@ func main() {
 		newStatusCmd(),
 		newDiffCmd(),
 		newCleanCmd(),
+		newCalibrateCmd(),
 		newReportCmd(), // top-level alias for bench show report
 	)
 
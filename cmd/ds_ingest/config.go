@@ -31,6 +31,8 @@ type DataImportConfig struct {
 	}
 	factory.StorageConfig
 	Embedding embedding.Config
+	// LanguageDetection fills missing article languages and flags mismatches (see ingest.WithLanguageDetector)
+	LanguageDetection bool
 }
 
 func (as *AppConfig) Load() (*DataImportConfig, error) {
@@ -56,6 +58,10 @@ func (as *AppConfig) Load() (*DataImportConfig, error) {
 		return nil, fmt.Errorf("MAPPING_CONFIG_PATH environment variable is not set")
 	}
 
+	// Language detection is enabled by default. Set LANGUAGE_DETECTION_ENABLED=false to trust
+	// the mapped language only.
+	languageDetection := os.Getenv("LANGUAGE_DETECTION_ENABLED") != "false"
+
 	dsPath := os.Getenv("DATASET_PATH")
 	if dsPath == "" {
 		slog.Error("DATASET_PATH environment variable is not set")
@@ -86,8 +92,9 @@ func (as *AppConfig) Load() (*DataImportConfig, error) {
 			Enabled: bulkEnabled == "true",
 			Size:    bulkSizeNum,
 		},
-		StorageConfig: *storageCfg,
-		Embedding:     *embed,
+		StorageConfig:     *storageCfg,
+		Embedding:         *embed,
+		LanguageDetection: languageDetection,
 	}
 
 	return cfg, nil
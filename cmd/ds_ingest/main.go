@@ -9,6 +9,7 @@ import (
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
 	"github.com/DjordjeVuckovic/news-hunter/internal/ingest"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ingest/langdetect"
 	"github.com/DjordjeVuckovic/news-hunter/internal/ingest/reader"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/factory"
 	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
@@ -101,6 +102,15 @@ func newPipeline(
 		opts = append(opts, ingest.WithBulk(cfg.BulkOptions.Size))
 	}
 
+	if cfg.LanguageDetection {
+		detector, err := langdetect.NewDetector()
+		if err != nil {
+			slog.Error("failed to create language detector", "error", err)
+			return nil, err
+		}
+		opts = append(opts, ingest.WithLanguageDetector(detector, ingest.DefaultLanguageMinConfidence))
+	}
+
 	if cfg.Embedding.Enabled {
 		ollama, err := embedding.NewOllamaClient(cfg.Embedding.BaseURL)
 		if err != nil {
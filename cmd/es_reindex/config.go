@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/es"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/factory"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/config/env"
+)
+
+const (
+	// sourceIndex copies the documents of the serving index with the _reindex API
+	sourceIndex = "index"
+	// sourcePG loads the articles and embeddings of PostgreSQL, the source of truth
+	sourcePG = "pg"
+
+	defaultBatchSize = 5_000
+)
+
+type ReindexConfig struct {
+	Es *es.ClientConfig
+	// PgConnStr is the database of the pg source
+	PgConnStr string
+	Source    string
+	Version   int
+	DeleteOld bool
+	BatchSize int
+	// Model selects the embeddings loaded by the pg source
+	Model string
+}
+
+func loadConfig() (*ReindexConfig, error) {
+	if err := env.LoadDotEnv(os.Getenv("ENV"), "cmd/es_reindex/.env"); err != nil {
+		slog.Info("Skipping .env environment variables...", "error", err)
+	}
+
+	cfg := &ReindexConfig{}
+	flag.StringVar(&cfg.Source, "source", sourceIndex, "Source of the documents: index (the serving index) or pg")
+	flag.IntVar(&cfg.Version, "version", es.IndexVersion, "Version of the index to build")
+	flag.BoolVar(&cfg.DeleteOld, "delete-old", false, "Delete the previous index in the alias swap")
+	flag.IntVar(&cfg.BatchSize, "batch-size", defaultBatchSize, "Rows read from PostgreSQL per batch")
+	flag.StringVar(&cfg.Model, "model", embedding.DefaultModel, "Embedding model loaded from PostgreSQL")
+	flag.Parse()
+
+	if cfg.Source != sourceIndex && cfg.Source != sourcePG {
+		return nil, fmt.Errorf("invalid source %q, expected %s or %s", cfg.Source, sourceIndex, sourcePG)
+	}
+	if cfg.BatchSize <= 0 {
+		return nil, fmt.Errorf("batch size must be positive, got %d", cfg.BatchSize)
+	}
+
+	storageCfg, err := factory.LoadEnv()
+	if err != nil {
+		return nil, err
+	}
+	if storageCfg.Type != storage.ES {
+		return nil, fmt.Errorf("es_reindex requires STORAGE_TYPE=%s, got %q", storage.ES, storageCfg.Type)
+	}
+	cfg.Es = storageCfg.Es
+
+	if cfg.Source == sourcePG {
+		cfg.PgConnStr = os.Getenv("PG_CONNECTION_STRING")
+		if cfg.PgConnStr == "" {
+			return nil, fmt.Errorf("PG_CONNECTION_STRING is required for the pg source")
+		}
+	}
+
+	return cfg, nil
+}
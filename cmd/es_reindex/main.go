@@ -0,0 +1,126 @@
+// Command es_reindex builds a new version of the Elasticsearch article index and swaps the
+// read and write aliases to it once it is complete, so searches keep running on the old
+// index until then. The documents come from the serving index or from PostgreSQL.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/es"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+)
+
+func main() {
+	cfg, err := loadConfig()
+	if err != nil {
+		slog.Error("failed to load configuration", "error", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx, cfg); err != nil {
+		slog.Error("reindex failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, cfg *ReindexConfig) error {
+	start := time.Now()
+
+	reindexer, err := es.NewReindexer(*cfg.Es)
+	if err != nil {
+		return err
+	}
+
+	serving, legacy, err := reindexer.Serving(ctx)
+	if err != nil {
+		return err
+	}
+	if cfg.Source == sourceIndex && len(serving) == 0 {
+		return fmt.Errorf("index %q does not exist, nothing to reindex", cfg.Es.IndexName)
+	}
+
+	dest, err := reindexer.CreateIndex(ctx, cfg.Version)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("🛫 Reindexing",
+		"source", cfg.Source,
+		"serving", serving,
+		"legacy", legacy,
+		"dest", dest)
+
+	var copied int64
+	switch cfg.Source {
+	case sourceIndex:
+		copied, err = reindexer.CopyIndex(ctx, serving, dest)
+	case sourcePG:
+		copied, err = loadFromPG(ctx, cfg, reindexer, dest)
+	}
+	if err != nil {
+		return fmt.Errorf("index %q is left without aliases: %w", dest, err)
+	}
+
+	if err := reindexer.SwapAliases(ctx, dest, cfg.DeleteOld); err != nil {
+		return err
+	}
+
+	slog.Info("✅ Reindex complete",
+		"dest", dest,
+		"documents", copied,
+		"duration", time.Since(start))
+	return nil
+}
+
+// loadFromPG indexes the articles of PostgreSQL into dest, then their embeddings of cfg.Model
+func loadFromPG(ctx context.Context, cfg *ReindexConfig, reindexer *es.Reindexer, dest string) (int64, error) {
+	pool, err := pg.NewConnectionPool(ctx, pg.PoolConfig{
+		ConnStr:          cfg.PgConnStr,
+		RegisterVecTypes: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer pool.Close()
+
+	indexer := reindexer.Indexer(dest)
+	var articles int64
+	err = pg.NewArticleReader(pool).Scan(ctx, cfg.BatchSize, func(batch []document.Article) error {
+		if err := indexer.SaveBulk(ctx, batch); err != nil {
+			return err
+		}
+		articles += int64(len(batch))
+		slog.Info("Indexed article batch", "count", len(batch), "total", articles)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load articles: %w", err)
+	}
+
+	embedder := reindexer.Embedder(dest)
+	var vectors int64
+	err = pg.NewEmbedder(pool).Scan(ctx, cfg.Model, cfg.BatchSize, func(batch []*embedding.Vec) error {
+		if err := embedder.SaveBulk(ctx, batch); err != nil {
+			return err
+		}
+		vectors += int64(len(batch))
+		slog.Info("Indexed embedding batch", "count", len(batch), "total", vectors)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load embeddings: %w", err)
+	}
+
+	return articles, nil
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/factory"
@@ -22,6 +25,11 @@ func NewAppConfig() *AppConfig {
 type NewsSearchConfig struct {
 	StorageConfig   factory.StorageConfig
 	EmbeddingConfig embedding.Config
+	// CalibrationFile holds the sigmoids of sigmoid score normalization; empty disables it
+	CalibrationFile string
+	// MultiSearchWorkers and MultiSearchBudget bound _msearch batches; zero keeps the router defaults
+	MultiSearchWorkers int
+	MultiSearchBudget  time.Duration
 }
 
 func (as *AppConfig) Load() (*NewsSearchConfig, error) {
@@ -43,8 +51,22 @@ func (as *AppConfig) Load() (*NewsSearchConfig, error) {
 		return nil, err
 	}
 
-	return &NewsSearchConfig{
+	cfg := &NewsSearchConfig{
 		StorageConfig:   *storageCfg,
 		EmbeddingConfig: *embed,
-	}, nil
+		CalibrationFile: os.Getenv("RANKING_CALIBRATION_FILE"),
+	}
+
+	if workers := os.Getenv("MSEARCH_WORKERS"); workers != "" {
+		if cfg.MultiSearchWorkers, err = strconv.Atoi(workers); err != nil || cfg.MultiSearchWorkers < 0 {
+			return nil, fmt.Errorf("invalid MSEARCH_WORKERS %q: expected a non-negative integer", workers)
+		}
+	}
+	if budget := os.Getenv("MSEARCH_TIME_BUDGET"); budget != "" {
+		if cfg.MultiSearchBudget, err = time.ParseDuration(budget); err != nil || cfg.MultiSearchBudget < 0 {
+			return nil, fmt.Errorf("invalid MSEARCH_TIME_BUDGET %q: expected a non-negative duration such as 10s", budget)
+		}
+	}
+
+	return cfg, nil
 }
@@ -17,6 +17,8 @@ import (
 	"github.com/DjordjeVuckovic/news-hunter/internal/api/router"
 	server2 "github.com/DjordjeVuckovic/news-hunter/internal/api/server"
 	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/factory"
 	pkgserver "github.com/DjordjeVuckovic/news-hunter/pkg/server"
 	"github.com/labstack/echo/v4"
@@ -87,9 +89,63 @@ func main() {
 		slog.Info("Semantic search disabled")
 	}
 
+	suggester, err := factory.NewSuggester(s.Context(), cfg.StorageConfig)
+	if err != nil {
+		slog.Warn("Spelling suggestions disabled: failed to create suggester", "error", err)
+	} else {
+		routerOpts = append(routerOpts, router.WithSuggester(suggester))
+	}
+
+	autocompleter, err := factory.NewAutocompleter(s.Context(), cfg.StorageConfig)
+	if err != nil {
+		slog.Warn("Title autocomplete disabled: failed to create autocompleter", "error", err)
+	} else {
+		routerOpts = append(routerOpts, router.WithAutocompleter(autocompleter))
+	}
+
+	exporter, err := factory.NewExporter(s.Context(), cfg.StorageConfig)
+	if err != nil {
+		slog.Warn("Export disabled: failed to create exporter", "error", err)
+	} else {
+		routerOpts = append(routerOpts, router.WithExporter(exporter))
+	}
+
+	if cfg.CalibrationFile != "" {
+		calibrations, err := ranking.ReadCalibrations(cfg.CalibrationFile)
+		if err != nil {
+			slog.Warn("Sigmoid score normalization disabled: failed to read calibrations", "error", err)
+		} else {
+			routerOpts = append(routerOpts, router.WithCalibrations(calibrations))
+			slog.Info("Sigmoid score normalization enabled", "kinds", len(calibrations))
+		}
+	}
+
+	reader, readerErr := factory.NewReader(s.Context(), cfg.StorageConfig)
+	if readerErr == nil {
+		routerOpts = append(routerOpts, router.WithArticleReader(reader))
+	}
+
+	if multiSearcher, ok := searcher.(storage.MultiSearcher); ok {
+		routerOpts = append(routerOpts, router.WithMultiSearcher(multiSearcher))
+	}
+	routerOpts = append(routerOpts, router.WithMultiSearchLimits(cfg.MultiSearchWorkers, cfg.MultiSearchBudget))
+
 	searchrouter := router.NewSearchRouter(s.Echo, searcher, routerOpts...)
 	searchrouter.Bind()
 
+	if readerErr != nil {
+		slog.Warn("Article endpoints disabled: failed to create reader", "error", readerErr)
+	} else {
+		var articleOpts []router.ArticleRouterOption
+		similarSearcher, err := factory.NewSimilarSearcher(s.Context(), cfg.StorageConfig)
+		if err != nil {
+			slog.Warn("Similar articles disabled: failed to create similar searcher", "error", err)
+		} else {
+			articleOpts = append(articleOpts, router.WithSimilarSearcher(similarSearcher))
+		}
+		router.NewArticleRouter(s.Echo, reader, articleOpts...).Bind()
+	}
+
 	go func() {
 		<-s.ShutdownSignal()
 		slog.Info("Shutdown started, cleaning up resources...")
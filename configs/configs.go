@@ -0,0 +1,9 @@
+// Package configs embeds the static configuration files that are applied from code.
+package configs
+
+import "embed"
+
+// Elasticsearch holds the ILM policy and the index template applied on index bootstrap
+//
+//go:embed elasticsearch/*.json
+var Elasticsearch embed.FS
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_articles_language;
+DROP INDEX IF EXISTS idx_articles_category;
+DROP INDEX IF EXISTS idx_articles_source_name;
+DROP INDEX IF EXISTS idx_articles_source_id;
@@ -0,0 +1,7 @@
+BEGIN;
+-- Expression indexes backing structured search filters on metadata fields.
+CREATE INDEX IF NOT EXISTS idx_articles_source_id ON articles ((metadata->>'sourceId'));
+CREATE INDEX IF NOT EXISTS idx_articles_source_name ON articles ((metadata->>'sourceName'));
+CREATE INDEX IF NOT EXISTS idx_articles_category ON articles ((metadata->>'category'));
+CREATE INDEX IF NOT EXISTS idx_articles_language ON articles (language);
+COMMIT;
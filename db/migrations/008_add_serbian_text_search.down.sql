@@ -0,0 +1,29 @@
+BEGIN;
+-- Restore the trigger function from 003 (language column used as regconfig directly)
+CREATE OR REPLACE FUNCTION update_article_search_vector()
+    RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.search_vector IS NULL OR NEW.search_vector = ''::tsvector THEN
+        NEW.search_vector :=
+            setweight(to_tsvector(COALESCE(NEW.language, 'english')::regconfig,
+                                  COALESCE(NEW.title, '')), 'A') ||
+            setweight(to_tsvector(COALESCE(NEW.language, 'english')::regconfig,
+                                  COALESCE(NEW.description, '')), 'B') ||
+            setweight(to_tsvector(COALESCE(NEW.language, 'english')::regconfig,
+                                  COALESCE(NEW.content, '')), 'C') ||
+            setweight(to_tsvector(COALESCE(NEW.language, 'english')::regconfig,
+                                  COALESCE(NEW.subtitle, '') || ' ' || COALESCE(NEW.author, '')), 'D');
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS article_ts_input(regconfig, text);
+DROP FUNCTION IF EXISTS article_ts_config(text);
+DROP TEXT SEARCH CONFIGURATION IF EXISTS news_serbian;
+DROP TEXT SEARCH DICTIONARY IF EXISTS serbian_stem_dict;
+DROP FUNCTION IF EXISTS serbian_normalize(text);
+DROP FUNCTION IF EXISTS serbian_fold(text);
+DROP FUNCTION IF EXISTS serbian_translit(text);
+DROP EXTENSION IF EXISTS unaccent;
+COMMIT;
@@ -0,0 +1,135 @@
+BEGIN;
+-- Serbian full-text search: Cyrillic/Latin transliteration, diacritic folding,
+-- stopword removal and stemming, plus a language-aware search_vector trigger.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Cyrillic -> Latin (Gaj) transliteration. Digraph letters are replaced first,
+-- the remaining letters map one-to-one.
+CREATE OR REPLACE FUNCTION serbian_translit(input text)
+    RETURNS text
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS
+$$
+SELECT translate(
+               replace(replace(replace(replace(replace(replace(input,
+                   'љ', 'lj'), 'Љ', 'Lj'), 'њ', 'nj'), 'Њ', 'Nj'), 'џ', 'dž'), 'Џ', 'Dž'),
+               'абвгдђежзијклмнопрстћуфхцчшАБВГДЂЕЖЗИЈКЛМНОПРСТЋУФХЦЧШ',
+               'abvgdđežzijklmnoprstćufhcčšABVGDĐEŽZIJKLMNOPRSTĆUFHCČŠ')
+$$;
+
+-- Script- and diacritic-insensitive form: "Ђоковић", "Đoković" and "Djokovic" fold alike
+-- (đ -> dj is applied before unaccent, which would otherwise map it to d).
+-- unaccent() is only STABLE; the dictionary-qualified call is safe to use in an IMMUTABLE function.
+CREATE OR REPLACE FUNCTION serbian_fold(input text)
+    RETURNS text
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS
+$$
+SELECT public.unaccent('public.unaccent'::regdictionary,
+                       replace(lower(serbian_translit(input)), 'đ', 'dj'))
+$$;
+
+-- serbian_fold plus stopword removal. PostgreSQL ships no Serbian stopword list and
+-- custom stopword files cannot be installed from SQL, so stopwords are stripped from
+-- the text on both the document and the query side.
+CREATE OR REPLACE FUNCTION serbian_normalize(input text)
+    RETURNS text
+    LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE AS
+$$
+SELECT regexp_replace(
+               serbian_fold(input),
+               '\m(a|ako|ali|bi|bih|bila|bile|bili|bilo|bio|biti|ce|cemo|ces|da|do|dok|ga|gde|i|ih|ili|iz|ja|je|jer|jesu|jos|ju|kad|kada|kako|kao|koja|koje|koji|kojih|kojima|koju|li|me|mi|mu|na|nad|nam|nas|ne|nego|neki|ni|nije|nisu|niti|njega|njegov|njen|njih|njihov|njoj|o|od|on|ona|one|oni|ono|ova|ove|ovi|ovo|pa|po|pod|pored|pre|prema|pri|sa|sam|samo|se|si|smo|ste|su|sve|svi|ta|taj|tako|te|ti|to|tu|u|uz|vas|vec|vi|za|zbog|sto)\M',
+               ' ', 'g')
+$$;
+
+-- Stemming dictionary: Snowball Serbian where the server provides it, otherwise no stemming.
+DO
+$$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM pg_ts_dict WHERE dictname = 'serbian_stem_dict') THEN
+            BEGIN
+                CREATE TEXT SEARCH DICTIONARY serbian_stem_dict (TEMPLATE = snowball, Language = serbian);
+            EXCEPTION
+                WHEN OTHERS THEN
+                    RAISE NOTICE 'snowball serbian stemmer unavailable, falling back to simple: %', SQLERRM;
+                    CREATE TEXT SEARCH DICTIONARY serbian_stem_dict (TEMPLATE = simple);
+            END;
+        END IF;
+    END
+$$;
+
+-- news_serbian is distinct from any built-in pg_catalog.serbian config, which would
+-- shadow a same-named public config. Input must be passed through serbian_normalize.
+DROP TEXT SEARCH CONFIGURATION IF EXISTS news_serbian;
+CREATE TEXT SEARCH CONFIGURATION news_serbian (COPY = simple);
+ALTER TEXT SEARCH CONFIGURATION news_serbian
+    ALTER MAPPING FOR asciiword, asciihword, hword_asciipart, word, hword, hword_part
+        WITH unaccent, serbian_stem_dict;
+
+-- Resolves the text search config for an article language. Accepts config names and
+-- ISO codes; unknown languages fall back to 'simple' instead of failing the write.
+CREATE OR REPLACE FUNCTION article_ts_config(lang text)
+    RETURNS regconfig
+    LANGUAGE plpgsql STABLE PARALLEL SAFE AS
+$$
+DECLARE
+    cfg regconfig;
+BEGIN
+    IF lang IS NULL OR btrim(lang) = '' THEN
+        RETURN 'english'::regconfig;
+    END IF;
+
+    CASE lower(btrim(lang))
+        WHEN 'serbian', 'sr', 'srpski' THEN RETURN 'news_serbian'::regconfig;
+        WHEN 'en' THEN RETURN 'english'::regconfig;
+        ELSE NULL;
+        END CASE;
+
+    SELECT c.oid::regconfig
+    INTO cfg
+    FROM pg_ts_config c
+             JOIN pg_namespace n ON n.oid = c.cfgnamespace
+    WHERE c.cfgname = lower(btrim(lang))
+      AND n.nspname IN ('pg_catalog', 'public')
+    ORDER BY n.nspname = 'pg_catalog' DESC
+    LIMIT 1;
+
+    RETURN COALESCE(cfg, 'simple'::regconfig);
+END;
+$$;
+
+-- Applies the language-specific pre-processing that the config cannot do itself.
+CREATE OR REPLACE FUNCTION article_ts_input(cfg regconfig, input text)
+    RETURNS text
+    LANGUAGE sql STABLE PARALLEL SAFE AS
+$$
+SELECT CASE
+           WHEN cfg = 'news_serbian'::regconfig THEN serbian_normalize(COALESCE(input, ''))
+           ELSE COALESCE(input, '')
+           END
+$$;
+
+CREATE OR REPLACE FUNCTION update_article_search_vector()
+    RETURNS TRIGGER AS
+$$
+DECLARE
+    cfg regconfig;
+BEGIN
+    -- Only compute if not already set by application
+    IF NEW.search_vector IS NULL OR NEW.search_vector = ''::tsvector THEN
+        cfg := article_ts_config(NEW.language);
+        NEW.search_vector :=
+                setweight(to_tsvector(cfg, article_ts_input(cfg, NEW.title)), 'A') ||
+                setweight(to_tsvector(cfg, article_ts_input(cfg, NEW.description)), 'B') ||
+                setweight(to_tsvector(cfg, article_ts_input(cfg, NEW.content)), 'C') ||
+                setweight(to_tsvector(cfg, article_ts_input(cfg,
+                        COALESCE(NEW.subtitle, '') || ' ' || COALESCE(NEW.author, ''))), 'D');
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Recompute vectors of non-English articles with the resolved config (the trigger fills NULL vectors)
+UPDATE articles
+SET search_vector = NULL
+WHERE article_ts_config(language) <> 'english'::regconfig;
+
+COMMIT;
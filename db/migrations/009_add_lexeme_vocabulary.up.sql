@@ -0,0 +1,13 @@
+BEGIN;
+-- Vocabulary of indexed lexemes, used to expand fuzzy match terms to nearby lexemes
+-- (levenshtein_less_equal from fuzzystrmatch, migration 005).
+-- Rebuilt after ingest: REFRESH MATERIALIZED VIEW CONCURRENTLY article_lexemes;
+CREATE MATERIALIZED VIEW IF NOT EXISTS article_lexemes AS
+SELECT word, ndoc, nentry
+FROM ts_stat('SELECT search_vector FROM articles');
+
+-- Unique index is required by REFRESH ... CONCURRENTLY
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_lexemes_word ON article_lexemes (word);
+-- Length pre-filter for candidates within the edit distance
+CREATE INDEX IF NOT EXISTS idx_article_lexemes_length ON article_lexemes (char_length(word));
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+-- Surface-word vocabulary ('simple' config: lower-cased, unstemmed) used for "did you mean"
+-- suggestions. Candidates are found by trigram similarity and ranked by document frequency.
+-- Rebuilt after ingest: REFRESH MATERIALIZED VIEW CONCURRENTLY article_words;
+CREATE MATERIALIZED VIEW IF NOT EXISTS article_words AS
+SELECT word, ndoc
+FROM ts_stat($$
+    SELECT to_tsvector('simple', COALESCE(title, '') || ' ' || COALESCE(description, '') || ' ' || COALESCE(content, ''))
+    FROM articles
+$$)
+WHERE char_length(word) >= 2
+  AND word ~ '[[:alpha:]]';
+
+-- Unique index is required by REFRESH ... CONCURRENTLY
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_words_word ON article_words (word);
+CREATE INDEX IF NOT EXISTS idx_article_words_trgm ON article_words USING gin (word gin_trgm_ops);
+COMMIT;
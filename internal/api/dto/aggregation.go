@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"fmt"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// AggregationParams represents one named facet; exactly one aggregation type must be set
+// Example:
+//
+//	{
+//	  "categories": {"terms": {"field": "category", "size": 5}},
+//	  "per_month": {"date_histogram": {"field": "published_at", "interval": "month"}}
+//	}
+type AggregationParams struct {
+	Terms         *TermsAggregationParams         `json:"terms,omitempty"`
+	DateHistogram *DateHistogramAggregationParams `json:"date_histogram,omitempty"`
+}
+
+// TermsAggregationParams counts documents per value of a keyword field
+// Fields: language, source_id, source_name, category, author
+type TermsAggregationParams struct {
+	Field string `json:"field" validate:"required"`
+	Size  int    `json:"size,omitempty"`
+}
+
+// DateHistogramAggregationParams counts documents per calendar interval
+// Fields: published_at, created_at. Intervals: hour, day, week, month (default), quarter, year
+type DateHistogramAggregationParams struct {
+	Field    string `json:"field" validate:"required"`
+	Interval string `json:"interval,omitempty"`
+}
+
+// AggregationsToDomain converts the named aggregation params into query.Aggregation values ordered by name
+func AggregationsToDomain(params map[string]AggregationParams) ([]query.Aggregation, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	aggs := make([]query.Aggregation, 0, len(params))
+	for name, p := range params {
+		agg, err := p.toDomain(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := agg.Validate(); err != nil {
+			return nil, apperr.NewValidationWrap("invalid aggregation", err)
+		}
+		aggs = append(aggs, agg)
+	}
+	query.SortAggregations(aggs)
+
+	return aggs, nil
+}
+
+func (p AggregationParams) toDomain(name string) (query.Aggregation, error) {
+	switch {
+	case p.Terms != nil && p.DateHistogram != nil:
+		return query.Aggregation{}, apperr.NewValidation(fmt.Sprintf("aggregation %s must specify only one type", name))
+	case p.Terms != nil:
+		size := p.Terms.Size
+		if size == 0 {
+			size = query.DefaultTermsAggregationSize
+		}
+		return query.Aggregation{
+			Name:  name,
+			Kind:  query.TermsAggregation,
+			Field: p.Terms.Field,
+			Size:  size,
+		}, nil
+	case p.DateHistogram != nil:
+		interval := query.CalendarInterval(p.DateHistogram.Interval)
+		if interval == "" {
+			interval = query.DefaultCalendarInterval
+		}
+		return query.Aggregation{
+			Name:     name,
+			Kind:     query.DateHistogramAggregation,
+			Field:    p.DateHistogram.Field,
+			Interval: interval,
+		}, nil
+	default:
+		return query.Aggregation{}, apperr.NewValidation(fmt.Sprintf("aggregation %s must specify one of: terms, date_histogram", name))
+	}
+}
@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestAggregationsToDomain(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    map[string]AggregationParams
+		want      []query.Aggregation
+		wantError bool
+	}{
+		{name: "no aggregations", params: nil, want: nil},
+		{
+			name: "defaults applied and ordered by name",
+			params: map[string]AggregationParams{
+				"per_month":  {DateHistogram: &DateHistogramAggregationParams{Field: "published_at"}},
+				"categories": {Terms: &TermsAggregationParams{Field: "category"}},
+			},
+			want: []query.Aggregation{
+				{Name: "categories", Kind: query.TermsAggregation, Field: "category", Size: query.DefaultTermsAggregationSize},
+				{Name: "per_month", Kind: query.DateHistogramAggregation, Field: "published_at", Interval: query.IntervalMonth},
+			},
+		},
+		{
+			name:      "unsupported terms field",
+			params:    map[string]AggregationParams{"titles": {Terms: &TermsAggregationParams{Field: "title"}}},
+			wantError: true,
+		},
+		{
+			name:      "size above maximum",
+			params:    map[string]AggregationParams{"sources": {Terms: &TermsAggregationParams{Field: "source_id", Size: 1000}}},
+			wantError: true,
+		},
+		{
+			name: "unsupported interval",
+			params: map[string]AggregationParams{
+				"per_decade": {DateHistogram: &DateHistogramAggregationParams{Field: "published_at", Interval: "decade"}},
+			},
+			wantError: true,
+		},
+		{
+			name:      "no aggregation type",
+			params:    map[string]AggregationParams{"empty": {}},
+			wantError: true,
+		},
+		{
+			name: "two aggregation types",
+			params: map[string]AggregationParams{"both": {
+				Terms:         &TermsAggregationParams{Field: "category"},
+				DateHistogram: &DateHistogramAggregationParams{Field: "published_at"},
+			}},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AggregationsToDomain(tt.params)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("AggregationsToDomain() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("AggregationsToDomain() = %+v, want %+v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("aggregation[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package dto
 import (
 	"time"
 
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
 	"github.com/google/uuid"
 )
 
@@ -29,10 +31,94 @@ type ArticleMetadata struct {
 
 	// System metadata
 	ImportedAt time.Time `json:"importedAt,omitempty"`
+
+	// Language detection
+	DetectedLanguage   string  `json:"detectedLanguage,omitempty"`
+	LanguageConfidence float64 `json:"languageConfidence,omitempty"`
+	LanguageMismatch   bool    `json:"languageMismatch,omitempty"`
 }
 
 type ArticleSearchResult struct {
 	Article         `json:"article" ` // Embedded Article struct for search results
 	Score           float64           `json:"score"`                      // Score rank between 0 and 1
 	ScoreNormalized float64           `json:"score_normalized,omitempty"` // ScoreNormalized is the normalized(between 0-1) score
+	// Highlight holds matched-term fragments per field, only when highlighting was requested
+	Highlight map[string][]string `json:"highlight,omitempty"`
+	// ScoreBreakdown holds the rescoring factors of the score, only when a breakdown was requested
+	ScoreBreakdown *query.ScoreBreakdown `json:"score_breakdown,omitempty"`
+	// Explanation explains how the score was computed, only when an explanation was requested
+	Explanation *query.Explanation `json:"explanation,omitempty"`
+}
+
+// ArticleFromDocument maps a stored document to its API representation
+func ArticleFromDocument(a document.Article) Article {
+	return Article{
+		ID:          a.ID,
+		Title:       a.Title,
+		Subtitle:    a.Subtitle,
+		Content:     a.Content,
+		Author:      a.Author,
+		Description: a.Description,
+		Language:    a.Language,
+		CreatedAt:   a.CreatedAt,
+		URL:         a.URL,
+		Metadata: ArticleMetadata{
+			SourceId:    a.Metadata.SourceId,
+			SourceName:  a.Metadata.SourceName,
+			PublishedAt: a.Metadata.PublishedAt,
+			Category:    a.Metadata.Category,
+			ImportedAt:  a.Metadata.ImportedAt,
+
+			DetectedLanguage:   a.Metadata.DetectedLanguage,
+			LanguageConfidence: a.Metadata.LanguageConfidence,
+			LanguageMismatch:   a.Metadata.LanguageMismatch,
+		},
+	}
+}
+
+// MaxMGetIDs caps the number of ids in one batch get request
+const MaxMGetIDs = 100
+
+// MGetRequest fetches several articles by id in one call
+// Example:
+//
+//	{"ids": ["550e8400-e29b-41d4-a716-446655440000", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"]}
+type MGetRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// MGetResponse returns found articles in request order; unknown ids are listed in NotFound
+type MGetResponse struct {
+	Articles []Article `json:"articles"`
+	NotFound []string  `json:"not_found,omitempty"`
+}
+
+// ExplainRequest explains how a structured query scores one article.
+// Query and rescoring take the same form as in SearchRequest.
+//
+// Example:
+//
+//	{
+//	  "query": {"multi_match": {"query": "climate change", "fields": ["title", "content"]}},
+//	  "rescoring": {"recency": {"scale": "7d"}}
+//	}
+type ExplainRequest struct {
+	Query     QueryWrapper     `json:"query"`
+	Rescoring *RescoringParams `json:"rescoring,omitempty"`
+}
+
+// ExplainResponse explains the score of an article; the explanation is absent when
+// the article does not match the query.
+type ExplainResponse struct {
+	ID          string             `json:"id"`
+	Matched     bool               `json:"matched"`
+	Score       float64            `json:"score"`
+	Explanation *query.Explanation `json:"explanation,omitempty"`
+}
+
+// SimilarArticlesResponse lists articles related to a source article.
+// Strategy is "vector" (kNN over the stored embedding) or "lexical" (term overlap fallback).
+type SimilarArticlesResponse struct {
+	Strategy string                `json:"strategy"`
+	Hits     []ArticleSearchResult `json:"hits"`
 }
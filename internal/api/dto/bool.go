@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// BoolParams represents a compound bool query. Clauses are match, multi_match, phrase
+// or nested bool queries.
+// Example:
+//
+//	{
+//	  "must":     [{"phrase": {"query": "climate change", "fields": ["title"]}}],
+//	  "should":   [{"match": {"field": "content", "query": "emissions"}}],
+//	  "must_not": [{"match": {"field": "author", "query": "smith"}}],
+//	  "filter":   [{"multi_match": {"query": "policy", "fields": ["title", "description"]}}],
+//	  "minimum_should_match": 1
+//	}
+type BoolParams struct {
+	Must               []BoolClauseParams `json:"must,omitempty"`
+	Should             []BoolClauseParams `json:"should,omitempty"`
+	MustNot            []BoolClauseParams `json:"must_not,omitempty"`
+	Filter             []BoolClauseParams `json:"filter,omitempty"`
+	MinimumShouldMatch int                `json:"minimum_should_match,omitempty"`
+}
+
+// BoolClauseParams is one clause of a bool query; exactly one field must be set
+type BoolClauseParams struct {
+	Match      *MatchParams      `json:"match,omitempty"`
+	MultiMatch *MultiMatchParams `json:"multi_match,omitempty"`
+	Phrase     *PhraseParams     `json:"phrase,omitempty"`
+	Bool       *BoolParams       `json:"bool,omitempty"`
+}
+
+func (p *BoolParams) ToDomain() (*query.Bool, error) {
+	q, err := p.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	if err := q.Validate(); err != nil {
+		return nil, apperr.NewValidationWrap("invalid bool query", err)
+	}
+	return q, nil
+}
+
+func (p *BoolParams) toDomain() (*query.Bool, error) {
+	q := &query.Bool{MinimumShouldMatch: p.MinimumShouldMatch}
+	for _, occurrence := range []struct {
+		params []BoolClauseParams
+		dest   *[]query.Clause
+	}{
+		{params: p.Must, dest: &q.Must},
+		{params: p.Should, dest: &q.Should},
+		{params: p.MustNot, dest: &q.MustNot},
+		{params: p.Filter, dest: &q.Filter},
+	} {
+		for _, cp := range occurrence.params {
+			c, err := cp.toDomain()
+			if err != nil {
+				return nil, err
+			}
+			*occurrence.dest = append(*occurrence.dest, c)
+		}
+	}
+	return q, nil
+}
+
+func (p *BoolClauseParams) toDomain() (query.Clause, error) {
+	set := 0
+	for _, ok := range []bool{p.Match != nil, p.MultiMatch != nil, p.Phrase != nil, p.Bool != nil} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return query.Clause{}, apperr.NewValidation("each bool clause must specify exactly one of: match, multi_match, phrase, bool")
+	}
+
+	var c query.Clause
+	var err error
+	switch {
+	case p.Match != nil:
+		c.Match, err = p.Match.ToDomain()
+	case p.MultiMatch != nil:
+		c.MultiMatch, err = p.MultiMatch.ToDomain()
+	case p.Phrase != nil:
+		c.Phrase, err = p.Phrase.ToDomain()
+	case p.Bool != nil:
+		c.Bool, err = p.Bool.toDomain()
+	}
+	if err != nil {
+		return query.Clause{}, err
+	}
+	return c, nil
+}
@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestBoolParamsToDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+		check   func(t *testing.T, q *query.Bool)
+	}{
+		{
+			name: "all occurrences with nested bool",
+			body: `{
+				"must": [{"phrase": {"query": "climate change", "fields": ["title"]}}],
+				"should": [
+					{"match": {"field": "content", "query": "emissions", "fuzziness": "AUTO"}},
+					{"bool": {"should": [{"match": {"field": "content", "query": "carbon"}}]}}
+				],
+				"must_not": [{"match": {"field": "author", "query": "smith"}}],
+				"filter": [{"multi_match": {"query": "policy", "fields": ["title", "description^2"]}}],
+				"minimum_should_match": 1
+			}`,
+			check: func(t *testing.T, q *query.Bool) {
+				if len(q.Must) != 1 || q.Must[0].Kind() != query.PhraseType {
+					t.Errorf("Must = %+v, want one phrase", q.Must)
+				}
+				if len(q.Should) != 2 || q.Should[1].Kind() != query.BoolType {
+					t.Errorf("Should = %+v, want match and nested bool", q.Should)
+				}
+				if q.Should[0].Match.GetFuzziness() != query.FuzzinessAuto {
+					t.Errorf("fuzziness = %q, want AUTO", q.Should[0].Match.Fuzziness)
+				}
+				if len(q.MustNot) != 1 || len(q.Filter) != 1 {
+					t.Errorf("MustNot = %d, Filter = %d, want 1 each", len(q.MustNot), len(q.Filter))
+				}
+				if q.Filter[0].MultiMatch.Fields[1].Weight != 2 {
+					t.Errorf("filter field weight = %v, want 2", q.Filter[0].MultiMatch.Fields[1].Weight)
+				}
+				if q.MinimumShouldMatch != 1 {
+					t.Errorf("MinimumShouldMatch = %d, want 1", q.MinimumShouldMatch)
+				}
+			},
+		},
+		{name: "empty bool", body: `{}`, wantErr: true},
+		{name: "empty clause", body: `{"must": [{}]}`, wantErr: true},
+		{name: "two queries in one clause", body: `{"must": [{"match": {"field": "title", "query": "a"}, "phrase": {"query": "a b", "fields": ["title"]}}]}`, wantErr: true},
+		{name: "invalid leaf", body: `{"must": [{"match": {"field": "title", "query": ""}}]}`, wantErr: true},
+		{name: "invalid leaf language", body: `{"must": [{"match": {"field": "title", "query": "a", "language": "klingon"}}]}`, wantErr: true},
+		{name: "minimum_should_match too high", body: `{"should": [{"match": {"field": "title", "query": "a"}}], "minimum_should_match": 2}`, wantErr: true},
+		{name: "empty nested bool", body: `{"must": [{"bool": {}}]}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var params BoolParams
+			if err := json.Unmarshal([]byte(tt.body), &params); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			got, err := params.ToDomain()
+			if tt.wantErr {
+				var ve *apperr.ValidationError
+				if !errors.As(err, &ve) {
+					t.Fatalf("ToDomain() error = %v, want validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ToDomain() error = %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestQueryWrapperBool(t *testing.T) {
+	var w QueryWrapper
+	if err := json.Unmarshal([]byte(`{"bool": {"must": [{"match": {"field": "title", "query": "climate"}}]}}`), &w); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got := w.GetQueryType(); got != query.BoolType {
+		t.Errorf("GetQueryType() = %q, want %q", got, query.BoolType)
+	}
+
+	err := json.Unmarshal([]byte(`{"bool": {"must": []}, "match": {"field": "title", "query": "climate"}}`), &w)
+	if err == nil {
+		t.Error("unmarshal with bool and match succeeded, want error")
+	}
+}
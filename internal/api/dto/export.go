@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+)
+
+// ExportRequest streams every hit of a structured query. Query, filters, sort, rescoring
+// and normalization take the same form as in SearchRequest; hybrid queries cannot be exported.
+//
+// Example (CSV of every English article about climate, newest first):
+//
+//	{
+//	  "query": {"match": {"field": "content", "query": "climate"}},
+//	  "filters": {"language": ["english"]},
+//	  "sort": ["published_at:desc"],
+//	  "format": "csv"
+//	}
+type ExportRequest struct {
+	Query         QueryWrapper     `json:"query"`
+	Filters       *FilterParams    `json:"filters,omitempty"`
+	Sort          []string         `json:"sort,omitempty"`
+	Rescoring     *RescoringParams `json:"rescoring,omitempty"`
+	Normalization string           `json:"normalization,omitempty"`
+	// Format of the stream: ndjson (default) or csv
+	Format string `json:"format,omitempty"`
+	// Limit caps the number of exported hits; 0 exports the full match set
+	Limit int64 `json:"limit,omitempty" validate:"omitempty,min=1"`
+}
+
+// ExportFormat is the encoding of an export stream
+type ExportFormat string
+
+const (
+	// ExportNDJSON writes one ArticleSearchResult JSON object per line
+	ExportNDJSON ExportFormat = "ndjson"
+	// ExportCSV writes a header row and one ExportCSVHeader row per hit
+	ExportCSV ExportFormat = "csv"
+)
+
+// ExportFormatToDomain parses the format of an export request; empty is ndjson
+func ExportFormatToDomain(format string) (ExportFormat, error) {
+	switch f := ExportFormat(strings.ToLower(strings.TrimSpace(format))); f {
+	case "":
+		return ExportNDJSON, nil
+	case ExportNDJSON, ExportCSV:
+		return f, nil
+	default:
+		return "", apperr.NewValidation(fmt.Sprintf("invalid format %q, expected ndjson or csv", format))
+	}
+}
+
+// ContentType is the media type of the stream
+func (f ExportFormat) ContentType() string {
+	if f == ExportCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// ExportCSVHeader lists the columns of a CSV export. The article body is left out;
+// export ndjson for full articles.
+var ExportCSVHeader = []string{
+	"id", "title", "author", "url", "language", "source_name", "category",
+	"published_at", "created_at", "score", "score_normalized",
+}
+
+// CSVRecord lists the ExportCSVHeader columns of a hit; dates are RFC 3339, empty when unknown
+func (h ArticleSearchResult) CSVRecord() []string {
+	return []string{
+		h.ID.String(),
+		h.Title,
+		h.Author,
+		h.URL,
+		h.Language,
+		h.Metadata.SourceName,
+		h.Metadata.Category,
+		csvTime(h.Metadata.PublishedAt),
+		csvTime(h.CreatedAt),
+		strconv.FormatFloat(h.Score, 'f', -1, 64),
+		strconv.FormatFloat(h.ScoreNormalized, 'f', -1, 64),
+	}
+}
+
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+)
+
+func TestExportFormatToDomain(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ExportFormat
+		wantErr bool
+	}{
+		{in: "", want: ExportNDJSON},
+		{in: "ndjson", want: ExportNDJSON},
+		{in: " CSV ", want: ExportCSV},
+		{in: "xlsx", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ExportFormatToDomain(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ExportFormatToDomain(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExportFormatToDomain(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestArticleSearchResultCSVRecord(t *testing.T) {
+	id := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+	hit := ArticleSearchResult{
+		Article: Article{
+			ID:        id,
+			Title:     "Climate, policy and \"change\"",
+			URL:       "https://example.com/a",
+			Language:  "english",
+			CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			Metadata:  ArticleMetadata{SourceName: "BBC", Category: "science"},
+		},
+		Score:           1.25,
+		ScoreNormalized: 0.5,
+	}
+
+	want := []string{
+		id.String(), "Climate, policy and \"change\"", "", "https://example.com/a", "english", "BBC", "science",
+		"", "2024-03-01T12:00:00Z", "1.25", "0.5",
+	}
+	got := hit.CSVRecord()
+	if !slices.Equal(got, want) {
+		t.Errorf("CSVRecord() = %q, want %q", got, want)
+	}
+	if len(got) != len(ExportCSVHeader) {
+		t.Errorf("CSVRecord() has %d columns, header has %d", len(got), len(ExportCSVHeader))
+	}
+}
+
+func TestQueryWrapperToDomain(t *testing.T) {
+	var w QueryWrapper
+	if err := json.Unmarshal([]byte(`{"phrase": {"query": "climate change", "fields": ["title"], "slop": 1}}`), &w); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	base, err := w.ToDomain()
+	if err != nil {
+		t.Fatalf("ToDomain() error: %v", err)
+	}
+	if base.Kind != query.PhraseType || base.Phrase == nil || base.Phrase.GetSlop() != 1 {
+		t.Errorf("ToDomain() = %+v, want the phrase query", base)
+	}
+
+	if _, err := (&QueryWrapper{}).ToDomain(); err == nil {
+		t.Error("ToDomain() of an empty wrapper should fail")
+	}
+}
@@ -0,0 +1,166 @@
+package dto
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// FilterParams represents structured, non-scoring filters shared by every search endpoint
+// Example:
+//
+//	{
+//	  "published_at": {"gte": "now-7d"},
+//	  "source_id": ["bbc"],
+//	  "category": ["science"]
+//	}
+type FilterParams struct {
+	PublishedAt *DateRangeParams `json:"published_at,omitempty"`
+	CreatedAt   *DateRangeParams `json:"created_at,omitempty"`
+	Language    []string         `json:"language,omitempty"`
+	SourceID    []string         `json:"source_id,omitempty"`
+	SourceName  []string         `json:"source_name,omitempty"`
+	Category    []string         `json:"category,omitempty"`
+	Author      []string         `json:"author,omitempty"`
+}
+
+// DateRangeParams is an inclusive date range
+// Bounds accept RFC3339 ("2024-05-01T10:00:00Z"), a calendar date ("2024-05-01")
+// or relative date math ("now", "now-7d", "now-24h"; units: s, m, h, d, w, M, y).
+// A calendar date used as "lte" covers the whole day.
+type DateRangeParams struct {
+	Gte string `json:"gte,omitempty"`
+	Lte string `json:"lte,omitempty"`
+}
+
+// ToDomain converts the filter params into query.Filters, resolving relative dates against now
+func (p *FilterParams) ToDomain() (*query.Filters, error) {
+	if p == nil {
+		return nil, nil
+	}
+	now := time.Now().UTC()
+
+	published, err := p.PublishedAt.toDomain(now)
+	if err != nil {
+		return nil, apperr.NewValidationWrap("invalid published_at filter", err)
+	}
+	created, err := p.CreatedAt.toDomain(now)
+	if err != nil {
+		return nil, apperr.NewValidationWrap("invalid created_at filter", err)
+	}
+
+	f := &query.Filters{
+		PublishedAt: published,
+		CreatedAt:   created,
+		Terms:       make(map[query.FilterField][]string),
+	}
+	for field, values := range map[query.FilterField][]string{
+		query.FilterLanguage:   p.Language,
+		query.FilterSourceID:   p.SourceID,
+		query.FilterSourceName: p.SourceName,
+		query.FilterCategory:   p.Category,
+		query.FilterAuthor:     p.Author,
+	} {
+		if cleaned := cleanFilterValues(values); len(cleaned) > 0 {
+			f.Terms[field] = cleaned
+		}
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, apperr.NewValidationWrap("invalid filters", err)
+	}
+	if f.IsEmpty() {
+		return nil, nil
+	}
+	return f, nil
+}
+
+func (p *DateRangeParams) toDomain(now time.Time) (*query.DateRange, error) {
+	if p == nil || (p.Gte == "" && p.Lte == "") {
+		return nil, nil
+	}
+	r := &query.DateRange{}
+	if p.Gte != "" {
+		t, err := ParseDateBound(p.Gte, now, false)
+		if err != nil {
+			return nil, fmt.Errorf("gte: %w", err)
+		}
+		r.Gte = &t
+	}
+	if p.Lte != "" {
+		t, err := ParseDateBound(p.Lte, now, true)
+		if err != nil {
+			return nil, fmt.Errorf("lte: %w", err)
+		}
+		r.Lte = &t
+	}
+	return r, nil
+}
+
+var dateMathPattern = regexp.MustCompile(`^now(?:([+-])(\d+)([smhdwMy]))?$`)
+
+// ParseDateBound parses an absolute or relative (date math) range bound.
+// roundUp extends a bare calendar date to the end of that day, mirroring
+// Elasticsearch's rounding of "lte" bounds.
+func ParseDateBound(v string, now time.Time, roundUp bool) (time.Time, error) {
+	v = strings.TrimSpace(v)
+
+	if m := dateMathPattern.FindStringSubmatch(v); m != nil {
+		if m[1] == "" {
+			return now, nil
+		}
+		n, err := strconv.Atoi(m[2])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date math %q: %w", v, err)
+		}
+		if m[1] == "-" {
+			n = -n
+		}
+		switch m[3] {
+		case "s":
+			return now.Add(time.Duration(n) * time.Second), nil
+		case "m":
+			return now.Add(time.Duration(n) * time.Minute), nil
+		case "h":
+			return now.Add(time.Duration(n) * time.Hour), nil
+		case "d":
+			return now.AddDate(0, 0, n), nil
+		case "w":
+			return now.AddDate(0, 0, 7*n), nil
+		case "M":
+			return now.AddDate(0, n, 0), nil
+		default: // "y"
+			return now.AddDate(n, 0, 0), nil
+		}
+	}
+
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unsupported date %q (expected RFC3339, YYYY-MM-DD or now-<n><unit>)", v)
+	}
+	if roundUp {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+func cleanFilterValues(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
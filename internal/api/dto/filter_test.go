@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestParseDateBound(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		roundUp bool
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "now", value: "now", want: now},
+		{name: "now minus days", value: "now-7d", want: now.AddDate(0, 0, -7)},
+		{name: "now plus hours", value: "now+6h", want: now.Add(6 * time.Hour)},
+		{name: "now minus weeks", value: "now-2w", want: now.AddDate(0, 0, -14)},
+		{name: "now minus months", value: "now-1M", want: now.AddDate(0, -1, 0)},
+		{name: "now minus minutes", value: "now-30m", want: now.Add(-30 * time.Minute)},
+		{name: "rfc3339", value: "2024-05-01T10:00:00Z", want: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "calendar date as gte", value: "2024-05-01", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{
+			name:    "calendar date as lte covers the day",
+			value:   "2024-05-01",
+			roundUp: true,
+			want:    time.Date(2024, 5, 1, 23, 59, 59, 999999999, time.UTC),
+		},
+		{name: "unknown unit", value: "now-7q", wantErr: true},
+		{name: "garbage", value: "yesterday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateBound(tt.value, now, tt.roundUp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateBound() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("ParseDateBound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterParamsToDomain(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    *FilterParams
+		wantNil   bool
+		wantErr   bool
+		wantTerms map[query.FilterField][]string
+	}{
+		{name: "nil params", params: nil, wantNil: true},
+		{name: "empty params", params: &FilterParams{Category: []string{" "}}, wantNil: true},
+		{
+			name: "comma separated values are split",
+			params: &FilterParams{
+				Category: []string{"science, politics"},
+				SourceID: []string{"bbc"},
+			},
+			wantTerms: map[query.FilterField][]string{
+				query.FilterCategory: {"science", "politics"},
+				query.FilterSourceID: {"bbc"},
+			},
+		},
+		{
+			name:   "date range only",
+			params: &FilterParams{PublishedAt: &DateRangeParams{Gte: "now-7d"}},
+		},
+		{
+			name:    "invalid date",
+			params:  &FilterParams{PublishedAt: &DateRangeParams{Gte: "last week"}},
+			wantErr: true,
+		},
+		{
+			name:    "gte after lte",
+			params:  &FilterParams{CreatedAt: &DateRangeParams{Gte: "2024-05-02", Lte: "2024-05-01"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.ToDomain()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToDomain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("ToDomain() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("ToDomain() = nil, want filters")
+			}
+			for field, want := range tt.wantTerms {
+				if gotValues := got.Terms[field]; len(gotValues) != len(want) {
+					t.Errorf("Terms[%s] = %v, want %v", field, gotValues, want)
+				} else {
+					for i := range want {
+						if gotValues[i] != want[i] {
+							t.Errorf("Terms[%s] = %v, want %v", field, gotValues, want)
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// HighlightParams requests matched-term fragments per field on every hit
+// Example:
+//
+//	{
+//	  "fields": ["title", "content"],
+//	  "fragment_size": 150,
+//	  "number_of_fragments": 3,
+//	  "pre_tag": "<mark>",
+//	  "post_tag": "</mark>"
+//	}
+type HighlightParams struct {
+	Fields            []string `json:"fields,omitempty"`
+	FragmentSize      int      `json:"fragment_size,omitempty"`
+	NumberOfFragments int      `json:"number_of_fragments,omitempty"`
+	PreTag            string   `json:"pre_tag,omitempty"`
+	PostTag           string   `json:"post_tag,omitempty"`
+}
+
+// ToDomain converts highlight params into query.Highlight; nil params disable highlighting
+func (p *HighlightParams) ToDomain() (*query.Highlight, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	var opts []query.HighlightOption
+	if p.FragmentSize != 0 {
+		opts = append(opts, query.WithHighlightFragmentSize(p.FragmentSize))
+	}
+	if p.NumberOfFragments != 0 {
+		opts = append(opts, query.WithHighlightNumberOfFragments(p.NumberOfFragments))
+	}
+	if p.PreTag != "" || p.PostTag != "" {
+		pre, post := p.PreTag, p.PostTag
+		if pre == "" {
+			pre = query.DefaultHighlightPreTag
+		}
+		if post == "" {
+			post = query.DefaultHighlightPostTag
+		}
+		opts = append(opts, query.WithHighlightTags(pre, post))
+	}
+
+	h, err := query.NewHighlight(p.Fields, opts...)
+	if err != nil {
+		return nil, apperr.NewValidationWrap("invalid highlight", err)
+	}
+
+	return h, nil
+}
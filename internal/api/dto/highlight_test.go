@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestHighlightParamsToDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  *HighlightParams
+		wantNil bool
+		wantErr bool
+		check   func(t *testing.T, h *query.Highlight)
+	}{
+		{name: "nil disables highlighting", params: nil, wantNil: true},
+		{
+			name:   "defaults",
+			params: &HighlightParams{},
+			check: func(t *testing.T, h *query.Highlight) {
+				if len(h.Fields) != len(query.DefaultHighlightFields) {
+					t.Errorf("Fields = %v, want defaults", h.Fields)
+				}
+				if h.PreTag != "<em>" || h.PostTag != "</em>" {
+					t.Errorf("tags = %q %q, want <em> </em>", h.PreTag, h.PostTag)
+				}
+				if h.FragmentSize != query.DefaultHighlightFragmentSize {
+					t.Errorf("FragmentSize = %d, want %d", h.FragmentSize, query.DefaultHighlightFragmentSize)
+				}
+			},
+		},
+		{
+			name:   "custom pre tag keeps default post tag",
+			params: &HighlightParams{Fields: []string{"title"}, PreTag: "<b>"},
+			check: func(t *testing.T, h *query.Highlight) {
+				if h.PreTag != "<b>" || h.PostTag != "</em>" {
+					t.Errorf("tags = %q %q", h.PreTag, h.PostTag)
+				}
+			},
+		},
+		{name: "unsupported field", params: &HighlightParams{Fields: []string{"url"}}, wantErr: true},
+		{name: "fragment size too large", params: &HighlightParams{FragmentSize: 5000}, wantErr: true},
+		{name: "negative fragments", params: &HighlightParams{NumberOfFragments: -1}, wantErr: true},
+		{name: "quoted tag", params: &HighlightParams{PreTag: `<span class="hl">`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.ToDomain()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToDomain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("ToDomain() = %+v, want nil", got)
+				}
+				return
+			}
+			tt.check(t, got)
+		})
+	}
+}
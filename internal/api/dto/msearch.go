@@ -0,0 +1,24 @@
+package dto
+
+// MaxMultiSearches caps the number of searches in one _msearch request
+const MaxMultiSearches = 50
+
+// MultiSearchResponse lists the responses of a batch of searches, in request order
+type MultiSearchResponse struct {
+	// TookMs is the wall time of the whole batch in milliseconds
+	TookMs    int64                     `json:"took_ms"`
+	Responses []MultiSearchItemResponse `json:"responses"`
+}
+
+// MultiSearchItemResponse is the outcome of one search of a batch: the search response
+// with status 200, or the HTTP status and message of its error. A failing search does
+// not fail the batch.
+//
+// Example:
+//
+//	{"status": 400, "error": "size parameter exceeds maximum of 10000"}
+type MultiSearchItemResponse struct {
+	Status int `json:"status"`
+	*SearchResponse
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
+)
+
+// NormalizationToDomain parses the score normalization of a search request
+// Methods: max (default), min_max, z_score, rank, sigmoid
+func NormalizationToDomain(method string) (ranking.Method, error) {
+	m, err := ranking.ParseMethod(strings.TrimSpace(method))
+	if err != nil {
+		return "", apperr.NewValidationWrap("invalid normalization", err)
+	}
+	return m, nil
+}
@@ -46,21 +46,103 @@ import (
 //	    }
 //	  }
 //	}
+//
+// Example with filters (applied to every query type without affecting scoring):
+//
+//	{
+//	  "size": 10,
+//	  "query": {"match": {"field": "title", "query": "climate"}},
+//	  "filters": {
+//	    "published_at": {"gte": "now-30d"},
+//	    "language": ["english"],
+//	    "category": ["science"]
+//	  }
+//	}
+//
+// Example with aggregations (facet counts over the full filtered match set):
+//
+//	{
+//	  "query": {"match": {"field": "title", "query": "climate"}},
+//	  "aggregations": {
+//	    "categories": {"terms": {"field": "category", "size": 5}},
+//	    "per_month": {"date_histogram": {"field": "published_at", "interval": "month"}}
+//	  }
+//	}
+//
+// Example with sorting (newest first, best match first within the same instant):
+//
+//	{
+//	  "query": {"match": {"field": "title", "query": "election"}},
+//	  "filters": {"published_at": {"gte": "now-24h"}},
+//	  "sort": ["published_at:desc", "relevance"]
+//	}
+//
+// Example with rescoring (recent articles and trusted sources first, with the factors per hit):
+//
+//	{
+//	  "query": {"match": {"field": "title", "query": "election"}},
+//	  "rescoring": {
+//	    "recency": {"function": "gauss", "scale": "2d"},
+//	    "source_weights": {"Reuters": 1.5},
+//	    "breakdown": true
+//	  }
+//	}
+//
+// Example with score explanations (how every hit's score was computed):
+//
+//	{
+//	  "query": {"multi_match": {"query": "climate change", "fields": ["title", "content"]}},
+//	  "explain": true
+//	}
+//
+// Example with score normalization (score_normalized as the standard score mapped to 0-1):
+//
+//	{
+//	  "query": {"match": {"field": "title", "query": "climate"}},
+//	  "normalization": "z_score"
+//	}
+//
+// Example with highlighting (matched-term fragments per field on every hit):
+//
+//	{
+//	  "query": {"phrase": {"query": "climate change", "fields": ["title", "content"]}},
+//	  "highlight": {"fields": ["title", "content"], "fragment_size": 150, "number_of_fragments": 3}
+//	}
 type SearchRequest struct {
-	Size   int          `json:"size,omitempty" validate:"omitempty,min=1"`
-	Cursor string       `json:"cursor,omitempty"`
-	Query  QueryWrapper `json:"query"`
+	Size         int                          `json:"size,omitempty" validate:"omitempty,min=1"`
+	Cursor       string                       `json:"cursor,omitempty"`
+	Sort         []string                     `json:"sort,omitempty"`
+	Query        QueryWrapper                 `json:"query"`
+	Filters      *FilterParams                `json:"filters,omitempty"`
+	Aggregations map[string]AggregationParams `json:"aggregations,omitempty"`
+	Highlight    *HighlightParams             `json:"highlight,omitempty"`
+	Rescoring    *RescoringParams             `json:"rescoring,omitempty"`
+	Explain      bool                         `json:"explain,omitempty"`
+	// Normalization selects how score_normalized is computed: max (default), min_max, z_score, rank or sigmoid
+	Normalization string `json:"normalization,omitempty"`
 }
 
 // SearchResponse represents the API response for full-text search
 // This is a concrete type for Swagger documentation (swag doesn't support generics yet)
 type SearchResponse struct {
-	NextCursor   *string               `json:"next_cursor,omitempty"`
-	HasMore      bool                  `json:"has_more"`
-	MaxScore     float64               `json:"max_score,omitempty"`
-	PageMaxScore float64               `json:"page_max_score,omitempty"`
-	TotalMatches int64                 `json:"total_matches,omitempty"`
-	Hits         []ArticleSearchResult `json:"hits"`
+	NextCursor   *string `json:"next_cursor,omitempty"`
+	HasMore      bool    `json:"has_more"`
+	MaxScore     float64 `json:"max_score,omitempty"`
+	PageMaxScore float64 `json:"page_max_score,omitempty"`
+	TotalMatches int64   `json:"total_matches,omitempty"`
+	// TotalRelation is "gte" when total_matches is a lower bound (the backend stopped counting)
+	TotalRelation string                             `json:"total_relation,omitempty"`
+	Hits          []ArticleSearchResult              `json:"hits"`
+	Aggregations  map[string]query.AggregationResult `json:"aggregations,omitempty"`
+	// Expansions lists the indexed terms fuzzy query terms were expanded to (PostgreSQL)
+	Expansions []query.TermExpansion `json:"expansions,omitempty"`
+	// Suggest carries spelling corrections when the query matched few or no articles
+	Suggest *query.Suggest `json:"suggest,omitempty"`
+}
+
+// AutocompleteResponse lists distinct article titles completing a type-ahead prefix, best first
+type AutocompleteResponse struct {
+	Completions []query.Completion `json:"completions"`
 }
 
 // QueryWrapper wraps the actual query type
@@ -71,6 +153,7 @@ type QueryWrapper struct {
 	Phrase     *PhraseParams     `json:"phrase,omitempty"`
 	Boolean    *BooleanParams    `json:"boolean,omitempty"`
 	Hybrid     *HybridParams     `json:"hybrid,omitempty"`
+	Bool       *BoolParams       `json:"bool,omitempty"`
 }
 
 // MatchParams represents match query parameters (maps directly to types)
@@ -88,6 +171,7 @@ type MultiMatchParams struct {
 	Fields       []string           `json:"fields" validate:"required,min=1"`
 	FieldWeights map[string]float64 `json:"field_weights,omitempty"`
 	Operator     string             `json:"operator,omitempty"`
+	Fuzziness    string             `json:"fuzziness,omitempty"`
 	Language     string             `json:"language,omitempty"`
 }
 
@@ -103,7 +187,7 @@ type MultiMatchParams struct {
 type PhraseParams struct {
 	Query    string   `json:"query" validate:"required,min=1"`
 	Fields   []string `json:"fields" validate:"required,min=1"`
-	Slop     int      `json:"slop,omitempty" validate:"min=0,max=3"`
+	Slop     int      `json:"slop,omitempty" validate:"min=0,max=10"`
 	Language string   `json:"language,omitempty"`
 }
 
@@ -152,8 +236,12 @@ func (p *MatchParams) ToDomain() (*query.Match, error) {
 	}
 	opts = append(opts, query.WithMatchOperator(op))
 
-	if p.Fuzziness != "" {
-		opts = append(opts, query.WithMatchFuzziness(p.Fuzziness))
+	fuzziness, err := query.ParseFuzziness(p.Fuzziness)
+	if err != nil {
+		return nil, apperr.NewValidationWrap("invalid fuzziness", err)
+	}
+	if fuzziness != "" {
+		opts = append(opts, query.WithMatchFuzziness(string(fuzziness)))
 	}
 
 	if p.Language != "" {
@@ -176,6 +264,14 @@ func (p *MultiMatchParams) ToDomain() (*query.MultiMatch, error) {
 	}
 	opts = append(opts, query.WithMultiMatchOperator(op))
 
+	fuzziness, err := query.ParseFuzziness(p.Fuzziness)
+	if err != nil {
+		return nil, apperr.NewValidationWrap("invalid fuzziness", err)
+	}
+	if fuzziness != "" {
+		opts = append(opts, query.WithMultiMatchFuzziness(string(fuzziness)))
+	}
+
 	if p.Language != "" {
 		lang := query.Language(p.Language)
 		if !query.SupportedLanguages[lang] {
@@ -264,19 +360,50 @@ func (q *QueryWrapper) GetQueryType() query.Kind {
 	if q.Hybrid != nil {
 		return query.HybridType
 	}
+	if q.Bool != nil {
+		return query.BoolType
+	}
 	return ""
 }
 
+// ToDomain converts the wrapped query into a query.Base of its kind
+func (q *QueryWrapper) ToDomain() (*query.Base, error) {
+	base := &query.Base{Kind: q.GetQueryType()}
+	var err error
+	switch base.Kind {
+	case query.MatchType:
+		base.Match, err = q.Match.ToDomain()
+	case query.MultiMatchType:
+		base.MultiMatch, err = q.MultiMatch.ToDomain()
+	case query.PhraseType:
+		base.Phrase, err = q.Phrase.ToDomain()
+	case query.BooleanType:
+		base.Boolean, err = q.Boolean.ToDomain()
+	case query.HybridType:
+		base.Hybrid, err = q.Hybrid.ToDomain()
+	case query.BoolType:
+		base.Bool, err = q.Bool.ToDomain()
+	default:
+		return nil, apperr.NewValidation("query must specify one of: match, multi_match, phrase, boolean, hybrid, bool")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return base, nil
+}
+
 type SemanticSearchRequest struct {
 	Query  string `json:"query"`
 	Size   int    `json:"size,omitempty"`
 	Cursor string `json:"cursor,omitempty"`
 }
 
+// SemanticSearchResponse lists the nearest articles; score is the cosine similarity
+// of the article to the query on every backend
 type SemanticSearchResponse struct {
-	NextCursor *string   `json:"next_cursor,omitempty"`
-	HasMore    bool      `json:"has_more"`
-	Hits       []Article `json:"hits"`
+	NextCursor *string               `json:"next_cursor,omitempty"`
+	HasMore    bool                  `json:"has_more"`
+	Hits       []ArticleSearchResult `json:"hits"`
 }
 
 func (p *SemanticSearchRequest) ToDomain() (*query.Semantic, error) {
@@ -317,9 +444,12 @@ func (q *QueryWrapper) UnmarshalJSON(data []byte) error {
 	if q.Hybrid != nil {
 		count++
 	}
+	if q.Bool != nil {
+		count++
+	}
 
 	if count == 0 {
-		return apperr.NewValidation("query must specify one of: match, multi_match, phrase, boolean, hybrid")
+		return apperr.NewValidation("query must specify one of: match, multi_match, phrase, boolean, hybrid, bool")
 	}
 	if count > 1 {
 		return apperr.NewValidation("query must specify only one query type")
@@ -0,0 +1,149 @@
+package dto
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// RescoringParams multiplies relevance scores by recency, source and field value factors
+// Example:
+//
+//	{
+//	  "recency": {"function": "gauss", "scale": "7d", "offset": "1d", "decay": 0.5},
+//	  "source_weights": {"Reuters": 1.5, "Daily Gossip": 0.5},
+//	  "field_value_factors": [{"field": "language_confidence", "modifier": "sqrt", "missing": 1}],
+//	  "breakdown": true
+//	}
+type RescoringParams struct {
+	Recency           *RecencyParams           `json:"recency,omitempty"`
+	SourceWeights     map[string]float64       `json:"source_weights,omitempty"`
+	FieldValueFactors []FieldValueFactorParams `json:"field_value_factors,omitempty"`
+	// Breakdown returns the factors of every hit score in score_breakdown
+	Breakdown bool `json:"breakdown,omitempty"`
+}
+
+// RecencyParams decays scores with the distance of published_at from origin
+// Function: exp (default) or gauss. Scale and offset are durations (30m, 12h, 7d, 2w);
+// origin accepts the date formats of filters and defaults to now.
+type RecencyParams struct {
+	Function string  `json:"function,omitempty"`
+	Origin   string  `json:"origin,omitempty"`
+	Scale    string  `json:"scale"`
+	Offset   string  `json:"offset,omitempty"`
+	Decay    float64 `json:"decay,omitempty"`
+}
+
+// FieldValueFactorParams scores by modifier(factor × field value)
+// Fields: language_confidence. Modifiers: none (default), log1p, sqrt. Factor defaults to 1.
+type FieldValueFactorParams struct {
+	Field    string   `json:"field"`
+	Factor   *float64 `json:"factor,omitempty"`
+	Modifier string   `json:"modifier,omitempty"`
+	Missing  float64  `json:"missing,omitempty"`
+}
+
+// ToDomain converts rescoring params into query.Rescoring; nil params disable rescoring
+func (p *RescoringParams) ToDomain() (*query.Rescoring, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	r := &query.Rescoring{
+		SourceWeights: p.SourceWeights,
+		Breakdown:     p.Breakdown,
+	}
+
+	if p.Recency != nil {
+		recency, err := p.Recency.toDomain(time.Now().UTC())
+		if err != nil {
+			return nil, apperr.NewValidationWrap("invalid rescoring recency", err)
+		}
+		r.Recency = recency
+	}
+
+	for _, f := range p.FieldValueFactors {
+		factor := 1.0
+		if f.Factor != nil {
+			factor = *f.Factor
+		}
+		modifier := query.ModifierNone
+		if f.Modifier != "" {
+			modifier = query.FactorModifier(f.Modifier)
+		}
+		r.FieldValueFactors = append(r.FieldValueFactors, query.FieldValueFactor{
+			Field:    query.ScoreField(f.Field),
+			Factor:   factor,
+			Modifier: modifier,
+			Missing:  f.Missing,
+		})
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, apperr.NewValidationWrap("invalid rescoring", err)
+	}
+	return r, nil
+}
+
+func (p *RecencyParams) toDomain(now time.Time) (*query.RecencyDecay, error) {
+	d := &query.RecencyDecay{
+		Function: query.DecayExp,
+		Origin:   now,
+		Decay:    query.DefaultDecay,
+	}
+	if p.Function != "" {
+		d.Function = query.DecayFunction(p.Function)
+	}
+	if p.Decay != 0 {
+		d.Decay = p.Decay
+	}
+	if p.Origin != "" {
+		origin, err := ParseDateBound(p.Origin, now, false)
+		if err != nil {
+			return nil, fmt.Errorf("origin: %w", err)
+		}
+		d.Origin = origin
+	}
+
+	if p.Scale == "" {
+		return nil, fmt.Errorf("scale is required")
+	}
+	scale, err := ParseDuration(p.Scale)
+	if err != nil {
+		return nil, fmt.Errorf("scale: %w", err)
+	}
+	d.Scale = scale
+	if p.Offset != "" {
+		if d.Offset, err = ParseDuration(p.Offset); err != nil {
+			return nil, fmt.Errorf("offset: %w", err)
+		}
+	}
+	return d, nil
+}
+
+var durationPattern = regexp.MustCompile(`^(\d+)([smhdw])$`)
+
+var durationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// ParseDuration parses a whole number of seconds, minutes, hours, days or weeks (e.g. "7d")
+func ParseDuration(v string) (time.Duration, error) {
+	m := durationPattern.FindStringSubmatch(v)
+	if m == nil {
+		return 0, fmt.Errorf("invalid duration %q (expected a number and one of s, m, h, d, w)", v)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", v, err)
+	}
+	return time.Duration(n) * durationUnits[m[2]], nil
+}
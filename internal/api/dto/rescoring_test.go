@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestRescoringParamsToDomain(t *testing.T) {
+	half := 0.5
+
+	tests := []struct {
+		name    string
+		params  *RescoringParams
+		wantNil bool
+		wantErr bool
+		check   func(t *testing.T, r *query.Rescoring)
+	}{
+		{name: "nil disables rescoring", params: nil, wantNil: true},
+		{
+			name:   "recency defaults",
+			params: &RescoringParams{Recency: &RecencyParams{Scale: "7d"}},
+			check: func(t *testing.T, r *query.Rescoring) {
+				d := r.Recency
+				if d.Function != query.DecayExp || d.Decay != query.DefaultDecay || d.Scale != 7*24*time.Hour || d.Offset != 0 {
+					t.Errorf("Recency = %+v, want exp decay 0.5 with scale 7d", d)
+				}
+				if time.Since(d.Origin) > time.Minute {
+					t.Errorf("Origin = %v, want now", d.Origin)
+				}
+			},
+		},
+		{
+			name:   "field value factor defaults",
+			params: &RescoringParams{FieldValueFactors: []FieldValueFactorParams{{Field: "language_confidence"}}},
+			check: func(t *testing.T, r *query.Rescoring) {
+				f := r.FieldValueFactors[0]
+				if f.Factor != 1 || f.Modifier != query.ModifierNone {
+					t.Errorf("FieldValueFactor = %+v, want factor 1 without modifier", f)
+				}
+			},
+		},
+		{
+			name: "source weights and custom factor",
+			params: &RescoringParams{
+				SourceWeights:     map[string]float64{"Reuters": 1.5},
+				FieldValueFactors: []FieldValueFactorParams{{Field: "language_confidence", Factor: &half, Modifier: "log1p"}},
+			},
+			check: func(t *testing.T, r *query.Rescoring) {
+				if r.SourceWeight("Reuters") != 1.5 || r.SourceWeight("AP") != 1 {
+					t.Errorf("SourceWeights = %v", r.SourceWeights)
+				}
+				if f := r.FieldValueFactors[0]; f.Factor != 0.5 || f.Modifier != query.ModifierLog1p {
+					t.Errorf("FieldValueFactor = %+v, want factor 0.5 with log1p", f)
+				}
+			},
+		},
+		{name: "empty block", params: &RescoringParams{Breakdown: true}, wantErr: true},
+		{name: "missing scale", params: &RescoringParams{Recency: &RecencyParams{Function: "gauss"}}, wantErr: true},
+		{name: "invalid duration", params: &RescoringParams{Recency: &RecencyParams{Scale: "1 week"}}, wantErr: true},
+		{name: "unknown function", params: &RescoringParams{Recency: &RecencyParams{Function: "linear", Scale: "1d"}}, wantErr: true},
+		{name: "decay out of range", params: &RescoringParams{Recency: &RecencyParams{Scale: "1d", Decay: 1.5}}, wantErr: true},
+		{name: "non-positive source weight", params: &RescoringParams{SourceWeights: map[string]float64{"Reuters": 0}}, wantErr: true},
+		{name: "unsupported field", params: &RescoringParams{FieldValueFactors: []FieldValueFactorParams{{Field: "title"}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.params.ToDomain()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToDomain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("ToDomain() = %+v, want nil", got)
+				}
+				return
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"30s": 30 * time.Second,
+		"15m": 15 * time.Minute,
+		"12h": 12 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+	}
+	for input, want := range tests {
+		got, err := ParseDuration(input)
+		if err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "7", "1.5d", "-1d", "1y"} {
+		if _, err := ParseDuration(input); err == nil {
+			t.Errorf("ParseDuration(%q) should fail", input)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// SortToDomain converts sort keys into query.Sort. Keys take the form "field[:asc|desc]"
+// and may be comma-separated within one entry, as in the sort query parameter.
+// Fields: relevance (default), published_at, created_at, source_name
+// Example:
+//
+//	["published_at:desc", "relevance"]
+func SortToDomain(keys []string) (query.Sort, error) {
+	sort, err := query.NewSort(cleanFilterValues(keys)...)
+	if err != nil {
+		return nil, apperr.NewValidationWrap("invalid sort", err)
+	}
+	return sort, nil
+}
@@ -0,0 +1,213 @@
+package router
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultSimilarSize is the number of related articles returned when size is not set
+const defaultSimilarSize = 10
+
+type ArticleRouter struct {
+	e               *echo.Echo
+	reader          storage.Reader
+	similarSearcher storage.SimilarSearcher
+}
+
+type ArticleRouterOption func(*ArticleRouter)
+
+func NewArticleRouter(e *echo.Echo, reader storage.Reader, opts ...ArticleRouterOption) *ArticleRouter {
+	router := &ArticleRouter{
+		e:      e,
+		reader: reader,
+	}
+
+	for _, opt := range opts {
+		opt(router)
+	}
+
+	return router
+}
+
+func WithSimilarSearcher(searcher storage.SimilarSearcher) ArticleRouterOption {
+	return func(r *ArticleRouter) {
+		r.similarSearcher = searcher
+	}
+}
+
+func (r *ArticleRouter) Bind() {
+	// Static routes (/v1/articles/search, /v1/articles/_search, ...) take precedence over :id
+	r.e.GET("/v1/articles/:id", r.getHandler)
+	r.e.POST("/v1/articles/_mget", r.mgetHandler)
+
+	if r.similarSearcher != nil {
+		r.e.GET("/v1/articles/:id/similar", r.similarHandler)
+	}
+}
+
+// getHandler returns a single article by id (GET)
+//
+// @Summary Get article by ID
+// @Description Returns the stored article with the given ID.
+// @Tags articles
+// @Produce json
+// @Param id path string true "Article ID (UUID)" example("550e8400-e29b-41d4-a716-446655440000")
+// @Success 200 {object} dto.Article "Article"
+// @Failure 400 {object} map[string]string "Bad request - invalid article ID"
+// @Failure 404 {object} map[string]string "Article not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/articles/{id} [get]
+func (r *ArticleRouter) getHandler(c echo.Context) error {
+	id, err := parseArticleID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	articles, err := r.reader.GetByIDs(c.Request().Context(), []uuid.UUID{id})
+	if err != nil {
+		slog.Error("Failed to get article", "error", err, "id", id)
+		return err
+	}
+	if len(articles) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, storage.ErrArticleNotFound.Error())
+	}
+
+	return c.JSON(http.StatusOK, dto.ArticleFromDocument(articles[0]))
+}
+
+// mgetHandler returns several articles by id in one call (POST)
+//
+// @Summary Batch get articles by ID
+// @Description Returns the stored articles for up to 100 IDs, in request order. Unknown IDs are listed in not_found.
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param request body dto.MGetRequest true "Article IDs"
+// @Success 200 {object} dto.MGetResponse "Found articles and unknown IDs"
+// @Failure 400 {object} map[string]string "Bad request - invalid or too many IDs"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/articles/_mget [post]
+func (r *ArticleRouter) mgetHandler(c echo.Context) error {
+	var req dto.MGetRequest
+	if err := c.Bind(&req); err != nil {
+		slog.Error("Failed to bind mget request", "error", err)
+		return apperr.NewValidation("invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(req.IDs))
+	seen := make(map[uuid.UUID]bool, len(req.IDs))
+	for _, raw := range req.IDs {
+		id, err := parseArticleID(raw)
+		if err != nil {
+			return err
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	articles, err := r.reader.GetByIDs(c.Request().Context(), ids)
+	if err != nil {
+		slog.Error("Failed to get articles", "error", err, "count", len(ids))
+		return err
+	}
+
+	byID := make(map[uuid.UUID]dto.Article, len(articles))
+	for _, a := range articles {
+		byID[a.ID] = dto.ArticleFromDocument(a)
+	}
+
+	resp := dto.MGetResponse{Articles: make([]dto.Article, 0, len(ids))}
+	for _, id := range ids {
+		if a, ok := byID[id]; ok {
+			resp.Articles = append(resp.Articles, a)
+		} else {
+			resp.NotFound = append(resp.NotFound, id.String())
+		}
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// similarHandler returns articles related to the given article (GET)
+//
+// Uses kNN over the stored embedding when the article has one, otherwise falls back
+// to term overlap (ES more_like_this / PG tsvector lexemes).
+//
+// @Summary More like this
+// @Description Returns articles related to the given article. Uses the stored embedding (kNN) when available, otherwise lexical term overlap. Supports the same filter parameters as search.
+// @Tags articles
+// @Produce json
+// @Param id path string true "Article ID (UUID)"
+// @Param size query int false "Number of related articles (default: 10, max: 10000)" example(10)
+// @Param published_from query string false "Published at lower bound (RFC3339, YYYY-MM-DD or now-7d)"
+// @Param published_to query string false "Published at upper bound (RFC3339, YYYY-MM-DD or now)"
+// @Param language query string false "Filter by language (comma-separated)"
+// @Param source_id query string false "Filter by source ID (comma-separated)"
+// @Param category query string false "Filter by category (comma-separated)"
+// @Success 200 {object} dto.SimilarArticlesResponse "Related articles"
+// @Failure 400 {object} map[string]string "Bad request - invalid parameters"
+// @Failure 404 {object} map[string]string "Article not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/articles/{id}/similar [get]
+func (r *ArticleRouter) similarHandler(c echo.Context) error {
+	id, err := parseArticleID(c.Param("id"))
+	if err != nil {
+		return err
+	}
+
+	size := defaultSimilarSize
+	if sizeStr := c.QueryParam("size"); sizeStr != "" {
+		if size, err = parseSize(sizeStr); err != nil {
+			return err
+		}
+	}
+
+	filters, err := parseFilterParams(c).ToDomain()
+	if err != nil {
+		return err
+	}
+
+	result, err := r.similarSearcher.SearchSimilar(c.Request().Context(), id, &dquery.BaseOptions{
+		Size:    size,
+		Filters: filters,
+	})
+	if errors.Is(err, storage.ErrArticleNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		slog.Error("Failed to find similar articles", "error", err, "id", id)
+		return err
+	}
+
+	hits := result.Hits
+	if hits == nil {
+		hits = []dto.ArticleSearchResult{}
+	}
+
+	return c.JSON(http.StatusOK, dto.SimilarArticlesResponse{
+		Strategy: string(result.Strategy),
+		Hits:     hits,
+	})
+}
+
+func parseArticleID(raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, apperr.NewValidation("invalid article id: " + raw)
+	}
+	return id, nil
+}
@@ -0,0 +1,113 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	apiserver "github.com/DjordjeVuckovic/news-hunter/internal/api/server"
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+var knownArticleID = uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+
+type stubReader struct{}
+
+func (stubReader) GetByIDs(_ context.Context, ids []uuid.UUID) ([]document.Article, error) {
+	var out []document.Article
+	for _, id := range ids {
+		if id == knownArticleID {
+			out = append(out, document.Article{ID: id, Title: "Known article"})
+		}
+	}
+	return out, nil
+}
+
+type stubSimilarSearcher struct{}
+
+func (stubSimilarSearcher) SearchSimilar(_ context.Context, id uuid.UUID, _ *dquery.BaseOptions) (*storage.SimilarResult, error) {
+	if id != knownArticleID {
+		return nil, storage.ErrArticleNotFound
+	}
+	return &storage.SimilarResult{Strategy: storage.SimilarityLexical}, nil
+}
+
+func newArticleTestServer() *echo.Echo {
+	e := echo.New()
+	(&apiserver.Server{Echo: e}).SetupValidator()
+	e.HTTPErrorHandler = apperr.GlobalErrorHandler()
+	NewArticleRouter(e, stubReader{}, WithSimilarSearcher(stubSimilarSearcher{})).Bind()
+	return e
+}
+
+func TestArticleRouter(t *testing.T) {
+	unknown := uuid.MustParse("987fcdeb-51a2-43d7-b890-123456789abc")
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantCode int
+	}{
+		{name: "get known article", method: http.MethodGet, path: "/v1/articles/" + knownArticleID.String(), wantCode: http.StatusOK},
+		{name: "get unknown article", method: http.MethodGet, path: "/v1/articles/" + unknown.String(), wantCode: http.StatusNotFound},
+		{name: "get invalid id", method: http.MethodGet, path: "/v1/articles/not-a-uuid", wantCode: http.StatusBadRequest},
+		{
+			name:     "mget",
+			method:   http.MethodPost,
+			path:     "/v1/articles/_mget",
+			body:     `{"ids":["` + knownArticleID.String() + `","` + unknown.String() + `"]}`,
+			wantCode: http.StatusOK,
+		},
+		{name: "mget without ids", method: http.MethodPost, path: "/v1/articles/_mget", body: `{"ids":[]}`, wantCode: http.StatusBadRequest},
+		{name: "similar known article", method: http.MethodGet, path: "/v1/articles/" + knownArticleID.String() + "/similar?size=5", wantCode: http.StatusOK},
+		{name: "similar unknown article", method: http.MethodGet, path: "/v1/articles/" + unknown.String() + "/similar", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newArticleTestServer()
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestArticleRouterMGetOrderAndNotFound(t *testing.T) {
+	e := newArticleTestServer()
+	unknown := uuid.MustParse("987fcdeb-51a2-43d7-b890-123456789abc")
+
+	body := `{"ids":["` + unknown.String() + `","` + knownArticleID.String() + `"]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/articles/_mget", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var got dto.MGetResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if len(got.Articles) != 1 || got.Articles[0].ID != knownArticleID {
+		t.Errorf("articles = %+v, want only %s", got.Articles, knownArticleID)
+	}
+	if len(got.NotFound) != 1 || got.NotFound[0] != unknown.String() {
+		t.Errorf("not_found = %v, want [%s]", got.NotFound, unknown)
+	}
+}
@@ -0,0 +1,205 @@
+package router
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/labstack/echo/v4"
+)
+
+// exportFlushEvery is the number of hits written between two flushes of an export stream
+const exportFlushEvery = 100
+
+// errExportLimit stops an export once the requested number of hits has been written
+var errExportLimit = errors.New("export limit reached")
+
+// WithExporter enables the streaming _export endpoint
+func WithExporter(exporter storage.Exporter) SearchRouterOption {
+	return func(r *SearchRouter) {
+		r.exporter = exporter
+	}
+}
+
+// exportHandler streams every hit of a structured query (POST)
+//
+// Unlike _search, the export is not paged: the backend walks a consistent snapshot of the
+// match set (Elasticsearch point in time, PostgreSQL REPEATABLE READ cursor) in batches and
+// every hit is written as it arrives, so memory stays bounded by one batch. The stream stops
+// when the client disconnects.
+//
+// @Summary Export all hits of a structured query
+// @Description Streams every hit of a match, multi_match, phrase, boolean or bool query as NDJSON (one hit per line, default) or CSV, in the requested sort order. Hits come from one consistent snapshot, without the size limit of search pages. Hybrid queries, aggregations and highlighting are not supported.
+// @Tags search
+// @Accept json
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Param request body dto.ExportRequest true "Query to export"
+// @Success 200 {object} dto.ArticleSearchResult "One hit per NDJSON line, or one CSV row per hit"
+// @Failure 400 {object} map[string]string "Bad request - invalid query, format or options"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/articles/_export [post]
+func (r *SearchRouter) exportHandler(c echo.Context) error {
+	var req dto.ExportRequest
+	if err := c.Bind(&req); err != nil {
+		slog.Error("Failed to bind export request", "error", err)
+		return apperr.NewValidation("invalid request body")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	format, err := dto.ExportFormatToDomain(req.Format)
+	if err != nil {
+		return err
+	}
+
+	queryType := req.Query.GetQueryType()
+	if queryType == dquery.HybridType {
+		return apperr.NewValidation("hybrid queries cannot be exported")
+	}
+	domainQuery, err := req.Query.ToDomain()
+	if err != nil {
+		return err
+	}
+
+	sort, err := dto.SortToDomain(req.Sort)
+	if err != nil {
+		return err
+	}
+
+	filters, err := req.Filters.ToDomain()
+	if err != nil {
+		return err
+	}
+
+	rescoring, err := req.Rescoring.ToDomain()
+	if err != nil {
+		return err
+	}
+
+	normalization, err := r.parseNormalization(req.Normalization, queryType)
+	if err != nil {
+		return err
+	}
+
+	opts := &dquery.BaseOptions{
+		Size:          storage.ExportBatchSize,
+		Filters:       filters,
+		Sort:          sort,
+		Rescoring:     rescoring,
+		Normalization: normalization,
+	}
+
+	ctx := c.Request().Context()
+	w := newExportWriter(c.Response(), format)
+	err = r.exporter.Export(ctx, domainQuery, opts, func(hit dto.ArticleSearchResult) error {
+		if err := w.write(hit); err != nil {
+			return err
+		}
+		if req.Limit > 0 && w.written >= req.Limit {
+			return errExportLimit
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil, errors.Is(err, errExportLimit):
+		if err := w.close(); err != nil {
+			slog.Error("Failed to finish export", "error", err, "exported", w.written)
+		}
+		slog.Info("Export finished", "kind", queryType, "format", format, "exported", w.written)
+		return nil
+	case ctx.Err() != nil:
+		slog.Info("Export aborted by the client", "kind", queryType, "exported", w.written)
+		return nil
+	case c.Response().Committed:
+		// The status is sent: the truncated stream is all the client gets
+		slog.Error("Export failed mid-stream", "error", err, "kind", queryType, "exported", w.written)
+		return nil
+	default:
+		slog.Error("Failed to execute export", "error", err, "kind", queryType)
+		return err
+	}
+}
+
+// exportWriter encodes hits onto the response. The status and headers are sent with the
+// first hit, so a backend failing before any hit still gets a JSON error response.
+type exportWriter struct {
+	res     *echo.Response
+	format  dto.ExportFormat
+	csv     *csv.Writer
+	json    *json.Encoder
+	written int64
+}
+
+func newExportWriter(res *echo.Response, format dto.ExportFormat) *exportWriter {
+	w := &exportWriter{res: res, format: format}
+	if format == dto.ExportCSV {
+		w.csv = csv.NewWriter(res)
+	} else {
+		w.json = json.NewEncoder(res)
+	}
+	return w
+}
+
+func (w *exportWriter) write(hit dto.ArticleSearchResult) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	if w.csv != nil {
+		if err := w.csv.Write(hit.CSVRecord()); err != nil {
+			return err
+		}
+	} else if err := w.json.Encode(hit); err != nil {
+		return err
+	}
+
+	w.written++
+	if w.written%exportFlushEvery == 0 {
+		return w.flush()
+	}
+	return nil
+}
+
+// close sends the headers of an empty export and flushes the buffered hits
+func (w *exportWriter) close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	return w.flush()
+}
+
+func (w *exportWriter) start() error {
+	if w.res.Committed {
+		return nil
+	}
+	w.res.Header().Set(echo.HeaderContentType, w.format.ContentType())
+	w.res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"articles.%s\"", w.format))
+	w.res.WriteHeader(http.StatusOK)
+
+	if w.csv != nil {
+		return w.csv.Write(dto.ExportCSVHeader)
+	}
+	return nil
+}
+
+func (w *exportWriter) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	w.res.Flush()
+	return nil
+}
@@ -0,0 +1,153 @@
+package router
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	apiserver "github.com/DjordjeVuckovic/news-hunter/internal/api/server"
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// stubExporter emits hits with descending scores, or fails before the first hit
+type stubExporter struct {
+	hits int
+	err  error
+}
+
+func (s stubExporter) Export(_ context.Context, _ *dquery.Base, _ *dquery.BaseOptions, fn storage.ExportFunc) error {
+	if s.err != nil {
+		return s.err
+	}
+	for i := range s.hits {
+		hit := dto.ArticleSearchResult{
+			Article: dto.Article{ID: uuid.New(), Title: "article"},
+			Score:   float64(s.hits - i),
+		}
+		if err := fn(hit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func serveExport(t *testing.T, exporter storage.Exporter, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	e := echo.New()
+	(&apiserver.Server{Echo: e}).SetupValidator()
+	e.HTTPErrorHandler = apperr.GlobalErrorHandler()
+
+	r := &SearchRouter{e: e, searcher: stubFtsSearcher{}, exporter: exporter}
+	r.Bind()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/articles/_export", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestExportHandlerNDJSON(t *testing.T) {
+	rec := serveExport(t, stubExporter{hits: 250}, `{"query":{"match":{"field":"title","query":"climate"}}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body: %s)", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get(echo.HeaderContentType); ct != "application/x-ndjson" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var hit dto.ArticleSearchResult
+		if err := json.Unmarshal(scanner.Bytes(), &hit); err != nil {
+			t.Fatalf("line %d: %v", lines+1, err)
+		}
+		lines++
+	}
+	if lines != 250 {
+		t.Errorf("exported %d lines, want 250", lines)
+	}
+}
+
+func TestExportHandlerCSVWithLimit(t *testing.T) {
+	rec := serveExport(t, stubExporter{hits: 50}, `{"query":{"phrase":{"query":"climate change","fields":["title"]}},"format":"csv","limit":10}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body: %s)", rec.Code, rec.Body.String())
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(records) != 11 {
+		t.Fatalf("got %d records, want the header and 10 hits", len(records))
+	}
+	if records[0][0] != "id" || records[1][len(records[1])-2] != "50" {
+		t.Errorf("unexpected records: %q, %q", records[0], records[1])
+	}
+}
+
+func TestExportHandlerEmpty(t *testing.T) {
+	rec := serveExport(t, stubExporter{}, `{"query":{"match":{"field":"title","query":"climate"}},"format":"csv"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got := strings.TrimSpace(rec.Body.String()); got != strings.Join(dto.ExportCSVHeader, ",") {
+		t.Errorf("body = %q, want the header only", got)
+	}
+}
+
+func TestExportHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter stubExporter
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "hybrid query",
+			body:     `{"query":{"hybrid":{"query":"climate"}}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid format",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"format":"xlsx"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid sort",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"sort":["title"]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "backend failure before the first hit",
+			exporter: stubExporter{err: errors.New("connection refused")},
+			body:     `{"query":{"match":{"field":"title","query":"climate"}}}`,
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveExport(t, tt.exporter, tt.body)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if ct := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(ct, echo.MIMEApplicationJSON) {
+				t.Errorf("Content-Type = %q, want a JSON error", ct)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// DefaultMultiSearchWorkers is the number of searches of a batch run concurrently
+	DefaultMultiSearchWorkers = 8
+	// DefaultMultiSearchBudget is the time a whole batch may take
+	DefaultMultiSearchBudget = 10 * time.Second
+)
+
+// WithMultiSearcher sends the full-text searches of a _msearch batch to the backend in one
+// request; hybrid searches still run on the worker pool
+func WithMultiSearcher(multiSearcher storage.MultiSearcher) SearchRouterOption {
+	return func(r *SearchRouter) {
+		r.multiSearcher = multiSearcher
+	}
+}
+
+// WithMultiSearchLimits bounds a _msearch batch: workers searches run concurrently and the
+// whole batch must finish within budget. Zero values keep the defaults.
+func WithMultiSearchLimits(workers int, budget time.Duration) SearchRouterOption {
+	return func(r *SearchRouter) {
+		r.msearchWorkers = workers
+		r.msearchBudget = budget
+	}
+}
+
+// multiSearchHandler runs a batch of structured searches (POST)
+//
+// Every item is a structured search request of any kind, hybrid included, and gets its own
+// response or error: a failing search does not fail the batch. Searches run concurrently
+// on a bounded worker pool; when the backend supports it (Elasticsearch), the full-text
+// searches are sent in one _msearch request instead. Searches still running when the time
+// budget of the batch runs out fail with 504.
+//
+// @Summary Run a batch of structured searches
+// @Description Executes up to 50 structured search requests (match, multi_match, phrase, boolean, bool, hybrid) concurrently within a time budget. Responses are returned in request order; each carries its HTTP status and either the search response or an error message.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param request body []dto.SearchRequest true "Structured search requests"
+// @Success 200 {object} dto.MultiSearchResponse "Responses in request order"
+// @Failure 400 {object} map[string]string "Bad request - not an array, empty or too many searches"
+// @Router /v1/articles/_msearch [post]
+// @Example Request:
+//
+//	[
+//	  {"size": 5, "query": {"match": {"field": "title", "query": "climate"}}},
+//	  {"size": 5, "query": {"hybrid": {"query": "renewable energy policy"}}},
+//	  {"query": {"boolean": {"expression": "election AND NOT poll"}}, "aggregations": {"sources": {"terms": {"field": "source_name"}}}}
+//	]
+func (r *SearchRouter) multiSearchHandler(c echo.Context) error {
+	var reqs []dto.SearchRequest
+	if err := c.Bind(&reqs); err != nil {
+		slog.Error("Failed to bind multi search request", "error", err)
+		return apperr.NewValidation("invalid request body: expected an array of search requests")
+	}
+	if len(reqs) == 0 {
+		return apperr.NewValidation("at least one search is required")
+	}
+	if len(reqs) > dto.MaxMultiSearches {
+		return apperr.NewValidation(fmt.Sprintf("number of searches exceeds maximum of %d", dto.MaxMultiSearches))
+	}
+
+	start := time.Now()
+	budget := r.multiSearchBudget()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), budget)
+	defer cancel()
+
+	responses := make([]dto.MultiSearchItemResponse, len(reqs))
+	var pending, batched []int
+	var items []storage.MultiSearchItem
+	for i := range reqs {
+		if err := c.Validate(&reqs[i]); err != nil {
+			responses[i] = r.multiSearchError(ctx, err)
+			continue
+		}
+
+		kind := reqs[i].Query.GetQueryType()
+		if r.multiSearcher == nil || kind == dquery.HybridType {
+			pending = append(pending, i)
+			continue
+		}
+
+		item, err := r.multiSearchItem(&reqs[i])
+		if err != nil {
+			responses[i] = r.multiSearchError(ctx, err)
+			continue
+		}
+		batched = append(batched, i)
+		items = append(items, item)
+	}
+
+	var wg sync.WaitGroup
+	if len(items) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.runBatched(ctx, reqs, batched, items, responses)
+		}()
+	}
+
+	jobs := make(chan int)
+	for range min(r.multiSearchWorkers(), len(pending)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				responses[i] = r.runSearch(ctx, &reqs[i])
+			}
+		}()
+	}
+	for _, i := range pending {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	took := time.Since(start)
+	slog.Info("Multi search finished",
+		"searches", len(reqs),
+		"batched", len(batched),
+		"took_ms", took.Milliseconds(),
+		"budget", budget)
+
+	return c.JSON(http.StatusOK, dto.MultiSearchResponse{TookMs: took.Milliseconds(), Responses: responses})
+}
+
+// multiSearchItem validates a full-text search request for the backend batch
+func (r *SearchRouter) multiSearchItem(req *dto.SearchRequest) (storage.MultiSearchItem, error) {
+	opts, err := r.searchOptions(req)
+	if err != nil {
+		return storage.MultiSearchItem{}, err
+	}
+	q, err := req.Query.ToDomain()
+	if err != nil {
+		return storage.MultiSearchItem{}, err
+	}
+	return storage.MultiSearchItem{Query: q, Options: opts}, nil
+}
+
+// runBatched sends the batched searches to the backend in one request
+func (r *SearchRouter) runBatched(ctx context.Context, reqs []dto.SearchRequest, batched []int, items []storage.MultiSearchItem, responses []dto.MultiSearchItemResponse) {
+	results, err := r.multiSearcher.MultiSearch(ctx, items)
+	if err != nil {
+		slog.Error("Failed to execute multi search", "error", err, "searches", len(items))
+	}
+
+	for n, i := range batched {
+		result := storage.MultiSearchResult{Err: err}
+		if err == nil {
+			result = results[n]
+		}
+		if result.Err != nil {
+			responses[i] = r.multiSearchError(ctx, result.Err)
+			continue
+		}
+
+		resp, err := r.toResponse(ctx, result.Result, suggestText(reqs[i].Query))
+		if err != nil {
+			responses[i] = r.multiSearchError(ctx, err)
+			continue
+		}
+		responses[i] = dto.MultiSearchItemResponse{Status: http.StatusOK, SearchResponse: resp}
+	}
+}
+
+// runSearch runs one search of a batch on its searcher; searches whose turn comes after
+// the budget ran out are not started
+func (r *SearchRouter) runSearch(ctx context.Context, req *dto.SearchRequest) dto.MultiSearchItemResponse {
+	if err := ctx.Err(); err != nil {
+		return r.multiSearchError(ctx, err)
+	}
+
+	resp, err := r.search(ctx, req)
+	if err != nil {
+		return r.multiSearchError(ctx, err)
+	}
+	return dto.MultiSearchItemResponse{Status: http.StatusOK, SearchResponse: resp}
+}
+
+// multiSearchError reports the error of one search of a batch with the status the search
+// would have been answered with on its own; searches cut by the budget fail with 504
+func (r *SearchRouter) multiSearchError(ctx context.Context, err error) dto.MultiSearchItemResponse {
+	code, msg := apperr.StatusOf(err)
+	timedOut := errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded)
+	if code == http.StatusInternalServerError && timedOut {
+		code, msg = http.StatusGatewayTimeout, fmt.Sprintf("search exceeded the time budget of the batch (%s)", r.multiSearchBudget())
+	}
+	return dto.MultiSearchItemResponse{Status: code, Error: msg}
+}
+
+func (r *SearchRouter) multiSearchWorkers() int {
+	if r.msearchWorkers > 0 {
+		return r.msearchWorkers
+	}
+	return DefaultMultiSearchWorkers
+}
+
+func (r *SearchRouter) multiSearchBudget() time.Duration {
+	if r.msearchBudget > 0 {
+		return r.msearchBudget
+	}
+	return DefaultMultiSearchBudget
+}
@@ -0,0 +1,167 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	apiserver "github.com/DjordjeVuckovic/news-hunter/internal/api/server"
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/labstack/echo/v4"
+)
+
+// slowFtsSearcher blocks match searches until their context is done
+type slowFtsSearcher struct {
+	stubFtsSearcher
+}
+
+func (slowFtsSearcher) SearchField(ctx context.Context, _ *dquery.Match, _ *dquery.BaseOptions) (*storage.SearchResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// stubMultiSearcher fails boolean searches, answers the others with one match and counts its calls
+type stubMultiSearcher struct {
+	calls atomic.Int32
+}
+
+func (s *stubMultiSearcher) MultiSearch(_ context.Context, items []storage.MultiSearchItem) ([]storage.MultiSearchResult, error) {
+	s.calls.Add(1)
+	results := make([]storage.MultiSearchResult, len(items))
+	for i, item := range items {
+		if item.Query.Kind == dquery.BooleanType {
+			results[i].Err = errors.New("search_phase_execution_exception")
+			continue
+		}
+		results[i].Result = &storage.SearchResult{TotalMatches: 1}
+	}
+	return results, nil
+}
+
+func serveMultiSearch(t *testing.T, r *SearchRouter, body string) (*httptest.ResponseRecorder, dto.MultiSearchResponse) {
+	t.Helper()
+	e := echo.New()
+	(&apiserver.Server{Echo: e}).SetupValidator()
+	e.HTTPErrorHandler = apperr.GlobalErrorHandler()
+	r.e = e
+	r.Bind()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/articles/_msearch", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	var resp dto.MultiSearchResponse
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+	}
+	return rec, resp
+}
+
+func itemStatuses(resp dto.MultiSearchResponse) []int {
+	statuses := make([]int, len(resp.Responses))
+	for i, item := range resp.Responses {
+		statuses[i] = item.Status
+	}
+	return statuses
+}
+
+func TestMultiSearchHandler(t *testing.T) {
+	body := `[
+		{"query":{"match":{"field":"title","query":"climate"}}},
+		{"query":{"match":{"field":"title","query":"climate"}},"size":100000},
+		{"query":{"hybrid":{"query":"climate"}}},
+		{"query":{"phrase":{"query":"climate change","fields":["title"]}}}
+	]`
+
+	rec, resp := serveMultiSearch(t, &SearchRouter{searcher: stubFtsSearcher{}}, body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body: %s)", rec.Code, rec.Body.String())
+	}
+
+	want := []int{http.StatusOK, http.StatusBadRequest, http.StatusBadRequest, http.StatusOK}
+	if got := itemStatuses(resp); !slices.Equal(got, want) {
+		t.Fatalf("statuses = %v, want %v", got, want)
+	}
+	if resp.Responses[0].SearchResponse == nil || resp.Responses[0].Hits == nil {
+		t.Errorf("first response = %+v, want a search response", resp.Responses[0])
+	}
+	if resp.Responses[2].Error != "hybrid search is not enabled on this server" {
+		t.Errorf("hybrid error = %q", resp.Responses[2].Error)
+	}
+}
+
+func TestMultiSearchHandlerBatchesFullTextSearches(t *testing.T) {
+	multiSearcher := &stubMultiSearcher{}
+	body := `[
+		{"query":{"match":{"field":"title","query":"climate"}}},
+		{"query":{"boolean":{"expression":"climate AND policy"}}},
+		{"query":{"hybrid":{"query":"climate"}}},
+		{"query":{"match":{"field":"title","query":"climate"}},"sort":["title"]}
+	]`
+
+	_, resp := serveMultiSearch(t, &SearchRouter{searcher: stubFtsSearcher{}, multiSearcher: multiSearcher}, body)
+
+	want := []int{http.StatusOK, http.StatusInternalServerError, http.StatusBadRequest, http.StatusBadRequest}
+	if got := itemStatuses(resp); !slices.Equal(got, want) {
+		t.Fatalf("statuses = %v, want %v", got, want)
+	}
+	if n := multiSearcher.calls.Load(); n != 1 {
+		t.Errorf("MultiSearch called %d times, want one batch", n)
+	}
+	if resp.Responses[1].Error != "internal server error" {
+		t.Errorf("backend error exposed: %q", resp.Responses[1].Error)
+	}
+}
+
+func TestMultiSearchHandlerTimeBudget(t *testing.T) {
+	r := &SearchRouter{searcher: slowFtsSearcher{}, msearchWorkers: 1, msearchBudget: 50 * time.Millisecond}
+	body := `[
+		{"query":{"match":{"field":"title","query":"climate"}}},
+		{"query":{"match":{"field":"title","query":"climate"}}},
+		{"query":{"phrase":{"query":"climate change","fields":["title"]}}}
+	]`
+
+	start := time.Now()
+	_, resp := serveMultiSearch(t, r, body)
+	if took := time.Since(start); took > time.Second {
+		t.Fatalf("batch took %v, want the budget to stop it", took)
+	}
+
+	want := []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusGatewayTimeout}
+	if got := itemStatuses(resp); !slices.Equal(got, want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+}
+
+func TestMultiSearchHandlerValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "not an array", body: `{"query":{"match":{"field":"title","query":"climate"}}}`},
+		{name: "empty", body: `[]`},
+		{name: "too many searches", body: "[" + strings.Repeat(`{"query":{"match":{"field":"title","query":"a"}}},`, dto.MaxMultiSearches) + `{}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, _ := serveMultiSearch(t, &SearchRouter{searcher: stubFtsSearcher{}}, tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400 (body: %s)", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -1,16 +1,20 @@
 package router
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
 	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
 	"github.com/DjordjeVuckovic/news-hunter/pkg/pagination"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -19,6 +23,14 @@ type SearchRouter struct {
 	searcher         storage.FtsSearcher
 	semanticSearcher storage.SemanticSearcher
 	hybridSearcher   storage.HybridSearcher
+	suggester        storage.Suggester
+	autocompleter    storage.Autocompleter
+	reader           storage.Reader
+	exporter         storage.Exporter
+	multiSearcher    storage.MultiSearcher
+	msearchWorkers   int
+	msearchBudget    time.Duration
+	calibrations     ranking.Calibrations
 }
 
 type SearchRouterOption func(*SearchRouter)
@@ -48,6 +60,35 @@ func WithHybridSearcher(searcher storage.HybridSearcher) SearchRouterOption {
 	}
 }
 
+// WithSuggester enables "did you mean" suggestions on search responses and the _suggest endpoint
+func WithSuggester(suggester storage.Suggester) SearchRouterOption {
+	return func(r *SearchRouter) {
+		r.suggester = suggester
+	}
+}
+
+// WithAutocompleter enables the title type-ahead endpoint
+func WithAutocompleter(autocompleter storage.Autocompleter) SearchRouterOption {
+	return func(r *SearchRouter) {
+		r.autocompleter = autocompleter
+	}
+}
+
+// WithArticleReader lets the _explain endpoint tell unknown articles (404) from articles
+// the query does not match
+func WithArticleReader(reader storage.Reader) SearchRouterOption {
+	return func(r *SearchRouter) {
+		r.reader = reader
+	}
+}
+
+// WithCalibrations enables sigmoid score normalization with sigmoids fitted per query kind
+func WithCalibrations(calibrations ranking.Calibrations) SearchRouterOption {
+	return func(r *SearchRouter) {
+		r.calibrations = calibrations
+	}
+}
+
 func (r *SearchRouter) Bind() {
 	// Simple query_string API (application-determined fields/weights)
 	r.e.GET("/v1/articles/search", r.searchHandler)
@@ -55,11 +96,32 @@ func (r *SearchRouter) Bind() {
 	// Unified structured search API (match/multi_match with query wrapper)
 	r.e.POST("/v1/articles/_search", r.structuredSearchHandler)
 
+	// Batch of structured searches run concurrently within a time budget
+	r.e.POST("/v1/articles/_msearch", r.multiSearchHandler)
+
+	// Score explanation of one article for a structured query
+	r.e.POST("/v1/articles/:id/_explain", r.explainHandler)
+
+	// Streaming export of all hits (only if provided via options)
+	if r.exporter != nil {
+		r.e.POST("/v1/articles/_export", r.exportHandler)
+	}
+
 	// Semantic search endpoint (only if provided via options)
 	if r.semanticSearcher != nil {
 		r.e.GET("/v1/articles/semantic_search", r.handleSematicQuery)
 	}
 
+	// Spelling suggestions endpoint (only if provided via options)
+	if r.suggester != nil {
+		r.e.GET("/v1/articles/_suggest", r.suggestHandler)
+	}
+
+	// Title autocomplete endpoint (only if provided via options)
+	if r.autocompleter != nil {
+		r.e.GET("/v1/articles/_autocomplete", r.autocompleteHandler)
+	}
+
 	// Capabilities discovery endpoint
 	r.e.GET("/v1/capabilities", r.capabilitiesHandler)
 }
@@ -67,7 +129,7 @@ func (r *SearchRouter) Bind() {
 // capabilitiesHandler reports which search paradigms the running backend supports (GET)
 //
 // The full-text searcher is always wired, so string_query, match, multi_match,
-// phrase and boolean are always available. Semantic search is reported as
+// phrase, boolean and bool are always available. Semantic search is reported as
 // available only when a semantic searcher has been wired into the router.
 //
 // @Summary Report supported search paradigms
@@ -83,6 +145,7 @@ func (r *SearchRouter) capabilitiesHandler(c echo.Context) error {
 		MultiMatch:  true,
 		Phrase:      true,
 		Boolean:     true,
+		Bool:        true,
 		Semantic:    r.semanticSearcher != nil,
 	}
 
@@ -100,12 +163,23 @@ func (r *SearchRouter) capabilitiesHandler(c echo.Context) error {
 // @Tags search
 // @Accept json
 // @Produce json
-// @Param q query string true "SearchStringQuery query text" example("climate change")
+// @Param q query string true "Query text in query_string syntax: field scopes, quoted phrases, prefix*, boost^2, +required, -prohibited, (groups)" example(climate +energ* -coal)
 // @Param size query int false "Results per page (default: 100, max: 10000)" example(10)
 // @Param cursor query string false "Pagination cursor (base64-encoded from previous response)"
+// @Param sort query string false "Sort keys field[:asc|desc], comma-separated; fields: relevance (default), published_at, created_at, source_name" example(published_at:desc,relevance)
+// @Param normalization query string false "Score normalization of score_normalized: max (default), min_max, z_score, rank, sigmoid" example(min_max)
 // @Param lang query string false "SearchStringQuery language: english, serbian (default: english)" example("english")
+// @Param published_from query string false "Published at lower bound (RFC3339, YYYY-MM-DD or now-7d)" example("now-7d")
+// @Param published_to query string false "Published at upper bound (RFC3339, YYYY-MM-DD or now)"
+// @Param created_from query string false "Created at lower bound (RFC3339, YYYY-MM-DD or now-7d)"
+// @Param created_to query string false "Created at upper bound (RFC3339, YYYY-MM-DD or now)"
+// @Param language query string false "Filter by language (comma-separated)" example("english")
+// @Param source_id query string false "Filter by source ID (comma-separated)"
+// @Param source_name query string false "Filter by source name (comma-separated)"
+// @Param category query string false "Filter by category (comma-separated)" example("science,politics")
+// @Param author query string false "Filter by author (comma-separated)"
 // @Success 200 {object} dto.SearchResponse "SearchStringQuery results with pagination metadata"
-// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters, or invalid query syntax (with character position)"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /v1/articles/search [get]
 // @Example Request:  GET /v1/articles/search?q=climate%20change&size=10&lang=english
@@ -123,30 +197,50 @@ func (r *SearchRouter) searchHandler(c echo.Context) error {
 		return apperr.NewValidation("q parameter is required")
 	}
 
-	sizeInt, err := r.parseSize(sizeStr)
+	sizeInt, err := parseSize(sizeStr)
 	if err != nil {
 		return err
 	}
 
-	var cursor *dquery.Cursor
-	if cursorStr != "" {
-		cursor, err = dquery.DecodeCursor(cursorStr)
-		if err != nil {
-			return apperr.NewValidation("invalid cursor parameter")
-		}
+	sort, err := dto.SortToDomain(c.QueryParams()["sort"])
+	if err != nil {
+		return err
+	}
+
+	cursor, err := decodeCursor(cursorStr, sort)
+	if err != nil {
+		return err
+	}
+
+	filters, err := parseFilterParams(c).ToDomain()
+	if err != nil {
+		return err
+	}
+
+	normalization, err := r.parseNormalization(c.QueryParam("normalization"), dquery.StringType)
+	if err != nil {
+		return err
 	}
 
 	queryString := dquery.NewQueryString(query)
 	searchResult, err := r.searcher.SearchStringQuery(c.Request().Context(), queryString, &dquery.BaseOptions{
-		Cursor: cursor,
-		Size:   sizeInt,
+		Cursor:        cursor,
+		Size:          sizeInt,
+		Filters:       filters,
+		Sort:          sort,
+		Normalization: normalization,
 	})
 	if err != nil {
 		slog.Error("Failed to execute full-text search", "error", err, "query", query)
 		return err
 	}
 
-	return r.buildResponse(c, searchResult)
+	resp, err := r.toResponse(c.Request().Context(), searchResult, query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
 }
 
 // structuredSearchHandler handles structured search requests (POST)
@@ -159,10 +253,10 @@ func (r *SearchRouter) searchHandler(c echo.Context) error {
 // - Fuzziness/typo tolerance
 //
 // Supports multiple query types via the query wrapper pattern.
-// Query types: match, multi_match (more coming: bool, phrase, query_string)
+// Query types: match, multi_match, phrase, boolean, hybrid, bool
 //
 // @Summary Structured search API
-// @Description Execute structured search queries with explicit control over fields, weights, and operators. Supports match and multi_match query types. Follows Elasticsearch query DSL pattern.
+// @Description Execute structured search queries with explicit control over fields, weights, and operators. Supports match, multi_match, phrase, boolean, hybrid and compound bool query types. Follows Elasticsearch query DSL pattern.
 // @Tags search
 // @Accept json
 // @Produce json
@@ -205,6 +299,20 @@ func (r *SearchRouter) searchHandler(c echo.Context) error {
 //	    }
 //	  }
 //	}
+//
+// @Example Bool Request:
+//
+//	{
+//	  "size": 10,
+//	  "query": {
+//	    "bool": {
+//	      "must": [{"phrase": {"query": "climate change", "fields": ["title"]}}],
+//	      "should": [{"match": {"field": "content", "query": "emissions"}}],
+//	      "must_not": [{"match": {"field": "author", "query": "smith"}}]
+//	    }
+//	  },
+//	  "filters": {"category": ["science"]}
+//	}
 func (r *SearchRouter) structuredSearchHandler(c echo.Context) error {
 	var req dto.SearchRequest
 	if err := c.Bind(&req); err != nil {
@@ -216,122 +324,262 @@ func (r *SearchRouter) structuredSearchHandler(c echo.Context) error {
 		return err
 	}
 
+	resp, err := r.search(c.Request().Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// search runs a structured search request: it validates the options, dispatches the query
+// to the searcher of its kind and builds the response
+func (r *SearchRouter) search(ctx context.Context, req *dto.SearchRequest) (*dto.SearchResponse, error) {
+	opts, err := r.searchOptions(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var searchResult *storage.SearchResult
+	switch queryType := req.Query.GetQueryType(); queryType {
+	case dquery.HybridType:
+		searchResult, err = r.searchHybrid(ctx, req.Query.Hybrid, opts)
+	default:
+		searchResult, err = r.searchFts(ctx, req.Query, opts)
+		if code, _ := apperr.StatusOf(err); err != nil && code == http.StatusInternalServerError {
+			slog.Error("Failed to execute structured search", "error", err, "kind", queryType)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toResponse(ctx, searchResult, suggestText(req.Query))
+}
+
+// searchOptions validates the paging, sort, filters, aggregations, highlighting, rescoring
+// and normalization of a structured search request
+func (r *SearchRouter) searchOptions(req *dto.SearchRequest) (*dquery.BaseOptions, error) {
 	sizeInt := pagination.PageDefaultSize
 	if req.Size > 0 {
 		if req.Size > pagination.PageMaxSize {
-			return apperr.NewValidation(fmt.Sprintf("size parameter exceeds maximum of %d", pagination.PageMaxSize))
+			return nil, apperr.NewValidation(fmt.Sprintf("size parameter exceeds maximum of %d", pagination.PageMaxSize))
 		}
 		sizeInt = req.Size
 	}
 
-	var cursor *dquery.Cursor
-	if req.Cursor != "" {
-		var err error
-		cursor, err = dquery.DecodeCursor(req.Cursor)
-		if err != nil {
-			return apperr.NewValidation("invalid cursor parameter")
-		}
+	sort, err := dto.SortToDomain(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
-	opts := &dquery.BaseOptions{
-		Cursor: cursor,
-		Size:   sizeInt,
+	cursor, err := decodeCursor(req.Cursor, sort)
+	if err != nil {
+		return nil, err
 	}
 
-	queryType := req.Query.GetQueryType()
-	switch queryType {
-	case dquery.MatchType:
-		return r.handleMatchQuery(c, req.Query.Match, opts)
-	case dquery.MultiMatchType:
-		return r.handleMultiMatchQuery(c, req.Query.MultiMatch, opts)
-	case dquery.PhraseType:
-		return r.handlePhraseQuery(c, req.Query.Phrase, opts)
-	case dquery.BooleanType:
-		return r.handleBooleanQuery(c, req.Query.Boolean, opts)
-	case dquery.HybridType:
-		return r.handleHybridQuery(c, req.Query.Hybrid, opts)
-	default:
-		return apperr.NewValidation("query must specify one of: match, multi_match, phrase, boolean, hybrid")
+	filters, err := req.Filters.ToDomain()
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (r *SearchRouter) handleHybridQuery(c echo.Context, params *dto.HybridParams, options *dquery.BaseOptions) error {
-	if r.hybridSearcher == nil {
-		return apperr.NewValidation("hybrid search is not enabled on this server")
+	aggregations, err := dto.AggregationsToDomain(req.Aggregations)
+	if err != nil {
+		return nil, err
 	}
 
-	domainQuery, err := params.ToDomain()
+	highlight, err := req.Highlight.ToDomain()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	searchResult, err := r.hybridSearcher.SearchHybrid(c.Request().Context(), domainQuery, options)
+	rescoring, err := req.Rescoring.ToDomain()
 	if err != nil {
-		slog.Error("Failed to execute hybrid search", "error", err, "query", params.Query)
-		return err
+		return nil, err
 	}
 
-	return r.buildResponse(c, searchResult)
+	normalization, err := r.parseNormalization(req.Normalization, req.Query.GetQueryType())
+	if err != nil {
+		return nil, err
+	}
+
+	return &dquery.BaseOptions{
+		Cursor:        cursor,
+		Size:          sizeInt,
+		Filters:       filters,
+		Aggregations:  aggregations,
+		Highlight:     highlight,
+		Sort:          sort,
+		Rescoring:     rescoring,
+		Explain:       req.Explain,
+		Normalization: normalization,
+	}, nil
 }
 
-func (r *SearchRouter) handleMatchQuery(c echo.Context, params *dto.MatchParams, options *dquery.BaseOptions) error {
-	domainQuery, err := params.ToDomain()
-	if err != nil {
-		return err
+// suggestText is the text spelling suggestions are computed for; expressions are not suggested
+func suggestText(q dto.QueryWrapper) string {
+	switch q.GetQueryType() {
+	case dquery.MatchType:
+		return q.Match.Query
+	case dquery.MultiMatchType:
+		return q.MultiMatch.Query
+	case dquery.PhraseType:
+		return q.Phrase.Query
+	case dquery.HybridType:
+		return q.Hybrid.Query
+	default:
+		return ""
 	}
+}
 
-	searchResult, err := r.searcher.SearchField(c.Request().Context(), domainQuery, options)
+// explainHandler explains how a structured query scores one article (POST)
+//
+// Runs the query restricted to the article with explanations enabled, so the score is
+// computed exactly as in search: same fields, weights and rescoring.
+//
+// @Summary Explain the score of an article
+// @Description Returns how a structured query (match, multi_match, phrase, boolean or bool, with optional rescoring) scores the given article as an explanation tree. Elasticsearch reports its Lucene explanation; PostgreSQL the ts_rank of every weight label (A title, B description, C content, D subtitle/author) with the matched lexemes and normalization flags.
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param id path string true "Article ID (UUID)"
+// @Param request body dto.ExplainRequest true "Query to explain"
+// @Success 200 {object} dto.ExplainResponse "Score explanation; matched is false when the article does not match"
+// @Failure 400 {object} map[string]string "Bad request - invalid article ID or query"
+// @Failure 404 {object} map[string]string "Article not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/articles/{id}/_explain [post]
+func (r *SearchRouter) explainHandler(c echo.Context) error {
+	id, err := parseArticleID(c.Param("id"))
 	if err != nil {
-		slog.Error("Failed to execute match search", "error", err, "field", params.Field, "query", params.Query)
 		return err
 	}
 
-	return r.buildResponse(c, searchResult)
-}
+	var req dto.ExplainRequest
+	if err := c.Bind(&req); err != nil {
+		slog.Error("Failed to bind explain request", "error", err)
+		return apperr.NewValidation("invalid request body")
+	}
 
-func (r *SearchRouter) handleMultiMatchQuery(c echo.Context, params *dto.MultiMatchParams, options *dquery.BaseOptions) error {
-	domainQuery, err := params.ToDomain()
-	if err != nil {
+	if err := c.Validate(&req); err != nil {
 		return err
 	}
 
-	searchResult, err := r.searcher.SearchFields(c.Request().Context(), domainQuery, options)
+	if req.Query.GetQueryType() == dquery.HybridType {
+		return apperr.NewValidation("hybrid queries cannot be explained")
+	}
+
+	rescoring, err := req.Rescoring.ToDomain()
 	if err != nil {
-		slog.Error("Failed to execute multi_match search", "error", err, "fields", params.Fields, "query", params.Query)
 		return err
 	}
 
-	return r.buildResponse(c, searchResult)
-}
+	opts := &dquery.BaseOptions{
+		Size:      1,
+		Filters:   &dquery.Filters{IDs: []uuid.UUID{id}},
+		Rescoring: rescoring,
+		Explain:   true,
+	}
 
-func (r *SearchRouter) handlePhraseQuery(c echo.Context, params *dto.PhraseParams, options *dquery.BaseOptions) error {
-	domainQuery, err := params.ToDomain()
+	ctx := c.Request().Context()
+	result, err := r.searchFts(ctx, req.Query, opts)
 	if err != nil {
+		slog.Error("Failed to explain article", "error", err, "id", id)
 		return err
 	}
 
-	searchResult, err := r.searcher.SearchPhrase(c.Request().Context(), domainQuery, options)
-	if err != nil {
-		slog.Error("Failed to execute phrase search", "error", err, "fields", params.Fields, "query", params.Query, "slop", params.Slop)
-		return err
+	resp := dto.ExplainResponse{ID: id.String()}
+	if len(result.Hits) > 0 {
+		hit := result.Hits[0]
+		resp.Matched, resp.Score, resp.Explanation = true, hit.Score, hit.Explanation
+		return c.JSON(http.StatusOK, resp)
+	}
+
+	if r.reader != nil {
+		articles, err := r.reader.GetByIDs(ctx, []uuid.UUID{id})
+		if err != nil {
+			slog.Error("Failed to get explained article", "error", err, "id", id)
+			return err
+		}
+		if len(articles) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, storage.ErrArticleNotFound.Error())
+		}
 	}
 
-	return r.buildResponse(c, searchResult)
+	return c.JSON(http.StatusOK, resp)
 }
 
-func (r *SearchRouter) handleBooleanQuery(c echo.Context, params *dto.BooleanParams, options *dquery.BaseOptions) error {
+// searchFts runs a structured full-text query; hybrid queries are not full-text queries
+func (r *SearchRouter) searchFts(ctx context.Context, q dto.QueryWrapper, opts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	switch q.GetQueryType() {
+	case dquery.MatchType:
+		domainQuery, err := q.Match.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+		return r.searcher.SearchField(ctx, domainQuery, opts)
+	case dquery.MultiMatchType:
+		domainQuery, err := q.MultiMatch.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+		return r.searcher.SearchFields(ctx, domainQuery, opts)
+	case dquery.PhraseType:
+		domainQuery, err := q.Phrase.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+		return r.searcher.SearchPhrase(ctx, domainQuery, opts)
+	case dquery.BooleanType:
+		domainQuery, err := q.Boolean.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+		return r.searcher.SearchBoolean(ctx, domainQuery, opts)
+	case dquery.BoolType:
+		domainQuery, err := q.Bool.ToDomain()
+		if err != nil {
+			return nil, err
+		}
+		return r.searcher.SearchBool(ctx, domainQuery, opts)
+	default:
+		return nil, apperr.NewValidation("query must specify one of: match, multi_match, phrase, boolean, hybrid, bool")
+	}
+}
+
+// searchHybrid runs a hybrid query, which supports neither aggregations, highlighting, sort,
+// rescoring nor explanations
+func (r *SearchRouter) searchHybrid(ctx context.Context, params *dto.HybridParams, options *dquery.BaseOptions) (*storage.SearchResult, error) {
+	if r.hybridSearcher == nil {
+		return nil, apperr.NewValidation("hybrid search is not enabled on this server")
+	}
+	if len(options.Aggregations) > 0 {
+		return nil, apperr.NewValidation("aggregations are not supported for hybrid queries")
+	}
+	if options.Highlight != nil {
+		return nil, apperr.NewValidation("highlight is not supported for hybrid queries")
+	}
+	if !options.Sort.IsRelevance() {
+		return nil, apperr.NewValidation("sort is not supported for hybrid queries")
+	}
+	if options.Rescoring != nil {
+		return nil, apperr.NewValidation("rescoring is not supported for hybrid queries")
+	}
+	if options.Explain {
+		return nil, apperr.NewValidation("explain is not supported for hybrid queries")
+	}
+
 	domainQuery, err := params.ToDomain()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	searchResult, err := r.searcher.SearchBoolean(c.Request().Context(), domainQuery, options)
+	searchResult, err := r.hybridSearcher.SearchHybrid(ctx, domainQuery, options)
 	if err != nil {
-		slog.Error("Failed to execute boolean search", "error", err, "expression", params.Expression)
-		return err
+		slog.Error("Failed to execute hybrid search", "error", err, "query", params.Query)
+		return nil, err
 	}
-
-	return r.buildResponse(c, searchResult)
+	return searchResult, nil
 }
 
 // handleSematicQuery handles semantic query search (GET)
@@ -348,6 +596,16 @@ func (r *SearchRouter) handleBooleanQuery(c echo.Context, params *dto.BooleanPar
 // @Param q query string true "SearchStringQuery query text" example("climate change")
 // @Param size query int false "Results per page (default: 100, max: 10000)" example(10)
 // @Param cursor query string false "Pagination cursor (base64-encoded from previous response)"
+// @Param normalization query string false "Score normalization of score_normalized: max (default), min_max, z_score, rank, sigmoid" example(min_max)
+// @Param published_from query string false "Published at lower bound (RFC3339, YYYY-MM-DD or now-7d)" example("now-7d")
+// @Param published_to query string false "Published at upper bound (RFC3339, YYYY-MM-DD or now)"
+// @Param created_from query string false "Created at lower bound (RFC3339, YYYY-MM-DD or now-7d)"
+// @Param created_to query string false "Created at upper bound (RFC3339, YYYY-MM-DD or now)"
+// @Param language query string false "Filter by language (comma-separated)" example("english")
+// @Param source_id query string false "Filter by source ID (comma-separated)"
+// @Param source_name query string false "Filter by source name (comma-separated)"
+// @Param category query string false "Filter by category (comma-separated)" example("science,politics")
+// @Param author query string false "Filter by author (comma-separated)"
 // @Success 200 {object} dto.SemanticSearchResponse "SearchStringQuery results with pagination metadata"
 // @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -361,7 +619,7 @@ func (r *SearchRouter) handleSematicQuery(c echo.Context) error {
 	}
 
 	sizeStr := c.QueryParam("size")
-	size, err := r.parseSize(sizeStr)
+	size, err := parseSize(sizeStr)
 	if err != nil {
 		return err
 	}
@@ -383,9 +641,21 @@ func (r *SearchRouter) handleSematicQuery(c echo.Context) error {
 		}
 	}
 
+	filters, err := parseFilterParams(c).ToDomain()
+	if err != nil {
+		return err
+	}
+
+	normalization, err := r.parseNormalization(c.QueryParam("normalization"), dquery.SemanticType)
+	if err != nil {
+		return err
+	}
+
 	options := &dquery.BaseOptions{
-		Cursor: cursor,
-		Size:   size,
+		Cursor:        cursor,
+		Size:          size,
+		Filters:       filters,
+		Normalization: normalization,
 	}
 
 	domainQuery, err := req.ToDomain()
@@ -401,7 +671,7 @@ func (r *SearchRouter) handleSematicQuery(c echo.Context) error {
 
 	var nextCursorStr *string
 	if searchResult.NextCursor != nil {
-		encoded, err := dquery.EncodeCursor(searchResult.NextCursor.Score, searchResult.NextCursor.ID)
+		encoded, err := searchResult.NextCursor.Encode()
 		if err != nil {
 			slog.Error("Failed to encode cursor", "error", err)
 			return fmt.Errorf("failed to encode cursor: %w", err)
@@ -411,7 +681,7 @@ func (r *SearchRouter) handleSematicQuery(c echo.Context) error {
 
 	hits := searchResult.Hits
 	if hits == nil {
-		hits = []dto.Article{}
+		hits = []dto.ArticleSearchResult{}
 	}
 
 	apiResponse := dto.SemanticSearchResponse{
@@ -423,7 +693,150 @@ func (r *SearchRouter) handleSematicQuery(c echo.Context) error {
 	return c.JSON(http.StatusOK, apiResponse)
 }
 
-func (r *SearchRouter) parseSize(sizeStr string) (int, error) {
+// suggestHandler returns spelling corrections for query text (GET)
+//
+// Only words that do not occur in the indexed articles are corrected; each correction
+// lists up to size options, best first. Text is the query with every misspelled word
+// replaced by its best option.
+//
+// @Summary Did you mean suggestions
+// @Description Returns spelling corrections for words of the query text that do not occur in any article. Elasticsearch uses the term suggester over title and content; PostgreSQL ranks vocabulary words by trigram similarity and document frequency.
+// @Tags search
+// @Produce json
+// @Param q query string true "Query text to correct" example("goverment budgett")
+// @Param size query int false "Corrections per term (default: 3, max: 10)" example(3)
+// @Success 200 {object} dquery.Suggest "Spelling suggestions"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/articles/_suggest [get]
+// @Example Request:  GET /v1/articles/_suggest?q=goverment%20budgett
+// @Example Response: {"text": "government budget", "terms": [{"term": "goverment", "options": [{"text": "government", "score": 0.8, "freq": 412}]}, ...]}
+func (r *SearchRouter) suggestHandler(c echo.Context) error {
+	text := c.QueryParam("q")
+	if text == "" {
+		return apperr.NewValidation("q parameter is required")
+	}
+
+	size, err := parseBoundedSize(c.QueryParam("size"), dquery.DefaultSuggestSize, dquery.MaxSuggestSize)
+	if err != nil {
+		return err
+	}
+
+	suggest, err := r.suggester.Suggest(c.Request().Context(), text, size)
+	if err != nil {
+		slog.Error("Failed to fetch spelling suggestions", "error", err, "query", text)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, suggest)
+}
+
+// autocompleteHandler completes a type-ahead prefix to article titles (GET)
+//
+// Every word of the prefix is matched as a word prefix ("climate ch" matches
+// "Climate change talks stall"). Completions are distinct titles, best first.
+// Designed for per-keystroke requests: no pagination, counts or highlighting.
+//
+// @Summary Title autocomplete
+// @Description Returns up to size distinct article titles completing the prefix, with article IDs. Elasticsearch uses a search_as_you_type sub-field on title; PostgreSQL uses a prefix tsquery on title lexemes plus trigram ranking.
+// @Tags search
+// @Produce json
+// @Param prefix query string true "Type-ahead input, 2 to 100 characters" example("climate ch")
+// @Param size query int false "Completions to return (default: 10, max: 25)" example(10)
+// @Param lang query string false "Prefix language: english, serbian (default: english; PostgreSQL only)" example("english")
+// @Success 200 {object} dto.AutocompleteResponse "Title completions"
+// @Failure 400 {object} map[string]string "Bad request - missing or invalid parameters"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /v1/articles/_autocomplete [get]
+// @Example Request:  GET /v1/articles/_autocomplete?prefix=climate%20ch&size=5
+// @Example Response: {"completions": [{"id": "550e8400-...", "title": "Climate change talks stall", "score": 1.62}]}
+func (r *SearchRouter) autocompleteHandler(c echo.Context) error {
+	prefix := dquery.NormalizePrefix(c.QueryParam("prefix"))
+	if prefix == "" {
+		return apperr.NewValidation("prefix parameter is required")
+	}
+	if len([]rune(prefix)) < dquery.AutocompleteMinPrefixLength {
+		return apperr.NewValidation(fmt.Sprintf("prefix parameter must be at least %d characters", dquery.AutocompleteMinPrefixLength))
+	}
+	if len([]rune(prefix)) > dquery.AutocompleteMaxPrefixLength {
+		return apperr.NewValidation(fmt.Sprintf("prefix parameter exceeds maximum length of %d", dquery.AutocompleteMaxPrefixLength))
+	}
+
+	size, err := parseBoundedSize(c.QueryParam("size"), dquery.DefaultAutocompleteSize, dquery.MaxAutocompleteSize)
+	if err != nil {
+		return err
+	}
+
+	lang, err := dquery.Language(c.QueryParam("lang")).Parse()
+	if err != nil {
+		return apperr.NewValidationWrap("invalid lang parameter", err)
+	}
+
+	completions, err := r.autocompleter.Autocomplete(c.Request().Context(), prefix, lang, size)
+	if err != nil {
+		slog.Error("Failed to execute autocomplete", "error", err, "prefix", prefix)
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dto.AutocompleteResponse{Completions: completions})
+}
+
+// parseNormalization resolves the requested score normalization of a query kind; sigmoid
+// normalization uses the sigmoid calibrated for the kind.
+func (r *SearchRouter) parseNormalization(method string, kind dquery.Kind) (ranking.Spec, error) {
+	m, err := dto.NormalizationToDomain(method)
+	if err != nil {
+		return ranking.Spec{}, err
+	}
+
+	spec := ranking.Spec{Method: m}
+	if m != ranking.MethodSigmoid {
+		return spec, nil
+	}
+	sigmoid, ok := r.calibrations.For(string(kind))
+	if !ok {
+		return ranking.Spec{}, apperr.NewValidation(fmt.Sprintf("sigmoid normalization is not calibrated for %s queries", kind))
+	}
+	spec.Sigmoid = &sigmoid
+	return spec, nil
+}
+
+// parseFilterParams reads structured filters from query parameters.
+// Term filters accept repeated parameters and comma-separated values (?category=science,politics).
+func parseFilterParams(c echo.Context) *dto.FilterParams {
+	params := c.QueryParams()
+	dateRange := func(gteKey, lteKey string) *dto.DateRangeParams {
+		gte, lte := params.Get(gteKey), params.Get(lteKey)
+		if gte == "" && lte == "" {
+			return nil
+		}
+		return &dto.DateRangeParams{Gte: gte, Lte: lte}
+	}
+
+	return &dto.FilterParams{
+		PublishedAt: dateRange("published_from", "published_to"),
+		CreatedAt:   dateRange("created_from", "created_to"),
+		Language:    params["language"],
+		SourceID:    params["source_id"],
+		SourceName:  params["source_name"],
+		Category:    params["category"],
+		Author:      params["author"],
+	}
+}
+
+// decodeCursor decodes the cursor parameter and checks that it continues the requested sort
+func decodeCursor(cursorStr string, sort dquery.Sort) (*dquery.Cursor, error) {
+	cursor, err := dquery.DecodeCursor(cursorStr)
+	if err != nil {
+		return nil, apperr.NewValidation("invalid cursor parameter")
+	}
+	if cursor != nil && !cursor.Continues(sort) {
+		return nil, apperr.NewValidation("cursor was issued for a different sort")
+	}
+	return cursor, nil
+}
+
+func parseSize(sizeStr string) (int, error) {
 	if sizeStr == "" {
 		return pagination.PageDefaultSize, nil
 	}
@@ -437,13 +850,31 @@ func (r *SearchRouter) parseSize(sizeStr string) (int, error) {
 	return sizeInt, nil
 }
 
-func (r *SearchRouter) buildResponse(c echo.Context, searchResult *storage.SearchResult) error {
+// parseBoundedSize parses a result count parameter of the non-paginated endpoints
+func parseBoundedSize(sizeStr string, defaultSize, maxSize int) (int, error) {
+	if sizeStr == "" {
+		return defaultSize, nil
+	}
+	size, err := strconv.Atoi(sizeStr)
+	if err != nil || size < 1 {
+		return 0, apperr.NewValidation("invalid size parameter")
+	}
+	if size > maxSize {
+		return 0, apperr.NewValidation(fmt.Sprintf("size parameter exceeds maximum of %d", maxSize))
+	}
+	return size, nil
+}
+
+// toResponse maps a search result to the API response. When the query matched few
+// articles, spelling suggestions for queryText are attached on a best-effort basis;
+// pass an empty queryText for queries whose text is not plain words (e.g. boolean).
+func (r *SearchRouter) toResponse(ctx context.Context, searchResult *storage.SearchResult, queryText string) (*dto.SearchResponse, error) {
 	var nextCursorStr *string
 	if searchResult.NextCursor != nil {
-		encoded, err := dquery.EncodeCursor(searchResult.NextCursor.Score, searchResult.NextCursor.ID)
+		encoded, err := searchResult.NextCursor.Encode()
 		if err != nil {
 			slog.Error("Failed to encode cursor", "error", err)
-			return fmt.Errorf("failed to encode cursor: %w", err)
+			return nil, fmt.Errorf("failed to encode cursor: %w", err)
 		}
 		nextCursorStr = &encoded
 	}
@@ -454,13 +885,25 @@ func (r *SearchRouter) buildResponse(c echo.Context, searchResult *storage.Searc
 	}
 
 	apiResponse := dto.SearchResponse{
-		Hits:         hits,
-		NextCursor:   nextCursorStr,
-		HasMore:      searchResult.HasMore,
-		MaxScore:     searchResult.MaxScore,
-		PageMaxScore: searchResult.PageMaxScore,
-		TotalMatches: searchResult.TotalMatches,
+		Hits:          hits,
+		NextCursor:    nextCursorStr,
+		HasMore:       searchResult.HasMore,
+		MaxScore:      searchResult.MaxScore,
+		PageMaxScore:  searchResult.PageMaxScore,
+		TotalMatches:  searchResult.TotalMatches,
+		TotalRelation: searchResult.TotalRelation,
+		Aggregations:  searchResult.Aggregations,
+		Expansions:    searchResult.Expansions,
+	}
+
+	if r.suggester != nil && queryText != "" && searchResult.TotalMatches <= dquery.SuggestMaxHits {
+		suggest, err := r.suggester.Suggest(ctx, queryText, dquery.DefaultSuggestSize)
+		if err != nil {
+			slog.Warn("Failed to fetch spelling suggestions", "error", err, "query", queryText)
+		} else if !suggest.IsEmpty() {
+			apiResponse.Suggest = suggest
+		}
 	}
 
-	return c.JSON(http.StatusOK, apiResponse)
+	return &apiResponse, nil
 }
@@ -5,13 +5,17 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
 	apiserver "github.com/DjordjeVuckovic/news-hunter/internal/api/server"
 	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -38,6 +42,9 @@ func (stubFtsSearcher) SearchPhrase(context.Context, *dquery.Phrase, *dquery.Bas
 func (stubFtsSearcher) SearchBoolean(context.Context, *dquery.Boolean, *dquery.BaseOptions) (*storage.SearchResult, error) {
 	return &storage.SearchResult{}, nil
 }
+func (stubFtsSearcher) SearchBool(context.Context, *dquery.Bool, *dquery.BaseOptions) (*storage.SearchResult, error) {
+	return &storage.SearchResult{}, nil
+}
 
 func TestStructuredSearchHandlerValidation(t *testing.T) {
 	tests := []struct {
@@ -55,6 +62,91 @@ func TestStructuredSearchHandlerValidation(t *testing.T) {
 			body:     `{"query":{"match":{"field":"title","query":""}}}`,
 			wantCode: http.StatusBadRequest,
 		},
+		{
+			name:     "valid match request with filters",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"filters":{"published_at":{"gte":"now-7d"},"category":["science"]}}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "valid match request with aggregations",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"aggregations":{"categories":{"terms":{"field":"category"}}}}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "invalid aggregation field",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"aggregations":{"titles":{"terms":{"field":"title"}}}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "valid phrase request with highlight",
+			body:     `{"query":{"phrase":{"query":"climate change","fields":["title"]}},"highlight":{"fields":["title","content"]}}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "invalid highlight field",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"highlight":{"fields":["url"]}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "valid bool request",
+			body:     `{"query":{"bool":{"must":[{"phrase":{"query":"climate change","fields":["title"]}}],"should":[{"match":{"field":"content","query":"emissions"}}],"must_not":[{"match":{"field":"author","query":"smith"}}]}},"filters":{"category":["science"]}}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "invalid bool request without clauses",
+			body:     `{"query":{"bool":{}}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "valid match request with sort",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"sort":["published_at:desc","relevance"]}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "invalid sort field",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"sort":["title"]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "relevance cursor with another sort",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"cursor":"eyJzIjowLjUsImkiOiIxMjNlNDU2Ny1lODliLTEyZDMtYTQ1Ni00MjY2MTQxNzQwMDAifQ==","sort":["published_at"]}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "valid match request with rescoring",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"rescoring":{"recency":{"function":"gauss","scale":"7d"},"source_weights":{"Reuters":1.5},"breakdown":true}}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "valid match request with explain",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"explain":true}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "valid match request with normalization",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"normalization":"z_score"}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "invalid normalization",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"normalization":"softmax"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "sigmoid normalization without calibration",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"normalization":"sigmoid"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid rescoring scale",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"rescoring":{"recency":{"scale":"a week"}}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid filter date",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"filters":{"published_at":{"gte":"last week"}}}`,
+			wantCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -78,6 +170,125 @@ func TestStructuredSearchHandlerValidation(t *testing.T) {
 	}
 }
 
+func TestParseNormalization(t *testing.T) {
+	r := &SearchRouter{calibrations: ranking.Calibrations{"match": {A: 0.5, B: -2}}}
+
+	spec, err := r.parseNormalization("", dquery.MatchType)
+	if err != nil || spec.Method != ranking.DefaultMethod {
+		t.Errorf("default normalization = %+v, %v", spec, err)
+	}
+
+	spec, err = r.parseNormalization("sigmoid", dquery.MatchType)
+	if err != nil || spec.Sigmoid == nil || *spec.Sigmoid != r.calibrations["match"] {
+		t.Errorf("sigmoid normalization of match queries = %+v, %v, want the match calibration", spec, err)
+	}
+
+	if _, err := r.parseNormalization("sigmoid", dquery.PhraseType); err == nil {
+		t.Error("sigmoid normalization of phrase queries should fail without a phrase or default calibration")
+	}
+}
+
+// explainingSearcher matches the known article for match queries on "climate"
+type explainingSearcher struct {
+	stubFtsSearcher
+}
+
+func (explainingSearcher) SearchField(_ context.Context, q *dquery.Match, opts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	if q.Query != "climate" || !opts.Explain || !slices.Equal(opts.Filters.IDs, []uuid.UUID{knownArticleID}) {
+		return &storage.SearchResult{}, nil
+	}
+	return &storage.SearchResult{Hits: []dto.ArticleSearchResult{{
+		Article:     dto.Article{ID: knownArticleID},
+		Score:       0.6,
+		Explanation: &dquery.Explanation{Value: 0.6, Description: "ts_rank", Details: []dquery.Explanation{{Value: 0.6, Field: "title", Terms: []string{"climat"}}}},
+	}}}, nil
+}
+
+func TestExplainHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		body        string
+		wantCode    int
+		wantMatched bool
+	}{
+		{
+			name:        "matching article",
+			id:          knownArticleID.String(),
+			body:        `{"query":{"match":{"field":"title","query":"climate"}}}`,
+			wantCode:    http.StatusOK,
+			wantMatched: true,
+		},
+		{
+			name:     "article not matching the query",
+			id:       knownArticleID.String(),
+			body:     `{"query":{"match":{"field":"title","query":"election"}}}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "unknown article",
+			id:       uuid.NewString(),
+			body:     `{"query":{"match":{"field":"title","query":"climate"}}}`,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "invalid article id",
+			id:       "not-a-uuid",
+			body:     `{"query":{"match":{"field":"title","query":"climate"}}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "hybrid query",
+			id:       knownArticleID.String(),
+			body:     `{"query":{"hybrid":{"query":"climate"}}}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid rescoring",
+			id:       knownArticleID.String(),
+			body:     `{"query":{"match":{"field":"title","query":"climate"}},"rescoring":{}}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			(&apiserver.Server{Echo: e}).SetupValidator()
+			e.HTTPErrorHandler = apperr.GlobalErrorHandler()
+
+			r := &SearchRouter{e: e, searcher: explainingSearcher{}, reader: stubReader{}}
+			r.Bind()
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/articles/"+tt.id+"/_explain", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var got dto.ExplainResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if got.ID != tt.id || got.Matched != tt.wantMatched {
+				t.Errorf("id = %s, matched = %v, want %s, %v", got.ID, got.Matched, tt.id, tt.wantMatched)
+			}
+			if tt.wantMatched && (got.Explanation == nil || got.Explanation.Details[0].Field != "title") {
+				t.Errorf("explanation = %+v, want the searcher's explanation", got.Explanation)
+			}
+			if !tt.wantMatched && got.Explanation != nil {
+				t.Errorf("explanation = %+v, want none for a non-matching article", got.Explanation)
+			}
+		})
+	}
+}
+
 func TestCapabilitiesHandler(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -112,7 +323,7 @@ func TestCapabilitiesHandler(t *testing.T) {
 				t.Fatalf("failed to decode body: %v", err)
 			}
 
-			for _, key := range []string{"string_query", "match", "multi_match", "phrase", "boolean"} {
+			for _, key := range []string{"string_query", "match", "multi_match", "phrase", "boolean", "bool"} {
 				if !got[key] {
 					t.Errorf("%q = false, want true", key)
 				}
@@ -123,3 +334,137 @@ func TestCapabilitiesHandler(t *testing.T) {
 		})
 	}
 }
+
+type stubSuggester struct{}
+
+func (stubSuggester) Suggest(_ context.Context, text string, _ int) (*dquery.Suggest, error) {
+	return dquery.NewSuggest(text, []dquery.TermSuggestion{
+		{Term: "climte", Options: []dquery.SuggestOption{{Text: "climate", Score: 0.8, Freq: 12}}},
+	}), nil
+}
+
+func TestSuggestHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		wantCode int
+		wantText string
+	}{
+		{name: "corrects misspelled word", target: "/v1/articles/_suggest?q=climte+change", wantCode: http.StatusOK, wantText: "climate change"},
+		{name: "custom size", target: "/v1/articles/_suggest?q=climte&size=5", wantCode: http.StatusOK, wantText: "climate"},
+		{name: "missing q", target: "/v1/articles/_suggest", wantCode: http.StatusBadRequest},
+		{name: "invalid size", target: "/v1/articles/_suggest?q=climte&size=0", wantCode: http.StatusBadRequest},
+		{name: "size above maximum", target: "/v1/articles/_suggest?q=climte&size=11", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = apperr.GlobalErrorHandler()
+
+			r := &SearchRouter{e: e, searcher: stubFtsSearcher{}, suggester: stubSuggester{}}
+			r.Bind()
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var got dquery.Suggest
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if got.Text != tt.wantText {
+				t.Errorf("text = %q, want %q", got.Text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestSearchResponseSuggest(t *testing.T) {
+	e := echo.New()
+	r := &SearchRouter{e: e, searcher: stubFtsSearcher{}, suggester: stubSuggester{}}
+	r.Bind()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/articles/search?q=climte", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var got struct {
+		Suggest *dquery.Suggest `json:"suggest"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if got.Suggest == nil || got.Suggest.Text != "climate" {
+		t.Fatalf("suggest = %+v, want text %q", got.Suggest, "climate")
+	}
+}
+
+type stubAutocompleter struct{}
+
+func (stubAutocompleter) Autocomplete(_ context.Context, prefix string, _ dquery.Language, size int) ([]dquery.Completion, error) {
+	return dquery.DistinctCompletions([]dquery.Completion{
+		{Title: prefix + " talks stall", Score: 2},
+		{Title: prefix + " report", Score: 1},
+	}, size), nil
+}
+
+func TestAutocompleteHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		wantCode  int
+		wantCount int
+	}{
+		{name: "default size", target: "/v1/articles/_autocomplete?prefix=climate+ch", wantCode: http.StatusOK, wantCount: 2},
+		{name: "custom size", target: "/v1/articles/_autocomplete?prefix=climate&size=1", wantCode: http.StatusOK, wantCount: 1},
+		{name: "serbian", target: "/v1/articles/_autocomplete?prefix=izbori&lang=serbian", wantCode: http.StatusOK, wantCount: 2},
+		{name: "missing prefix", target: "/v1/articles/_autocomplete?prefix=+", wantCode: http.StatusBadRequest},
+		{name: "prefix too short", target: "/v1/articles/_autocomplete?prefix=c", wantCode: http.StatusBadRequest},
+		{name: "prefix too long", target: "/v1/articles/_autocomplete?prefix=" + strings.Repeat("a", 101), wantCode: http.StatusBadRequest},
+		{name: "size above maximum", target: "/v1/articles/_autocomplete?prefix=climate&size=26", wantCode: http.StatusBadRequest},
+		{name: "unsupported language", target: "/v1/articles/_autocomplete?prefix=climate&lang=klingon", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = apperr.GlobalErrorHandler()
+
+			r := &SearchRouter{e: e, searcher: stubFtsSearcher{}, autocompleter: stubAutocompleter{}}
+			r.Bind()
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var got struct {
+				Completions []dquery.Completion `json:"completions"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode body: %v", err)
+			}
+			if len(got.Completions) != tt.wantCount {
+				t.Errorf("completions = %d, want %d", len(got.Completions), tt.wantCount)
+			}
+		})
+	}
+}
@@ -3,9 +3,11 @@ package apperr_test
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/labstack/echo/v4"
 )
 
 func TestNewValidation(t *testing.T) {
@@ -55,3 +57,25 @@ func TestValidationError_NotFoundForPlainErrors(t *testing.T) {
 		t.Fatal("errors.As should NOT find ValidationError in plain error chain")
 	}
 }
+
+func TestStatusOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantMsg  string
+	}{
+		{"validation", fmt.Errorf("parse: %w", apperr.NewValidation("bad sort")), http.StatusBadRequest, "bad sort"},
+		{"http error", echo.NewHTTPError(http.StatusNotFound, "article not found"), http.StatusNotFound, "article not found"},
+		{"plain error", errors.New("connection refused"), http.StatusInternalServerError, "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, msg := apperr.StatusOf(tt.err)
+			if code != tt.wantCode || msg != tt.wantMsg {
+				t.Errorf("StatusOf() = %d, %q, want %d, %q", code, msg, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
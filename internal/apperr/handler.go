@@ -15,20 +15,35 @@ func GlobalErrorHandler() echo.HTTPErrorHandler {
 			return
 		}
 
+		code, msg := StatusOf(err)
+
 		var ve *ValidationError
 		if errors.As(err, &ve) {
-			_ = c.JSON(http.StatusBadRequest, map[string]string{"error": ve.Message, "title": "validation error"})
+			_ = c.JSON(code, map[string]string{"error": msg, "title": "validation error"})
 			return
 		}
 
 		var he *echo.HTTPError
-		if errors.As(err, &he) {
-			msg := fmt.Sprintf("%v", he.Message)
-			_ = c.JSON(he.Code, map[string]string{"error": msg})
-			return
+		if !errors.As(err, &he) {
+			slog.Error("Unhandled error", "error", err)
 		}
+		_ = c.JSON(code, map[string]string{"error": msg})
+	}
+}
 
-		slog.Error("Unhandled error", "error", err)
-		_ = c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+// StatusOf maps an error to the HTTP status and message the client is sent: validation
+// errors are 400, echo HTTP errors keep their code and any other error is a 500 whose
+// details are not exposed
+func StatusOf(err error) (int, string) {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return http.StatusBadRequest, ve.Message
 	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return he.Code, fmt.Sprintf("%v", he.Message)
+	}
+
+	return http.StatusInternalServerError, "internal server error"
 }
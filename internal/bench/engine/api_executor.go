@@ -84,12 +84,15 @@ func (e *APIExecutor) Execute(ctx context.Context, rawQuery string, _ []any) (*E
 	}
 
 	ids := make([]uuid.UUID, 0, len(searchResp.Hits))
+	scores := make([]float64, 0, len(searchResp.Hits))
 	for _, hit := range searchResp.Hits {
 		ids = append(ids, hit.Article.ID)
+		scores = append(scores, hit.Score)
 	}
 
 	return &Execution{
 		RankedDocIDs: ids,
+		RawScores:    scores,
 		TotalMatches: searchResp.TotalMatches,
 		Latency:      latency,
 	}, nil
@@ -122,6 +125,7 @@ type apiSearchResponse struct {
 
 type apiSearchHit struct {
 	Article apiArticle `json:"article"`
+	Score   float64    `json:"score"`
 }
 
 type apiArticle struct {
@@ -60,16 +60,19 @@ func (e *EsExecutor) Execute(ctx context.Context, rawQuery string, _ []any) (*Ex
 	}
 
 	ids := make([]uuid.UUID, 0, len(esResp.Hits.Hits))
+	scores := make([]float64, 0, len(esResp.Hits.Hits))
 	for _, hit := range esResp.Hits.Hits {
 		id, err := uuid.Parse(hit.Source.ID)
 		if err != nil {
 			return nil, fmt.Errorf("es parse doc id %q: %w", hit.Source.ID, err)
 		}
 		ids = append(ids, id)
+		scores = append(scores, hit.Score)
 	}
 
 	return &Execution{
 		RankedDocIDs: ids,
+		RawScores:    scores,
 		TotalMatches: esResp.Hits.Total.Value,
 		Latency:      latency,
 	}, nil
@@ -279,6 +282,7 @@ type esTotal struct {
 }
 
 type esHit struct {
+	Score  float64  `json:"_score"`
 	Source esSource `json:"_source"`
 }
 
@@ -23,6 +23,9 @@ type Validator interface {
 
 type Execution struct {
 	RankedDocIDs []uuid.UUID
+	// RawScores holds the engine score of every ranked doc, when the engine reports scores
+	// (API and Elasticsearch); used to calibrate score normalization
+	RawScores    []float64
 	TotalMatches int64
 	Latency      time.Duration
 }
@@ -10,6 +10,7 @@ type QueryResult struct {
 	EngineName   string
 	Scores       metrics.ScoreSet
 	RankedDocIDs []uuid.UUID
+	RawScores    []float64 // engine score per ranked doc, when the engine reports scores
 	TotalMatches int64
 	Latency      LatencyStats
 	Error        error
@@ -194,6 +194,7 @@ func (r *Runner) runEnginesForQuery(
 					EngineName:   engName,
 					Scores:       scores,
 					RankedDocIDs: result.rankedIDs,
+					RawScores:    result.rawScores,
 					TotalMatches: result.totalMatches,
 					Latency:      result.latencyStats,
 					Error:        result.err,
@@ -250,6 +251,7 @@ func (r *Runner) judgmentsFor(q *suite.Query) map[uuid.UUID]int {
 
 type execResult struct {
 	rankedIDs    []uuid.UUID
+	rawScores    []float64
 	totalMatches int64
 	latencyStats LatencyStats
 	err          error
@@ -286,6 +288,7 @@ func (r *Runner) executeWithRetries(
 
 	return execResult{
 		rankedIDs:    lastExec.RankedDocIDs,
+		rawScores:    lastExec.RawScores,
 		totalMatches: lastExec.TotalMatches,
 		latencyStats: ComputeLatencyStats(latencies),
 	}
@@ -0,0 +1,261 @@
+// Package langdetect provides a dependency-free character n-gram language detector
+// for the languages supported by the search backends.
+package langdetect
+
+import (
+	"embed"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+const (
+	// ngramSize is the length of the character n-grams used for classification
+	ngramSize = 3
+
+	defaultMaxRunes = 2_000
+	defaultMinRunes = 10
+
+	// defaultMinFit is the mean log-likelihood gain per n-gram over an unseen n-gram that the
+	// best language must reach. The posterior only compares the supported languages, so it is
+	// close to 1 for any text; the fit rejects text that none of the profiles explains, such
+	// as German, French or Russian, which stays below ~1.2 while news text in a supported
+	// language scores ~1.4-2.0.
+	defaultMinFit = 1.2
+)
+
+//go:embed profiles/*.txt
+var profileFS embed.FS
+
+// profileFiles maps supported languages to their embedded training text
+var profileFiles = map[string]string{
+	string(query.LanguageEnglish): "profiles/english.txt",
+	string(query.LanguageSerbian): "profiles/serbian.txt",
+}
+
+// Detection is the outcome of language detection.
+// Language is empty when the text is too short or contains no letters.
+type Detection struct {
+	Language   string
+	Confidence float64
+}
+
+// profile holds smoothed n-gram log probabilities of one language
+type profile struct {
+	logProbs  map[string]float64
+	unseenLog float64
+}
+
+var (
+	profilesOnce sync.Once
+	profiles     map[string]*profile
+	profilesErr  error
+)
+
+// Detector classifies text with a naive Bayes model over character trigrams.
+// Cyrillic is transliterated to Latin first, so Serbian is recognised in both scripts.
+type Detector struct {
+	profiles map[string]*profile
+	maxRunes int
+	minRunes int
+	minFit   float64
+}
+
+type Option func(d *Detector)
+
+// WithMaxRunes caps the number of letters inspected per text
+func WithMaxRunes(n int) Option {
+	return func(d *Detector) {
+		if n > 0 {
+			d.maxRunes = n
+		}
+	}
+}
+
+// WithMinRunes sets the minimum number of letters required for a detection
+func WithMinRunes(n int) Option {
+	return func(d *Detector) {
+		if n > 0 {
+			d.minRunes = n
+		}
+	}
+}
+
+// WithMinFit sets the mean log-likelihood gain per n-gram over an unseen n-gram required
+// for a detection (see defaultMinFit)
+func WithMinFit(fit float64) Option {
+	return func(d *Detector) {
+		if fit > 0 {
+			d.minFit = fit
+		}
+	}
+}
+
+// NewDetector creates a detector backed by the embedded language profiles
+func NewDetector(opts ...Option) (*Detector, error) {
+	profilesOnce.Do(func() {
+		profiles, profilesErr = loadProfiles()
+	})
+	if profilesErr != nil {
+		return nil, profilesErr
+	}
+
+	d := &Detector{
+		profiles: profiles,
+		maxRunes: defaultMaxRunes,
+		minRunes: defaultMinRunes,
+		minFit:   defaultMinFit,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d, nil
+}
+
+// Detect returns the most probable language of text with its posterior probability.
+// Language is empty when the text fits none of the profiles (see WithMinFit).
+func (d *Detector) Detect(text string) Detection {
+	normalized, letters := normalizeText(text, d.maxRunes)
+	if letters < d.minRunes {
+		return Detection{}
+	}
+
+	grams := ngrams(normalized)
+	if len(grams) == 0 {
+		return Detection{}
+	}
+
+	scores := make(map[string]float64, len(d.profiles))
+	best, bestScore := "", math.Inf(-1)
+	for lang, p := range d.profiles {
+		var score float64
+		for _, g := range grams {
+			if lp, ok := p.logProbs[g]; ok {
+				score += lp
+			} else {
+				score += p.unseenLog
+			}
+		}
+		scores[lang] = score
+		if score > bestScore || (score == bestScore && lang < best) {
+			best, bestScore = lang, score
+		}
+	}
+
+	// Absolute fit: the posterior is relative to the supported languages only
+	if fit := bestScore/float64(len(grams)) - d.profiles[best].unseenLog; fit < d.minFit {
+		return Detection{}
+	}
+
+	// Posterior of the best language: 1 / Σ exp(score_l - score_best)
+	var sum float64
+	for _, score := range scores {
+		sum += math.Exp(score - bestScore)
+	}
+
+	return Detection{Language: best, Confidence: 1 / sum}
+}
+
+// Supported reports whether the detector has a profile for a canonical language name
+func Supported(lang string) bool {
+	_, ok := profileFiles[lang]
+	return ok
+}
+
+// Normalize maps language names and ISO 639-1 codes to the canonical language names
+// used by the search backends ("en" → "english", "sr" → "serbian"). Unknown values
+// are returned lower-cased.
+func Normalize(lang string) string {
+	switch l := strings.ToLower(strings.TrimSpace(lang)); l {
+	case "en", "eng":
+		return string(query.LanguageEnglish)
+	case "sr", "srp", "srpski":
+		return string(query.LanguageSerbian)
+	default:
+		return l
+	}
+}
+
+func loadProfiles() (map[string]*profile, error) {
+	result := make(map[string]*profile, len(profileFiles))
+	for lang, file := range profileFiles {
+		data, err := profileFS.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read %s profile: %w", lang, err)
+		}
+		normalized, _ := normalizeText(string(data), 0)
+		result[lang] = newProfile(ngrams(normalized))
+	}
+	return result, nil
+}
+
+// newProfile builds add-one smoothed log probabilities from training n-grams
+func newProfile(grams []string) *profile {
+	counts := make(map[string]int)
+	for _, g := range grams {
+		counts[g]++
+	}
+	total := float64(len(grams) + len(counts) + 1)
+
+	p := &profile{
+		logProbs:  make(map[string]float64, len(counts)),
+		unseenLog: math.Log(1 / total),
+	}
+	for g, c := range counts {
+		p.logProbs[g] = math.Log(float64(c+1) / total)
+	}
+	return p
+}
+
+// normalizeText lower-cases text, transliterates Cyrillic to Latin and replaces
+// every non-letter with a space. maxRunes limits the number of letters kept (0 = no limit).
+// Returns the normalized text and its letter count.
+func normalizeText(text string, maxRunes int) (string, int) {
+	var b strings.Builder
+	letters := 0
+	space := true
+	for _, r := range strings.ToLower(text) {
+		if !unicode.IsLetter(r) {
+			if !space {
+				b.WriteByte(' ')
+				space = true
+			}
+			continue
+		}
+		if maxRunes > 0 && letters >= maxRunes {
+			break
+		}
+		if latin, ok := cyrillicToLatin[r]; ok {
+			b.WriteString(latin)
+		} else {
+			b.WriteRune(r)
+		}
+		letters++
+		space = false
+	}
+	return strings.TrimSpace(b.String()), letters
+}
+
+// ngrams returns the character n-grams of each word padded with spaces ("je" → " je", "je ")
+func ngrams(text string) []string {
+	var grams []string
+	for _, word := range strings.Fields(text) {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+ngramSize <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+ngramSize]))
+		}
+	}
+	return grams
+}
+
+// cyrillicToLatin is the Serbian Cyrillic to Gaj Latin alphabet mapping (lower case)
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'ђ': "đ", 'е': "e", 'ж': "ž", 'з': "z",
+	'и': "i", 'ј': "j", 'к': "k", 'л': "l", 'љ': "lj", 'м': "m", 'н': "n", 'њ': "nj", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'ћ': "ć", 'у': "u", 'ф': "f", 'х': "h", 'ц': "c",
+	'ч': "č", 'џ': "dž", 'ш': "š",
+}
@@ -0,0 +1,95 @@
+package langdetect
+
+import (
+	"testing"
+)
+
+func TestDetector_Detect(t *testing.T) {
+	d, err := NewDetector()
+	if err != nil {
+		t.Fatalf("NewDetector() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "english news",
+			text: "The minister said the new law will protect workers and help small businesses recover after the crisis.",
+			want: "english",
+		},
+		{
+			name: "serbian latin",
+			text: "Ministar je rekao da će novi zakon zaštititi radnike i pomoći malim preduzećima da se oporave posle krize.",
+			want: "serbian",
+		},
+		{
+			name: "serbian latin without diacritics",
+			text: "Ministar je rekao da ce novi zakon zastititi radnike i pomoci malim preduzecima da se oporave posle krize.",
+			want: "serbian",
+		},
+		{
+			name: "serbian cyrillic",
+			text: "Министар је рекао да ће нови закон заштитити раднике и помоћи малим предузећима да се опораве после кризе.",
+			want: "serbian",
+		},
+		{
+			name: "german is not supported",
+			text: "Der Minister sagte, das neue Gesetz werde die Arbeitnehmer schützen und kleinen Unternehmen helfen, sich nach der Krise zu erholen.",
+			want: "",
+		},
+		{
+			name: "french is not supported",
+			text: "Le ministre a déclaré que la nouvelle loi protégera les travailleurs et aidera les petites entreprises à se remettre de la crise.",
+			want: "",
+		},
+		{
+			name: "russian cyrillic is not serbian",
+			text: "Министр заявил, что новый закон защитит работников и поможет малому бизнесу восстановиться после кризиса.",
+			want: "",
+		},
+		{
+			name: "too short",
+			text: "OK 2024",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := d.Detect(tt.text)
+			if got.Language != tt.want {
+				t.Fatalf("Detect() language = %q (confidence %.3f), want %q", got.Language, got.Confidence, tt.want)
+			}
+			if tt.want != "" && got.Confidence < 0.9 {
+				t.Errorf("Detect() confidence = %.3f, want >= 0.9", got.Confidence)
+			}
+		})
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for lang, want := range map[string]bool{"english": true, "serbian": true, "german": false, "en": false} {
+		if got := Supported(lang); got != want {
+			t.Errorf("Supported(%q) = %v, want %v", lang, got, want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"en":        "english",
+		" English ": "english",
+		"sr":        "serbian",
+		"srpski":    "serbian",
+		"Serbian":   "serbian",
+		"de":        "de",
+		"":          "",
+	} {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ingest
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/ingest/langdetect"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+)
+
+// DefaultLanguageMinConfidence is the detection confidence required to fill a missing
+// language or to flag a mismatch with the mapped one
+const DefaultLanguageMinConfidence = 0.8
+
+// LanguageDetector identifies the language of article text
+type LanguageDetector interface {
+	Detect(text string) langdetect.Detection
+}
+
+// WithLanguageDetector enables the language detection stage. Articles without a
+// language get the detected one when confidence >= minConfidence; articles with a
+// mapped language keep it and are flagged when detection disagrees, as long as the
+// detector could have recognised the mapped language.
+func WithLanguageDetector(detector LanguageDetector, minConfidence float64) PipelineOption {
+	return func(pipeline *ArticlePipeline) {
+		pipeline.langDetector = detector
+		pipeline.langMinConfidence = minConfidence
+	}
+}
+
+// detectLanguage runs the language detection stage on a single article.
+// The resulting Language selects the PG text search config (article_ts_config)
+// and the ES per-language fields (title_sr, ...) the text is indexed in.
+func (p *ArticlePipeline) detectLanguage(article document.Article) document.Article {
+	if p.langDetector == nil {
+		return article
+	}
+
+	detection := p.langDetector.Detect(strings.Join([]string{article.Title, article.Description, article.Content}, " "))
+	if detection.Language == "" {
+		return article
+	}
+
+	article.Metadata.DetectedLanguage = detection.Language
+	article.Metadata.LanguageConfidence = utils.RoundFloat64(detection.Confidence, 4)
+	reliable := detection.Confidence >= p.langMinConfidence
+
+	mapped := langdetect.Normalize(article.Language)
+	switch {
+	case mapped == "":
+		if reliable {
+			article.Language = detection.Language
+		}
+	case mapped != detection.Language && reliable && langdetect.Supported(mapped):
+		article.Metadata.LanguageMismatch = true
+		slog.Warn("Mapped article language differs from detected language",
+			"pipeline", p.config.Name,
+			"title", article.Title,
+			"mapped", article.Language,
+			"detected", detection.Language,
+			"confidence", detection.Confidence,
+		)
+	}
+
+	return article
+}
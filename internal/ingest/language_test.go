@@ -0,0 +1,85 @@
+package ingest
+
+import (
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/ingest/langdetect"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+)
+
+type stubDetector struct {
+	detection langdetect.Detection
+}
+
+func (s stubDetector) Detect(string) langdetect.Detection {
+	return s.detection
+}
+
+func TestArticlePipeline_DetectLanguage(t *testing.T) {
+	tests := []struct {
+		name         string
+		mapped       string
+		detection    langdetect.Detection
+		wantLanguage string
+		wantDetected string
+		wantMismatch bool
+	}{
+		{
+			name:         "fills missing language",
+			detection:    langdetect.Detection{Language: "serbian", Confidence: 0.99},
+			wantLanguage: "serbian",
+			wantDetected: "serbian",
+		},
+		{
+			name:         "low confidence keeps missing language",
+			detection:    langdetect.Detection{Language: "serbian", Confidence: 0.6},
+			wantLanguage: "",
+			wantDetected: "serbian",
+		},
+		{
+			name:         "mapped code agrees with detection",
+			mapped:       "sr",
+			detection:    langdetect.Detection{Language: "serbian", Confidence: 0.99},
+			wantLanguage: "sr",
+			wantDetected: "serbian",
+		},
+		{
+			name:         "mapped language mismatch is flagged and kept",
+			mapped:       "english",
+			detection:    langdetect.Detection{Language: "serbian", Confidence: 0.95},
+			wantLanguage: "english",
+			wantDetected: "serbian",
+			wantMismatch: true,
+		},
+		{
+			name:         "mapped unsupported language is not a mismatch",
+			mapped:       "german",
+			detection:    langdetect.Detection{Language: "english", Confidence: 0.95},
+			wantLanguage: "german",
+			wantDetected: "english",
+		},
+		{
+			name:         "undetermined text is left untouched",
+			mapped:       "english",
+			detection:    langdetect.Detection{},
+			wantLanguage: "english",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPipeline(nil, nil, WithLanguageDetector(stubDetector{detection: tt.detection}, DefaultLanguageMinConfidence))
+
+			got := p.detectLanguage(document.Article{Title: "t", Language: tt.mapped})
+			if got.Language != tt.wantLanguage {
+				t.Errorf("Language = %q, want %q", got.Language, tt.wantLanguage)
+			}
+			if got.Metadata.DetectedLanguage != tt.wantDetected {
+				t.Errorf("DetectedLanguage = %q, want %q", got.Metadata.DetectedLanguage, tt.wantDetected)
+			}
+			if got.Metadata.LanguageMismatch != tt.wantMismatch {
+				t.Errorf("LanguageMismatch = %v, want %v", got.Metadata.LanguageMismatch, tt.wantMismatch)
+			}
+		})
+	}
+}
@@ -41,6 +41,9 @@ type ArticlePipeline struct {
 
 	embedder     *embedding.Embedder
 	embedIndexer storage.EmbedIndexer
+
+	langDetector      LanguageDetector
+	langMinConfidence float64
 }
 
 type PipelineOption func(pipeline *ArticlePipeline)
@@ -114,6 +117,13 @@ func (p *ArticlePipeline) Run(ctx context.Context) error {
 		runErr = p.processBasic(ctx, results)
 	}
 
+	// Derived search structures are rebuilt once per run; failures leave the stored articles intact
+	if refresher, ok := p.storer.(storage.IndexRefresher); ok {
+		if err := refresher.Refresh(ctx); err != nil {
+			slog.Error("Error refreshing index", "error", err, "pipeline", p.config.Name)
+		}
+	}
+
 	duration := time.Since(start)
 	slog.Info("Pipeline run completed",
 		"pipeline", p.config.Name,
@@ -154,17 +164,18 @@ func (p *ArticlePipeline) processBasic(ctx context.Context, results <-chan Resul
 				continue
 			}
 
-			if id, err := p.storer.Save(ctx, res.Result); err != nil {
+			article := p.detectLanguage(res.Result)
+			if id, err := p.storer.Save(ctx, article); err != nil {
 				slog.Error("Error saving article",
 					"error", err,
 					"pipeline", p.config.Name,
-					"title", res.Result.Title,
+					"title", article.Title,
 				)
 				errorCount++
 			} else {
 				slog.Debug("Vec saved successfully",
 					"id", id,
-					"title", res.Result.Title,
+					"title", article.Title,
 					"pipeline", p.config.Name,
 				)
 				processedCount++
@@ -226,7 +237,7 @@ func (p *ArticlePipeline) processBatch(ctx context.Context, results <-chan Resul
 				continue
 			}
 
-			articles = append(articles, res.Result)
+			articles = append(articles, p.detectLanguage(res.Result))
 
 			if len(articles) >= p.config.Bulk.Size {
 				if err := p.storer.SaveBulk(ctx, articles); err != nil {
@@ -0,0 +1,158 @@
+package ranking
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// DefaultCalibration is the calibration key used for query kinds without their own sigmoid
+const DefaultCalibration = "default"
+
+// Sigmoid maps a raw score s to 1 / (1 + exp(-(A*s + B))), the probability that a hit
+// scoring s is relevant (Platt scaling)
+type Sigmoid struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+}
+
+func (s Sigmoid) Normalize(score float64, _ int64) float64 {
+	return 1 / (1 + math.Exp(-(s.A*score + s.B)))
+}
+
+// Sample is a judged hit: its raw score and whether it was judged relevant
+type Sample struct {
+	Score    float64
+	Relevant bool
+}
+
+const (
+	fitMaxIterations = 100
+	fitMinStep       = 1e-10
+	fitEpsilon       = 1e-5
+	fitRidge         = 1e-12
+)
+
+// FitSigmoid fits a sigmoid to judged hits by maximum likelihood (Platt scaling, with
+// Platt's smoothed targets so a separable sample does not diverge), using Newton's method
+// with a backtracking line search. It needs both relevant and non-relevant samples.
+func FitSigmoid(samples []Sample) (Sigmoid, error) {
+	var positives, negatives float64
+	for _, s := range samples {
+		if s.Relevant {
+			positives++
+		} else {
+			negatives++
+		}
+	}
+	if positives == 0 || negatives == 0 {
+		return Sigmoid{}, fmt.Errorf("sigmoid calibration needs relevant and non-relevant samples, got %.0f and %.0f", positives, negatives)
+	}
+
+	hiTarget, loTarget := (positives+1)/(positives+2), 1/(negatives+2)
+	targets := make([]float64, len(samples))
+	for i, s := range samples {
+		targets[i] = loTarget
+		if s.Relevant {
+			targets[i] = hiTarget
+		}
+	}
+
+	loss := func(a, b float64) float64 {
+		var l float64
+		for i, s := range samples {
+			z := a*s.Score + b
+			l += targets[i]*softplus(-z) + (1-targets[i])*softplus(z)
+		}
+		return l
+	}
+
+	sig := Sigmoid{B: math.Log((positives + 1) / (negatives + 1))}
+	current := loss(sig.A, sig.B)
+	for range fitMaxIterations {
+		var gA, gB, hAA, hAB, hBB float64
+		for i, s := range samples {
+			p := sig.Normalize(s.Score, 0)
+			d := p - targets[i]
+			w := p * (1 - p)
+			gA += d * s.Score
+			gB += d
+			hAA += w * s.Score * s.Score
+			hAB += w * s.Score
+			hBB += w
+		}
+		if math.Abs(gA) < fitEpsilon && math.Abs(gB) < fitEpsilon {
+			break
+		}
+
+		hAA, hBB = hAA+fitRidge, hBB+fitRidge
+		det := hAA*hBB - hAB*hAB
+		dA := -(hBB*gA - hAB*gB) / det
+		dB := -(hAA*gB - hAB*gA) / det
+		descent := gA*dA + gB*dB
+
+		step := 1.0
+		for step >= fitMinStep {
+			a, b := sig.A+step*dA, sig.B+step*dB
+			if next := loss(a, b); next < current+1e-4*step*descent {
+				sig, current = Sigmoid{A: a, B: b}, next
+				break
+			}
+			step /= 2
+		}
+		if step < fitMinStep {
+			break
+		}
+	}
+	return sig, nil
+}
+
+// softplus is log(1 + exp(x)), computed without overflow
+func softplus(x float64) float64 {
+	if x > 0 {
+		return x + math.Log1p(math.Exp(-x))
+	}
+	return math.Log1p(math.Exp(x))
+}
+
+// Calibrations holds fitted sigmoids keyed by query kind (match, multi_match, semantic, ...),
+// with DefaultCalibration as the fallback. Raw scores depend on the backend, so a
+// calibration file belongs to the backend it was fitted on.
+type Calibrations map[string]Sigmoid
+
+// For returns the sigmoid of a query kind, falling back to the default calibration
+func (c Calibrations) For(kind string) (Sigmoid, bool) {
+	if s, ok := c[kind]; ok {
+		return s, true
+	}
+	s, ok := c[DefaultCalibration]
+	return s, ok
+}
+
+// ReadCalibrations reads a calibration file: a JSON object of sigmoids keyed by query kind
+// Example: {"match": {"a": 0.42, "b": -2.1}, "default": {"a": 0.38, "b": -1.9}}
+func ReadCalibrations(path string) (Calibrations, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calibrations: %w", err)
+	}
+
+	var c Calibrations
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse calibrations %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// WriteCalibrations writes a calibration file, indented for review
+func WriteCalibrations(path string, c Calibrations) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal calibrations: %w", err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write calibrations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package ranking
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFitSigmoid(t *testing.T) {
+	// Relevance grows with the score and the classes overlap between 3 and 5
+	var samples []Sample
+	for _, s := range []float64{0.5, 1, 1.5, 2, 2.5, 3, 4} {
+		samples = append(samples, Sample{Score: s})
+	}
+	for _, s := range []float64{3.5, 4.5, 5, 6, 7, 8, 9} {
+		samples = append(samples, Sample{Score: s, Relevant: true})
+	}
+
+	sig, err := FitSigmoid(samples)
+	if err != nil {
+		t.Fatalf("FitSigmoid() error: %v", err)
+	}
+	if sig.A <= 0 {
+		t.Fatalf("A = %v, want a sigmoid increasing with the score", sig.A)
+	}
+	if p := sig.Normalize(1, 0); p > 0.2 {
+		t.Errorf("P(relevant | 1) = %v, want below 0.2", p)
+	}
+	if p := sig.Normalize(8, 0); p < 0.8 {
+		t.Errorf("P(relevant | 8) = %v, want above 0.8", p)
+	}
+	if mid := -sig.B / sig.A; mid < 3 || mid > 5 {
+		t.Errorf("the sigmoid crosses 0.5 at %v, want within the overlap", mid)
+	}
+}
+
+func TestFitSigmoid_SeparableSamplesConverge(t *testing.T) {
+	samples := []Sample{{Score: 1}, {Score: 2}, {Score: 10, Relevant: true}, {Score: 11, Relevant: true}}
+
+	sig, err := FitSigmoid(samples)
+	if err != nil {
+		t.Fatalf("FitSigmoid() error: %v", err)
+	}
+	// Platt's smoothed targets cap the fitted probabilities at 3/4 and 1/4
+	if p := sig.Normalize(11, 0); p > 0.9 {
+		t.Errorf("P(relevant | 11) = %v, want the smoothed target to bound the fit", p)
+	}
+}
+
+func TestFitSigmoid_OneClass(t *testing.T) {
+	if _, err := FitSigmoid([]Sample{{Score: 1, Relevant: true}, {Score: 2, Relevant: true}}); err == nil {
+		t.Error("FitSigmoid() without non-relevant samples should fail")
+	}
+}
+
+func TestCalibrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calibration.json")
+	want := Calibrations{"match": {A: 0.4, B: -2}, DefaultCalibration: {A: 0.3, B: -1.5}}
+	if err := WriteCalibrations(path, want); err != nil {
+		t.Fatalf("WriteCalibrations() error: %v", err)
+	}
+
+	got, err := ReadCalibrations(path)
+	if err != nil {
+		t.Fatalf("ReadCalibrations() error: %v", err)
+	}
+	if s, ok := got.For("match"); !ok || s != want["match"] {
+		t.Errorf("For(match) = %+v, %v", s, ok)
+	}
+	if s, ok := got.For("phrase"); !ok || s != want[DefaultCalibration] {
+		t.Errorf("For(phrase) = %+v, %v, want the default calibration", s, ok)
+	}
+	if _, ok := (Calibrations{"match": {A: 1}}).For("phrase"); ok {
+		t.Error("For() without a default calibration should report no sigmoid")
+	}
+}
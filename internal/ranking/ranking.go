@@ -0,0 +1,193 @@
+// Package ranking normalizes engine scores to comparable 0–1 scores.
+//
+// Raw scores are not comparable across backends or query kinds: BM25 in Elasticsearch,
+// ts_rank in PostgreSQL, cosine similarity for semantic search and RRF for hybrid search
+// all live on different scales. A Normalizer maps a raw score, given the statistics of
+// the scores of the whole match set and the rank of the hit, to [0, 1].
+package ranking
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Method names a score normalization
+type Method string
+
+const (
+	// MethodMax divides the score by the highest score of the match set (the default)
+	MethodMax Method = "max"
+	// MethodMinMax rescales the score between the lowest and highest score of the match set
+	MethodMinMax Method = "min_max"
+	// MethodZScore maps the standard score of the score through the standard normal CDF
+	MethodZScore Method = "z_score"
+	// MethodRank ignores the score: 1 for the first hit, decreasing linearly with the rank
+	MethodRank Method = "rank"
+	// MethodSigmoid maps the score through a sigmoid fitted on relevance judgments, so the
+	// normalized score estimates the probability the hit is relevant
+	MethodSigmoid Method = "sigmoid"
+)
+
+// DefaultMethod is the normalization used when none is requested
+const DefaultMethod = MethodMax
+
+// Methods lists the supported normalization methods
+var Methods = []Method{MethodMax, MethodMinMax, MethodZScore, MethodRank, MethodSigmoid}
+
+// ParseMethod parses a normalization method; an empty string is the default method
+func ParseMethod(s string) (Method, error) {
+	if s == "" {
+		return DefaultMethod, nil
+	}
+	for _, m := range Methods {
+		if string(m) == s {
+			return m, nil
+		}
+	}
+
+	names := make([]string, len(Methods))
+	for i, m := range Methods {
+		names[i] = string(m)
+	}
+	return "", fmt.Errorf("unknown normalization %q, expected one of %s", s, strings.Join(names, ", "))
+}
+
+// NeedsDistribution reports whether the method needs the minimum, mean and standard
+// deviation of the match set, beyond its maximum and size
+func (m Method) NeedsDistribution() bool {
+	return m == MethodMinMax || m == MethodZScore
+}
+
+// Stats describes the raw scores of a match set
+type Stats struct {
+	Count  int64
+	Max    float64
+	Min    float64
+	Mean   float64
+	StdDev float64 // population standard deviation
+}
+
+// StatsOf computes the statistics of the given scores
+func StatsOf(scores []float64) Stats {
+	if len(scores) == 0 {
+		return Stats{}
+	}
+
+	s := Stats{Count: int64(len(scores)), Max: scores[0], Min: scores[0]}
+	var sum float64
+	for _, v := range scores {
+		s.Max = math.Max(s.Max, v)
+		s.Min = math.Min(s.Min, v)
+		sum += v
+	}
+	s.Mean = sum / float64(len(scores))
+
+	var squares float64
+	for _, v := range scores {
+		squares += (v - s.Mean) * (v - s.Mean)
+	}
+	s.StdDev = math.Sqrt(squares / float64(len(scores)))
+	return s
+}
+
+// Normalizer maps a raw score to [0, 1]. rank is the 1-based position of the hit in
+// the match set.
+type Normalizer interface {
+	Normalize(score float64, rank int64) float64
+}
+
+// Spec selects a normalization; the zero value is the default method
+type Spec struct {
+	Method Method
+	// Sigmoid holds the calibrated sigmoid, required by MethodSigmoid
+	Sigmoid *Sigmoid
+}
+
+// Validate checks that the method is known and that a sigmoid is calibrated when needed
+func (s Spec) Validate() error {
+	if _, err := ParseMethod(string(s.Method)); err != nil {
+		return err
+	}
+	if s.Method == MethodSigmoid && s.Sigmoid == nil {
+		return fmt.Errorf("normalization %q requires a calibrated sigmoid", MethodSigmoid)
+	}
+	return nil
+}
+
+// NeedsDistribution reports whether the normalizer needs the full Stats of the match set
+func (s Spec) NeedsDistribution() bool {
+	return s.Method.NeedsDistribution()
+}
+
+// Normalizer builds the normalizer of the spec over the statistics of a match set
+func (s Spec) Normalizer(stats Stats) Normalizer {
+	switch s.Method {
+	case MethodMinMax:
+		return minMaxNormalizer{min: stats.Min, max: stats.Max}
+	case MethodZScore:
+		return zScoreNormalizer{mean: stats.Mean, stdDev: stats.StdDev}
+	case MethodRank:
+		return rankNormalizer{count: stats.Count}
+	case MethodSigmoid:
+		if s.Sigmoid != nil {
+			return *s.Sigmoid
+		}
+	}
+	return maxNormalizer{max: stats.Max}
+}
+
+// maxNormalizer divides by the highest score; a non-positive maximum leaves scores as-is
+type maxNormalizer struct {
+	max float64
+}
+
+func (n maxNormalizer) Normalize(score float64, _ int64) float64 {
+	if n.max <= 0 {
+		return clamp(score)
+	}
+	return clamp(score / n.max)
+}
+
+// minMaxNormalizer rescales between the lowest and highest score; when all scores are
+// equal every hit scores 1
+type minMaxNormalizer struct {
+	min, max float64
+}
+
+func (n minMaxNormalizer) Normalize(score float64, _ int64) float64 {
+	if n.max <= n.min {
+		return 1
+	}
+	return clamp((score - n.min) / (n.max - n.min))
+}
+
+// zScoreNormalizer maps the standard score through the standard normal CDF, so a hit
+// scoring the mean gets 0.5; when all scores are equal every hit gets 0.5
+type zScoreNormalizer struct {
+	mean, stdDev float64
+}
+
+func (n zScoreNormalizer) Normalize(score float64, _ int64) float64 {
+	if n.stdDev == 0 {
+		return 0.5
+	}
+	z := (score - n.mean) / n.stdDev
+	return clamp(0.5 * math.Erfc(-z/math.Sqrt2))
+}
+
+// rankNormalizer scores 1 - (rank - 1) / count: 1 for the first hit, 1/count for the last
+type rankNormalizer struct {
+	count int64
+}
+
+func (n rankNormalizer) Normalize(_ float64, rank int64) float64 {
+	if n.count <= 0 {
+		return 1
+	}
+	return clamp(1 - float64(rank-1)/float64(n.count))
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,86 @@
+package ranking
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseMethod(t *testing.T) {
+	if m, err := ParseMethod(""); err != nil || m != DefaultMethod {
+		t.Errorf("ParseMethod(\"\") = %q, %v, want the default method", m, err)
+	}
+	if m, err := ParseMethod("z_score"); err != nil || m != MethodZScore {
+		t.Errorf("ParseMethod(z_score) = %q, %v", m, err)
+	}
+	if _, err := ParseMethod("softmax"); err == nil {
+		t.Error("ParseMethod(softmax) should fail")
+	}
+}
+
+func TestStatsOf(t *testing.T) {
+	s := StatsOf([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	if s.Count != 8 || s.Min != 2 || s.Max != 9 || s.Mean != 5 || s.StdDev != 2 {
+		t.Errorf("StatsOf() = %+v", s)
+	}
+	if s := StatsOf(nil); s != (Stats{}) {
+		t.Errorf("StatsOf(nil) = %+v, want zero stats", s)
+	}
+}
+
+func TestSpec_Normalizer(t *testing.T) {
+	stats := Stats{Count: 4, Min: 2, Max: 10, Mean: 6, StdDev: 2}
+
+	tests := []struct {
+		name  string
+		spec  Spec
+		score float64
+		rank  int64
+		want  float64
+	}{
+		{name: "default is max", spec: Spec{}, score: 5, want: 0.5},
+		{name: "max", spec: Spec{Method: MethodMax}, score: 10, want: 1},
+		{name: "min_max lowest", spec: Spec{Method: MethodMinMax}, score: 2, want: 0},
+		{name: "min_max middle", spec: Spec{Method: MethodMinMax}, score: 6, want: 0.5},
+		{name: "z_score mean", spec: Spec{Method: MethodZScore}, score: 6, want: 0.5},
+		{name: "z_score one deviation", spec: Spec{Method: MethodZScore}, score: 8, want: 0.841345},
+		{name: "rank first", spec: Spec{Method: MethodRank}, score: 2, rank: 1, want: 1},
+		{name: "rank last", spec: Spec{Method: MethodRank}, score: 10, rank: 4, want: 0.25},
+		{name: "sigmoid", spec: Spec{Method: MethodSigmoid, Sigmoid: &Sigmoid{A: 1, B: -6}}, score: 6, want: 0.5},
+		{name: "negative scores clamp", spec: Spec{Method: MethodMax}, score: -1, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.spec.Normalizer(stats).Normalize(tt.score, tt.rank)
+			if math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("Normalize(%g, %d) = %v, want %v", tt.score, tt.rank, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpec_Normalizer_DegenerateStats(t *testing.T) {
+	flat := Stats{Count: 3, Min: 1.5, Max: 1.5, Mean: 1.5}
+
+	if got := (Spec{Method: MethodMinMax}).Normalizer(flat).Normalize(1.5, 2); got != 1 {
+		t.Errorf("min_max over equal scores = %v, want 1", got)
+	}
+	if got := (Spec{Method: MethodZScore}).Normalizer(flat).Normalize(1.5, 2); got != 0.5 {
+		t.Errorf("z_score over equal scores = %v, want 0.5", got)
+	}
+	if got := (Spec{}).Normalizer(Stats{}).Normalize(0.3, 1); got != 0.3 {
+		t.Errorf("max without a positive maximum = %v, want the score as-is", got)
+	}
+}
+
+func TestSpec_Validate(t *testing.T) {
+	if err := (Spec{Method: MethodSigmoid}).Validate(); err == nil {
+		t.Error("sigmoid without a calibration should be rejected")
+	}
+	if err := (Spec{Method: MethodSigmoid, Sigmoid: &Sigmoid{A: 1}}).Validate(); err != nil {
+		t.Errorf("calibrated sigmoid: %v", err)
+	}
+	if err := (Spec{}).Validate(); err != nil {
+		t.Errorf("zero spec: %v", err)
+	}
+}
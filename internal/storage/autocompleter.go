@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// Autocompleter completes a type-ahead prefix to article titles.
+// Completions are distinct by title, best first, at most size.
+type Autocompleter interface {
+	Autocomplete(ctx context.Context, prefix string, lang query.Language, size int) ([]query.Completion, error)
+}
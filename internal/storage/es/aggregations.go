@@ -0,0 +1,184 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/calendarinterval"
+)
+
+// dateFields maps date fields to date fields of the index mapping.
+var dateFields = map[dquery.DateField]string{
+	dquery.DatePublishedAt: "published_at",
+	dquery.DateCreatedAt:   "created_at",
+}
+
+// buildAggregations compiles facets into native ES aggregations keyed by aggregation name.
+// Aggregations run over every document matching the query (including filters), independent
+// of search_after paging. Returns nil when no aggregation is requested.
+func buildAggregations(aggs []dquery.Aggregation) (map[string]types.Aggregations, error) {
+	if len(aggs) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]types.Aggregations, len(aggs))
+	for _, agg := range aggs {
+		switch agg.Kind {
+		case dquery.TermsAggregation:
+			field, ok := filterFields[dquery.FilterField(agg.Field)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported terms aggregation field: %s", agg.Field)
+			}
+			size := agg.Size
+			result[agg.Name] = types.Aggregations{
+				Terms: &types.TermsAggregation{Field: &field, Size: &size},
+			}
+		case dquery.DateHistogramAggregation:
+			field, ok := dateFields[dquery.DateField(agg.Field)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported date_histogram field: %s", agg.Field)
+			}
+			// min_doc_count=1 drops empty gaps, matching the GROUP BY semantics of the PG backend
+			minDocCount := 1
+			timeZone := "UTC"
+			result[agg.Name] = types.Aggregations{
+				DateHistogram: &types.DateHistogramAggregation{
+					Field:            &field,
+					CalendarInterval: &calendarinterval.CalendarInterval{Name: string(agg.Interval)},
+					MinDocCount:      &minDocCount,
+					TimeZone:         &timeZone,
+				},
+			}
+		default:
+			return nil, fmt.Errorf("unsupported aggregation kind: %s", agg.Kind)
+		}
+	}
+
+	return result, nil
+}
+
+// scoreStatsAggregation names the aggregation computing the score statistics of the match
+// set, requested only by normalizations that need the score distribution
+const scoreStatsAggregation = "_score_stats"
+
+// buildScoreStatsAggregation computes extended stats over the score of every matching document
+func buildScoreStatsAggregation() types.Aggregations {
+	source := "_score"
+	return types.Aggregations{
+		ExtendedStats: &types.ExtendedStatsAggregation{Script: &types.Script{Source: &source}},
+	}
+}
+
+// scoreStats is the part of an extended_stats response normalization needs
+type scoreStats struct {
+	Count  int64    `json:"count"`
+	Min    *float64 `json:"min"`
+	Max    *float64 `json:"max"`
+	Avg    *float64 `json:"avg"`
+	StdDev *float64 `json:"std_deviation_population"`
+}
+
+// mapScoreStats reads the score statistics of the match set. Without the score stats
+// aggregation, only the size and max score of the match set are known.
+func mapScoreStats(resAggs map[string]types.Aggregate, total int64, maxScore float64) (ranking.Stats, error) {
+	stats := ranking.Stats{Count: total, Max: maxScore, Min: maxScore, Mean: maxScore}
+	raw, ok := resAggs[scoreStatsAggregation]
+	if !ok {
+		return stats, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return ranking.Stats{}, fmt.Errorf("failed to encode score stats: %w", err)
+	}
+	var parsed scoreStats
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return ranking.Stats{}, fmt.Errorf("failed to decode score stats: %w", err)
+	}
+
+	stats.Count = parsed.Count
+	for _, v := range []struct {
+		dst *float64
+		src *float64
+	}{{&stats.Min, parsed.Min}, {&stats.Max, parsed.Max}, {&stats.Mean, parsed.Avg}, {&stats.StdDev, parsed.StdDev}} {
+		if v.src != nil {
+			*v.dst = *v.src
+		}
+	}
+	return stats, nil
+}
+
+// aggregateBuckets is the bucket shape shared by sterms/lterms and date_histogram responses.
+type aggregateBuckets struct {
+	Buckets []struct {
+		Key      json.RawMessage `json:"key"`
+		DocCount int64           `json:"doc_count"`
+	} `json:"buckets"`
+}
+
+// mapAggregations converts ES aggregate responses into backend-neutral buckets.
+// Typed aggregates are round-tripped through JSON so that every terms variant
+// (string, long, ...) and the date histogram share one decoding path.
+func mapAggregations(aggs []dquery.Aggregation, resAggs map[string]types.Aggregate) (map[string]dquery.AggregationResult, error) {
+	if len(aggs) == 0 {
+		return nil, nil
+	}
+
+	results := dquery.EmptyAggregationResults(aggs)
+	for _, agg := range aggs {
+		raw, ok := resAggs[agg.Name]
+		if !ok {
+			continue
+		}
+
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode aggregation %s: %w", agg.Name, err)
+		}
+		var parsed aggregateBuckets
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return nil, fmt.Errorf("failed to decode aggregation %s: %w", agg.Name, err)
+		}
+
+		buckets := make([]dquery.Bucket, 0, len(parsed.Buckets))
+		for _, b := range parsed.Buckets {
+			key, err := bucketKey(agg.Kind, b.Key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bucket key in aggregation %s: %w", agg.Name, err)
+			}
+			buckets = append(buckets, dquery.Bucket{Key: key, DocCount: b.DocCount})
+		}
+		results[agg.Name] = dquery.AggregationResult{Buckets: buckets}
+	}
+
+	return results, nil
+}
+
+// bucketKey renders a bucket key; date histogram keys (epoch millis) become RFC3339 UTC timestamps.
+func bucketKey(kind dquery.AggregationKind, raw json.RawMessage) (string, error) {
+	if kind == dquery.DateHistogramAggregation {
+		var millis int64
+		if err := json.Unmarshal(raw, &millis); err != nil {
+			return "", err
+		}
+		return time.UnixMilli(millis).UTC().Format(time.RFC3339), nil
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return "", err
+	}
+	if i, err := n.Int64(); err == nil {
+		return strconv.FormatInt(i, 10), nil
+	}
+	return n.String(), nil
+}
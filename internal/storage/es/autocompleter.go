@@ -0,0 +1,106 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/operator"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/textquerytype"
+	"github.com/google/uuid"
+)
+
+const (
+	autocompleteAnalyzer = "autocomplete_analyzer"
+	autocompleteSubField = "autocomplete"
+	// autocompleteField is the search_as_you_type sub-field of title (see createTitleProperty)
+	autocompleteField = "title." + autocompleteSubField
+)
+
+// autocompleteFields are the search_as_you_type sub-fields matched by bool_prefix:
+// the root field and its shingle sub-fields (the _index_prefix sub-field is used
+// implicitly for the last term).
+var autocompleteFields = []string{
+	autocompleteField,
+	autocompleteField + "._2gram",
+	autocompleteField + "._3gram",
+}
+
+type Autocompleter struct {
+	client    *elasticsearch.TypedClient
+	indexName string
+}
+
+func NewAutocompleter(config ClientConfig) (*Autocompleter, error) {
+	client, err := newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Elasticsearch client: %w", err)
+	}
+	return &Autocompleter{
+		client:    client,
+		indexName: config.IndexName,
+	}, nil
+}
+
+// Autocomplete implements storage.Autocompleter with a bool_prefix multi_match over the
+// title.autocomplete sub-field. The sub-field is analyzed language-neutrally (lowercase,
+// Cyrillic → Latin, diacritics folded), so lang is not needed to pick a field.
+func (a *Autocompleter) Autocomplete(ctx context.Context, prefix string, _ dquery.Language, size int) ([]dquery.Completion, error) {
+	prefix = dquery.NormalizePrefix(prefix)
+	if prefix == "" {
+		return []dquery.Completion{}, nil
+	}
+
+	slog.Debug("Executing es autocomplete", "prefix", prefix, "size", size)
+
+	and := operator.And
+	res, err := a.client.Search().
+		Index(a.indexName).
+		Query(&types.Query{
+			MultiMatch: &types.MultiMatchQuery{
+				Query:    prefix,
+				Type:     &textquerytype.Boolprefix,
+				Fields:   autocompleteFields,
+				Operator: &and,
+			},
+		}).
+		SourceIncludes_("id", "title").
+		TrackTotalHits(false).
+		Size(size * dquery.AutocompleteCandidateFactor).
+		Do(ctx)
+	if err != nil {
+		slog.Error("Failed to execute autocomplete", "error", err)
+		return nil, fmt.Errorf("failed to execute autocomplete: %w", err)
+	}
+
+	candidates := make([]dquery.Completion, 0, len(res.Hits.Hits))
+	for _, hit := range res.Hits.Hits {
+		var doc struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal(hit.Source_, &doc); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal completion: %w", err)
+		}
+		id, err := uuid.Parse(doc.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid article id %q: %w", doc.ID, err)
+		}
+		var score float64
+		if hit.Score_ != nil {
+			score = utils.RoundFloat64(float64(*hit.Score_), dquery.ScoreDecimalPlaces)
+		}
+		candidates = append(candidates, dquery.Completion{ID: id, Title: doc.Title, Score: score})
+	}
+
+	return dquery.DistinctCompletions(candidates, size), nil
+}
+
+// Compile-time interface assertions
+var _ storage.Autocompleter = (*Autocompleter)(nil)
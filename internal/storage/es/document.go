@@ -1,9 +1,12 @@
 package es
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/densevectorsimilarity"
 	"github.com/google/uuid"
@@ -26,6 +29,21 @@ type ArticleDocument struct {
 	Category    string    `json:"category"`
 	ImportedAt  time.Time `json:"imported_at"`
 	IndexedAt   time.Time `json:"indexed_at"`
+
+	DetectedLanguage   string  `json:"detected_language,omitempty"`
+	LanguageConfidence float64 `json:"language_confidence,omitempty"`
+	LanguageMismatch   bool    `json:"language_mismatch,omitempty"`
+
+	// Per-language copies of the text fields, analyzed by the analyzer of their language.
+	// Only the copies of the article language are filled (see setLanguageFields).
+	TitleEn       string `json:"title_en,omitempty"`
+	SubtitleEn    string `json:"subtitle_en,omitempty"`
+	DescriptionEn string `json:"description_en,omitempty"`
+	ContentEn     string `json:"content_en,omitempty"`
+	TitleSr       string `json:"title_sr,omitempty"`
+	SubtitleSr    string `json:"subtitle_sr,omitempty"`
+	DescriptionSr string `json:"description_sr,omitempty"`
+	ContentSr     string `json:"content_sr,omitempty"`
 }
 
 type IndexBuilder struct {
@@ -42,7 +60,10 @@ func (b *IndexBuilder) mapToESDocument(article document.Article) ArticleDocument
 	if article.ID == uuid.Nil {
 		article.ID = uuid.New()
 	}
-	return ArticleDocument{
+	// The language selects the per-language fields (title_en, title_sr, ...) the text is
+	// copied to; unknown values keep the default.
+	article.Language = b.normalizeLanguage(article.Language)
+	doc := ArticleDocument{
 		ID:          article.ID.String(),
 		Title:       article.Title,
 		Subtitle:    article.Subtitle,
@@ -58,43 +79,129 @@ func (b *IndexBuilder) mapToESDocument(article document.Article) ArticleDocument
 		Category:    article.Metadata.Category,
 		ImportedAt:  article.Metadata.ImportedAt,
 		IndexedAt:   time.Now(),
+
+		DetectedLanguage:   article.Metadata.DetectedLanguage,
+		LanguageConfidence: article.Metadata.LanguageConfidence,
+		LanguageMismatch:   article.Metadata.LanguageMismatch,
+	}
+	doc.setLanguageFields()
+	return doc
+}
+
+// setLanguageFields copies the text fields to the per-language fields of the document
+// language, so English stemming never runs on Serbian text and the other way round.
+// Languages without an analyzer are only indexed in the language-neutral base fields.
+func (d *ArticleDocument) setLanguageFields() {
+	switch languageSuffixes[dquery.Language(d.Language)] {
+	case "en":
+		d.TitleEn, d.SubtitleEn, d.DescriptionEn, d.ContentEn = d.Title, d.Subtitle, d.Description, d.Content
+	case "sr":
+		d.TitleSr, d.SubtitleSr, d.DescriptionSr, d.ContentSr = d.Title, d.Subtitle, d.Description, d.Content
+	}
+}
+
+// toDomain maps a stored ES document back to the domain article
+func (d ArticleDocument) toDomain() (document.Article, error) {
+	id, err := uuid.Parse(d.ID)
+	if err != nil {
+		return document.Article{}, fmt.Errorf("parse document id %q: %w", d.ID, err)
+	}
+	return document.Article{
+		ID:          id,
+		Title:       d.Title,
+		Subtitle:    d.Subtitle,
+		Content:     d.Content,
+		Author:      d.Author,
+		Description: d.Description,
+		Language:    d.Language,
+		CreatedAt:   d.CreatedAt,
+		URL:         d.URL,
+		Metadata: document.ArticleMetadata{
+			SourceId:    d.SourceId,
+			SourceName:  d.SourceName,
+			PublishedAt: d.PublishedAt,
+			Category:    d.Category,
+			ImportedAt:  d.ImportedAt,
+
+			DetectedLanguage:   d.DetectedLanguage,
+			LanguageConfidence: d.LanguageConfidence,
+			LanguageMismatch:   d.LanguageMismatch,
+		},
+	}, nil
+}
+
+// normalizeLanguage maps an article language to a supported query language
+// ("sr" → serbian, "" → default).
+func (b *IndexBuilder) normalizeLanguage(lang string) string {
+	switch l := dquery.Language(strings.ToLower(strings.TrimSpace(lang))); l {
+	case "":
+		return b.defaultLanguage
+	case "sr", "srpski":
+		return string(dquery.LanguageSerbian)
+	case "en":
+		return string(dquery.LanguageEnglish)
+	default:
+		if dquery.SupportedLanguages[l] {
+			return string(l)
+		}
+		return lang
 	}
 }
 
 func (b *IndexBuilder) buildSettings() types.IndexSettings {
+	analyzers, filters := buildLanguageAnalysis()
+	analyzers["multilingual_analyzer"] = types.StandardAnalyzer{
+		Stopwords: []string{"_none_"},
+	}
+	analyzers[autocompleteAnalyzer] = types.CustomAnalyzer{
+		Tokenizer: "standard",
+		Filter:    []string{"lowercase", "serbian_normalization"},
+	}
 	return types.IndexSettings{
 		Analysis: &types.IndexSettingsAnalysis{
-			Analyzer: map[string]types.Analyzer{
-				"multilingual_analyzer": types.StandardAnalyzer{
-					Stopwords: []string{"_none_"},
-				},
-			},
+			Analyzer: analyzers,
+			Filter:   filters,
 		},
 	}
 }
 
 func (b *IndexBuilder) buildMapping() types.TypeMapping {
-	return types.TypeMapping{
-		Properties: map[string]types.Property{
-			"id":           types.NewKeywordProperty(),
-			"title":        b.createTextPropertyWithKeyword("multilingual_analyzer"),
-			"subtitle":     b.createTextProperty("multilingual_analyzer"),
-			"description":  b.createTextProperty("multilingual_analyzer"),
-			"content":      b.createTextProperty("multilingual_analyzer"),
-			"author":       b.createTextPropertyWithKeyword(""),
-			"url":          types.NewKeywordProperty(),
-			"language":     types.NewKeywordProperty(),
-			"created_at":   types.NewDateProperty(),
-			"source_id":    types.NewKeywordProperty(),
-			"source_name":  b.createTextPropertyWithKeyword(""),
-			"published_at": types.NewDateProperty(),
-			"category":     types.NewKeywordProperty(),
-			"imported_at":  types.NewDateProperty(),
-			"indexed_at":   types.NewDateProperty(),
-			// Document embedding lives on the article doc (see embedder.go).
-			"embedding":       b.denseVectorProperty(),
-			"embedding_model": types.NewKeywordProperty(),
-		},
+	properties := map[string]types.Property{
+		"id":           types.NewKeywordProperty(),
+		"title":        b.createTitleProperty(),
+		"subtitle":     b.createTextProperty("multilingual_analyzer"),
+		"description":  b.createTextProperty("multilingual_analyzer"),
+		"content":      b.createTextProperty("multilingual_analyzer"),
+		"author":       b.createTextPropertyWithKeyword(""),
+		"url":          types.NewKeywordProperty(),
+		"language":     types.NewKeywordProperty(),
+		"created_at":   types.NewDateProperty(),
+		"source_id":    types.NewKeywordProperty(),
+		"source_name":  b.createTextPropertyWithKeyword(""),
+		"published_at": types.NewDateProperty(),
+		"category":     types.NewKeywordProperty(),
+		"imported_at":  types.NewDateProperty(),
+		"indexed_at":   types.NewDateProperty(),
+		// Ingest language detection (see ingest.WithLanguageDetector)
+		"detected_language":   types.NewKeywordProperty(),
+		"language_confidence": types.NewFloatNumberProperty(),
+		"language_mismatch":   types.NewBooleanProperty(),
+		// Document embedding lives on the article doc (see embedder.go).
+		"embedding":       b.denseVectorProperty(),
+		"embedding_model": types.NewKeywordProperty(),
+	}
+	b.addLanguageProperties(properties)
+	return types.TypeMapping{Properties: properties}
+}
+
+// addLanguageProperties adds the per-language copy of every analyzed text field
+// (title_en, title_sr, ...), analyzed by the analyzer of its language. Queries target the
+// copy of the requested language (see languageField).
+func (b *IndexBuilder) addLanguageProperties(properties map[string]types.Property) {
+	for field := range languageTextFields {
+		for suffix, analyzer := range languageAnalyzers {
+			properties[field+"_"+suffix] = b.createTextProperty(analyzer)
+		}
 	}
 }
 
@@ -129,3 +236,14 @@ func (b *IndexBuilder) createTextPropertyWithKeyword(analyzer string) types.Prop
 	}
 	return textProp
 }
+
+// createTitleProperty creates the title field: a keyword sub-field and the
+// title.autocomplete search_as_you_type sub-field used by the Autocompleter.
+func (b *IndexBuilder) createTitleProperty() types.Property {
+	titleProp := b.createTextPropertyWithKeyword("multilingual_analyzer").(*types.TextProperty)
+	autocomplete := types.NewSearchAsYouTypeProperty()
+	analyzer := autocompleteAnalyzer
+	autocomplete.Analyzer = &analyzer
+	titleProp.Fields[autocompleteSubField] = autocomplete
+	return titleProp
+}
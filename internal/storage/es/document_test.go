@@ -0,0 +1,73 @@
+package es
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/ingest/langdetect"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+)
+
+func TestMapToESDocument_LanguageFields(t *testing.T) {
+	detector, err := langdetect.NewDetector()
+	if err != nil {
+		t.Fatalf("NewDetector() error = %v", err)
+	}
+	const serbianContent = "Ministar je rekao da će novi zakon zaštititi radnike i pomoći malim preduzećima da se oporave posle krize."
+
+	tests := []struct {
+		name       string
+		article    document.Article
+		wantFields []string
+	}{
+		{
+			name: "detected serbian",
+			article: document.Article{
+				Title:    "Novi zakon o radu",
+				Content:  serbianContent,
+				Language: detector.Detect(serbianContent).Language,
+			},
+			wantFields: []string{"content_sr", "title_sr"},
+		},
+		{
+			name:       "mapped english code",
+			article:    document.Article{Title: "Climate talks", Description: "Leaders meet", Language: "en"},
+			wantFields: []string{"description_en", "title_en"},
+		},
+		{
+			name:    "language without analyzer",
+			article: document.Article{Title: "Klimagipfel", Language: "german"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(NewIndexBuilder().mapToESDocument(tt.article))
+			if err != nil {
+				t.Fatalf("marshal document: %v", err)
+			}
+			var doc map[string]any
+			if err := json.Unmarshal(raw, &doc); err != nil {
+				t.Fatalf("decode document: %v", err)
+			}
+
+			var got []string
+			for field := range doc {
+				if baseField(field) != field {
+					got = append(got, field)
+				}
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.wantFields) {
+				t.Errorf("language fields = %v, want %v", got, tt.wantFields)
+			}
+			for _, field := range got {
+				if doc[field] != doc[strings.Split(field, "_")[0]] {
+					t.Errorf("%s = %v, want a copy of the base field", field, doc[field])
+				}
+			}
+		})
+	}
+}
@@ -36,7 +36,15 @@ func NewEmbedder(ctx context.Context, config ClientConfig) (*Embedder, error) {
 		return nil, fmt.Errorf("failed to create Elasticsearch client: %w", err)
 	}
 
-	e := &Embedder{client: client, indexName: config.IndexName}
+	ok, err := ensureWriteAlias(ctx, client, config.IndexName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("index %q does not exist, ingest the articles first", config.IndexName)
+	}
+
+	e := &Embedder{client: client, indexName: WriteAlias(config.IndexName)}
 	if err := e.ensureEmbeddingField(ctx); err != nil {
 		return nil, err
 	}
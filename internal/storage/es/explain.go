@@ -0,0 +1,46 @@
+package es
+
+import (
+	"regexp"
+	"strings"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// weightDescription matches Lucene term and phrase weight leaves,
+// e.g. `weight(title:climat in 12) [PerFieldSimilarity], result of:` or
+// `weight(content:"climat chang"~2 in 7) [PerFieldSimilarity], result of:`
+var weightDescription = regexp.MustCompile(`^weight\(([\w.]+):("[^"]*"|\S+?)(?:~\d+)? in \d+\)`)
+
+// filterClauseDescription is the description of bool filter clauses, which never score
+const filterClauseDescription = "match on required clause"
+
+// mapExplanation normalizes the Lucene explanation of a hit into an explanation tree
+func mapExplanation(e *types.Explanation) *dquery.Explanation {
+	node := normalizeExplanation(float64(e.Value), e.Description, e.Details)
+	return &node
+}
+
+// normalizeExplanation drops filter clauses (the structured filters and the id restriction
+// of _explain), collapses sums left with a single detail and extracts the field and terms
+// of weight(...) leaves.
+func normalizeExplanation(value float64, description string, details []types.ExplanationDetail) dquery.Explanation {
+	node := dquery.Explanation{Value: value, Description: description}
+	if m := weightDescription.FindStringSubmatch(description); m != nil {
+		node.Field = m[1]
+		node.Terms = strings.Fields(strings.Trim(m[2], `"`))
+	}
+
+	for _, d := range details {
+		if strings.HasPrefix(d.Description, filterClauseDescription) {
+			continue
+		}
+		node.Details = append(node.Details, normalizeExplanation(float64(d.Value), d.Description, d.Details))
+	}
+
+	if len(node.Details) == 1 && len(details) > 1 && node.Details[0].Value == node.Value {
+		return node.Details[0]
+	}
+	return node
+}
@@ -0,0 +1,65 @@
+package es
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+func TestMapExplanation(t *testing.T) {
+	// bool query with a filter clause, as sent with structured filters or by _explain
+	e := &types.Explanation{
+		Value:       2.5,
+		Description: "sum of:",
+		Details: []types.ExplanationDetail{
+			{
+				Value:       2.5,
+				Description: "max of:",
+				Details: []types.ExplanationDetail{
+					{Value: 2.5, Description: "weight(title:climat in 12) [PerFieldSimilarity], result of:"},
+					{Value: 1.1, Description: `weight(content:"climat chang"~2 in 12) [PerFieldSimilarity], result of:`},
+				},
+			},
+			{
+				Value:       0,
+				Description: "match on required clause, product of:",
+				Details: []types.ExplanationDetail{
+					{Value: 0, Description: "# clause"},
+					{Value: 1, Description: "ConstantScore(_id:([fe 1f]))"},
+				},
+			},
+		},
+	}
+
+	got := mapExplanation(e)
+	if got.Description != "max of:" || got.Value != 2.5 {
+		t.Fatalf("root = %q (%g), want the scoring clause once the filter clause is dropped", got.Description, got.Value)
+	}
+	if len(got.Details) != 2 {
+		t.Fatalf("expected 2 details, got %d", len(got.Details))
+	}
+
+	title, content := got.Details[0], got.Details[1]
+	if title.Field != "title" || !slices.Equal(title.Terms, []string{"climat"}) {
+		t.Errorf("title leaf = %+v, want field title and term climat", title)
+	}
+	if content.Field != "content" || !slices.Equal(content.Terms, []string{"climat", "chang"}) {
+		t.Errorf("content leaf = %+v, want field content and phrase terms", content)
+	}
+}
+
+func TestMapExplanation_KeepsSingleDetailSums(t *testing.T) {
+	e := &types.Explanation{
+		Value:       1.2,
+		Description: "sum of:",
+		Details: []types.ExplanationDetail{
+			{Value: 1.2, Description: "weight(title:climat in 3) [PerFieldSimilarity], result of:"},
+		},
+	}
+
+	got := mapExplanation(e)
+	if got.Description != "sum of:" || len(got.Details) != 1 {
+		t.Errorf("got %+v, want the sum kept: nothing was dropped", got)
+	}
+}
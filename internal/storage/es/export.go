@@ -0,0 +1,113 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// exportKeepAlive is how long the point in time of an export is kept between two batches
+const exportKeepAlive = "2m"
+
+// Export implements storage.Exporter interface
+// Opens a point in time on the index and pages through it with search_after in batches of
+// baseOpts.Size, sorted like search (see buildSort). The first batch also reports the total
+// and, when the normalization needs them, the score statistics of the snapshot.
+func (r *Searcher) Export(ctx context.Context, query *dquery.Base, baseOpts *dquery.BaseOptions, fn storage.ExportFunc) error {
+	size := baseOpts.Size
+	if size <= 0 {
+		size = storage.ExportBatchSize
+	}
+
+	slog.Info("Executing es export",
+		"kind", query.Kind,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"batch_size", size)
+
+	q, err := compileQuery(query)
+	if err != nil {
+		return err
+	}
+	q = withFilters(withRescoring(q, baseOpts.Rescoring), baseOpts.Filters)
+
+	pit, err := r.client.OpenPointInTime(r.indexName).KeepAlive(exportKeepAlive).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open point in time: %w", err)
+	}
+	pitID := pit.Id
+	// The request context is cancelled when the client disconnects; the point in time must still be closed
+	defer func() {
+		if _, err := r.client.ClosePointInTime().Id(pitID).Do(context.WithoutCancel(ctx)); err != nil {
+			slog.Warn("Failed to close export point in time", "error", err)
+		}
+	}()
+
+	var stats ranking.Stats
+	var searchAfter []types.FieldValue
+	var exported int64
+	for {
+		searchReq := r.client.Search().
+			Pit(&types.PointInTimeReference{Id: pitID, KeepAlive: exportKeepAlive}).
+			Query(q).
+			Size(size).
+			TrackScores(true).
+			Sort(buildSort(baseOpts.Sort)...)
+
+		first := searchAfter == nil
+		if first {
+			searchReq = searchReq.TrackTotalHits(true)
+			if baseOpts.Normalization.NeedsDistribution() {
+				searchReq = searchReq.Aggregations(map[string]types.Aggregations{
+					scoreStatsAggregation: buildScoreStatsAggregation(),
+				})
+			}
+		} else {
+			searchReq = searchReq.SearchAfter(searchAfter...).TrackTotalHits(false)
+		}
+
+		res, err := searchReq.Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to execute %s export: %w", query.Kind, err)
+		}
+		if res.PitId != nil {
+			pitID = *res.PitId
+		}
+
+		if first {
+			maxScore := dquery.CalcSafeScore((*float64)(res.Hits.MaxScore))
+			if stats, err = mapScoreStats(res.Aggregations, res.Hits.Total.Value, maxScore); err != nil {
+				return err
+			}
+		}
+
+		hits, rawScores, err := r.mapToResult(res.Hits.Hits)
+		if err != nil {
+			return fmt.Errorf("failed to map export hits to types: %w", err)
+		}
+
+		storage.NormalizeScores(baseOpts.Normalization, hits, rawScores, stats, exported)
+		for i := range hits {
+			storage.ExplainRescoring(baseOpts.Rescoring, &hits[i], rawScores[i])
+			if err := fn(hits[i]); err != nil {
+				return err
+			}
+		}
+		exported += int64(len(hits))
+
+		if len(hits) < size {
+			break
+		}
+		searchAfter = res.Hits.Hits[len(res.Hits.Hits)-1].Sort
+	}
+
+	slog.Info("ES export finished", "kind", query.Kind, "exported", exported, "total_matches", stats.Count)
+	return nil
+}
+
+// Compile-time interface assertions
+var _ storage.Exporter = (*Searcher)(nil)
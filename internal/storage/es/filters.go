@@ -0,0 +1,84 @@
+package es
+
+import (
+	"time"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// filterFields maps filter fields to keyword fields of the index mapping (see IndexBuilder.buildMapping).
+// Text fields with a keyword sub-field are filtered on the sub-field for exact matching.
+var filterFields = map[dquery.FilterField]string{
+	dquery.FilterLanguage:   "language",
+	dquery.FilterSourceID:   "source_id",
+	dquery.FilterSourceName: "source_name.keyword",
+	dquery.FilterCategory:   "category",
+	dquery.FilterAuthor:     "author.keyword",
+}
+
+// buildFilterQueries compiles structured filters into bool filter clauses
+// (range on date fields, terms on keyword fields). Returns nil when no filter is set.
+func buildFilterQueries(filters *dquery.Filters) []types.Query {
+	if filters.IsEmpty() {
+		return nil
+	}
+
+	var clauses []types.Query
+
+	addRange := func(field string, r *dquery.DateRange) {
+		if r.IsEmpty() {
+			return
+		}
+		rq := types.DateRangeQuery{}
+		if r.Gte != nil {
+			gte := r.Gte.UTC().Format(time.RFC3339Nano)
+			rq.Gte = &gte
+		}
+		if r.Lte != nil {
+			lte := r.Lte.UTC().Format(time.RFC3339Nano)
+			rq.Lte = &lte
+		}
+		clauses = append(clauses, types.Query{
+			Range: map[string]types.RangeQuery{field: rq},
+		})
+	}
+	addRange("published_at", filters.PublishedAt)
+	addRange("created_at", filters.CreatedAt)
+
+	for _, field := range filters.TermFields() {
+		clauses = append(clauses, types.Query{
+			Terms: &types.TermsQuery{
+				TermsQuery: map[string]types.TermsQueryField{
+					filterFields[field]: filters.Terms[field],
+				},
+			},
+		})
+	}
+
+	if len(filters.IDs) > 0 {
+		ids := make([]string, 0, len(filters.IDs))
+		for _, id := range filters.IDs {
+			ids = append(ids, id.String())
+		}
+		clauses = append(clauses, types.Query{Ids: &types.IdsQuery{Values: ids}})
+	}
+
+	return clauses
+}
+
+// withFilters wraps a scoring query in a bool query whose filter clauses narrow the
+// match set without contributing to the score. The query is returned unchanged when
+// no filter is set.
+func withFilters(q *types.Query, filters *dquery.Filters) *types.Query {
+	clauses := buildFilterQueries(filters)
+	if len(clauses) == 0 {
+		return q
+	}
+	return &types.Query{
+		Bool: &types.BoolQuery{
+			Must:   []types.Query{*q},
+			Filter: clauses,
+		},
+	}
+}
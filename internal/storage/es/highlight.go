@@ -0,0 +1,44 @@
+package es
+
+import (
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// buildHighlight compiles a highlight request into the ES highlight API.
+// require_field_match is disabled so requested fields are highlighted even when the
+// query targeted other fields, matching ts_headline behaviour of the PG backend.
+// Fields are highlighted on their per-language field so stemmed query terms match.
+// Returns nil when highlighting is not requested.
+func buildHighlight(h *dquery.Highlight, lang dquery.Language) *types.Highlight {
+	if h == nil {
+		return nil
+	}
+
+	fields := make(map[string]types.HighlightField, len(h.Fields))
+	for _, field := range h.Fields {
+		fields[languageField(field, lang)] = types.HighlightField{}
+	}
+
+	fragmentSize := h.FragmentSize
+	numberOfFragments := h.NumberOfFragments
+	requireFieldMatch := false
+
+	return &types.Highlight{
+		Fields:            fields,
+		FragmentSize:      &fragmentSize,
+		NumberOfFragments: &numberOfFragments,
+		PreTags:           []string{h.PreTag},
+		PostTags:          []string{h.PostTag},
+		RequireFieldMatch: &requireFieldMatch,
+	}
+}
+
+// mapHighlight keys highlight fragments by the requested field instead of the per-language field
+func mapHighlight(highlight map[string][]string) map[string][]string {
+	result := make(map[string][]string, len(highlight))
+	for field, fragments := range highlight {
+		result[baseField(field)] = fragments
+	}
+	return result
+}
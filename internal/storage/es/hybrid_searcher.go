@@ -9,6 +9,7 @@ import (
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
 	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
 	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
@@ -57,14 +58,15 @@ func (s *HybridSearcher) SearchHybrid(ctx context.Context, query *dquery.Hybrid,
 		"language", query.GetLanguage(),
 		"k", k,
 		"size", size,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
 		"leg_depth", legDepth)
 
-	lexicalIDs, err := s.lexicalLeg(ctx, query.Query, legDepth)
+	lexicalIDs, err := s.lexicalLeg(ctx, query.Query, query.GetLanguage(), baseOpts.Filters, legDepth)
 	if err != nil {
 		return nil, fmt.Errorf("hybrid lexical leg: %w", err)
 	}
 
-	vectorHits, err := s.vectorLeg(ctx, query.Query, legDepth)
+	vectorHits, err := s.vectorLeg(ctx, query.Query, baseOpts.Filters, legDepth)
 	if err != nil {
 		return nil, fmt.Errorf("hybrid vector leg: %w", err)
 	}
@@ -75,6 +77,11 @@ func (s *HybridSearcher) SearchHybrid(ctx context.Context, query *dquery.Hybrid,
 	}
 
 	totalMatches := int64(len(fused))
+	fusedScores := make([]float64, len(fused))
+	for i, c := range fused {
+		fusedScores[i] = c.score
+	}
+	stats := ranking.StatsOf(fusedScores)
 	if len(fused) > size {
 		fused = fused[:size]
 	}
@@ -86,21 +93,24 @@ func (s *HybridSearcher) SearchHybrid(ctx context.Context, query *dquery.Hybrid,
 
 	maxScore := fused[0].score
 	articles := make([]dto.ArticleSearchResult, 0, len(fused))
+	rawScores := make([]float64, 0, len(fused))
 	for _, c := range fused {
 		doc, ok := docs[c.id]
 		if !ok {
 			continue
 		}
 		articles = append(articles, dto.ArticleSearchResult{
-			Article:         doc,
-			Score:           utils.RoundFloat64(c.score, dquery.ScoreDecimalPlaces),
-			ScoreNormalized: utils.RoundFloat64(c.score/maxScore, dquery.ScoreDecimalPlaces),
+			Article: doc,
+			Score:   utils.RoundFloat64(c.score, dquery.ScoreDecimalPlaces),
 		})
+		rawScores = append(rawScores, c.score)
 	}
 
 	if len(articles) == 0 {
 		return &storage.SearchResult{}, nil
 	}
+	// RRF scores are normalized over the fused candidates of both legs
+	storage.NormalizeScores(baseOpts.Normalization, articles, rawScores, stats, 0)
 
 	slog.Info("ES hybrid search results fetched",
 		"total_page_matches", len(articles),
@@ -117,25 +127,21 @@ func (s *HybridSearcher) SearchHybrid(ctx context.Context, query *dquery.Hybrid,
 	}, nil
 }
 
-// lexicalLeg runs a BM25 multi_match over the default fields/weights and returns
-// matched doc IDs in rank order.
-func (s *HybridSearcher) lexicalLeg(ctx context.Context, query string, depth int) ([]uuid.UUID, error) {
+// lexicalLeg runs a BM25 multi_match over the default fields/weights (analyzed in lang) and returns
+// matched doc IDs in rank order. Filters are applied in filter context.
+func (s *HybridSearcher) lexicalLeg(ctx context.Context, query string, lang dquery.Language, filters *dquery.Filters, depth int) ([]uuid.UUID, error) {
 	fields := dquery.DefaultFields
 	weights := dquery.DefaultFieldWeights
 	fieldsWithBoost := make([]string, 0, len(fields))
 	for _, field := range fields {
-		if w := weights[field]; w != 1.0 {
-			fieldsWithBoost = append(fieldsWithBoost, fmt.Sprintf("%s^%.1f", field, w))
-		} else {
-			fieldsWithBoost = append(fieldsWithBoost, field)
-		}
+		fieldsWithBoost = append(fieldsWithBoost, boostedField(field, weights[field], lang))
 	}
 
 	res, err := s.client.Search().
 		Index(s.indexName).
-		Query(&types.Query{
+		Query(withFilters(&types.Query{
 			MultiMatch: &types.MultiMatchQuery{Query: query, Fields: fieldsWithBoost},
-		}).
+		}, filters)).
 		SourceIncludes_("id").
 		Size(depth).
 		Do(ctx)
@@ -147,8 +153,8 @@ func (s *HybridSearcher) lexicalLeg(ctx context.Context, query string, depth int
 }
 
 // vectorLeg embeds the query and runs a kNN search over the embedding field,
-// returning matched doc IDs in rank order.
-func (s *HybridSearcher) vectorLeg(ctx context.Context, query string, depth int) ([]uuid.UUID, error) {
+// returning matched doc IDs in rank order. Filters are applied as kNN pre-filters.
+func (s *HybridSearcher) vectorLeg(ctx context.Context, query string, filters *dquery.Filters, depth int) ([]uuid.UUID, error) {
 	vec, err := s.embedder.EmbedQuery(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("embed query: %w", err)
@@ -163,6 +169,7 @@ func (s *HybridSearcher) vectorLeg(ctx context.Context, query string, depth int)
 		QueryVector:   vec.Embedding,
 		K:             &depth,
 		NumCandidates: &numCandidates,
+		Filter:        buildFilterQueries(filters),
 	}
 
 	res, err := s.client.Search().
@@ -256,6 +263,10 @@ func (s *HybridSearcher) fetchDocs(ctx context.Context, candidates []fusedCandid
 				PublishedAt: doc.PublishedAt,
 				Category:    doc.Category,
 				ImportedAt:  doc.ImportedAt,
+
+				DetectedLanguage:   doc.DetectedLanguage,
+				LanguageConfidence: doc.LanguageConfidence,
+				LanguageMismatch:   doc.LanguageMismatch,
 			},
 		}
 	}
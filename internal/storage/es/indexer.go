@@ -6,16 +6,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/esutil"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 	"github.com/google/uuid"
 )
 
+// Indexer writes articles through the write alias of the index (see WriteAlias)
 type Indexer struct {
 	client       *elasticsearch.TypedClient
+	alias        string
 	indexName    string
 	config       ClientConfig
 	indexBuilder *IndexBuilder
@@ -29,7 +33,8 @@ func NewIndexer(ctx context.Context, config ClientConfig) (*Indexer, error) {
 	}
 	storer := &Indexer{
 		client:       client,
-		indexName:    config.IndexName,
+		alias:        config.IndexName,
+		indexName:    WriteAlias(config.IndexName),
 		config:       config,
 		indexBuilder: NewIndexBuilder(),
 	}
@@ -127,33 +132,40 @@ func (e *Indexer) SaveBulk(ctx context.Context, articles []document.Article) err
 	return nil
 }
 
+// EnsureIndex bootstraps the index lifecycle. It applies the ILM policy and the index
+// template, then creates the index of IndexVersion behind the read and write aliases when
+// there is no index yet. An existing index keeps serving even when it is an older version or
+// a legacy unversioned index; cmd/es_reindex migrates it.
 func (e *Indexer) EnsureIndex(ctx context.Context) error {
-	existsRes, err := e.client.Indices.Exists(e.indexName).Do(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to check if index exists: %w", err)
+	if err := applyLifecycle(ctx, e.client, e.alias); err != nil {
+		return err
 	}
 
-	if existsRes {
-		slog.Info("Index already exists", "index", e.indexName)
-		return nil
+	indices, legacy, err := servingIndices(ctx, e.client, e.alias)
+	if err != nil {
+		return err
 	}
 
-	settings := e.indexBuilder.buildSettings()
-
-	mappings := e.indexBuilder.buildMapping()
-
-	createRes, err := e.client.Indices.Create(e.indexName).
-		Settings(&settings).
-		Mappings(&mappings).
-		Do(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to create index: %w", err)
+	if len(indices) == 0 {
+		isWriteIndex := true
+		return createIndex(ctx, e.client, VersionedIndex(e.alias, IndexVersion), e.indexBuilder, map[string]types.Alias{
+			e.alias:             {},
+			WriteAlias(e.alias): {IsWriteIndex: &isWriteIndex},
+		})
 	}
 
-	if !createRes.Acknowledged {
-		return fmt.Errorf("index creation was not acknowledged")
+	if current := VersionedIndex(e.alias, IndexVersion); !slices.Contains(indices, current) {
+		slog.Warn("Index is not the current version, run es_reindex to migrate it",
+			"alias", e.alias,
+			"indices", indices,
+			"legacy", legacy,
+			"current", current)
+	} else {
+		slog.Info("Index already exists", "index", current, "alias", e.alias)
 	}
 
-	slog.Info("Index created successfully", "index", e.indexName)
+	if _, err := ensureWriteAlias(ctx, e.client, e.alias); err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,105 @@
+package es
+
+import (
+	"fmt"
+	"strings"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+const (
+	englishAnalyzer = "english_analyzer"
+	serbianAnalyzer = "serbian_analyzer"
+	serbianStop     = "serbian_stop"
+)
+
+// languageSuffixes maps languages to the suffix of the per-language copies of the
+// analyzed text fields (e.g. serbian → title_sr).
+var languageSuffixes = map[dquery.Language]string{
+	dquery.LanguageEnglish: "en",
+	dquery.LanguageSerbian: "sr",
+}
+
+// languageAnalyzers maps per-language field suffixes to their analyzers.
+var languageAnalyzers = map[string]string{
+	"en": englishAnalyzer,
+	"sr": serbianAnalyzer,
+}
+
+// languageTextFields are the text fields that have per-language copies.
+var languageTextFields = map[string]bool{
+	"title":       true,
+	"subtitle":    true,
+	"description": true,
+	"content":     true,
+}
+
+// serbianStopwords is the Serbian stopword list in the folded Latin form produced by
+// the serbian_normalization filter (Cyrillic → Latin, diacritics removed, đ → dj).
+var serbianStopwords = []string{
+	"a", "ako", "ali", "bi", "bih", "bila", "bile", "bili", "bilo", "bio", "biti", "ce", "cemo", "ces",
+	"da", "do", "dok", "ga", "gde", "i", "ih", "ili", "iz", "ja", "je", "jer", "jesu", "jos", "ju",
+	"kad", "kada", "kako", "kao", "koja", "koje", "koji", "kojih", "kojima", "koju", "li", "me", "mi",
+	"mu", "na", "nad", "nam", "nas", "ne", "nego", "neki", "ni", "nije", "nisu", "niti", "njega",
+	"njegov", "njen", "njih", "njihov", "njoj", "o", "od", "on", "ona", "one", "oni", "ono", "ova",
+	"ove", "ovi", "ovo", "pa", "po", "pod", "pored", "pre", "prema", "pri", "sa", "sam", "samo", "se",
+	"si", "smo", "ste", "sto", "su", "sve", "svi", "ta", "taj", "tako", "te", "ti", "to", "tu", "u",
+	"uz", "vas", "vec", "vi", "za", "zbog",
+}
+
+// buildLanguageAnalysis returns the per-language analyzers and the token filters they use.
+//
+//	english_analyzer: standard → lowercase → stop (_english_) → porter_stem
+//	serbian_analyzer: standard → lowercase → serbian_normalization → serbian_stop
+//
+// serbian_normalization transliterates Cyrillic to Latin and folds diacritics, so
+// "Ђоковић", "Đoković" and "Djokovic" produce the same term.
+func buildLanguageAnalysis() (map[string]types.Analyzer, map[string]types.TokenFilter) {
+	analyzers := map[string]types.Analyzer{
+		englishAnalyzer: types.CustomAnalyzer{
+			Tokenizer: "standard",
+			Filter:    []string{"lowercase", "stop", "porter_stem"},
+		},
+		serbianAnalyzer: types.CustomAnalyzer{
+			Tokenizer: "standard",
+			Filter:    []string{"lowercase", "serbian_normalization", serbianStop},
+		},
+	}
+	filters := map[string]types.TokenFilter{
+		serbianStop: types.StopTokenFilter{
+			Stopwords: serbianStopwords,
+		},
+	}
+	return analyzers, filters
+}
+
+// languageField returns the per-language copy of an analyzed text field
+// (title + serbian → title_sr). Other fields are returned unchanged.
+func languageField(field string, lang dquery.Language) string {
+	suffix, ok := languageSuffixes[lang]
+	if !ok || !languageTextFields[field] {
+		return field
+	}
+	return field + "_" + suffix
+}
+
+// boostedField renders a language-specific field with an optional boost ("title_en^3.0").
+func boostedField(field string, weight float64, lang dquery.Language) string {
+	field = languageField(field, lang)
+	if weight != 1.0 {
+		return fmt.Sprintf("%s^%.1f", field, weight)
+	}
+	return field
+}
+
+// baseField strips a per-language suffix from a field name (title_sr → title).
+func baseField(field string) string {
+	if i := strings.LastIndexByte(field, '_'); i > 0 {
+		base, suffix := field[:i], field[i+1:]
+		if _, known := languageAnalyzers[suffix]; known && languageTextFields[base] {
+			return base
+		}
+	}
+	return field
+}
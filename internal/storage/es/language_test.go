@@ -0,0 +1,93 @@
+package es
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	pkgtesting "github.com/DjordjeVuckovic/news-hunter/pkg/testing"
+	"github.com/google/uuid"
+)
+
+func TestLanguageField(t *testing.T) {
+	tests := []struct {
+		field  string
+		lang   dquery.Language
+		weight float64
+		want   string
+	}{
+		{field: "title", lang: dquery.LanguageEnglish, weight: 1.0, want: "title_en"},
+		{field: "content", lang: dquery.LanguageSerbian, weight: 1.0, want: "content_sr"},
+		{field: "title", lang: dquery.LanguageSerbian, weight: 3.0, want: "title_sr^3.0"},
+		{field: "author", lang: dquery.LanguageSerbian, weight: 1.0, want: "author"},
+		{field: "title", lang: "klingon", weight: 2.0, want: "title^2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := boostedField(tt.field, tt.weight, tt.lang); got != tt.want {
+				t.Errorf("boostedField(%q, %v, %q) = %q, want %q", tt.field, tt.weight, tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseField(t *testing.T) {
+	for field, want := range map[string]string{
+		"title_sr":      "title",
+		"content_en":    "content",
+		"title.keyword": "title.keyword",
+		"author":        "author",
+	} {
+		if got := baseField(field); got != want {
+			t.Errorf("baseField(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestSearcher_SerbianScriptInsensitive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("requires Docker (testcontainers ES)")
+	}
+	ctx := context.Background()
+
+	container := pkgtesting.NewESContainer(ctx, t)
+	cfg := ClientConfig{Addresses: []string{container.Address}, IndexName: "articles_language_test"}
+
+	indexer, err := NewIndexer(ctx, cfg)
+	if err != nil {
+		t.Fatalf("NewIndexer: %v", err)
+	}
+	embedder, err := NewEmbedder(ctx, cfg)
+	if err != nil {
+		t.Fatalf("NewEmbedder: %v", err)
+	}
+	searcher, err := NewSearcher(cfg)
+	if err != nil {
+		t.Fatalf("NewSearcher: %v", err)
+	}
+
+	cyrillicID := uuid.New()
+	if _, err := indexer.Save(ctx, document.Article{ID: cyrillicID, Title: "Ђоковић осваја турнир", Language: "sr"}); err != nil {
+		t.Fatalf("index cyrillic article: %v", err)
+	}
+	if _, err := indexer.Save(ctx, document.Article{ID: uuid.New(), Title: "Weather report", Language: "english"}); err != nil {
+		t.Fatalf("index english article: %v", err)
+	}
+	refresh(t, embedder)
+
+	res, err := searcher.SearchField(ctx,
+		dquery.NewMatch("title", "Djokovic", dquery.WithMatchLanguage(dquery.LanguageSerbian)),
+		&dquery.BaseOptions{Size: 10},
+	)
+	if err != nil {
+		t.Fatalf("SearchField: %v", err)
+	}
+	if len(res.Hits) != 1 || res.Hits[0].Article.ID != cyrillicID {
+		t.Fatalf("hits = %v, want only the Cyrillic article", res.Hits)
+	}
+	if res.Hits[0].Article.Language != string(dquery.LanguageSerbian) {
+		t.Errorf("language = %q, want normalized %q", res.Hits[0].Article.Language, dquery.LanguageSerbian)
+	}
+}
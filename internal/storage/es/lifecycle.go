@@ -0,0 +1,215 @@
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/DjordjeVuckovic/news-hunter/configs"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// IndexVersion is the version of the index layout built by IndexBuilder. Bump it when a
+// mapping or analysis change needs a new index, then run cmd/es_reindex to build it and
+// swap the aliases.
+const IndexVersion = 4
+
+const (
+	// ilmPolicyName is the policy of configs/elasticsearch/ilm-policy.json, referenced by
+	// the index template
+	ilmPolicyName = "news-ilm-policy"
+
+	ilmPolicyFile     = "elasticsearch/ilm-policy.json"
+	indexTemplateFile = "elasticsearch/index-template.json"
+)
+
+// VersionedIndex returns the concrete index of a version behind alias, e.g. articles_v3
+func VersionedIndex(alias string, version int) string {
+	return fmt.Sprintf("%s_v%d", alias, version)
+}
+
+// WriteAlias returns the alias indexers write through. ClientConfig.IndexName is the read
+// alias that searchers and readers use.
+func WriteAlias(alias string) string {
+	return alias + "_write"
+}
+
+// indexTemplate returns the index template of configs/elasticsearch with its patterns
+// matching the versioned indices of alias
+func indexTemplate(alias string) ([]byte, error) {
+	raw, err := configs.Elasticsearch.ReadFile(indexTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index template: %w", err)
+	}
+
+	var template map[string]any
+	if err := json.Unmarshal(raw, &template); err != nil {
+		return nil, fmt.Errorf("failed to decode index template: %w", err)
+	}
+	template["index_patterns"] = []string{alias + "_v*"}
+
+	return json.Marshal(template)
+}
+
+// applyLifecycle puts the ILM policy and the index template of configs/elasticsearch. Both
+// are idempotent, so they are applied on every bootstrap and follow the files.
+func applyLifecycle(ctx context.Context, client *elasticsearch.TypedClient, alias string) error {
+	policy, err := configs.Elasticsearch.ReadFile(ilmPolicyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read ILM policy: %w", err)
+	}
+	if _, err := client.Ilm.PutLifecycle(ilmPolicyName).Raw(bytes.NewReader(policy)).Do(ctx); err != nil {
+		return fmt.Errorf("failed to put ILM policy %q: %w", ilmPolicyName, err)
+	}
+
+	template, err := indexTemplate(alias)
+	if err != nil {
+		return err
+	}
+	name := alias + "-template"
+	if _, err := client.Indices.PutIndexTemplate(name).Raw(bytes.NewReader(template)).Do(ctx); err != nil {
+		return fmt.Errorf("failed to put index template %q: %w", name, err)
+	}
+
+	slog.Info("Index lifecycle applied", "policy", ilmPolicyName, "template", name)
+	return nil
+}
+
+// aliasIndices returns the concrete indices behind alias, sorted; empty when the alias does
+// not exist
+func aliasIndices(ctx context.Context, client *elasticsearch.TypedClient, alias string) ([]string, error) {
+	exists, err := client.Indices.ExistsAlias(alias).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check if alias %q exists: %w", alias, err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	res, err := client.Indices.GetAlias().Name(alias).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alias %q: %w", alias, err)
+	}
+	indices := make([]string, 0, len(res))
+	for index := range res {
+		indices = append(indices, index)
+	}
+	slices.Sort(indices)
+
+	return indices, nil
+}
+
+// servingIndices returns the indices searches are served from: the indices behind the read
+// alias, or the unversioned index named like it that predates the aliases (legacy).
+// Empty when neither exists.
+func servingIndices(ctx context.Context, client *elasticsearch.TypedClient, alias string) (indices []string, legacy bool, err error) {
+	indices, err = aliasIndices(ctx, client, alias)
+	if err != nil || len(indices) > 0 {
+		return indices, false, err
+	}
+
+	exists, err := client.Indices.Exists(alias).Do(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check if index exists: %w", err)
+	}
+	if !exists {
+		return nil, false, nil
+	}
+	return []string{alias}, true, nil
+}
+
+// ensureWriteAlias points the write alias at the serving index when it is missing, which is
+// the case for a legacy index. It reports false when there is no index yet.
+func ensureWriteAlias(ctx context.Context, client *elasticsearch.TypedClient, alias string) (bool, error) {
+	writeAlias := WriteAlias(alias)
+	exists, err := client.Indices.ExistsAlias(writeAlias).Do(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if alias %q exists: %w", writeAlias, err)
+	}
+	if exists {
+		return true, nil
+	}
+
+	indices, legacy, err := servingIndices(ctx, client, alias)
+	if err != nil || len(indices) == 0 {
+		return false, err
+	}
+	if len(indices) > 1 {
+		return false, fmt.Errorf("alias %q spans %d indices %v, can not choose the write index", alias, len(indices), indices)
+	}
+
+	if _, err := client.Indices.UpdateAliases().Actions(addAlias(indices[0], writeAlias, true)).Do(ctx); err != nil {
+		return false, fmt.Errorf("failed to add alias %q: %w", writeAlias, err)
+	}
+	slog.Info("Write alias added", "alias", writeAlias, "index", indices[0], "legacy", legacy)
+	return true, nil
+}
+
+// createIndex creates a versioned index with the settings and mappings of the builder,
+// behind the given aliases (none for an index that is being rebuilt)
+func createIndex(ctx context.Context, client *elasticsearch.TypedClient, index string, builder *IndexBuilder, aliases map[string]types.Alias) error {
+	settings := builder.buildSettings()
+	mappings := builder.buildMapping()
+
+	req := client.Indices.Create(index).
+		Settings(&settings).
+		Mappings(&mappings)
+	if len(aliases) > 0 {
+		req = req.Aliases(aliases)
+	}
+
+	createRes, err := req.Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	if !createRes.Acknowledged {
+		return fmt.Errorf("index creation was not acknowledged")
+	}
+
+	slog.Info("Index created successfully", "index", index)
+	return nil
+}
+
+// swapActions moves the read and write aliases from the serving indices to dest. Old indices
+// are deleted with deleteOld; a legacy index always is, since an alias can not be added
+// while an index has its name.
+func swapActions(alias string, serving []string, legacy bool, dest string, deleteOld bool) []types.IndicesAction {
+	writeAlias := WriteAlias(alias)
+
+	var actions []types.IndicesAction
+	for _, index := range serving {
+		if index == dest {
+			continue
+		}
+		if deleteOld || legacy {
+			actions = append(actions, types.IndicesAction{RemoveIndex: &types.RemoveIndexAction{Index: &index}})
+			continue
+		}
+		actions = append(actions,
+			removeAlias(index, alias),
+			removeAlias(index, writeAlias),
+		)
+	}
+
+	return append(actions,
+		addAlias(dest, alias, false),
+		addAlias(dest, writeAlias, true),
+	)
+}
+
+func addAlias(index, alias string, isWriteIndex bool) types.IndicesAction {
+	add := &types.AddAction{Index: &index, Alias: &alias}
+	if isWriteIndex {
+		add.IsWriteIndex = &isWriteIndex
+	}
+	return types.IndicesAction{Add: add}
+}
+
+func removeAlias(index, alias string) types.IndicesAction {
+	mustExist := false
+	return types.IndicesAction{Remove: &types.RemoveAction{Index: &index, Alias: &alias, MustExist: &mustExist}}
+}
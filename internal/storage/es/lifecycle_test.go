@@ -0,0 +1,112 @@
+package es
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+func TestIndexTemplate(t *testing.T) {
+	raw, err := indexTemplate("articles")
+	if err != nil {
+		t.Fatalf("indexTemplate() error = %v", err)
+	}
+
+	var template struct {
+		IndexPatterns []string `json:"index_patterns"`
+		Template      struct {
+			Settings map[string]any `json:"settings"`
+		} `json:"template"`
+	}
+	if err := json.Unmarshal(raw, &template); err != nil {
+		t.Fatalf("decode template: %v", err)
+	}
+
+	if len(template.IndexPatterns) != 1 || template.IndexPatterns[0] != "articles_v*" {
+		t.Errorf("index_patterns = %v, want the versioned indices of the alias", template.IndexPatterns)
+	}
+	if got := template.Template.Settings["index.lifecycle.name"]; got != ilmPolicyName {
+		t.Errorf("index.lifecycle.name = %v, want %s", got, ilmPolicyName)
+	}
+}
+
+func TestSwapActions(t *testing.T) {
+	type action struct {
+		kind, index, alias string
+		isWriteIndex       bool
+	}
+	flatten := func(actions []types.IndicesAction) []action {
+		var out []action
+		for _, a := range actions {
+			switch {
+			case a.Add != nil:
+				out = append(out, action{"add", *a.Add.Index, *a.Add.Alias, a.Add.IsWriteIndex != nil && *a.Add.IsWriteIndex})
+			case a.Remove != nil:
+				out = append(out, action{"remove", *a.Remove.Index, *a.Remove.Alias, false})
+			case a.RemoveIndex != nil:
+				out = append(out, action{"remove_index", *a.RemoveIndex.Index, "", false})
+			}
+		}
+		return out
+	}
+	add := []action{
+		{"add", "articles_v3", "articles", false},
+		{"add", "articles_v3", "articles_write", true},
+	}
+
+	tests := []struct {
+		name      string
+		serving   []string
+		legacy    bool
+		deleteOld bool
+		want      []action
+	}{
+		{
+			name:    "moves the aliases from the previous version",
+			serving: []string{"articles_v2"},
+			want: append([]action{
+				{"remove", "articles_v2", "articles", false},
+				{"remove", "articles_v2", "articles_write", false},
+			}, add...),
+		},
+		{
+			name:      "deletes the previous version",
+			serving:   []string{"articles_v2"},
+			deleteOld: true,
+			want:      append([]action{{"remove_index", "articles_v2", "", false}}, add...),
+		},
+		{
+			name:    "always deletes a legacy index named like the alias",
+			serving: []string{"articles"},
+			legacy:  true,
+			want:    append([]action{{"remove_index", "articles", "", false}}, add...),
+		},
+		{
+			name:    "keeps the destination when it already serves",
+			serving: []string{"articles_v2", "articles_v3"},
+			want: append([]action{
+				{"remove", "articles_v2", "articles", false},
+				{"remove", "articles_v2", "articles_write", false},
+			}, add...),
+		},
+		{
+			name: "adds the aliases when nothing serves",
+			want: add,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := flatten(swapActions("articles", tt.serving, tt.legacy, VersionedIndex("articles", 3), tt.deleteOld))
+			if len(got) != len(tt.want) {
+				t.Fatalf("actions = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("action %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
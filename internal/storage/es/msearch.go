@@ -0,0 +1,83 @@
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// MultiSearch implements storage.MultiSearcher interface
+// Builds every search exactly as execute does and sends them in one _msearch request.
+// Searches failing to compile are reported without being sent; Elasticsearch reports
+// per-search failures in the response and they are mapped like a failed single search
+// (see searchError).
+func (r *Searcher) MultiSearch(ctx context.Context, items []storage.MultiSearchItem) ([]storage.MultiSearchResult, error) {
+	results := make([]storage.MultiSearchResult, len(items))
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	sent := make([]int, 0, len(items))
+	for i, item := range items {
+		q, err := compileQuery(item.Query)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		req, err := buildSearchRequest(q, item.Query.GetLanguage(), item.Options)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		// The index is set on the request path: every header is empty
+		if err := enc.Encode(struct{}{}); err != nil {
+			return nil, fmt.Errorf("failed to encode msearch header: %w", err)
+		}
+		if err := enc.Encode(req); err != nil {
+			return nil, fmt.Errorf("failed to encode msearch body: %w", err)
+		}
+		sent = append(sent, i)
+	}
+
+	slog.Info("Executing es multi search", "searches", len(items), "sent", len(sent))
+	if len(sent) == 0 {
+		return results, nil
+	}
+
+	res, err := r.client.Msearch().Index(r.indexName).Raw(&body).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute multi search: %w", err)
+	}
+	if len(res.Responses) != len(sent) {
+		return nil, fmt.Errorf("multi search returned %d responses for %d searches", len(res.Responses), len(sent))
+	}
+
+	for n, i := range sent {
+		item := items[i]
+		switch resItem := res.Responses[n].(type) {
+		case *types.MultiSearchItem:
+			results[i].Result, results[i].Err = r.mapSearchResponse(item.Query.Kind, resItem.Hits, resItem.Aggregations, item.Options)
+		case *types.ErrorResponseBase:
+			results[i].Err = searchError(item.Query.Kind, types.ElasticsearchError{ErrorCause: resItem.Error, Status: resItem.Status})
+		default:
+			results[i].Err = fmt.Errorf("unexpected multi search response %T", resItem)
+		}
+	}
+	return results, nil
+}
+
+// errorCauseMessage formats an Elasticsearch error as "type: reason"
+func errorCauseMessage(cause types.ErrorCause) string {
+	if cause.Reason == nil {
+		return cause.Type
+	}
+	return cause.Type + ": " + *cause.Reason
+}
+
+// Compile-time interface assertions
+var _ storage.MultiSearcher = (*Searcher)(nil)
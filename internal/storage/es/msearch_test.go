@@ -0,0 +1,47 @@
+package es
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+func TestSearchError(t *testing.T) {
+	reason := "Failed to parse query [climate AND]"
+	cause := types.ErrorCause{Type: "query_shard_exception", Reason: &reason}
+
+	tests := []struct {
+		name           string
+		status         int
+		wantValidation bool
+	}{
+		{name: "bad request", status: http.StatusBadRequest, wantValidation: true},
+		{name: "not found", status: http.StatusNotFound, wantValidation: true},
+		{name: "internal error", status: http.StatusInternalServerError},
+		{name: "unavailable", status: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := searchError(dquery.StringType, types.ElasticsearchError{ErrorCause: cause, Status: tt.status})
+
+			var ve *apperr.ValidationError
+			if got := errors.As(err, &ve); got != tt.wantValidation {
+				t.Fatalf("validation error = %v, want %v (err: %v)", got, tt.wantValidation, err)
+			}
+			if tt.wantValidation {
+				want := "invalid query_string search: query_shard_exception: " + reason
+				if ve.Message != want {
+					t.Errorf("message = %q, want %q", ve.Message, want)
+				}
+			}
+			if status, _ := apperr.StatusOf(err); tt.wantValidation != (status == http.StatusBadRequest) {
+				t.Errorf("StatusOf() = %d", status)
+			}
+		})
+	}
+}
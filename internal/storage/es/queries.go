@@ -0,0 +1,338 @@
+package es
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/operator"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/textquerytype"
+)
+
+// buildMatchQuery translates a match query on the per-language field of its field
+func buildMatchQuery(query *dquery.Match) *types.Query {
+	field := languageField(query.Field, query.GetLanguage())
+
+	matchQuery := types.MatchQuery{
+		Query:    query.Query,
+		Operator: esOperator(query.GetOperator().IsAnd()),
+	}
+	if query.Fuzziness != "" {
+		fuzziness := query.Fuzziness
+		matchQuery.Fuzziness = &fuzziness
+	}
+
+	return &types.Query{
+		Match: map[string]types.MatchQuery{field: matchQuery},
+	}
+}
+
+// buildMultiMatchQuery translates a multi_match query over the boosted per-language fields
+func buildMultiMatchQuery(query *dquery.MultiMatch) *types.Query {
+	lang := query.GetLanguage()
+
+	fields := query.GetFields()
+	fieldsWithWeight := make([]string, 0, len(fields))
+	for _, field := range fields {
+		fieldsWithWeight = append(fieldsWithWeight, boostedField(field.Name, field.Weight, lang))
+	}
+
+	multiMatch := &types.MultiMatchQuery{
+		Query:    query.Query,
+		Fields:   fieldsWithWeight,
+		Operator: esOperator(query.GetOperator().IsAnd()),
+	}
+	if query.Fuzziness != "" {
+		fuzziness := query.Fuzziness
+		multiMatch.Fuzziness = &fuzziness
+	}
+
+	return &types.Query{MultiMatch: multiMatch}
+}
+
+// buildPhraseQuery translates a phrase query to a bool query with one match_phrase
+// should clause per field; at least one field must contain the phrase
+func buildPhraseQuery(query *dquery.Phrase) *types.Query {
+	lang := query.GetLanguage()
+	slop := query.GetSlop()
+
+	shouldClauses := make([]types.Query, 0, len(query.Fields))
+	for _, field := range query.Fields {
+		matchPhraseQuery := types.MatchPhraseQuery{
+			Query: query.Query,
+		}
+		if slop > 0 {
+			matchPhraseQuery.Slop = &slop
+		}
+
+		shouldClauses = append(shouldClauses, types.Query{
+			MatchPhrase: map[string]types.MatchPhraseQuery{
+				languageField(field, lang): matchPhraseQuery,
+			},
+		})
+	}
+
+	return &types.Query{Bool: &types.BoolQuery{
+		Should:             shouldClauses,
+		MinimumShouldMatch: "1",
+	}}
+}
+
+// buildBoolQuery translates a compound query to a native bool query. The occurrence
+// semantics are identical, so clauses map one-to-one; minimum_should_match is only
+// sent when set explicitly (Elasticsearch applies the same default).
+func buildBoolQuery(query *dquery.Bool) *types.Query {
+	boolQuery := &types.BoolQuery{
+		Must:    buildClauses(query.Must),
+		Should:  buildClauses(query.Should),
+		MustNot: buildClauses(query.MustNot),
+		Filter:  buildClauses(query.Filter),
+	}
+	if query.MinimumShouldMatch > 0 {
+		boolQuery.MinimumShouldMatch = strconv.Itoa(query.MinimumShouldMatch)
+	}
+	return &types.Query{Bool: boolQuery}
+}
+
+func buildClauses(clauses []dquery.Clause) []types.Query {
+	if len(clauses) == 0 {
+		return nil
+	}
+	queries := make([]types.Query, 0, len(clauses))
+	for _, c := range clauses {
+		var q *types.Query
+		switch {
+		case c.Match != nil:
+			q = buildMatchQuery(c.Match)
+		case c.MultiMatch != nil:
+			q = buildMultiMatchQuery(c.MultiMatch)
+		case c.Phrase != nil:
+			q = buildPhraseQuery(c.Phrase)
+		case c.Bool != nil:
+			q = buildBoolQuery(c.Bool)
+		default:
+			continue
+		}
+		queries = append(queries, *q)
+	}
+	return queries
+}
+
+// buildQueryStringQuery translates a parsed query_string group to a bool query:
+// + clauses are must, - clauses must_not and the others should. Elasticsearch applies
+// the same minimum_should_match default (1 without must clauses, 0 otherwise).
+func buildQueryStringQuery(group *token.Group, lang dquery.Language) *types.Query {
+	boolQuery := &types.BoolQuery{Boost: esBoost(group.Boost)}
+	for _, clause := range group.Clauses {
+		q := buildQueryStringNode(clause.Node, lang)
+		switch clause.Occur {
+		case token.Must:
+			boolQuery.Must = append(boolQuery.Must, *q)
+		case token.MustNot:
+			boolQuery.MustNot = append(boolQuery.MustNot, *q)
+		default:
+			boolQuery.Should = append(boolQuery.Should, *q)
+		}
+	}
+	return &types.Query{Bool: boolQuery}
+}
+
+// buildQueryStringNode translates a term or phrase to a match query on the per-language
+// field of its field, or to a multi_match over the default fields when unscoped.
+// Words of one term must all match, like the & of the PostgreSQL translation.
+func buildQueryStringNode(node token.Node, lang dquery.Language) *types.Query {
+	switch n := node.(type) {
+	case *token.Group:
+		return buildQueryStringQuery(n, lang)
+	case *token.Phrase:
+		if n.Field == "" {
+			phrase := textquerytype.Phrase
+			return &types.Query{MultiMatch: &types.MultiMatchQuery{
+				Query:  n.Value,
+				Fields: defaultBoostedFields(lang),
+				Type:   &phrase,
+				Boost:  esBoost(n.Boost),
+			}}
+		}
+		return &types.Query{MatchPhrase: map[string]types.MatchPhraseQuery{
+			languageField(n.Field, lang): {Query: n.Value, Boost: esBoost(n.Boost)},
+		}}
+	case *token.Term:
+		if n.Field == "" {
+			multiMatch := &types.MultiMatchQuery{
+				Query:    n.Value,
+				Fields:   defaultBoostedFields(lang),
+				Operator: esOperator(true),
+				Boost:    esBoost(n.Boost),
+			}
+			if n.Prefix {
+				phrasePrefix := textquerytype.Phraseprefix
+				multiMatch.Type = &phrasePrefix
+			}
+			return &types.Query{MultiMatch: multiMatch}
+		}
+		field := languageField(n.Field, lang)
+		if n.Prefix {
+			return &types.Query{MatchPhrasePrefix: map[string]types.MatchPhrasePrefixQuery{
+				field: {Query: n.Value, Boost: esBoost(n.Boost)},
+			}}
+		}
+		return &types.Query{Match: map[string]types.MatchQuery{
+			field: {Query: n.Value, Operator: esOperator(true), Boost: esBoost(n.Boost)},
+		}}
+	default:
+		return &types.Query{MatchNone: &types.MatchNoneQuery{}}
+	}
+}
+
+// buildBooleanQuery translates a parsed boolean expression to nested bool queries:
+// AND → must (NOT operands → must_not), OR → should with minimum_should_match 1,
+// NOT → must_not. Terms and phrases are multi_match queries over the default fields with
+// their recommended weights, NEAR/N is an unordered intervals query per field.
+func buildBooleanQuery(node token.Node, lang dquery.Language) *types.Query {
+	fields := make([]string, 0, len(dquery.DefaultFields))
+	for _, field := range dquery.DefaultFields {
+		fields = append(fields, boostedField(field, dquery.RecommendedFieldWeights[field], lang))
+	}
+	return buildBooleanNode(node, fields, lang)
+}
+
+func buildBooleanNode(node token.Node, fields []string, lang dquery.Language) *types.Query {
+	switch n := node.(type) {
+	case *token.Term:
+		return &types.Query{MultiMatch: &types.MultiMatchQuery{
+			Query:  n.Value,
+			Fields: fields,
+		}}
+	case *token.Phrase:
+		phrase := textquerytype.Phrase
+		return &types.Query{MultiMatch: &types.MultiMatchQuery{
+			Query:  n.Value,
+			Fields: fields,
+			Type:   &phrase,
+		}}
+	case *token.And:
+		boolQuery := &types.BoolQuery{}
+		for _, operand := range n.Operands {
+			if not, ok := operand.(*token.Not); ok {
+				boolQuery.MustNot = append(boolQuery.MustNot, *buildBooleanNode(not.Operand, fields, lang))
+				continue
+			}
+			boolQuery.Must = append(boolQuery.Must, *buildBooleanNode(operand, fields, lang))
+		}
+		return &types.Query{Bool: boolQuery}
+	case *token.Or:
+		should := make([]types.Query, 0, len(n.Operands))
+		for _, operand := range n.Operands {
+			should = append(should, *buildBooleanNode(operand, fields, lang))
+		}
+		return &types.Query{Bool: &types.BoolQuery{Should: should, MinimumShouldMatch: "1"}}
+	case *token.Not:
+		return &types.Query{Bool: &types.BoolQuery{
+			MustNot: []types.Query{*buildBooleanNode(n.Operand, fields, lang)},
+		}}
+	case *token.Near:
+		return buildNearQuery(n, lang)
+	default:
+		return &types.Query{MatchNone: &types.MatchNoneQuery{}}
+	}
+}
+
+// buildNearQuery translates NEAR/N to one intervals query per default field: both operands
+// in any order with at most N positions between them. Each operand matches its words as a
+// gapless ordered sequence, so a phrase keeps its word order.
+func buildNearQuery(near *token.Near, lang dquery.Language) *types.Query {
+	maxGaps, unordered := near.Distance, false
+	operands := make([]types.Intervals, 0, 2)
+	for _, operand := range []token.Node{near.Left, near.Right} {
+		operands = append(operands, types.Intervals{Match: nearOperandInterval(operand)})
+	}
+
+	should := make([]types.Query, 0, len(dquery.DefaultFields))
+	for _, field := range dquery.DefaultFields {
+		should = append(should, types.Query{Intervals: map[string]types.IntervalsQuery{
+			languageField(field, lang): {
+				AllOf: &types.IntervalsAllOf{
+					Intervals: operands,
+					MaxGaps:   &maxGaps,
+					Ordered:   &unordered,
+				},
+				Boost: esBoost(dquery.RecommendedFieldWeights[field]),
+			},
+		}})
+	}
+	return &types.Query{Bool: &types.BoolQuery{Should: should, MinimumShouldMatch: "1"}}
+}
+
+func nearOperandInterval(node token.Node) *types.IntervalsMatch {
+	noGaps, ordered := 0, true
+	var text string
+	switch n := node.(type) {
+	case *token.Term:
+		text = n.Value
+	case *token.Phrase:
+		text = n.Value
+	}
+	return &types.IntervalsMatch{Query: text, MaxGaps: &noGaps, Ordered: &ordered}
+}
+
+// defaultBoostedFields lists the default fields with their default weights
+func defaultBoostedFields(lang dquery.Language) []string {
+	fields := make([]string, 0, len(dquery.DefaultFields))
+	for _, field := range dquery.DefaultFields {
+		fields = append(fields, boostedField(field, dquery.DefaultFieldWeights[field], lang))
+	}
+	return fields
+}
+
+// esBoost returns the boost to send, or nil for the default boost of 1
+func esBoost(boost float64) *float32 {
+	if boost == 1 {
+		return nil
+	}
+	b := float32(boost)
+	return &b
+}
+
+// esOperator maps the AND/OR flag of a domain operator to the ES operator enum
+func esOperator(and bool) *operator.Operator {
+	op := operator.Or
+	if and {
+		op = operator.And
+	}
+	return &op
+}
+
+// compileQuery builds a full-text query exactly as the matching Search method does
+func compileQuery(query *dquery.Base) (*types.Query, error) {
+	switch query.Kind {
+	case dquery.StringType:
+		parser := token.NewQueryStringParser(
+			token.WithDefaultOperator(query.QueryString.GetDefaultOperator()),
+			token.WithFields(dquery.QueryStringFields...),
+		)
+		root, err := parser.Parse(query.QueryString.Query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse query_string: %w", err)
+		}
+		return buildQueryStringQuery(root, query.QueryString.GetLanguage()), nil
+	case dquery.MatchType:
+		return buildMatchQuery(query.Match), nil
+	case dquery.MultiMatchType:
+		return buildMultiMatchQuery(query.MultiMatch), nil
+	case dquery.PhraseType:
+		return buildPhraseQuery(query.Phrase), nil
+	case dquery.BooleanType:
+		root, err := token.NewBooleanParser().Parse(query.Boolean.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean query expression: %w", err)
+		}
+		return buildBooleanQuery(root, query.Boolean.GetLanguage()), nil
+	case dquery.BoolType:
+		return buildBoolQuery(query.Bool), nil
+	default:
+		return nil, fmt.Errorf("unsupported full-text query kind %q", query.Kind)
+	}
+}
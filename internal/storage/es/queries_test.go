@@ -0,0 +1,135 @@
+package es
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token/tokentest"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// The compiled bool query must match exactly the documents the parsed expression matches:
+// random expressions are evaluated against random term matches by the reference
+// evaluator of the AST and by a bool query evaluator (pg: TestBooleanTsQuery_AgreesWithAST)
+func TestBuildBooleanQuery_AgreesWithAST(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	parser := token.NewBooleanParser()
+
+	for i := 0; i < 500; i++ {
+		expression := tokentest.RandomExpression(r, 4)
+		node, err := parser.Parse(expression)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", expression, err)
+		}
+		q := buildBooleanQuery(node, dquery.LanguageEnglish)
+
+		for j := 0; j < 16; j++ {
+			matches := tokentest.RandomMatch(r)
+			want := tokentest.Eval(node, matches)
+			if got := evalQuery(t, q, matches); got != want {
+				t.Fatalf("%q: matches %v = %v, want %v (AST %s)",
+					expression, matches, got, want, tokentest.Format(node))
+			}
+		}
+	}
+}
+
+func TestBuildBooleanQuery_Leaves(t *testing.T) {
+	node, err := token.NewBooleanParser().Parse(`"climate change" AND NOT coal`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	q := buildBooleanQuery(node, dquery.LanguageEnglish)
+	if q.Bool == nil || len(q.Bool.Must) != 1 || len(q.Bool.MustNot) != 1 {
+		t.Fatalf("expected bool with one must and one must_not clause, got %+v", q)
+	}
+
+	phrase := q.Bool.Must[0].MultiMatch
+	if phrase == nil || phrase.Query != "climate change" || phrase.Type == nil || phrase.Type.String() != "phrase" {
+		t.Errorf("must clause = %+v, want a phrase multi_match", q.Bool.Must[0])
+	}
+	term := q.Bool.MustNot[0].MultiMatch
+	if term == nil || term.Query != "coal" || term.Type != nil {
+		t.Errorf("must_not clause = %+v, want a multi_match on coal", q.Bool.MustNot[0])
+	}
+}
+
+func TestBuildBooleanQuery_Near(t *testing.T) {
+	node, err := token.NewBooleanParser().Parse(`"carbon tax" NEAR/3 reform`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	q := buildBooleanQuery(node, dquery.LanguageEnglish)
+	if q.Bool == nil || len(q.Bool.Should) != len(dquery.DefaultFields) {
+		t.Fatalf("expected bool with one should clause per default field, got %+v", q)
+	}
+
+	intervals, ok := q.Bool.Should[0].Intervals["title_en"]
+	if !ok || intervals.AllOf == nil {
+		t.Fatalf("first should clause = %+v, want all_of intervals on title_en", q.Bool.Should[0])
+	}
+	allOf := intervals.AllOf
+	if *allOf.MaxGaps != 3 || *allOf.Ordered {
+		t.Errorf("all_of max_gaps = %d, ordered = %v, want 3 and false", *allOf.MaxGaps, *allOf.Ordered)
+	}
+	if len(allOf.Intervals) != 2 || allOf.Intervals[0].Match.Query != "carbon tax" || allOf.Intervals[1].Match.Query != "reform" {
+		t.Errorf("all_of intervals = %+v, want matches on the two operands", allOf.Intervals)
+	}
+	if phrase := allOf.Intervals[0].Match; *phrase.MaxGaps != 0 || !*phrase.Ordered {
+		t.Errorf("phrase operand = %+v, want an ordered match without gaps", phrase)
+	}
+	if intervals.Boost == nil || *intervals.Boost != 3 {
+		t.Errorf("title boost = %v, want 3", intervals.Boost)
+	}
+}
+
+// evalQuery evaluates the bool / multi_match queries built by buildBooleanQuery with
+// Elasticsearch semantics; a multi_match is looked up by its query text
+func evalQuery(t *testing.T, q *types.Query, matches map[string]bool) bool {
+	t.Helper()
+	switch {
+	case q.MultiMatch != nil:
+		return matches[q.MultiMatch.Query]
+	case q.Bool != nil:
+		b := q.Bool
+		for i := range b.Must {
+			if !evalQuery(t, &b.Must[i], matches) {
+				return false
+			}
+		}
+		for i := range b.MustNot {
+			if evalQuery(t, &b.MustNot[i], matches) {
+				return false
+			}
+		}
+
+		// Elasticsearch default: one should clause is required without must / filter clauses
+		minimumShouldMatch := 0
+		if len(b.Must) == 0 && len(b.Filter) == 0 && len(b.Should) > 0 {
+			minimumShouldMatch = 1
+		}
+		if b.MinimumShouldMatch != nil {
+			n, err := strconv.Atoi(fmt.Sprint(b.MinimumShouldMatch))
+			if err != nil {
+				t.Fatalf("unexpected minimum_should_match %v", b.MinimumShouldMatch)
+			}
+			minimumShouldMatch = n
+		}
+		matched := 0
+		for i := range b.Should {
+			if evalQuery(t, &b.Should[i], matches) {
+				matched++
+			}
+		}
+		return matched >= minimumShouldMatch
+	default:
+		t.Fatalf("unexpected query %+v", q)
+		return false
+	}
+}
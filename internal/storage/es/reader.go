@@ -0,0 +1,192 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/google/uuid"
+)
+
+// similarMaxQueryTerms mirrors the more_like_this max_query_terms default and the PG fallback.
+const similarMaxQueryTerms = 25
+
+// similarFields are the text fields used by the more_like_this fallback.
+var similarFields = []string{"title", "description", "content"}
+
+// Reader is the Elasticsearch implementation of storage.Reader and storage.SimilarSearcher.
+// Documents are keyed by _id == article UUID (see Indexer).
+type Reader struct {
+	client    *elasticsearch.TypedClient
+	indexName string
+}
+
+func NewReader(config ClientConfig) (*Reader, error) {
+	client, err := newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Elasticsearch client: %w", err)
+	}
+	return &Reader{
+		client:    client,
+		indexName: config.IndexName,
+	}, nil
+}
+
+func (r *Reader) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]document.Article, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	idStrs := make([]string, len(ids))
+	for i, id := range ids {
+		idStrs[i] = id.String()
+	}
+
+	res, err := r.client.Search().
+		Index(r.indexName).
+		Query(&types.Query{Ids: &types.IdsQuery{Values: idStrs}}).
+		SourceExcludes_("embedding").
+		Size(len(ids)).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query articles by ids: %w", err)
+	}
+
+	articles := make([]document.Article, 0, len(res.Hits.Hits))
+	for _, hit := range res.Hits.Hits {
+		var doc ArticleDocument
+		if err := json.Unmarshal(hit.Source_, &doc); err != nil {
+			return nil, fmt.Errorf("unmarshal article source: %w", err)
+		}
+		article, err := doc.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, article)
+	}
+
+	return articles, nil
+}
+
+// SearchSimilar implements storage.SimilarSearcher.
+// Articles with a stored embedding are matched with kNN on the embedding field;
+// otherwise a more_like_this query over the article's text fields is used.
+func (r *Reader) SearchSimilar(ctx context.Context, id uuid.UUID, baseOpts *dquery.BaseOptions) (*storage.SimilarResult, error) {
+	source, err := r.client.Search().
+		Index(r.indexName).
+		Query(&types.Query{Ids: &types.IdsQuery{Values: []string{id.String()}}}).
+		SourceIncludes_("id", "embedding", "embedding_model").
+		Size(1).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch source article: %w", err)
+	}
+	if len(source.Hits.Hits) == 0 {
+		return nil, storage.ErrArticleNotFound
+	}
+
+	var src struct {
+		Embedding []float32 `json:"embedding"`
+		Model     string    `json:"embedding_model"`
+	}
+	if err := json.Unmarshal(source.Hits.Hits[0].Source_, &src); err != nil {
+		return nil, fmt.Errorf("unmarshal source article: %w", err)
+	}
+
+	// The source article itself is never a neighbour
+	excludeSelf := types.Query{
+		Bool: &types.BoolQuery{
+			MustNot: []types.Query{{Ids: &types.IdsQuery{Values: []string{id.String()}}}},
+		},
+	}
+
+	size := baseOpts.Size
+	searchReq := r.client.Search().
+		Index(r.indexName).
+		SourceExcludes_("embedding").
+		Size(size)
+
+	strategy := storage.SimilarityLexical
+	if len(src.Embedding) > 0 {
+		strategy = storage.SimilarityVector
+
+		numCandidates := max(size*10, minNumCandidates)
+		filters := append(buildFilterQueries(baseOpts.Filters), excludeSelf)
+		if src.Model != "" {
+			// Neighbours must be embedded with the same model to be comparable
+			filters = append(filters, types.Query{
+				Term: map[string]types.TermQuery{"embedding_model": {Value: src.Model}},
+			})
+		}
+		searchReq = searchReq.Knn(types.KnnSearch{
+			Field:         "embedding",
+			QueryVector:   src.Embedding,
+			K:             &size,
+			NumCandidates: &numCandidates,
+			Filter:        filters,
+		})
+	} else {
+		minTermFreq := 1
+		maxQueryTerms := similarMaxQueryTerms
+		index, docID := r.indexName, id.String()
+		mlt := &types.Query{
+			MoreLikeThis: &types.MoreLikeThisQuery{
+				Fields:        similarFields,
+				Like:          []types.Like{types.LikeDocument{Index_: &index, Id_: &docID}},
+				MinTermFreq:   &minTermFreq,
+				MaxQueryTerms: &maxQueryTerms,
+			},
+		}
+		searchReq = searchReq.Query(withFilters(mlt, baseOpts.Filters))
+	}
+
+	slog.Info("Executing es similar articles search",
+		"id", id,
+		"strategy", strategy,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", size)
+
+	res, err := searchReq.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute similar articles search: %w", err)
+	}
+
+	maxScore := dquery.CalcSafeScore((*float64)(res.Hits.MaxScore))
+	hits := make([]dto.ArticleSearchResult, 0, len(res.Hits.Hits))
+	for _, hit := range res.Hits.Hits {
+		var doc ArticleDocument
+		if err := json.Unmarshal(hit.Source_, &doc); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal document: %w", err)
+		}
+		article, err := doc.toDomain()
+		if err != nil {
+			return nil, err
+		}
+
+		var score float64
+		if hit.Score_ != nil {
+			score = float64(*hit.Score_)
+		}
+		hits = append(hits, dto.ArticleSearchResult{
+			Article:         dto.ArticleFromDocument(article),
+			Score:           utils.RoundFloat64(score, dquery.ScoreDecimalPlaces),
+			ScoreNormalized: utils.RoundFloat64(score/maxScore, dquery.ScoreDecimalPlaces),
+		})
+	}
+
+	return &storage.SimilarResult{Hits: hits, Strategy: strategy}, nil
+}
+
+// Compile-time interface assertions
+var (
+	_ storage.Reader          = (*Reader)(nil)
+	_ storage.SimilarSearcher = (*Reader)(nil)
+)
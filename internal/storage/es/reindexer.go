@@ -0,0 +1,183 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+// Reindexer builds a new version of the index next to the serving one and swaps the read and
+// write aliases to it in one atomic request, so searches never see a partial index. Writes
+// keep going to the serving index while it is being built; pause ingestion for the duration
+// of a reindex or re-run the ingest afterwards.
+type Reindexer struct {
+	client       *elasticsearch.TypedClient
+	alias        string
+	config       ClientConfig
+	indexBuilder *IndexBuilder
+}
+
+func NewReindexer(config ClientConfig) (*Reindexer, error) {
+	client, err := newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Elasticsearch client: %w", err)
+	}
+
+	return &Reindexer{
+		client:       client,
+		alias:        config.IndexName,
+		config:       config,
+		indexBuilder: NewIndexBuilder(),
+	}, nil
+}
+
+// Serving returns the indices behind the read alias, or the legacy unversioned index
+func (r *Reindexer) Serving(ctx context.Context) (indices []string, legacy bool, err error) {
+	return servingIndices(ctx, r.client, r.alias)
+}
+
+// CreateIndex applies the index lifecycle and creates the index of a version without aliases,
+// so it receives no traffic until SwapAliases. The index must not exist yet.
+func (r *Reindexer) CreateIndex(ctx context.Context, version int) (string, error) {
+	if err := applyLifecycle(ctx, r.client, r.alias); err != nil {
+		return "", err
+	}
+
+	index := VersionedIndex(r.alias, version)
+	exists, err := r.client.Indices.Exists(index).Do(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to check if index exists: %w", err)
+	}
+	if exists {
+		return "", fmt.Errorf("index %q already exists, delete it to rebuild the version", index)
+	}
+
+	if err := createIndex(ctx, r.client, index, r.indexBuilder, nil); err != nil {
+		return "", err
+	}
+	return index, nil
+}
+
+// languageFieldsSource copies the text fields of a document to the per-language fields of
+// its language, like ArticleDocument.setLanguageFields
+const languageFieldsSource = `
+	String suffix = params.suffixes[ctx._source.language];
+	if (suffix != null) {
+		for (String field : params.fields) {
+			if (ctx._source[field] != null) {
+				ctx._source[field + '_' + suffix] = ctx._source[field];
+			}
+		}
+	}`
+
+// languageFieldsScript returns the _reindex script filling the per-language fields, which
+// indices of older versions do not have
+func languageFieldsScript() (*types.Script, error) {
+	suffixes, err := json.Marshal(languageSuffixes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode language suffixes: %w", err)
+	}
+	fields := make([]string, 0, len(languageTextFields))
+	for field := range languageTextFields {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	encodedFields, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode language fields: %w", err)
+	}
+
+	source := languageFieldsSource
+	return &types.Script{
+		Source: &source,
+		Params: map[string]json.RawMessage{"suffixes": suffixes, "fields": encodedFields},
+	}, nil
+}
+
+// CopyIndex copies every document of the source indices into dest with the _reindex API and
+// waits for it to complete. Documents keep their ids; dest applies its own mappings, and the
+// per-language fields are filled from the language of every document.
+func (r *Reindexer) CopyIndex(ctx context.Context, sources []string, dest string) (int64, error) {
+	slog.Info("Copying index", "sources", sources, "dest", dest)
+
+	script, err := languageFieldsScript()
+	if err != nil {
+		return 0, err
+	}
+	res, err := r.client.Reindex().
+		Source(&types.ReindexSource{Index: sources}).
+		Dest(&types.ReindexDestination{Index: dest}).
+		Script(script).
+		WaitForCompletion(true).
+		Do(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reindex %v into %q: %w", sources, dest, err)
+	}
+	if len(res.Failures) > 0 {
+		first := res.Failures[0]
+		reason := first.Cause.Type
+		if first.Cause.Reason != nil {
+			reason += ": " + *first.Cause.Reason
+		}
+		return 0, fmt.Errorf("failed to reindex %d documents into %q, first %s: %s",
+			len(res.Failures), dest, first.Id, reason)
+	}
+
+	var copied int64
+	if res.Total != nil {
+		copied = *res.Total
+	}
+	return copied, nil
+}
+
+// Indexer returns an Indexer that writes to dest directly, to load it from another source
+func (r *Reindexer) Indexer(dest string) *Indexer {
+	return &Indexer{
+		client:       r.client,
+		alias:        r.alias,
+		indexName:    dest,
+		config:       r.config,
+		indexBuilder: r.indexBuilder,
+	}
+}
+
+// Embedder returns an Embedder that writes to dest directly
+func (r *Reindexer) Embedder(dest string) *Embedder {
+	return &Embedder{client: r.client, indexName: dest}
+}
+
+// SwapAliases refreshes dest and atomically moves the read and write aliases to it from the
+// serving indices. With deleteOld the serving indices are deleted in the same request; a
+// legacy unversioned index always is, since the alias takes its name.
+func (r *Reindexer) SwapAliases(ctx context.Context, dest string, deleteOld bool) error {
+	serving, legacy, err := r.Serving(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.client.Indices.Refresh().Index(dest).Do(ctx); err != nil {
+		return fmt.Errorf("failed to refresh index %q: %w", dest, err)
+	}
+
+	actions := swapActions(r.alias, serving, legacy, dest, deleteOld)
+	res, err := r.client.Indices.UpdateAliases().Actions(actions...).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to swap aliases to %q: %w", dest, err)
+	}
+	if !res.Acknowledged {
+		return fmt.Errorf("alias swap to %q was not acknowledged", dest)
+	}
+
+	slog.Info("Aliases swapped",
+		"alias", r.alias,
+		"write_alias", WriteAlias(r.alias),
+		"from", serving,
+		"to", dest,
+		"deleted_old", deleteOld || legacy)
+	return nil
+}
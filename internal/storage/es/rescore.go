@@ -0,0 +1,96 @@
+package es
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/fieldvaluefactormodifier"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/functionboostmode"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/functionscoremode"
+)
+
+// scoreFields maps score fields to numeric fields of the index mapping (see IndexBuilder.buildMapping).
+var scoreFields = map[dquery.ScoreField]string{
+	dquery.ScoreFieldLanguageConfidence: "language_confidence",
+}
+
+// withRescoring wraps the query in a function_score query multiplying the query score by
+// every function: a date decay on published_at, one weight per source (filtered on the
+// source keyword) and field_value_factor functions. Sources without a weight match no
+// weight function and keep a factor of 1. A nil rescoring returns the query unchanged.
+func withRescoring(q *types.Query, r *dquery.Rescoring) *types.Query {
+	if r == nil {
+		return q
+	}
+
+	var functions []types.FunctionScore
+	if d := r.Recency; d != nil {
+		origin := d.Origin.UTC().Format(time.RFC3339Nano)
+		decay := types.Float64(d.Decay)
+		decayFunction := types.DateDecayFunction{
+			DecayFunctionBaseDateMathDuration: map[string]types.DecayPlacementDateMathDuration{
+				"published_at": {
+					Origin: &origin,
+					Scale:  esSeconds(d.Scale),
+					Offset: esSeconds(d.Offset),
+					Decay:  &decay,
+				},
+			},
+		}
+		if d.Function == dquery.DecayGauss {
+			functions = append(functions, types.FunctionScore{Gauss: decayFunction})
+		} else {
+			functions = append(functions, types.FunctionScore{Exp: decayFunction})
+		}
+	}
+
+	sources := make([]string, 0, len(r.SourceWeights))
+	for source := range r.SourceWeights {
+		sources = append(sources, source)
+	}
+	slices.Sort(sources)
+	for _, source := range sources {
+		weight := types.Float64(r.SourceWeights[source])
+		functions = append(functions, types.FunctionScore{
+			Filter: &types.Query{Term: map[string]types.TermQuery{
+				filterFields[dquery.FilterSourceName]: {Value: source},
+			}},
+			Weight: &weight,
+		})
+	}
+
+	for _, f := range r.FieldValueFactors {
+		factor, missing := types.Float64(f.Factor), types.Float64(f.Missing)
+		modifier := fieldvaluefactormodifier.None
+		switch f.Modifier {
+		case dquery.ModifierLog1p:
+			modifier = fieldvaluefactormodifier.Log1p
+		case dquery.ModifierSqrt:
+			modifier = fieldvaluefactormodifier.Sqrt
+		}
+		functions = append(functions, types.FunctionScore{
+			FieldValueFactor: &types.FieldValueFactorScoreFunction{
+				Field:    scoreFields[f.Field],
+				Factor:   &factor,
+				Missing:  &missing,
+				Modifier: &modifier,
+			},
+		})
+	}
+
+	scoreMode, boostMode := functionscoremode.Multiply, functionboostmode.Multiply
+	return &types.Query{FunctionScore: &types.FunctionScoreQuery{
+		Query:     q,
+		Functions: functions,
+		ScoreMode: &scoreMode,
+		BoostMode: &boostMode,
+	}}
+}
+
+// esSeconds renders a duration in whole seconds, the resolution rescoring is validated to
+func esSeconds(d time.Duration) string {
+	return fmt.Sprintf("%ds", int64(d.Seconds()))
+}
@@ -0,0 +1,46 @@
+package es
+
+import (
+	"testing"
+	"time"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+)
+
+func TestWithRescoring(t *testing.T) {
+	q := &types.Query{MatchAll: &types.MatchAllQuery{}}
+	if got := withRescoring(q, nil); got != q {
+		t.Fatalf("nil rescoring should return the query unchanged, got %+v", got)
+	}
+
+	r := &dquery.Rescoring{
+		Recency:       &dquery.RecencyDecay{Function: dquery.DecayGauss, Origin: time.Now(), Scale: 48 * time.Hour, Decay: 0.5},
+		SourceWeights: map[string]float64{"Reuters": 1.5, "AP": 1.2},
+		FieldValueFactors: []dquery.FieldValueFactor{
+			{Field: dquery.ScoreFieldLanguageConfidence, Factor: 1, Modifier: dquery.ModifierSqrt, Missing: 1},
+		},
+	}
+	fs := withRescoring(q, r).FunctionScore
+	if fs == nil || fs.Query != q {
+		t.Fatalf("expected a function_score query wrapping the query")
+	}
+	if fs.ScoreMode.String() != "multiply" || fs.BoostMode.String() != "multiply" {
+		t.Errorf("score_mode = %s, boost_mode = %s, want multiply", fs.ScoreMode, fs.BoostMode)
+	}
+	if len(fs.Functions) != 4 {
+		t.Fatalf("expected gauss, two weights and a field_value_factor, got %d functions", len(fs.Functions))
+	}
+
+	gauss, ok := fs.Functions[0].Gauss.(types.DateDecayFunction)
+	if !ok || gauss.DecayFunctionBaseDateMathDuration["published_at"].Scale != "172800s" {
+		t.Errorf("first function = %+v, want a gauss decay on published_at with scale 172800s", fs.Functions[0])
+	}
+	source := fs.Functions[1]
+	if source.Filter.Term["source_name.keyword"].Value != "AP" || float64(*source.Weight) != 1.2 {
+		t.Errorf("second function = %+v, want the AP weight (sources sorted)", source)
+	}
+	if f := fs.Functions[3].FieldValueFactor; f == nil || f.Field != "language_confidence" || f.Modifier.String() != "sqrt" {
+		t.Errorf("last function = %+v, want a sqrt field_value_factor on language_confidence", fs.Functions[3])
+	}
+}
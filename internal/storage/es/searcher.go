@@ -3,25 +3,28 @@ package es
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"slices"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	"github.com/DjordjeVuckovic/news-hunter/internal/token"
 	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
 	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
 	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/core/search"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
-	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/operator"
-	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/totalhitsrelation"
 	"github.com/google/uuid"
 )
 
 type Searcher struct {
 	client    *elasticsearch.TypedClient
 	indexName string
-	tokenizer *token.BoolTokenizer
 }
 
 func NewSearcher(config ClientConfig) (*Searcher, error) {
@@ -34,134 +37,40 @@ func NewSearcher(config ClientConfig) (*Searcher, error) {
 	return &Searcher{
 		client:    client,
 		indexName: config.IndexName,
-		tokenizer: token.NewBoolTokenizer(),
 	}, nil
 }
 
 // SearchStringQuery implements the storage.FtsSearcher interface.
-// Performs simple string-based search using Elasticsearch's multi_match query with BM25
-// Application determines optimal fields and weights based on index configuration
+// Parses the query_string syntax (field scopes, phrases, prefixes, boosts, +/- and groups)
+// and translates it to a bool query; unscoped terms search the default fields with BM25
 func (r *Searcher) SearchStringQuery(ctx context.Context, query *dquery.String, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
-	// Use default fields with default weights (application-determined)
-	cursor, size := baseOpts.Cursor, baseOpts.Size
-	fields := dquery.DefaultFields
-	fieldWeights := dquery.DefaultFieldWeights
 	queryOperator := query.GetDefaultOperator()
+	lang := query.GetLanguage()
 
 	slog.Info("Executing es query_string search",
 		"query", query.Query,
-		"language", query.GetLanguage(),
-		"fields", fields,
+		"language", lang,
+		"fields", dquery.DefaultFields,
 		"operator", queryOperator,
-		"has_cursor", cursor != nil,
-		"size", size)
-
-	// Build field list with boosting from application defaults
-	// Format: "title^1.0", "description^1.0", "content^1.0"
-	fieldsWithBoost := make([]string, 0, len(fields))
-	for _, field := range fields {
-		weight := fieldWeights[field]
-		if weight != 1.0 {
-			fieldsWithBoost = append(fieldsWithBoost, fmt.Sprintf("%s^%.1f", field, weight))
-		} else {
-			fieldsWithBoost = append(fieldsWithBoost, field)
-		}
-	}
-
-	// Build multi_match query
-	multiMatch := &types.MultiMatchQuery{
-		Query:  query.Query,
-		Fields: fieldsWithBoost,
-	}
-
-	// Set operator (AND/OR)
-	if queryOperator == "and" {
-		and := operator.And
-		multiMatch.Operator = &and
-	} else {
-		or := operator.Or
-		multiMatch.Operator = &or
-	}
-
-	slog.Debug("Elasticsearch multi_match query",
-		"fields_with_boost", fieldsWithBoost,
-		"operator", queryOperator)
-
-	searchReq := r.client.Search().
-		Index(r.indexName).
-		Query(&types.Query{
-			MultiMatch: multiMatch,
-		}).
-		Size(size + 1).
-		TrackScores(true)
-
-	if cursor != nil {
-		searchReq = searchReq.SearchAfter(
-			types.FieldValue(cursor.Score),
-			types.FieldValue(cursor.ID.String()),
-		)
-	}
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
 
-	sortOrderDesc := sortorder.Desc
-	searchReq = searchReq.Sort(
-		&types.SortOptions{
-			SortOptions: map[string]types.FieldSort{
-				"_score": {Order: &sortOrderDesc},
-			},
-		},
-		&types.SortOptions{
-			SortOptions: map[string]types.FieldSort{
-				"id": {Order: &sortOrderDesc},
-			},
-		},
+	parser := token.NewQueryStringParser(
+		token.WithDefaultOperator(queryOperator),
+		token.WithFields(dquery.QueryStringFields...),
 	)
-
-	var err error
-
-	res, err := searchReq.Do(ctx)
-	if err != nil {
-		slog.Error("Elasticsearch query failed", "error", err, "query", query.Query, "cursor", cursor != nil)
-		return nil, fmt.Errorf("failed to execute search: %w", err)
-	}
-
-	maxScore := dquery.CalcSafeScore((*float64)(res.Hits.MaxScore))
-
-	articles, rawScores, err := r.mapToResult(res.Hits.Hits, maxScore)
+	root, err := parser.Parse(query.Query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to map search results to types: %w", err)
-	}
-
-	slog.Info("Es search results fetched",
-		"total_matches", res.Hits.Total.Value,
-		"returned_count", len(articles),
-		"max_score", *res.Hits.MaxScore,
-		"normalized_max", maxScore)
-
-	hasMore := len(articles) > size
-	if hasMore {
-		articles = articles[:size]
-		rawScores = rawScores[:size]
+		return nil, fmt.Errorf("failed to parse query_string: %w", err)
 	}
 
-	var nextCursor *dquery.Cursor
-	if hasMore && len(articles) > 0 {
-		nextCursor = &dquery.Cursor{
-			Score: rawScores[len(rawScores)-1],
-			ID:    articles[len(articles)-1].Article.ID,
-		}
-	}
+	slog.Debug("Parsed query_string", "input", query.Query, "parsed", root.String())
 
-	return &storage.SearchResult{
-		Hits:         articles,
-		NextCursor:   nextCursor,
-		HasMore:      hasMore,
-		MaxScore:     utils.RoundFloat64(float64(*res.Hits.MaxScore), dquery.ScoreDecimalPlaces),
-		PageMaxScore: utils.RoundFloat64(rawScores[0], dquery.ScoreDecimalPlaces),
-		TotalMatches: res.Hits.Total.Value,
-	}, nil
+	return r.execute(ctx, dquery.StringType, buildQueryStringQuery(root, lang), lang, baseOpts)
 }
 
-func (r *Searcher) mapToResult(hits []types.Hit, maxScore float64) ([]dto.ArticleSearchResult, []float64, error) {
+func (r *Searcher) mapToResult(hits []types.Hit) ([]dto.ArticleSearchResult, []float64, error) {
 	if hits == nil {
 		return make([]dto.ArticleSearchResult, 0), make([]float64, 0), nil
 	}
@@ -191,16 +100,25 @@ func (r *Searcher) mapToResult(hits []types.Hit, maxScore float64) ([]dto.Articl
 				PublishedAt: doc.PublishedAt,
 				Category:    doc.Category,
 				ImportedAt:  doc.ImportedAt,
+
+				DetectedLanguage:   doc.DetectedLanguage,
+				LanguageConfidence: doc.LanguageConfidence,
+				LanguageMismatch:   doc.LanguageMismatch,
 			},
 		}
 
 		rawScore := float64(*hit.Score_)
-		normalizedRank := rawScore / maxScore
 
 		searchResult := dto.ArticleSearchResult{
-			Article:         article,
-			ScoreNormalized: normalizedRank,
-			Score:           float64(*hit.Score_),
+			Article: article,
+			Score:   rawScore,
+		}
+		if len(hit.Highlight) > 0 {
+			searchResult.Highlight = mapHighlight(hit.Highlight)
+		}
+		if hit.Explanation_ != nil {
+			searchResult.Explanation = mapExplanation(hit.Explanation_)
+			searchResult.Explanation.Round(dquery.ScoreDecimalPlaces)
 		}
 
 		articles = append(articles, searchResult)
@@ -213,434 +131,201 @@ func (r *Searcher) mapToResult(hits []types.Hit, maxScore float64) ([]dto.Articl
 // SearchField implements storage.SingleMatchSearcher interface
 // Performs single-field match query using Elasticsearch's match query
 func (r *Searcher) SearchField(ctx context.Context, query *dquery.Match, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
-	cursor, size := baseOpts.Cursor, baseOpts.Size
+	lang := query.GetLanguage()
+	field := languageField(query.Field, lang)
+
 	slog.Info("Executing es match search",
 		"query", query.Query,
-		"field", query.Field,
+		"field", field,
+		"language", lang,
 		"operator", query.GetOperator(),
 		"fuzziness", query.Fuzziness,
-		"has_cursor", cursor != nil,
-		"size", size)
-
-	// Build single-field match query
-	matchQuery := &types.MatchQuery{
-		Query: query.Query,
-	}
-
-	// Set operator using value object
-	if query.GetOperator().IsAnd() {
-		and := operator.And
-		matchQuery.Operator = &and
-	} else {
-		or := operator.Or
-		matchQuery.Operator = &or
-	}
-
-	// Set fuzziness if specified
-	if query.Fuzziness != "" {
-		matchQuery.Fuzziness = &query.Fuzziness
-	}
-
-	slog.Debug("Elasticsearch match query",
-		"field", query.Field,
-		"operator", query.GetOperator(),
-		"fuzziness", query.Fuzziness)
-
-	// Build search request with match query on specific field
-	searchReq := r.client.Search().
-		Index(r.indexName).
-		Query(&types.Query{
-			Match: map[string]types.MatchQuery{
-				query.Field: *matchQuery,
-			},
-		}).
-		Size(size + 1).
-		TrackScores(true)
-
-	// Add cursor support and sorting
-	if cursor != nil {
-		searchReq = searchReq.SearchAfter(
-			types.FieldValue(cursor.Score),
-			types.FieldValue(cursor.ID.String()),
-		)
-	}
-
-	sortOrderDesc := sortorder.Desc
-	searchReq = searchReq.Sort(
-		&types.SortOptions{
-			SortOptions: map[string]types.FieldSort{
-				"_score": {Order: &sortOrderDesc},
-			},
-		},
-		&types.SortOptions{
-			SortOptions: map[string]types.FieldSort{
-				"id": {Order: &sortOrderDesc},
-			},
-		},
-	)
-
-	// Execute query
-	res, err := searchReq.Do(ctx)
-	if err != nil {
-		slog.Error("Elasticsearch match query failed", "error", err, "query", query.Query, "field", query.Field)
-		return nil, fmt.Errorf("failed to execute match search: %w", err)
-	}
-
-	maxScore := dquery.CalcSafeScore((*float64)(res.Hits.MaxScore))
-
-	articles, rawScores, err := r.mapToResult(res.Hits.Hits, maxScore)
-	if err != nil {
-		return nil, fmt.Errorf("failed to map search results to types: %w", err)
-	}
-
-	slog.Info("ES match search results fetched",
-		"total_matches", res.Hits.Total.Value,
-		"returned_count", len(articles),
-		"max_score", *res.Hits.MaxScore,
-		"normalized_max", maxScore)
-
-	hasMore := len(articles) > size
-	if hasMore {
-		articles = articles[:size]
-		rawScores = rawScores[:size]
-	}
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
 
-	var nextCursor *dquery.Cursor
-	if hasMore && len(articles) > 0 {
-		nextCursor = &dquery.Cursor{
-			Score: rawScores[len(rawScores)-1],
-			ID:    articles[len(articles)-1].Article.ID,
-		}
-	}
-
-	return &storage.SearchResult{
-		Hits:         articles,
-		NextCursor:   nextCursor,
-		HasMore:      hasMore,
-		MaxScore:     utils.RoundFloat64(float64(*res.Hits.MaxScore), dquery.ScoreDecimalPlaces),
-		PageMaxScore: utils.RoundFloat64(rawScores[0], dquery.ScoreDecimalPlaces),
-		TotalMatches: res.Hits.Total.Value,
-	}, nil
+	return r.execute(ctx, dquery.MatchType, buildMatchQuery(query), lang, baseOpts)
 }
 
 // SearchFields implements storage.MultiMatchSearcher interface
 // Performs multi-field match query using Elasticsearch's multi_match query
 func (r *Searcher) SearchFields(ctx context.Context, query *dquery.MultiMatch, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
-	cursor, size := baseOpts.Cursor, baseOpts.Size
+	lang := query.GetLanguage()
+
 	slog.Info("Executing es multi_match search",
 		"query", query.Query,
 		"fields", query.Fields,
+		"language", lang,
 		"operator", query.GetOperator(),
-		"has_cursor", cursor != nil,
-		"size", size)
-
-	// Extract query parameters
-	fields := query.GetFields()
-	queryOperator := query.GetOperator()
-
-	// Build field list with boosting
-	fieldsWithWeight := make([]string, 0, len(fields))
-	for _, field := range fields {
-		if field.Weight != 1.0 {
-			fieldsWithWeight = append(fieldsWithWeight, fmt.Sprintf("%s^%.1f", field.Name, field.Weight))
-		} else {
-			fieldsWithWeight = append(fieldsWithWeight, field.Name)
-		}
-	}
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
 
-	// Build multi_match query
-	multiMatch := &types.MultiMatchQuery{
-		Query:  query.Query,
-		Fields: fieldsWithWeight,
-	}
-
-	// Set operator using value object
-	if queryOperator.IsAnd() {
-		and := operator.And
-		multiMatch.Operator = &and
-	} else {
-		or := operator.Or
-		multiMatch.Operator = &or
-	}
-
-	// Build and execute search request
-	searchReq := r.client.Search().
-		Index(r.indexName).
-		Query(&types.Query{
-			MultiMatch: multiMatch,
-		}).
-		Size(size + 1).
-		TrackScores(true)
-
-	// Add cursor support and sorting
-	if cursor != nil {
-		searchReq = searchReq.SearchAfter(
-			types.FieldValue(cursor.Score),
-			types.FieldValue(cursor.ID.String()),
-		)
-	}
-
-	sortOrderDesc := sortorder.Desc
-	searchReq = searchReq.Sort(
-		&types.SortOptions{
-			SortOptions: map[string]types.FieldSort{
-				"_score": {Order: &sortOrderDesc},
-			},
-		},
-		&types.SortOptions{
-			SortOptions: map[string]types.FieldSort{
-				"id": {Order: &sortOrderDesc},
-			},
-		},
-	)
-
-	// Execute query
-	res, err := searchReq.Do(ctx)
-	if err != nil {
-		slog.Error("Elasticsearch multi_match query failed", "error", err, "query", query.Query)
-		return nil, fmt.Errorf("failed to execute multi_match search: %w", err)
-	}
-
-	maxScore := dquery.CalcSafeScore((*float64)(res.Hits.MaxScore))
-
-	articles, rawScores, err := r.mapToResult(res.Hits.Hits, maxScore)
-	if err != nil {
-		return nil, fmt.Errorf("failed to map search results to types: %w", err)
-	}
-
-	slog.Info("ES multi_match search results fetched",
-		"total_matches", res.Hits.Total.Value,
-		"returned_count", len(articles),
-		"max_score", *res.Hits.MaxScore,
-		"normalized_max", maxScore)
-
-	hasMore := len(articles) > size
-	if hasMore {
-		articles = articles[:size]
-		rawScores = rawScores[:size]
-	}
-
-	var nextCursor *dquery.Cursor
-	if hasMore && len(articles) > 0 {
-		nextCursor = &dquery.Cursor{
-			Score: rawScores[len(rawScores)-1],
-			ID:    articles[len(articles)-1].Article.ID,
-		}
-	}
-
-	return &storage.SearchResult{
-		Hits:         articles,
-		NextCursor:   nextCursor,
-		HasMore:      hasMore,
-		MaxScore:     utils.RoundFloat64(float64(*res.Hits.MaxScore), dquery.ScoreDecimalPlaces),
-		PageMaxScore: utils.RoundFloat64(rawScores[0], dquery.ScoreDecimalPlaces),
-		TotalMatches: res.Hits.Total.Value,
-	}, nil
+	return r.execute(ctx, dquery.MultiMatchType, buildMultiMatchQuery(query), lang, baseOpts)
 }
 
 // SearchPhrase implements storage.FtsSearcher interface
 // Performs phrase search using Elasticsearch's match_phrase query with slop support
 func (r *Searcher) SearchPhrase(ctx context.Context, query *dquery.Phrase, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
-	cursor, size := baseOpts.Cursor, baseOpts.Size
 	slop := query.GetSlop()
+	lang := query.GetLanguage()
 
 	slog.Info("Executing es phrase search",
 		"query", query.Query,
 		"fields", query.Fields,
 		"slop", slop,
-		"language", query.GetLanguage(),
-		"has_cursor", cursor != nil,
-		"size", size)
-
-	// Build bool query with should clauses for each field
-	// Each field gets a match_phrase query with the same slop
-	shouldClauses := make([]types.Query, 0, len(query.Fields))
-	for _, field := range query.Fields {
-		matchPhraseQuery := types.MatchPhraseQuery{
-			Query: query.Query,
-		}
-		if slop > 0 {
-			matchPhraseQuery.Slop = &slop
-		}
-
-		shouldClauses = append(shouldClauses, types.Query{
-			MatchPhrase: map[string]types.MatchPhraseQuery{
-				field: matchPhraseQuery,
-			},
-		})
-	}
+		"language", lang,
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
 
-	// Build bool query - at least one field should match
-	boolQuery := &types.BoolQuery{
-		Should:             shouldClauses,
-		MinimumShouldMatch: "1",
-	}
+	return r.execute(ctx, dquery.PhraseType, buildPhraseQuery(query), lang, baseOpts)
+}
 
-	slog.Debug("Elasticsearch phrase query",
-		"fields", query.Fields,
-		"slop", slop,
-		"num_should_clauses", len(shouldClauses))
-
-	// Build search request
-	searchReq := r.client.Search().
-		Index(r.indexName).
-		Query(&types.Query{
-			Bool: boolQuery,
-		}).
-		Size(size + 1).
-		TrackScores(true)
-
-	// Add cursor support
-	if cursor != nil {
-		searchReq = searchReq.SearchAfter(
-			types.FieldValue(cursor.Score),
-			types.FieldValue(cursor.ID.String()),
-		)
-	}
+// SearchBoolean implements storage.FtsSearcher interface
+// Parses the expression with the shared token.BooleanParser and compiles the AST to
+// nested bool queries (see buildBooleanQuery)
+func (r *Searcher) SearchBoolean(ctx context.Context, query *dquery.Boolean, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	lang := query.GetLanguage()
 
-	// Add sorting
-	sortOrderDesc := sortorder.Desc
-	searchReq = searchReq.Sort(
-		&types.SortOptions{
-			SortOptions: map[string]types.FieldSort{
-				"_score": {Order: &sortOrderDesc},
-			},
-		},
-		&types.SortOptions{
-			SortOptions: map[string]types.FieldSort{
-				"id": {Order: &sortOrderDesc},
-			},
-		},
-	)
+	slog.Info("Executing es boolean search",
+		"expression", query.Expression,
+		"language", lang,
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
 
-	// Execute query
-	res, err := searchReq.Do(ctx)
+	root, err := token.NewBooleanParser().Parse(query.Expression)
 	if err != nil {
-		slog.Error("Elasticsearch phrase query failed", "error", err, "query", query.Query, "fields", query.Fields)
-		return nil, fmt.Errorf("failed to execute phrase search: %w", err)
+		slog.Error("Invalid boolean query expression", "error", err, "expression", query.Expression)
+		return nil, fmt.Errorf("invalid boolean query expression: %w", err)
 	}
 
-	maxScore := dquery.CalcSafeScore((*float64)(res.Hits.MaxScore))
-
-	articles, rawScores, err := r.mapToResult(res.Hits.Hits, maxScore)
-	if err != nil {
-		return nil, fmt.Errorf("failed to map search results to types: %w", err)
-	}
+	return r.execute(ctx, dquery.BooleanType, buildBooleanQuery(root, lang), lang, baseOpts)
+}
 
-	slog.Info("ES phrase search results fetched",
-		"total_matches", res.Hits.Total.Value,
-		"returned_count", len(articles),
-		"max_score", res.Hits.MaxScore,
-		"normalized_max", maxScore)
+// SearchBool implements storage.FtsSearcher interface
+// Translates the compound query to a native bool query; leaf clauses are built exactly
+// like the standalone match, multi_match and phrase queries.
+func (r *Searcher) SearchBool(ctx context.Context, query *dquery.Bool, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	lang := query.GetLanguage()
+
+	slog.Info("Executing es bool search",
+		"must", len(query.Must),
+		"should", len(query.Should),
+		"must_not", len(query.MustNot),
+		"filter", len(query.Filter),
+		"minimum_should_match", query.GetMinimumShouldMatch(),
+		"language", lang,
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	return r.execute(ctx, dquery.BoolType, buildBoolQuery(query), lang, baseOpts)
+}
 
-	hasMore := len(articles) > size
-	if hasMore {
-		articles = articles[:size]
-		rawScores = rawScores[:size]
+// execute runs a scoring query with structured filters applied in filter context,
+// sorted by the requested sort (see buildSort) and paginated with search_after. lang selects the analyzed
+// per-language fields used for highlighting.
+func (r *Searcher) execute(ctx context.Context, kind dquery.Kind, q *types.Query, lang dquery.Language, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	req, err := buildSearchRequest(q, lang, baseOpts)
+	if err != nil {
+		return nil, err
 	}
 
-	var nextCursor *dquery.Cursor
-	if hasMore && len(articles) > 0 {
-		nextCursor = &dquery.Cursor{
-			Score: rawScores[len(rawScores)-1],
-			ID:    articles[len(articles)-1].Article.ID,
+	res, err := r.client.Search().Index(r.indexName).Request(req).Do(ctx)
+	if err != nil {
+		slog.Error("Elasticsearch query failed", "error", err, "kind", kind, "cursor", baseOpts.Cursor != nil)
+		var esErr *types.ElasticsearchError
+		if errors.As(err, &esErr) {
+			return nil, searchError(kind, *esErr)
 		}
+		return nil, fmt.Errorf("failed to execute %s search: %w", kind, err)
 	}
 
-	// Handle case where no results found
-	var maxScoreValue float64
-	var pageMaxScore float64
-	if res.Hits.MaxScore != nil {
-		maxScoreValue = utils.RoundFloat64(float64(*res.Hits.MaxScore), dquery.ScoreDecimalPlaces)
-	}
-	if len(rawScores) > 0 {
-		pageMaxScore = utils.RoundFloat64(rawScores[0], dquery.ScoreDecimalPlaces)
-	}
+	return r.mapSearchResponse(kind, res.Hits, res.Aggregations, baseOpts)
+}
 
-	return &storage.SearchResult{
-		Hits:         articles,
-		NextCursor:   nextCursor,
-		HasMore:      hasMore,
-		MaxScore:     maxScoreValue,
-		PageMaxScore: pageMaxScore,
-		TotalMatches: res.Hits.Total.Value,
-	}, nil
+// searchError maps an Elasticsearch error response of a search: a 4xx means the request
+// was rejected (e.g. an unparsable query_string or an unknown field type) and is reported
+// as a validation error, anything else stays an internal error.
+func searchError(kind dquery.Kind, esErr types.ElasticsearchError) error {
+	if esErr.Status >= http.StatusBadRequest && esErr.Status < http.StatusInternalServerError {
+		return apperr.NewValidation(fmt.Sprintf("invalid %s search: %s", kind, errorCauseMessage(esErr.ErrorCause)))
+	}
+	return fmt.Errorf("failed to execute %s search: %w", kind, &esErr)
 }
 
-func (r *Searcher) SearchBoolean(ctx context.Context, query *dquery.Boolean, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
-	cursor, size := baseOpts.Cursor, baseOpts.Size
+// buildSearchRequest builds the search body of a scoring query: filters, rescoring, one extra
+// hit to detect another page, aggregations (with the score statistics when the normalization
+// needs them), highlighting, explanations, sort and search_after.
+func buildSearchRequest(q *types.Query, lang dquery.Language, baseOpts *dquery.BaseOptions) (*search.Request, error) {
+	size := baseOpts.Size + 1
+	trackScores := true
 
-	slog.Info("Executing es boolean search",
-		"expression", query.Expression,
-		"has_cursor", cursor != nil,
-		"size", size)
+	req := search.NewRequest()
+	req.Query = withFilters(withRescoring(q, baseOpts.Rescoring), baseOpts.Filters)
+	req.Size = &size
+	req.TrackScores = &trackScores
 
-	tokens := r.tokenizer.Tokenize(query.Expression)
-	if err := r.tokenizer.Validate(tokens); err != nil {
-		slog.Error("Invalid boolean query expression", "error", err, "expression", query.Expression)
-		return nil, fmt.Errorf("invalid boolean query expression: %w", err)
+	aggs, err := buildAggregations(baseOpts.Aggregations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build aggregations: %w", err)
 	}
-
-	fields := make([]string, 0, len(dquery.RecommendedFieldWeights))
-	for field, weight := range dquery.RecommendedFieldWeights {
-		if weight != 1.0 {
-			fields = append(fields, fmt.Sprintf("%s^%.1f", field, weight))
-		} else {
-			fields = append(fields, field)
+	if baseOpts.Normalization.NeedsDistribution() {
+		if aggs == nil {
+			aggs = make(map[string]types.Aggregations, 1)
 		}
+		aggs[scoreStatsAggregation] = buildScoreStatsAggregation()
 	}
+	req.Aggregations = aggs
 
-	queryStringQuery := &types.QueryStringQuery{
-		Query:  query.Expression,
-		Fields: fields,
+	req.Highlight = buildHighlight(baseOpts.Highlight, lang)
+
+	if baseOpts.Explain {
+		explain := true
+		req.Explain = &explain
 	}
 
-	searchReq := r.client.Search().
-		Index(r.indexName).
-		Query(&types.Query{
-			QueryString: queryStringQuery,
-		}).
-		Size(size + 1).
-		TrackScores(true)
-
-	if cursor != nil {
-		searchReq = searchReq.SearchAfter(
-			types.FieldValue(cursor.Score),
-			types.FieldValue(cursor.ID.String()),
-		)
+	if baseOpts.Cursor != nil {
+		req.SearchAfter = buildSearchAfter(baseOpts.Sort, baseOpts.Cursor)
 	}
+	req.Sort = buildSort(baseOpts.Sort)
 
-	sortOrderDesc := sortorder.Desc
-	searchReq = searchReq.Sort(
-		&types.SortOptions{
-			SortOptions: map[string]types.FieldSort{
-				"_score": {Order: &sortOrderDesc},
-			},
-		},
-		&types.SortOptions{
-			SortOptions: map[string]types.FieldSort{
-				"id": {Order: &sortOrderDesc},
-			},
-		},
-	)
+	return req, nil
+}
 
-	res, err := searchReq.Do(ctx)
+// mapSearchResponse maps the hits and aggregations of a search (or of one _msearch item) built
+// by buildSearchRequest to a page: normalized scores, rescoring breakdowns and the next cursor.
+func (r *Searcher) mapSearchResponse(kind dquery.Kind, resHits types.HitsMetadata, resAggs map[string]types.Aggregate, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	cursor, size := baseOpts.Cursor, baseOpts.Size
+
+	var total int64
+	if resHits.Total != nil {
+		total = resHits.Total.Value
+	}
+	maxScore := dquery.CalcSafeScore((*float64)(resHits.MaxScore))
+
+	aggregations, err := mapAggregations(baseOpts.Aggregations, resAggs)
 	if err != nil {
-		slog.Error("Elasticsearch boolean query failed", "error", err, "expression", query.Expression)
-		return nil, fmt.Errorf("failed to execute boolean search: %w", err)
+		return nil, err
 	}
 
-	maxScore := dquery.CalcSafeScore((*float64)(res.Hits.MaxScore))
+	stats, err := mapScoreStats(resAggs, total, maxScore)
+	if err != nil {
+		return nil, err
+	}
 
-	articles, rawScores, err := r.mapToResult(res.Hits.Hits, maxScore)
+	articles, rawScores, err := r.mapToResult(resHits.Hits)
 	if err != nil {
 		return nil, fmt.Errorf("failed to map search results to types: %w", err)
 	}
 
-	slog.Info("ES boolean search results fetched",
-		"total_matches", res.Hits.Total.Value,
+	slog.Info("ES search results fetched",
+		"kind", kind,
+		"total_matches", total,
 		"returned_count", len(articles),
-		"max_score", res.Hits.MaxScore,
+		"max_score", resHits.MaxScore,
 		"normalized_max", maxScore)
 
 	hasMore := len(articles) > size
@@ -648,31 +333,43 @@ func (r *Searcher) SearchBoolean(ctx context.Context, query *dquery.Boolean, bas
 		articles = articles[:size]
 		rawScores = rawScores[:size]
 	}
+	offset := storage.PageOffset(cursor)
+	storage.NormalizeScores(baseOpts.Normalization, articles, rawScores, stats, offset)
+	for i := range articles {
+		storage.ExplainRescoring(baseOpts.Rescoring, &articles[i], rawScores[i])
+	}
 
 	var nextCursor *dquery.Cursor
 	if hasMore && len(articles) > 0 {
-		nextCursor = &dquery.Cursor{
-			Score: rawScores[len(rawScores)-1],
-			ID:    articles[len(articles)-1].Article.ID,
-		}
+		nextCursor = storage.NextCursor(baseOpts.Sort, articles[len(articles)-1].Article, rawScores[len(rawScores)-1])
+		nextCursor.Offset = offset + int64(len(articles))
 	}
 
+	// Handle case where no results found
 	var maxScoreValue float64
 	var pageMaxScore float64
-	if res.Hits.MaxScore != nil {
-		maxScoreValue = utils.RoundFloat64(float64(*res.Hits.MaxScore), dquery.ScoreDecimalPlaces)
+	if resHits.MaxScore != nil {
+		maxScoreValue = utils.RoundFloat64(float64(*resHits.MaxScore), dquery.ScoreDecimalPlaces)
 	}
 	if len(rawScores) > 0 {
-		pageMaxScore = utils.RoundFloat64(rawScores[0], dquery.ScoreDecimalPlaces)
+		pageMaxScore = utils.RoundFloat64(slices.Max(rawScores), dquery.ScoreDecimalPlaces)
+	}
+
+	var totalRelation string
+	if resHits.Total != nil && resHits.Total.Relation == totalhitsrelation.Gte {
+		// track_total_hits stops counting at 10,000 hits
+		totalRelation = storage.TotalRelationGte
 	}
 
 	return &storage.SearchResult{
-		Hits:         articles,
-		NextCursor:   nextCursor,
-		HasMore:      hasMore,
-		MaxScore:     maxScoreValue,
-		PageMaxScore: pageMaxScore,
-		TotalMatches: res.Hits.Total.Value,
+		Hits:          articles,
+		NextCursor:    nextCursor,
+		HasMore:       hasMore,
+		MaxScore:      maxScoreValue,
+		PageMaxScore:  pageMaxScore,
+		TotalMatches:  total,
+		TotalRelation: totalRelation,
+		Aggregations:  aggregations,
 	}, nil
 }
 
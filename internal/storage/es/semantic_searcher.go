@@ -8,8 +8,10 @@ import (
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
 	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
 	"github.com/google/uuid"
@@ -68,6 +70,9 @@ func (s *SemanticSearcher) SearchSemantic(ctx context.Context, query *dquery.Sem
 		K:             &k,
 		NumCandidates: &numCandidates,
 		Similarity:    &sim,
+		// kNN filters are applied during the graph search, so k neighbours
+		// are still returned when a filter is set.
+		Filter: buildFilterQueries(baseOpts.Filters),
 	}
 
 	slog.Info("Executing es semantic kNN search",
@@ -75,6 +80,7 @@ func (s *SemanticSearcher) SearchSemantic(ctx context.Context, query *dquery.Sem
 		"model", s.model,
 		"k", k,
 		"num_candidates", numCandidates,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
 		"size", size)
 
 	res, err := s.client.Search().
@@ -92,10 +98,12 @@ func (s *SemanticSearcher) SearchSemantic(ctx context.Context, query *dquery.Sem
 		return nil, fmt.Errorf("failed to execute semantic search: %w", err)
 	}
 
-	hits, err := s.mapToArticles(res.Hits.Hits)
+	hits, similarities, err := s.mapToResult(res.Hits.Hits)
 	if err != nil {
 		return nil, fmt.Errorf("failed to map semantic search results: %w", err)
 	}
+	// kNN returns a single page, so the page is the match set
+	storage.NormalizeScores(baseOpts.Normalization, hits, similarities, ranking.StatsOf(similarities), 0)
 
 	slog.Info("ES semantic search results fetched",
 		"total_matches", res.Hits.Total.Value,
@@ -109,20 +117,28 @@ func (s *SemanticSearcher) SearchSemantic(ctx context.Context, query *dquery.Sem
 	}, nil
 }
 
-func (s *SemanticSearcher) mapToArticles(hits []types.Hit) ([]dto.Article, error) {
-	articles := make([]dto.Article, 0, len(hits))
+// mapToResult maps kNN hits to articles scored by cosine similarity; the _score of a
+// cosine kNN search is (1 + similarity) / 2
+func (s *SemanticSearcher) mapToResult(hits []types.Hit) ([]dto.ArticleSearchResult, []float64, error) {
+	articles := make([]dto.ArticleSearchResult, 0, len(hits))
+	similarities := make([]float64, 0, len(hits))
 	for _, hit := range hits {
 		var doc ArticleDocument
 		if err := json.Unmarshal(hit.Source_, &doc); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal document: %w", err)
+			return nil, nil, fmt.Errorf("failed to unmarshal document: %w", err)
 		}
 
 		id, err := uuid.Parse(doc.ID)
 		if err != nil {
-			return nil, fmt.Errorf("parse document id %q: %w", doc.ID, err)
+			return nil, nil, fmt.Errorf("parse document id %q: %w", doc.ID, err)
 		}
 
-		articles = append(articles, dto.Article{
+		var similarity float64
+		if hit.Score_ != nil {
+			similarity = 2*float64(*hit.Score_) - 1
+		}
+
+		article := dto.Article{
 			ID:          id,
 			Title:       doc.Title,
 			Subtitle:    doc.Subtitle,
@@ -138,10 +154,19 @@ func (s *SemanticSearcher) mapToArticles(hits []types.Hit) ([]dto.Article, error
 				PublishedAt: doc.PublishedAt,
 				Category:    doc.Category,
 				ImportedAt:  doc.ImportedAt,
+
+				DetectedLanguage:   doc.DetectedLanguage,
+				LanguageConfidence: doc.LanguageConfidence,
+				LanguageMismatch:   doc.LanguageMismatch,
 			},
+		}
+		articles = append(articles, dto.ArticleSearchResult{
+			Article: article,
+			Score:   utils.RoundFloat64(similarity, dquery.ScoreDecimalPlaces),
 		})
+		similarities = append(similarities, similarity)
 	}
-	return articles, nil
+	return articles, similarities, nil
 }
 
 var _ storage.SemanticSearcher = (*SemanticSearcher)(nil)
@@ -0,0 +1,70 @@
+package es
+
+import (
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/sortorder"
+)
+
+// sortFields maps sort fields to sortable fields of the index mapping (see IndexBuilder.buildMapping).
+var sortFields = map[dquery.SortField]string{
+	dquery.SortPublishedAt: "published_at",
+	dquery.SortCreatedAt:   "created_at",
+	dquery.SortSourceName:  "source_name.keyword",
+}
+
+// buildSort translates the sort to sort options ending with the id tiebreaker.
+// Missing values sort as the smallest value in both directions, like PostgreSQL's
+// coalesced sort columns (see pg.BuildOrderBy).
+func buildSort(sort dquery.Sort) []types.SortCombinations {
+	if len(sort) == 0 {
+		sort = dquery.DefaultSort
+	}
+
+	desc := sortorder.Desc
+	options := make([]types.SortCombinations, 0, len(sort)+1)
+	for _, k := range sort {
+		if k.Field == dquery.SortRelevance {
+			options = append(options, &types.SortOptions{
+				SortOptions: map[string]types.FieldSort{"_score": {Order: &desc}},
+			})
+			continue
+		}
+
+		order, missing := sortorder.Asc, "_first"
+		if k.Order == dquery.SortDesc {
+			order, missing = sortorder.Desc, "_last"
+		}
+		options = append(options, &types.SortOptions{
+			SortOptions: map[string]types.FieldSort{sortFields[k.Field]: {Order: &order, Missing: missing}},
+		})
+	}
+
+	return append(options, &types.SortOptions{
+		SortOptions: map[string]types.FieldSort{"id": {Order: &desc}},
+	})
+}
+
+// buildSearchAfter lists the cursor's sort values in the order of buildSort.
+// Dates are compared as epoch milliseconds, the resolution of the date field.
+func buildSearchAfter(sort dquery.Sort, cursor *dquery.Cursor) []types.FieldValue {
+	if len(sort) == 0 {
+		sort = dquery.DefaultSort
+	}
+
+	values := make([]types.FieldValue, 0, len(sort)+1)
+	fieldValues := cursor.Values
+	for _, k := range sort {
+		switch {
+		case k.Field == dquery.SortRelevance:
+			values = append(values, cursor.Score)
+		case fieldValues[0].Time != nil:
+			values = append(values, fieldValues[0].Time.UnixMilli())
+			fieldValues = fieldValues[1:]
+		default:
+			values = append(values, *fieldValues[0].Keyword)
+			fieldValues = fieldValues[1:]
+		}
+	}
+	return append(values, cursor.ID.String())
+}
@@ -0,0 +1,53 @@
+package es
+
+import (
+	"testing"
+	"time"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/google/uuid"
+)
+
+func TestBuildSort(t *testing.T) {
+	sort, err := dquery.ParseSort("source_name,relevance")
+	if err != nil {
+		t.Fatalf("ParseSort() error = %v", err)
+	}
+
+	options := buildSort(sort)
+	if len(options) != 3 {
+		t.Fatalf("expected source_name, _score and id sort options, got %d", len(options))
+	}
+	source := options[0].(*types.SortOptions).SortOptions["source_name.keyword"]
+	if source.Order == nil || source.Order.String() != "asc" || source.Missing != "_first" {
+		t.Errorf("source_name sort = %+v, want asc with missing values first", source)
+	}
+	if _, ok := options[1].(*types.SortOptions).SortOptions["_score"]; !ok {
+		t.Errorf("second sort option = %+v, want _score", options[1])
+	}
+	if _, ok := options[2].(*types.SortOptions).SortOptions["id"]; !ok {
+		t.Errorf("last sort option = %+v, want the id tiebreaker", options[2])
+	}
+}
+
+func TestBuildSearchAfter(t *testing.T) {
+	sort, err := dquery.ParseSort("published_at,relevance")
+	if err != nil {
+		t.Fatalf("ParseSort() error = %v", err)
+	}
+	published := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	id := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	cursor := &dquery.Cursor{Score: 2.5, ID: id, Sort: sort, Values: []dquery.SortValue{dquery.TimeSortValue(published)}}
+
+	values := buildSearchAfter(sort, cursor)
+	want := []types.FieldValue{published.UnixMilli(), 2.5, id.String()}
+	if len(values) != len(want) {
+		t.Fatalf("search_after = %v, want %v", values, want)
+	}
+	for i := range want {
+		if values[i] != want[i] {
+			t.Errorf("search_after[%d] = %v, want %v", i, values[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v8/typedapi/types/enums/suggestmode"
+)
+
+// suggestFields are the fields term suggestions are drawn from. The base fields are
+// analyzed with the standard analyzer (unstemmed), so options are real surface words.
+var suggestFields = []string{"title", "content"}
+
+type Suggester struct {
+	client    *elasticsearch.TypedClient
+	indexName string
+}
+
+func NewSuggester(config ClientConfig) (*Suggester, error) {
+	client, err := newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Elasticsearch client: %w", err)
+	}
+	return &Suggester{
+		client:    client,
+		indexName: config.IndexName,
+	}, nil
+}
+
+// termSuggestEntry is the term suggester response shape for one analyzed query token
+type termSuggestEntry struct {
+	Text    string `json:"text"`
+	Options []struct {
+		Text  string  `json:"text"`
+		Score float64 `json:"score"`
+		Freq  int64   `json:"freq"`
+	} `json:"options"`
+}
+
+// Suggest implements storage.Suggester using one term suggester per field in "missing"
+// mode, so only tokens absent from the field get corrections. Options of both fields are
+// merged per token (highest score and frequency win).
+func (s *Suggester) Suggest(ctx context.Context, text string, size int) (*dquery.Suggest, error) {
+	if len(dquery.SuggestTerms(text)) == 0 {
+		return &dquery.Suggest{}, nil
+	}
+
+	slog.Info("Executing es term suggest", "text", text, "size", size)
+
+	minWordLength := dquery.SuggestMinWordLength
+	suggesters := make(map[string]types.FieldSuggester, len(suggestFields))
+	for _, field := range suggestFields {
+		suggesters[field] = types.FieldSuggester{
+			Term: &types.TermSuggester{
+				Field:         field,
+				SuggestMode:   &suggestmode.Missing,
+				Size:          &size,
+				MinWordLength: &minWordLength,
+			},
+		}
+	}
+
+	res, err := s.client.Search().
+		Index(s.indexName).
+		Size(0).
+		Suggest(&types.Suggester{
+			Text:       &text,
+			Suggesters: suggesters,
+		}).
+		Do(ctx)
+	if err != nil {
+		slog.Error("Failed to execute term suggest", "error", err)
+		return nil, fmt.Errorf("failed to execute term suggest: %w", err)
+	}
+
+	merged := make(map[string]map[string]dquery.SuggestOption)
+	var order []string
+	for _, field := range suggestFields {
+		raw, ok := res.Suggest[field]
+		if !ok {
+			continue
+		}
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s suggestions: %w", field, err)
+		}
+		var entries []termSuggestEntry
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("failed to decode %s suggestions: %w", field, err)
+		}
+
+		for _, entry := range entries {
+			if len(entry.Options) == 0 {
+				continue
+			}
+			options, seen := merged[entry.Text]
+			if !seen {
+				options = make(map[string]dquery.SuggestOption)
+				merged[entry.Text] = options
+				order = append(order, entry.Text)
+			}
+			for _, o := range entry.Options {
+				current := options[o.Text]
+				current.Text = o.Text
+				current.Score = max(current.Score, utils.RoundFloat64(o.Score, dquery.ScoreDecimalPlaces))
+				current.Freq = max(current.Freq, o.Freq)
+				options[o.Text] = current
+			}
+		}
+	}
+
+	suggestions := make([]dquery.TermSuggestion, 0, len(order))
+	for _, term := range order {
+		options := make([]dquery.SuggestOption, 0, len(merged[term]))
+		for _, o := range merged[term] {
+			options = append(options, o)
+		}
+		sort.Slice(options, func(i, j int) bool {
+			if options[i].Score != options[j].Score {
+				return options[i].Score > options[j].Score
+			}
+			if options[i].Freq != options[j].Freq {
+				return options[i].Freq > options[j].Freq
+			}
+			return options[i].Text < options[j].Text
+		})
+		if len(options) > size {
+			options = options[:size]
+		}
+		suggestions = append(suggestions, dquery.TermSuggestion{Term: term, Options: options})
+	}
+
+	return dquery.NewSuggest(text, suggestions), nil
+}
+
+// Compile-time interface assertions
+var _ storage.Suggester = (*Suggester)(nil)
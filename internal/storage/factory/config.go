@@ -4,17 +4,27 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/es"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/in_mem"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg/tiger"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/solr"
 )
 
 type StorageConfig struct {
 	storage.Type
-	Pg *pg.PoolConfig
-	Es *es.ClientConfig
+	Pg   *pg.PoolConfig
+	Es   *es.ClientConfig
+	Solr *solr.ClientConfig
+	// TigerCandidateLimit is the number of bm25 index candidates pg_textsearch queries filter
+	// (TIGER_CANDIDATE_LIMIT, 0 scans the table)
+	TigerCandidateLimit int
+	// InMem is the store shared by every in-memory component built from this config
+	InMem *in_mem.Store
 }
 
 func LoadEnv() (*StorageConfig, error) {
@@ -23,12 +33,12 @@ func LoadEnv() (*StorageConfig, error) {
 		slog.Error("STORAGE_TYPE environment variable is not set")
 		return nil, fmt.Errorf("STORAGE_TYPE environment variable is not set")
 	}
-	if storageType != storage.ES && storageType != storage.PG && storageType != storage.InMem {
+	if storageType != storage.ES && storageType != storage.PG && storageType != storage.Parade && storageType != storage.Tiger && storageType != storage.Solr && storageType != storage.InMem {
 		slog.Error("Invalid STORAGE_TYPE environment variable value", "value", storageType)
 		return nil, fmt.Errorf(
 			"invalid STORAGE_TYPE environment variable value: %s, expected one of %v",
 			storageType,
-			[]storage.Type{storage.ES, storage.PG, storage.InMem})
+			[]storage.Type{storage.ES, storage.PG, storage.Parade, storage.Tiger, storage.Solr, storage.InMem})
 	}
 
 	var esCfg *es.ClientConfig
@@ -45,8 +55,22 @@ func LoadEnv() (*StorageConfig, error) {
 		}
 	}
 
+	var solrCfg *solr.ClientConfig
+	if storageType == storage.Solr {
+		solrCfg = &solr.ClientConfig{
+			URL:        os.Getenv("SOLR_URL"),
+			Collection: os.Getenv("SOLR_COLLECTION"),
+			Username:   os.Getenv("SOLR_USERNAME"),
+			Password:   os.Getenv("SOLR_PASSWORD"),
+		}
+		if solrCfg.URL == "" || solrCfg.Collection == "" {
+			slog.Error("Solr configuration is incomplete", "url", solrCfg.URL, "collection", solrCfg.Collection)
+			return nil, fmt.Errorf("solr configuration is incomplete: URL or collection is missing")
+		}
+	}
+
 	var pgCfg *pg.PoolConfig
-	if storageType == storage.PG {
+	if storageType == storage.PG || storageType == storage.Parade || storageType == storage.Tiger {
 		pgCfg = &pg.PoolConfig{
 			ConnStr: os.Getenv("PG_CONNECTION_STRING"),
 		}
@@ -56,9 +80,54 @@ func LoadEnv() (*StorageConfig, error) {
 		}
 	}
 
+	tigerCandidateLimit := tiger.DefaultCandidateLimit
+	if v := os.Getenv("TIGER_CANDIDATE_LIMIT"); storageType == storage.Tiger && v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			slog.Error("Invalid TIGER_CANDIDATE_LIMIT environment variable value", "value", v)
+			return nil, fmt.Errorf("invalid TIGER_CANDIDATE_LIMIT environment variable value: %s, expected a non-negative integer", v)
+		}
+		tigerCandidateLimit = limit
+	}
+
+	var inMemStore *in_mem.Store
+	if storageType == storage.InMem {
+		store, err := loadInMemStore(os.Getenv("IN_MEM_DATA_FILE"))
+		if err != nil {
+			slog.Error("Failed to load in-memory data file", "error", err)
+			return nil, err
+		}
+		inMemStore = store
+	}
+
 	return &StorageConfig{
-		Type: storageType,
-		Pg:   pgCfg,
-		Es:   esCfg,
+		Type:  storageType,
+		Pg:    pgCfg,
+		Es:    esCfg,
+		Solr:  solrCfg,
+		InMem: inMemStore,
+
+		TigerCandidateLimit: tigerCandidateLimit,
 	}, nil
 }
+
+// loadInMemStore creates an in-memory store, seeded with the articles of an NDJSON file when set
+func loadInMemStore(path string) (*in_mem.Store, error) {
+	store := in_mem.NewStore()
+	if path == "" {
+		return store, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open in-memory data file: %w", err)
+	}
+	defer f.Close()
+
+	n, err := store.Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load in-memory data file %s: %w", path, err)
+	}
+	slog.Info("Loaded articles into memory", "file", path, "count", n)
+	return store, nil
+}
@@ -3,13 +3,18 @@ package factory
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
 	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/es"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/in_mem"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg/native"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg/parade"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg/tiger"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/solr"
 )
 
 // TODO: accept pool as param and reuse it across indexer and searcher when using PG, to avoid creating multiple pools
@@ -33,10 +38,13 @@ func NewIndexer(ctx context.Context, cfg StorageConfig) (storage.Indexer, error)
 		return es.NewIndexer(ctx, esConfig)
 
 	case storage.Solr:
-		return nil, fmt.Errorf("solr storer not yet implemented")
+		if cfg.Solr == nil {
+			return nil, fmt.Errorf("solr config is not set")
+		}
+		return solr.NewIndexer(ctx, *cfg.Solr)
 
 	case storage.InMem:
-		return in_mem.NewInMemIndexer(), nil
+		return in_mem.NewIndexer(inMemStore(cfg)), nil
 
 	default:
 		return nil, fmt.Errorf(string(storage.ErrUnsupportedStorer), cfg.Type)
@@ -45,7 +53,7 @@ func NewIndexer(ctx context.Context, cfg StorageConfig) (storage.Indexer, error)
 
 func NewEmbedderIndexer(ctx context.Context, cfg StorageConfig) (storage.EmbedIndexer, error) {
 	switch cfg.Type {
-	case storage.PG:
+	case storage.PG, storage.Parade, storage.Tiger:
 		pgConfig := pg.PoolConfig{
 			ConnStr:          cfg.Pg.ConnStr,
 			RegisterVecTypes: true,
@@ -63,6 +71,15 @@ func NewEmbedderIndexer(ctx context.Context, cfg StorageConfig) (storage.EmbedIn
 			return nil, fmt.Errorf("elasticsearch config is not set")
 		}
 		return es.NewEmbedder(ctx, *cfg.Es)
+
+	case storage.Solr:
+		if cfg.Solr == nil {
+			return nil, fmt.Errorf("solr config is not set")
+		}
+		return solr.NewEmbedder(ctx, *cfg.Solr)
+
+	case storage.InMem:
+		return in_mem.NewEmbedIndexer(inMemStore(cfg)), nil
 	}
 	return nil, fmt.Errorf(string(storage.ErrUnsupportedStorer), cfg.Type)
 }
@@ -80,39 +97,163 @@ func NewSearcher(ctx context.Context, cfg StorageConfig) (storage.FtsSearcher, e
 
 		return native.NewReader(pool)
 
+	case storage.Parade:
+		pool, err := pg.NewConnectionPool(ctx, *cfg.Pg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PostgreSQL connection pool: %w", err)
+		}
+
+		return parade.NewSearcher(pool)
+
+	case storage.Tiger:
+		pool, err := pg.NewConnectionPool(ctx, *cfg.Pg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PostgreSQL connection pool: %w", err)
+		}
+
+		return tiger.NewSearcher(pool, tiger.WithCandidateLimit(cfg.TigerCandidateLimit))
+
 	case storage.ES:
 		esConfig := *cfg.Es
 
 		return es.NewSearcher(esConfig)
 
 	case storage.Solr:
-		return nil, fmt.Errorf("solr reader not yet implemented")
+		if cfg.Solr == nil {
+			return nil, fmt.Errorf("solr config is not set")
+		}
+		return solr.NewSearcher(*cfg.Solr)
 
 	case storage.InMem:
-		// TODO: Implement InMem when needed
-		return nil, fmt.Errorf("inmem reader not yet implemented")
+		return in_mem.NewSearcher(inMemStore(cfg)), nil
 
 	default:
 		return nil, fmt.Errorf(string(storage.ErrUnsupportedStorer), cfg.Type)
 	}
 }
 
+// NewExporter creates a new storage.Exporter (streaming export of all hits) based on the storage type
+func NewExporter(ctx context.Context, cfg StorageConfig) (storage.Exporter, error) {
+	switch cfg.Type {
+	case storage.PG:
+		pool, err := pg.NewConnectionPool(ctx, *cfg.Pg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PostgreSQL connection pool: %w", err)
+		}
+
+		return native.NewReader(pool)
+
+	case storage.ES:
+		if cfg.Es == nil {
+			return nil, fmt.Errorf("elasticsearch config is not set")
+		}
+		return es.NewSearcher(*cfg.Es)
+
+	default:
+		return nil, unsupported("export", cfg.Type)
+	}
+}
+
+// NewReader creates a new storage.Reader based on the storage type
 func NewReader(ctx context.Context, cfg StorageConfig) (storage.Reader, error) {
-	if cfg.Type != storage.PG {
-		return nil, fmt.Errorf("reader not supported for storage type %s", cfg.Type)
+	switch cfg.Type {
+	case storage.PG, storage.Parade, storage.Tiger:
+		pool, err := pg.NewConnectionPool(ctx, *cfg.Pg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PostgreSQL connection pool: %w", err)
+		}
+
+		return pg.NewArticleReader(pool), nil
+
+	case storage.ES:
+		if cfg.Es == nil {
+			return nil, fmt.Errorf("elasticsearch config is not set")
+		}
+		return es.NewReader(*cfg.Es)
+
+	case storage.Solr:
+		if cfg.Solr == nil {
+			return nil, fmt.Errorf("solr config is not set")
+		}
+		return solr.NewReader(*cfg.Solr)
+
+	case storage.InMem:
+		return in_mem.NewReader(inMemStore(cfg)), nil
+
+	default:
+		return nil, unsupported("article reading", cfg.Type)
 	}
+}
+
+// NewSimilarSearcher creates a new storage.SimilarSearcher based on the storage type
+func NewSimilarSearcher(ctx context.Context, cfg StorageConfig) (storage.SimilarSearcher, error) {
+	switch cfg.Type {
+	case storage.PG:
+		pool, err := pg.NewConnectionPool(ctx, *cfg.Pg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PostgreSQL connection pool: %w", err)
+		}
 
-	pool, err := pg.NewConnectionPool(ctx, *cfg.Pg)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create PostgreSQL connection pool: %w", err)
+		return pg.NewArticleReader(pool), nil
+
+	case storage.ES:
+		if cfg.Es == nil {
+			return nil, fmt.Errorf("elasticsearch config is not set")
+		}
+		return es.NewReader(*cfg.Es)
+
+	default:
+		return nil, unsupported("similar article search", cfg.Type)
 	}
+}
+
+// NewSuggester creates a new storage.Suggester ("did you mean") based on the storage type
+func NewSuggester(ctx context.Context, cfg StorageConfig) (storage.Suggester, error) {
+	switch cfg.Type {
+	case storage.PG:
+		pool, err := pg.NewConnectionPool(ctx, *cfg.Pg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PostgreSQL connection pool: %w", err)
+		}
+
+		return pg.NewSuggester(pool), nil
 
-	return pg.NewArticleReader(pool), nil
+	case storage.ES:
+		if cfg.Es == nil {
+			return nil, fmt.Errorf("elasticsearch config is not set")
+		}
+		return es.NewSuggester(*cfg.Es)
+
+	default:
+		return nil, unsupported("spelling suggestion", cfg.Type)
+	}
 }
 
-func NewSemanticSearcher(ctx context.Context, cfg StorageConfig, client embedding.Client) (storage.SemanticSearcher, error) {
+// NewAutocompleter creates a new storage.Autocompleter (title type-ahead) based on the storage type
+func NewAutocompleter(ctx context.Context, cfg StorageConfig) (storage.Autocompleter, error) {
 	switch cfg.Type {
 	case storage.PG:
+		pool, err := pg.NewConnectionPool(ctx, *cfg.Pg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PostgreSQL connection pool: %w", err)
+		}
+
+		return pg.NewAutocompleter(pool), nil
+
+	case storage.ES:
+		if cfg.Es == nil {
+			return nil, fmt.Errorf("elasticsearch config is not set")
+		}
+		return es.NewAutocompleter(*cfg.Es)
+
+	default:
+		return nil, unsupported("title autocomplete", cfg.Type)
+	}
+}
+
+func NewSemanticSearcher(ctx context.Context, cfg StorageConfig, client embedding.Client) (storage.SemanticSearcher, error) {
+	switch cfg.Type {
+	case storage.PG, storage.Parade, storage.Tiger:
 		pgConfig := pg.PoolConfig{
 			ConnStr:          cfg.Pg.ConnStr,
 			RegisterVecTypes: true,
@@ -141,10 +282,22 @@ func NewSemanticSearcher(ctx context.Context, cfg StorageConfig, client embeddin
 		return es.NewSemanticSearcher(*cfg.Es, embedder, model)
 
 	case storage.Solr:
-		return nil, fmt.Errorf("solr semantic searcher not yet implemented")
+		if cfg.Solr == nil {
+			return nil, fmt.Errorf("solr config is not set")
+		}
+
+		model := embedding.DefaultModel
+		embedder := embedding.NewEmbedder(client,
+			embedding.WithExecutorMaxLength(1024),
+			embedding.WithExecutorModel(model),
+		)
+
+		return solr.NewSemanticSearcher(*cfg.Solr, embedder, model)
 
 	case storage.InMem:
-		return nil, fmt.Errorf("inmem semantic searcher not yet implemented")
+		embedder := embedding.NewEmbedder(client, embedding.WithExecutorMaxLength(1024))
+
+		return in_mem.NewSemanticSearcher(embedder, inMemStore(cfg)), nil
 
 	default:
 		return nil, fmt.Errorf(string(storage.ErrUnsupportedStorer), cfg.Type)
@@ -181,13 +334,32 @@ func NewHybridSearcher(ctx context.Context, cfg StorageConfig, client embedding.
 
 		return es.NewHybridSearcher(*cfg.Es, embedder, model)
 
-	case storage.Solr:
-		return nil, fmt.Errorf("solr hybrid searcher not yet implemented")
+	case storage.Parade, storage.Tiger, storage.Solr:
+		return nil, unsupported("hybrid search", cfg.Type)
 
 	case storage.InMem:
-		return nil, fmt.Errorf("inmem hybrid searcher not yet implemented")
+		embedder := embedding.NewEmbedder(client, embedding.WithExecutorMaxLength(1024))
+
+		return in_mem.NewHybridSearcher(embedder, inMemStore(cfg)), nil
 
 	default:
 		return nil, fmt.Errorf(string(storage.ErrUnsupportedStorer), cfg.Type)
 	}
 }
+
+// unsupported reports a capability the storage type does not implement as a validation
+// error, so requests that need it are rejected instead of failing as internal errors
+func unsupported(capability string, storageType storage.Type) error {
+	return apperr.NewValidation(fmt.Sprintf("%s is not supported by storage type %s", capability, storageType))
+}
+
+// inMemStore returns the store shared by the in-memory components of a config.
+// Configs built without LoadEnv get an empty store, shared from then on.
+func inMemStore(cfg StorageConfig) *in_mem.Store {
+	if cfg.InMem != nil {
+		return cfg.InMem
+	}
+	return defaultInMemStore()
+}
+
+var defaultInMemStore = sync.OnceValue(in_mem.NewStore)
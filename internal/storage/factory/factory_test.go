@@ -0,0 +1,25 @@
+package factory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/solr"
+)
+
+func TestNewHybridSearcher_UnsupportedStorage(t *testing.T) {
+	for _, storageType := range []storage.Type{storage.Parade, storage.Tiger, storage.Solr} {
+		t.Run(string(storageType), func(t *testing.T) {
+			cfg := StorageConfig{Type: storageType, Solr: &solr.ClientConfig{URL: "http://localhost:8983/solr", Collection: "articles"}}
+			_, err := NewHybridSearcher(context.Background(), cfg, nil)
+
+			var ve *apperr.ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("NewHybridSearcher() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
@@ -7,15 +7,18 @@ import (
 	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/es"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/in_mem"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg"
 )
 
 // VectorStoreConfig selects and configures a storage.VectorStore. Postgres
 // takes precedence: when a PG connection is available the PG store is used;
-// Elasticsearch is the fallback when only an ES backend is configured.
+// Elasticsearch is the fallback when only an ES backend is configured, then
+// an in-memory store.
 type VectorStoreConfig struct {
 	PgConnStr string
 	Es        *es.ClientConfig
+	InMem     *in_mem.Store
 
 	// EmbeddingClient embeds query text (the document vectors come from the
 	// store). Required for the PG store.
@@ -64,7 +67,21 @@ func NewVectorStore(ctx context.Context, cfg VectorStoreConfig) (storage.VectorS
 		)
 		return es.NewVectorStore(*cfg.Es, embedder, model)
 
+	case cfg.InMem != nil:
+		if cfg.EmbeddingClient == nil {
+			return nil, fmt.Errorf("vector store: embedding client is required for query embedding")
+		}
+		model := cfg.Model
+		if model == "" {
+			model = embedding.DefaultModel
+		}
+		embedder := embedding.NewEmbedder(cfg.EmbeddingClient,
+			embedding.WithExecutorMaxLength(1024),
+			embedding.WithExecutorModel(model),
+		)
+		return in_mem.NewVectorStore(embedder, cfg.InMem, model), nil
+
 	default:
-		return nil, fmt.Errorf("vector store: no Postgres or Elasticsearch backend configured")
+		return nil, fmt.Errorf("vector store: no Postgres, Elasticsearch or in-memory backend configured")
 	}
 }
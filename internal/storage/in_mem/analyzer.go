@@ -0,0 +1,186 @@
+package in_mem
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// analyzedToken is an analyzed term with its position in the field and its byte offsets in the text.
+// Removed stopwords keep their position, so phrase distances count them like Lucene does.
+type analyzedToken struct {
+	term       string
+	pos        int
+	start, end int
+}
+
+// analyzer turns text into terms. It mirrors the Elasticsearch analyzers of the
+// per-language sub-fields (see es.buildLanguageAnalysis):
+//
+//	english: split on non-alphanumerics → lowercase → stop (_english_) → minimal plural stemmer
+//	serbian: split on non-alphanumerics → lowercase → Cyrillic to Latin, folded diacritics → serbian stop
+//	standard: split on non-alphanumerics → lowercase (fields without language sub-fields)
+//
+// English uses a plural-only stemmer instead of Porter: "policies" and "policy" match,
+// "elections" and "election" match, "electoral" and "election" do not.
+type analyzer struct {
+	fold      func(string) string
+	stopwords map[string]bool
+	stem      func(string) string
+}
+
+var (
+	englishAnalyzer = &analyzer{
+		fold:      strings.ToLower,
+		stopwords: wordSet(englishStopwords),
+		stem:      stemEnglishPlural,
+	}
+	serbianAnalyzer = &analyzer{
+		fold:      foldSerbian,
+		stopwords: wordSet(serbianStopwords),
+	}
+	standardAnalyzer = &analyzer{
+		fold: strings.ToLower,
+	}
+)
+
+// englishStopwords is the Lucene _english_ stopword list
+var englishStopwords = []string{
+	"a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "if", "in", "into", "is", "it",
+	"no", "not", "of", "on", "or", "such", "that", "the", "their", "then", "there", "these",
+	"they", "this", "to", "was", "will", "with",
+}
+
+// serbianStopwords is the stopword list of the Elasticsearch serbian_stop filter, in folded Latin form
+var serbianStopwords = []string{
+	"a", "ako", "ali", "bi", "bih", "bila", "bile", "bili", "bilo", "bio", "biti", "ce", "cemo", "ces",
+	"da", "do", "dok", "ga", "gde", "i", "ih", "ili", "iz", "ja", "je", "jer", "jesu", "jos", "ju",
+	"kad", "kada", "kako", "kao", "koja", "koje", "koji", "kojih", "kojima", "koju", "li", "me", "mi",
+	"mu", "na", "nad", "nam", "nas", "ne", "nego", "neki", "ni", "nije", "nisu", "niti", "njega",
+	"njegov", "njen", "njih", "njihov", "njoj", "o", "od", "on", "ona", "one", "oni", "ono", "ova",
+	"ove", "ovi", "ovo", "pa", "po", "pod", "pored", "pre", "prema", "pri", "sa", "sam", "samo", "se",
+	"si", "smo", "ste", "sto", "su", "sve", "svi", "ta", "taj", "tako", "te", "ti", "to", "tu", "u",
+	"uz", "vas", "vec", "vi", "za", "zbog",
+}
+
+// languageAnalyzers are the analyzers of the per-language sub-fields of the text fields
+var languageAnalyzers = map[dquery.Language]*analyzer{
+	dquery.LanguageEnglish: englishAnalyzer,
+	dquery.LanguageSerbian: serbianAnalyzer,
+}
+
+// analyze splits text into words and returns the terms that survive the stopword filter
+func (a *analyzer) analyze(text string) []analyzedToken {
+	var tokens []analyzedToken
+	for pos, w := range words(text) {
+		if term := a.term(text[w.start:w.end]); term != "" {
+			tokens = append(tokens, analyzedToken{term: term, pos: pos, start: w.start, end: w.end})
+		}
+	}
+	return tokens
+}
+
+// span is the byte range of a word in a text
+type span struct {
+	start, end int
+}
+
+// words splits text into runs of letters and digits
+func words(text string) []span {
+	var spans []span
+	for start := 0; start < len(text); {
+		r, size := utf8.DecodeRuneInString(text[start:])
+		if !isWordRune(r) {
+			start += size
+			continue
+		}
+		end := start + size
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if !isWordRune(r) {
+				break
+			}
+			end += size
+		}
+		spans = append(spans, span{start: start, end: end})
+		start = end
+	}
+	return spans
+}
+
+// term analyzes a single word; stopwords analyze to ""
+func (a *analyzer) term(word string) string {
+	word = a.fold(word)
+	if a.stopwords[word] {
+		return ""
+	}
+	if a.stem != nil {
+		word = a.stem(word)
+	}
+	return word
+}
+
+// terms returns the analyzed terms of text in order
+func (a *analyzer) terms(text string) []string {
+	tokens := a.analyze(text)
+	terms := make([]string, len(tokens))
+	for i, t := range tokens {
+		terms[i] = t.term
+	}
+	return terms
+}
+
+// prefix folds a prefix without stemming it, so "energ*" still matches "energy"
+func (a *analyzer) prefix(word string) string {
+	return a.fold(word)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// stemEnglishPlural is Lucene's EnglishMinimalStemmer: it removes plural endings only
+func stemEnglishPlural(word string) string {
+	n := len(word)
+	if n < 3 || word[n-1] != 's' {
+		return word
+	}
+	switch word[n-2] {
+	case 'u', 's':
+		return word
+	case 'e':
+		if n > 3 && word[n-3] == 'i' && word[n-4] != 'a' && word[n-4] != 'e' {
+			return word[:n-3] + "y"
+		}
+		switch word[n-3] {
+		case 'i', 'a', 'o', 'e':
+			return word
+		}
+	}
+	return word[:n-1]
+}
+
+// serbianFolding transliterates Cyrillic to Latin and removes diacritics
+var serbianFolding = strings.NewReplacer(
+	"а", "a", "б", "b", "в", "v", "г", "g", "д", "d", "ђ", "dj", "е", "e", "ж", "z", "з", "z",
+	"и", "i", "ј", "j", "к", "k", "л", "l", "љ", "lj", "м", "m", "н", "n", "њ", "nj", "о", "o",
+	"п", "p", "р", "r", "с", "s", "т", "t", "ћ", "c", "у", "u", "ф", "f", "х", "h", "ц", "c",
+	"ч", "c", "џ", "dz", "ш", "s",
+	"č", "c", "ć", "c", "đ", "dj", "š", "s", "ž", "z",
+)
+
+// foldSerbian lowercases a word and folds it like the serbian_normalization filter,
+// so "Ђоковић", "Đoković" and "Djokovic" produce the same term
+func foldSerbian(word string) string {
+	return serbianFolding.Replace(strings.ToLower(word))
+}
+
+func wordSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, w := range list {
+		set[w] = true
+	}
+	return set
+}
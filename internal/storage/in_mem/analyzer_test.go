@@ -0,0 +1,79 @@
+package in_mem
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAnalyzerTerms(t *testing.T) {
+	tests := []struct {
+		name     string
+		analyzer *analyzer
+		text     string
+		want     []string
+	}{
+		{
+			name:     "english stopwords and plurals",
+			analyzer: englishAnalyzer,
+			text:     "The Policies of the Elections, and their Votes",
+			want:     []string{"policy", "election", "vote"},
+		},
+		{
+			name:     "english keeps non-plural endings",
+			analyzer: englishAnalyzer,
+			text:     "status glass campus",
+			want:     []string{"status", "glass", "campus"},
+		},
+		{
+			name:     "serbian cyrillic and diacritics fold to latin",
+			analyzer: serbianAnalyzer,
+			text:     "Ђоковић i Đoković su Djokovic",
+			want:     []string{"djokovic", "djokovic", "djokovic"},
+		},
+		{
+			name:     "standard lowercases only",
+			analyzer: standardAnalyzer,
+			text:     "Jane O'Neil",
+			want:     []string{"jane", "o", "neil"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.analyzer.terms(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("terms(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzePositionsCountStopwords(t *testing.T) {
+	tokens := englishAnalyzer.analyze("state of the union")
+	if len(tokens) != 2 {
+		t.Fatalf("got %d tokens, want 2", len(tokens))
+	}
+	if tokens[0].pos != 0 || tokens[1].pos != 3 {
+		t.Errorf("positions = %d, %d, want 0, 3", tokens[0].pos, tokens[1].pos)
+	}
+	if tokens[1].start != 13 || tokens[1].end != 18 {
+		t.Errorf("offsets of union = %d-%d, want 13-18", tokens[1].start, tokens[1].end)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"climate", "climate", 0},
+		{"climate", "climat", 1},
+		{"climate", "clmiate", 2},
+		{"ćao", "cao", 1},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
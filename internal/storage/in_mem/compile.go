@@ -0,0 +1,295 @@
+package in_mem
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// compiler translates full-text queries to nodes over the index of a store, the way
+// es.compileQuery translates them to Elasticsearch queries. It must run under the read
+// lock of the store, since fuzzy and prefix terms are expanded against the index.
+type compiler struct {
+	store *Store
+	// expansions are the fuzzy expansions of the query terms, in query order
+	expansions []dquery.TermExpansion
+}
+
+// compile builds the node of a full-text query
+func (c *compiler) compile(query *dquery.Base) (node, error) {
+	switch query.Kind {
+	case dquery.StringType:
+		parser := token.NewQueryStringParser(
+			token.WithDefaultOperator(query.QueryString.GetDefaultOperator()),
+			token.WithFields(dquery.QueryStringFields...),
+		)
+		root, err := parser.Parse(query.QueryString.Query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse query_string: %w", err)
+		}
+		return c.queryString(root, query.QueryString.GetLanguage()), nil
+	case dquery.MatchType:
+		return c.match(query.Match), nil
+	case dquery.MultiMatchType:
+		return c.multiMatch(query.MultiMatch), nil
+	case dquery.PhraseType:
+		return c.phrase(query.Phrase), nil
+	case dquery.BooleanType:
+		root, err := token.NewBooleanParser().Parse(query.Boolean.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean query expression: %w", err)
+		}
+		return c.boolean(root, query.Boolean.GetLanguage()), nil
+	case dquery.BoolType:
+		return c.bool(query.Bool), nil
+	default:
+		return nil, fmt.Errorf("unsupported full-text query kind %q", query.Kind)
+	}
+}
+
+// match compiles a match query on the language index of its field
+func (c *compiler) match(query *dquery.Match) node {
+	return c.matchField(query.Field, query.Query, query.GetLanguage(), query.GetOperator().IsAnd(), query.GetFuzziness(), 1)
+}
+
+// multiMatch compiles a best_fields multi_match: the best matching field scores, times its weight
+func (c *compiler) multiMatch(query *dquery.MultiMatch) node {
+	lang, and, fuzziness := query.GetLanguage(), query.GetOperator().IsAnd(), query.GetFuzziness()
+	children := make([]node, 0, len(query.Fields))
+	for _, field := range query.GetFields() {
+		children = append(children, c.matchField(field.Name, query.Query, lang, and, fuzziness, field.Weight))
+	}
+	return &disMaxNode{children: children, boost: 1}
+}
+
+// phrase compiles a phrase query: at least one of the fields must contain the phrase
+func (c *compiler) phrase(query *dquery.Phrase) node {
+	lang, slop := query.GetLanguage(), query.GetSlop()
+	should := make([]node, 0, len(query.Fields))
+	for _, field := range query.Fields {
+		should = append(should, c.phraseField(field, query.Query, lang, slop, false, 1))
+	}
+	return newBoolNode(nil, should, nil, nil, 1)
+}
+
+// bool compiles a compound query clause by clause
+func (c *compiler) bool(query *dquery.Bool) node {
+	return newBoolNode(
+		c.clauses(query.Must),
+		c.clauses(query.Should),
+		c.clauses(query.MustNot),
+		c.clauses(query.Filter),
+		query.MinimumShouldMatch,
+	)
+}
+
+func (c *compiler) clauses(clauses []dquery.Clause) []node {
+	if len(clauses) == 0 {
+		return nil
+	}
+	nodes := make([]node, 0, len(clauses))
+	for _, clause := range clauses {
+		switch {
+		case clause.Match != nil:
+			nodes = append(nodes, c.match(clause.Match))
+		case clause.MultiMatch != nil:
+			nodes = append(nodes, c.multiMatch(clause.MultiMatch))
+		case clause.Phrase != nil:
+			nodes = append(nodes, c.phrase(clause.Phrase))
+		case clause.Bool != nil:
+			nodes = append(nodes, c.bool(clause.Bool))
+		}
+	}
+	return nodes
+}
+
+// queryString compiles a parsed query_string group: + clauses are must, - clauses
+// must_not and the others should
+func (c *compiler) queryString(group *token.Group, lang dquery.Language) node {
+	var must, should, mustNot []node
+	for _, clause := range group.Clauses {
+		n := c.queryStringNode(clause.Node, lang)
+		switch clause.Occur {
+		case token.Must:
+			must = append(must, n)
+		case token.MustNot:
+			mustNot = append(mustNot, n)
+		default:
+			should = append(should, n)
+		}
+	}
+	b := newBoolNode(must, should, mustNot, nil, 0)
+	b.boost = group.Boost
+	return b
+}
+
+// queryStringNode compiles a term or phrase on its field, or over the default fields
+// with their default weights when unscoped. Words of one term must all match.
+func (c *compiler) queryStringNode(n token.Node, lang dquery.Language) node {
+	switch n := n.(type) {
+	case *token.Group:
+		return c.queryString(n, lang)
+	case *token.Phrase:
+		if n.Field == "" {
+			return c.defaultFields(dquery.DefaultFieldWeights, n.Boost, func(field string, weight float64) node {
+				return c.phraseField(field, n.Value, lang, 0, false, weight)
+			})
+		}
+		return c.phraseField(n.Field, n.Value, lang, 0, false, n.Boost)
+	case *token.Term:
+		if n.Field == "" {
+			return c.defaultFields(dquery.DefaultFieldWeights, n.Boost, func(field string, weight float64) node {
+				if n.Prefix {
+					return c.phraseField(field, n.Value, lang, 0, true, weight)
+				}
+				return c.matchField(field, n.Value, lang, true, dquery.NoFuzziness, weight)
+			})
+		}
+		if n.Prefix {
+			return c.phraseField(n.Field, n.Value, lang, 0, true, n.Boost)
+		}
+		return c.matchField(n.Field, n.Value, lang, true, dquery.NoFuzziness, n.Boost)
+	default:
+		return &matchNode{}
+	}
+}
+
+// boolean compiles a parsed boolean expression: AND → must (NOT operands → must_not),
+// OR → should, NOT → must_not. Terms and phrases match over the default fields with
+// their recommended weights, NEAR/N matches both operands close together in one field.
+func (c *compiler) boolean(n token.Node, lang dquery.Language) node {
+	switch n := n.(type) {
+	case *token.Term:
+		return c.defaultFields(dquery.RecommendedFieldWeights, 1, func(field string, weight float64) node {
+			return c.matchField(field, n.Value, lang, false, dquery.NoFuzziness, weight)
+		})
+	case *token.Phrase:
+		return c.defaultFields(dquery.RecommendedFieldWeights, 1, func(field string, weight float64) node {
+			return c.phraseField(field, n.Value, lang, 0, false, weight)
+		})
+	case *token.And:
+		var must, mustNot []node
+		for _, operand := range n.Operands {
+			if not, ok := operand.(*token.Not); ok {
+				mustNot = append(mustNot, c.boolean(not.Operand, lang))
+				continue
+			}
+			must = append(must, c.boolean(operand, lang))
+		}
+		return newBoolNode(must, nil, mustNot, nil, 0)
+	case *token.Or:
+		should := make([]node, 0, len(n.Operands))
+		for _, operand := range n.Operands {
+			should = append(should, c.boolean(operand, lang))
+		}
+		return newBoolNode(nil, should, nil, nil, 1)
+	case *token.Not:
+		return newBoolNode(nil, nil, []node{c.boolean(n.Operand, lang)}, nil, 0)
+	case *token.Near:
+		should := make([]node, 0, len(dquery.DefaultFields))
+		for _, field := range dquery.DefaultFields {
+			ix := c.store.field(field, lang)
+			should = append(should, &nearNode{
+				field:    field,
+				ix:       ix,
+				left:     c.nearOperand(ix, n.Left),
+				right:    c.nearOperand(ix, n.Right),
+				distance: n.Distance,
+				boost:    dquery.RecommendedFieldWeights[field],
+			})
+		}
+		return newBoolNode(nil, should, nil, nil, 1)
+	default:
+		return &matchNode{}
+	}
+}
+
+func (c *compiler) nearOperand(ix *fieldIndex, n token.Node) phraseTerms {
+	switch n := n.(type) {
+	case *token.Term:
+		return analyzePhrase(ix, n.Value, false)
+	case *token.Phrase:
+		return analyzePhrase(ix, n.Value, false)
+	default:
+		return phraseTerms{}
+	}
+}
+
+// defaultFields builds a multi_match over the default fields, field by field
+func (c *compiler) defaultFields(weights map[string]float64, boost float64, build func(field string, weight float64) node) node {
+	children := make([]node, 0, len(dquery.DefaultFields))
+	for _, field := range dquery.DefaultFields {
+		children = append(children, build(field, weights[field]))
+	}
+	return &disMaxNode{children: children, boost: boost}
+}
+
+// matchField compiles a match of text on a field. With fuzziness every term also matches
+// the indexed terms within its edit distance, reported as expansions.
+func (c *compiler) matchField(field, text string, lang dquery.Language, and bool, fuzziness dquery.Fuzziness, boost float64) node {
+	ix := c.store.field(field, lang)
+	n := &matchNode{field: field, ix: ix, and: and, boost: boost}
+	if ix == nil {
+		return n
+	}
+	for _, term := range ix.analyzer.terms(text) {
+		variants := []string{term}
+		if maxEdits := fuzziness.MaxEdits(term); maxEdits > 0 {
+			expanded := expandTerm([]*fieldIndex{ix}, term, maxEdits, dquery.DefaultFuzzyMaxExpansions)
+			c.addExpansions(term, expanded)
+			variants = append(variants, expanded...)
+		}
+		n.terms = append(n.terms, variants)
+	}
+	return n
+}
+
+// addExpansions records the expansions of a term, merged across the fields it was expanded in
+func (c *compiler) addExpansions(term string, expanded []string) {
+	if len(expanded) == 0 {
+		return
+	}
+	for i := range c.expansions {
+		if c.expansions[i].Term != term {
+			continue
+		}
+		for _, e := range expanded {
+			if !slices.Contains(c.expansions[i].Expansions, e) {
+				c.expansions[i].Expansions = append(c.expansions[i].Expansions, e)
+			}
+		}
+		return
+	}
+	c.expansions = append(c.expansions, dquery.TermExpansion{Term: term, Expansions: expanded})
+}
+
+// phraseField compiles a phrase on a field; with prefix the last word matches the indexed
+// terms starting with it (match_phrase_prefix)
+func (c *compiler) phraseField(field, text string, lang dquery.Language, slop int, prefix bool, boost float64) node {
+	ix := c.store.field(field, lang)
+	n := &phraseNode{field: field, ix: ix, slop: slop, boost: boost}
+	if ix != nil {
+		n.phraseTerms = analyzePhrase(ix, text, prefix)
+	}
+	return n
+}
+
+// analyzePhrase analyzes the words of a phrase with their relative positions
+func analyzePhrase(ix *fieldIndex, text string, prefix bool) phraseTerms {
+	if ix == nil {
+		return phraseTerms{}
+	}
+	var p phraseTerms
+	tokens := ix.analyzer.analyze(text)
+	for i, t := range tokens {
+		variants := []string{t.term}
+		if prefix && i == len(tokens)-1 {
+			variants = ix.prefixed(ix.analyzer.prefix(text[t.start:t.end]), dquery.DefaultFuzzyMaxExpansions)
+		}
+		p.groups = append(p.groups, variants)
+		p.offsets = append(p.offsets, t.pos-tokens[0].pos)
+	}
+	return p
+}
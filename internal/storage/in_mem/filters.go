@@ -0,0 +1,191 @@
+package in_mem
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// filterValue returns the value of an article a terms filter or aggregation compares,
+// the same fields as the filter columns of the PostgreSQL backend
+func filterValue(a document.Article, field query.FilterField) string {
+	switch field {
+	case query.FilterLanguage:
+		return a.Language
+	case query.FilterAuthor:
+		return a.Author
+	case query.FilterSourceID:
+		return a.Metadata.SourceId
+	case query.FilterSourceName:
+		return a.Metadata.SourceName
+	case query.FilterCategory:
+		return a.Metadata.Category
+	default:
+		return ""
+	}
+}
+
+// dateValue returns the date of an article a range filter or date histogram compares
+func dateValue(a document.Article, field query.DateField) time.Time {
+	if field == query.DateCreatedAt {
+		return a.CreatedAt
+	}
+	return a.Metadata.PublishedAt
+}
+
+// matchesFilters reports whether an article passes the filters. Ranges are inclusive and
+// an article without the date never matches a range; terms match exact values.
+func matchesFilters(a document.Article, filters *query.Filters) bool {
+	if filters.IsEmpty() {
+		return true
+	}
+	if len(filters.IDs) > 0 && !slices.Contains(filters.IDs, a.ID) {
+		return false
+	}
+	for field, r := range map[query.DateField]*query.DateRange{
+		query.DatePublishedAt: filters.PublishedAt,
+		query.DateCreatedAt:   filters.CreatedAt,
+	} {
+		if !inRange(dateValue(a, field), r) {
+			return false
+		}
+	}
+	for _, field := range filters.TermFields() {
+		if !slices.Contains(filters.Terms[field], filterValue(a, field)) {
+			return false
+		}
+	}
+	return true
+}
+
+func inRange(t time.Time, r *query.DateRange) bool {
+	if r.IsEmpty() {
+		return true
+	}
+	if t.IsZero() {
+		return false
+	}
+	if r.Gte != nil && t.Before(*r.Gte) {
+		return false
+	}
+	if r.Lte != nil && t.After(*r.Lte) {
+		return false
+	}
+	return true
+}
+
+// aggregate computes the facets over the matching articles.
+//
+//	terms: one bucket per non-empty value, most frequent first, ties by key, at most Size buckets
+//	date_histogram: one bucket per calendar interval (UTC) with articles, oldest first
+func aggregate(aggs []query.Aggregation, articles []document.Article) map[string]query.AggregationResult {
+	if len(aggs) == 0 {
+		return nil
+	}
+
+	results := make(map[string]query.AggregationResult, len(aggs))
+	for _, agg := range aggs {
+		counts := make(map[string]int64)
+		var keys []string
+		for _, a := range articles {
+			var key string
+			switch agg.Kind {
+			case query.TermsAggregation:
+				key = filterValue(a, query.FilterField(agg.Field))
+			case query.DateHistogramAggregation:
+				if t := dateValue(a, query.DateField(agg.Field)); !t.IsZero() {
+					key = truncateDate(t, agg.Interval).Format(time.RFC3339)
+				}
+			}
+			if key == "" {
+				continue
+			}
+			if counts[key] == 0 {
+				keys = append(keys, key)
+			}
+			counts[key]++
+		}
+
+		buckets := make([]query.Bucket, 0, len(keys))
+		for _, key := range keys {
+			buckets = append(buckets, query.Bucket{Key: key, DocCount: counts[key]})
+		}
+		if agg.Kind == query.TermsAggregation {
+			sort.Slice(buckets, func(i, j int) bool {
+				if buckets[i].DocCount != buckets[j].DocCount {
+					return buckets[i].DocCount > buckets[j].DocCount
+				}
+				return buckets[i].Key < buckets[j].Key
+			})
+			buckets = buckets[:min(len(buckets), agg.Size)]
+		} else {
+			// RFC3339 keys in UTC sort chronologically
+			sort.Slice(buckets, func(i, j int) bool { return buckets[i].Key < buckets[j].Key })
+		}
+		results[agg.Name] = query.AggregationResult{Buckets: buckets}
+	}
+	return results
+}
+
+// truncateDate truncates a time in UTC to the start of its calendar interval, like
+// date_trunc; weeks start on Monday
+func truncateDate(t time.Time, interval query.CalendarInterval) time.Time {
+	t = t.UTC()
+	year, month, day := t.Date()
+	switch interval {
+	case query.IntervalHour:
+		return t.Truncate(time.Hour)
+	case query.IntervalDay:
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	case query.IntervalWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, time.UTC)
+	case query.IntervalQuarter:
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, time.UTC)
+	case query.IntervalYear:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// compareCursors orders two hits by their sort positions: negative when a comes first.
+// Every sort ends with the id descending, like the ORDER BY of the PostgreSQL backend.
+func compareCursors(s query.Sort, a, b *query.Cursor) int {
+	if len(s) == 0 {
+		s = query.DefaultSort
+	}
+	value := 0
+	for _, k := range s {
+		var c int
+		if k.Field == query.SortRelevance {
+			c = cmp.Compare(a.Score, b.Score)
+		} else {
+			c = compareSortValues(a.Values[value], b.Values[value])
+			value++
+		}
+		if k.Order == query.SortDesc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return -bytes.Compare(a.ID[:], b.ID[:])
+}
+
+func compareSortValues(a, b query.SortValue) int {
+	if a.Time != nil && b.Time != nil {
+		return a.Time.Compare(*b.Time)
+	}
+	if a.Keyword != nil && b.Keyword != nil {
+		return strings.Compare(*a.Keyword, *b.Keyword)
+	}
+	return 0
+}
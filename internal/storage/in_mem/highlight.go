@@ -0,0 +1,95 @@
+package in_mem
+
+import (
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// highlightArticle builds the highlight fragments of an article: the words of each field
+// whose analyzed term is one of the matched query terms, wrapped in the highlight tags.
+// Fields without a matched word are left out; nil when no field has one.
+func (s *Store) highlightArticle(h *dquery.Highlight, a document.Article, lang dquery.Language, terms map[string]bool) map[string][]string {
+	result := make(map[string][]string)
+	for _, field := range h.Fields {
+		ix := s.field(field, lang)
+		if ix == nil {
+			continue
+		}
+		text := fieldText(a, field)
+		var matches []span
+		for _, t := range ix.analyzer.analyze(text) {
+			if terms[t.term] {
+				matches = append(matches, span{start: t.start, end: t.end})
+			}
+		}
+		if fragments := fragment(text, matches, h); len(fragments) > 0 {
+			result[field] = fragments
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// fragment cuts text into at most NumberOfFragments fragments of about FragmentSize bytes
+// around the matches, in text order. A fragment starts at most half its size before its
+// first match and ends on a word boundary.
+func fragment(text string, matches []span, h *dquery.Highlight) []string {
+	if len(matches) == 0 {
+		return nil
+	}
+	ws := words(text)
+
+	var fragments []string
+	next := 0
+	for next < len(matches) && len(fragments) < h.NumberOfFragments {
+		first := matches[next]
+
+		// the first word of the fragment: back off to give the match some context
+		start := wordAt(ws, first.start)
+		for start > 0 && first.end-ws[start-1].start <= h.FragmentSize/2 {
+			start--
+		}
+		// the last word: extend while the fragment fits, at least the first match
+		end := wordAt(ws, first.start)
+		for end+1 < len(ws) && ws[end+1].end-ws[start].start <= h.FragmentSize {
+			end++
+		}
+		// fill short fields from the start when there is room left
+		for start > 0 && ws[end].end-ws[start-1].start <= h.FragmentSize {
+			start--
+		}
+		from, to := ws[start].start, ws[end].end
+		if start == 0 {
+			from = 0
+		}
+
+		var b strings.Builder
+		pos := from
+		for next < len(matches) && matches[next].end <= to {
+			m := matches[next]
+			b.WriteString(text[pos:m.start])
+			b.WriteString(h.PreTag)
+			b.WriteString(text[m.start:m.end])
+			b.WriteString(h.PostTag)
+			pos = m.end
+			next++
+		}
+		b.WriteString(text[pos:to])
+		fragments = append(fragments, strings.TrimSpace(b.String()))
+	}
+	return fragments
+}
+
+// wordAt returns the index of the word starting at offset
+func wordAt(ws []span, offset int) int {
+	for i, w := range ws {
+		if w.start >= offset {
+			return i
+		}
+	}
+	return len(ws) - 1
+}
@@ -2,47 +2,106 @@ package in_mem
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
-	"sync"
+	"slices"
 
+	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
 	"github.com/google/uuid"
 )
 
-type InMemIndexer struct {
-	storageLock sync.RWMutex
-	storage     map[uuid.UUID]document.Article
+// Indexer saves articles to a Store and indexes them for search
+type Indexer struct {
+	store *Store
 }
 
-func NewInMemIndexer() *InMemIndexer {
-	return &InMemIndexer{
-		storage: make(map[uuid.UUID]document.Article),
-	}
+func NewIndexer(store *Store) *Indexer {
+	return &Indexer{store: store}
 }
 
-func (s *InMemIndexer) Save(_ context.Context, article document.Article) (uuid.UUID, error) {
-	// Implement the logic to save the article to a JSON file
-	// This is a placeholder implementation
-	// You would typically use encoding/json to marshal the article and write it to the file
-	slog.Info("Saving article to JSON file", "title", article.Title)
-	s.storageLock.Lock()
-	defer s.storageLock.Unlock()
-	s.storage[article.ID] = article
-	// For now, just return a new UUID
-	return uuid.New(), nil
+// Save indexes an article, replacing the stored version with the same ID.
+// Articles without an ID get a new one.
+func (s *Indexer) Save(_ context.Context, article document.Article) (uuid.UUID, error) {
+	if article.ID == uuid.Nil {
+		article.ID = document.NewArticleID()
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	s.store.put(article)
+
+	return article.ID, nil
 }
 
-func (s *InMemIndexer) SaveBulk(ctx context.Context, articles []document.Article) error {
-	s.storageLock.Lock()
-	defer s.storageLock.Unlock()
+func (s *Indexer) SaveBulk(_ context.Context, articles []document.Article) error {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
 
 	for _, article := range articles {
 		if article.ID == uuid.Nil {
-			article.ID = uuid.New()
+			article.ID = document.NewArticleID()
 		}
-		s.storage[article.ID] = article
-		slog.Info("Saving article to in-memory storage", "title", article.Title, "id", article.ID)
+		s.store.put(article)
 	}
 
+	slog.Debug("Indexed articles in memory", "count", len(articles), "total", len(s.store.docs))
 	return nil
 }
+
+// EmbedIndexer saves article embeddings to a Store, one vector per article and model
+type EmbedIndexer struct {
+	store *Store
+}
+
+func NewEmbedIndexer(store *Store) *EmbedIndexer {
+	return &EmbedIndexer{store: store}
+}
+
+func (e *EmbedIndexer) Save(_ context.Context, vec *embedding.Vec) (uuid.UUID, error) {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	if err := e.store.putVector(vec); err != nil {
+		return uuid.Nil, err
+	}
+	return vec.ID, nil
+}
+
+func (e *EmbedIndexer) SaveBulk(_ context.Context, vecs []*embedding.Vec) error {
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	for _, vec := range vecs {
+		if err := e.store.putVector(vec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putVector stores the embedding of an article for its model.
+// The caller must hold the write lock.
+func (s *Store) putVector(vec *embedding.Vec) error {
+	if vec.ID == uuid.Nil {
+		return fmt.Errorf("embedding has no article id")
+	}
+	if len(vec.Embedding) == 0 {
+		return fmt.Errorf("embedding of article %s is empty", vec.ID)
+	}
+
+	models, ok := s.vectors[vec.ID]
+	if !ok {
+		models = make(map[string][]float32, 1)
+		s.vectors[vec.ID] = models
+	}
+	models[vec.Model] = slices.Clone(vec.Embedding)
+	return nil
+}
+
+// Compile-time interface assertions
+var (
+	_ storage.Indexer      = (*Indexer)(nil)
+	_ storage.EmbedIndexer = (*EmbedIndexer)(nil)
+)
@@ -0,0 +1,188 @@
+package in_mem
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// BM25 parameters, the Elasticsearch defaults
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// fieldIndex is the inverted index of one analyzed field: positional postings per term
+// and the field length of every document, the statistics BM25 needs.
+type fieldIndex struct {
+	analyzer *analyzer
+	// postings maps a term to the positions it occurs at in every document containing it
+	postings map[string]map[uuid.UUID][]int
+	// lengths holds the number of terms of the field per document; documents with an
+	// empty field are absent
+	lengths     map[uuid.UUID]int
+	totalLength int
+}
+
+func newFieldIndex(a *analyzer) *fieldIndex {
+	return &fieldIndex{
+		analyzer: a,
+		postings: make(map[string]map[uuid.UUID][]int),
+		lengths:  make(map[uuid.UUID]int),
+	}
+}
+
+// add indexes the text of a document's field; the document must not be indexed yet
+func (ix *fieldIndex) add(id uuid.UUID, text string) {
+	tokens := ix.analyzer.analyze(text)
+	if len(tokens) == 0 {
+		return
+	}
+	for _, t := range tokens {
+		docs, ok := ix.postings[t.term]
+		if !ok {
+			docs = make(map[uuid.UUID][]int)
+			ix.postings[t.term] = docs
+		}
+		docs[id] = append(docs[id], t.pos)
+	}
+	ix.lengths[id] = len(tokens)
+	ix.totalLength += len(tokens)
+}
+
+// remove drops a document indexed with text from the index
+func (ix *fieldIndex) remove(id uuid.UUID, text string) {
+	length, ok := ix.lengths[id]
+	if !ok {
+		return
+	}
+	for _, term := range ix.analyzer.terms(text) {
+		docs := ix.postings[term]
+		delete(docs, id)
+		if len(docs) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	delete(ix.lengths, id)
+	ix.totalLength -= length
+}
+
+// docCount is the number of documents with a non-empty field
+func (ix *fieldIndex) docCount() int {
+	return len(ix.lengths)
+}
+
+// idf is the BM25 inverse document frequency of a term: ln(1 + (N - df + 0.5) / (df + 0.5))
+func (ix *fieldIndex) idf(term string) float64 {
+	n, df := float64(ix.docCount()), float64(len(ix.postings[term]))
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// avgLength is the average field length over the documents with a non-empty field
+func (ix *fieldIndex) avgLength() float64 {
+	if len(ix.lengths) == 0 {
+		return 0
+	}
+	return float64(ix.totalLength) / float64(len(ix.lengths))
+}
+
+// tfNorm is the BM25 term frequency saturation of freq occurrences in a document:
+// freq × (k1 + 1) / (freq + k1 × (1 - b + b × length / avgLength))
+func (ix *fieldIndex) tfNorm(id uuid.UUID, freq float64) float64 {
+	length := float64(ix.lengths[id])
+	return freq * (bm25K1 + 1) / (freq + bm25K1*(1-bm25B+bm25B*length/ix.avgLength()))
+}
+
+// termScore is the BM25 score of a term in a document, 0 when the document does not contain it
+func (ix *fieldIndex) termScore(id uuid.UUID, term string) float64 {
+	positions := ix.postings[term][id]
+	if len(positions) == 0 {
+		return 0
+	}
+	return ix.idf(term) * ix.tfNorm(id, float64(len(positions)))
+}
+
+// expandTerm returns the terms indexed in any of the fields within maxEdits of term,
+// closest and most frequent first, at most limit of them. term itself is not an expansion.
+func expandTerm(fields []*fieldIndex, term string, maxEdits, limit int) []string {
+	type candidate struct {
+		distance int
+		df       int
+	}
+	candidates := make(map[string]*candidate)
+	n := utf8.RuneCountInString(term)
+	for _, ix := range fields {
+		for indexed, docs := range ix.postings {
+			if c, ok := candidates[indexed]; ok {
+				c.df += len(docs)
+				continue
+			}
+			m := utf8.RuneCountInString(indexed)
+			if indexed == term || m < n-maxEdits || m > n+maxEdits {
+				continue
+			}
+			if d := levenshtein(term, indexed); d <= maxEdits {
+				candidates[indexed] = &candidate{distance: d, df: len(docs)}
+			}
+		}
+	}
+
+	terms := make([]string, 0, len(candidates))
+	for t := range candidates {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		a, b := candidates[terms[i]], candidates[terms[j]]
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		if a.df != b.df {
+			return a.df > b.df
+		}
+		return terms[i] < terms[j]
+	})
+	return terms[:min(len(terms), limit)]
+}
+
+// prefixed returns the indexed terms starting with prefix, most frequent first, at most limit of them
+func (ix *fieldIndex) prefixed(prefix string, limit int) []string {
+	var terms []string
+	for indexed := range ix.postings {
+		if strings.HasPrefix(indexed, prefix) {
+			terms = append(terms, indexed)
+		}
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		di, dj := len(ix.postings[terms[i]]), len(ix.postings[terms[j]])
+		if di != dj {
+			return di > dj
+		}
+		return terms[i] < terms[j]
+	})
+	return terms[:min(len(terms), limit)]
+}
+
+// levenshtein is the edit distance between two strings, counted in runes
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,543 @@
+package in_mem
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+)
+
+// scores maps the articles a query matches to their score
+type scores map[uuid.UUID]float64
+
+// node is a compiled full-text query evaluated against the index of a Store.
+// Nodes mirror the Elasticsearch queries a request compiles to (see es.compileQuery)
+// and score them with BM25, so both backends rank alike on the same articles.
+// Nodes must be evaluated under the read lock of the store they were compiled on.
+type node interface {
+	// eval returns the score of every article the node matches
+	eval(s *Store) scores
+	// explain explains the score of an article; false when the node does not match it
+	explain(s *Store, id uuid.UUID) (dquery.Explanation, bool)
+	// collectTerms adds the indexed terms the node matches on to terms, for highlighting
+	collectTerms(terms map[string]bool)
+}
+
+// matchNode matches the analyzed terms of a text in one field (match query).
+// Every query term matches itself or any of its fuzzy expansions and scores the best of them.
+type matchNode struct {
+	field string
+	ix    *fieldIndex
+	// terms lists the variants of every query term, the term itself first
+	terms [][]string
+	and   bool
+	boost float64
+}
+
+func (n *matchNode) eval(_ *Store) scores {
+	result := make(scores)
+	if n.ix == nil {
+		return result
+	}
+
+	matched := make(map[uuid.UUID]int)
+	for _, variants := range n.terms {
+		best := make(map[uuid.UUID]float64)
+		for _, term := range variants {
+			for id := range n.ix.postings[term] {
+				best[id] = max(best[id], n.ix.termScore(id, term))
+			}
+		}
+		for id, score := range best {
+			result[id] += score
+			matched[id]++
+		}
+	}
+
+	for id := range result {
+		if n.and && matched[id] < len(n.terms) {
+			delete(result, id)
+			continue
+		}
+		result[id] *= n.boost
+	}
+	return result
+}
+
+func (n *matchNode) explain(_ *Store, id uuid.UUID) (dquery.Explanation, bool) {
+	if n.ix == nil {
+		return dquery.Explanation{}, false
+	}
+
+	e := dquery.Explanation{Description: "sum of:"}
+	for _, variants := range n.terms {
+		var best *dquery.Explanation
+		for _, term := range variants {
+			if freq := len(n.ix.postings[term][id]); freq > 0 {
+				d := n.ix.explainBM25(id, n.field, []string{term}, n.ix.idf(term), float64(freq))
+				if best == nil || d.Value > best.Value {
+					best = &d
+				}
+			}
+		}
+		if best != nil {
+			e.Value += best.Value
+			e.Details = append(e.Details, *best)
+		}
+	}
+
+	if len(e.Details) == 0 || (n.and && len(e.Details) < len(n.terms)) {
+		return dquery.Explanation{}, false
+	}
+	return boosted(collapse(e), n.boost), true
+}
+
+func (n *matchNode) collectTerms(terms map[string]bool) {
+	for _, variants := range n.terms {
+		for _, term := range variants {
+			terms[term] = true
+		}
+	}
+}
+
+// phraseTerms are the analyzed terms of a phrase with their positions relative to the first
+type phraseTerms struct {
+	// groups lists the variants of every phrase term (several for a trailing prefix)
+	groups  [][]string
+	offsets []int
+}
+
+// occurrence is a match of a phrase between two positions; extra counts the positions
+// beyond the phrase length it spans
+type occurrence struct {
+	start, end, extra int
+}
+
+// occurrences finds the matches of the phrase in a document: its terms in order, with at
+// most slop extra positions between them in total. Every start position counts once,
+// with the tightest match that follows it.
+func (p phraseTerms) occurrences(ix *fieldIndex, id uuid.UUID, slop int) []occurrence {
+	if len(p.groups) == 0 {
+		return nil
+	}
+	positions := make([][]int, len(p.groups))
+	for i, variants := range p.groups {
+		for _, term := range variants {
+			positions[i] = append(positions[i], ix.postings[term][id]...)
+		}
+		if len(positions[i]) == 0 {
+			return nil
+		}
+		sort.Ints(positions[i])
+	}
+
+	var found []occurrence
+	for _, start := range positions[0] {
+		prev, extra, ok := start, 0, true
+		for i := 1; i < len(p.groups) && ok; i++ {
+			want := prev + p.offsets[i] - p.offsets[i-1]
+			j := sort.SearchInts(positions[i], want)
+			if j == len(positions[i]) {
+				ok = false
+				break
+			}
+			extra += positions[i][j] - want
+			prev = positions[i][j]
+			ok = extra <= slop
+		}
+		if ok {
+			found = append(found, occurrence{start: start, end: prev, extra: extra})
+		}
+	}
+	return found
+}
+
+// candidates returns the documents containing a variant of every phrase term
+func (p phraseTerms) candidates(ix *fieldIndex) map[uuid.UUID]bool {
+	var docs map[uuid.UUID]bool
+	for _, variants := range p.groups {
+		group := make(map[uuid.UUID]bool)
+		for _, term := range variants {
+			for id := range ix.postings[term] {
+				if docs == nil || docs[id] {
+					group[id] = true
+				}
+			}
+		}
+		docs = group
+	}
+	return docs
+}
+
+// idf sums the idf of the phrase terms, each scored with its rarest variant
+func (p phraseTerms) idf(ix *fieldIndex) float64 {
+	var idf float64
+	for _, variants := range p.groups {
+		best := 0.0
+		for _, term := range variants {
+			if len(ix.postings[term]) > 0 {
+				best = max(best, ix.idf(term))
+			}
+		}
+		idf += best
+	}
+	return idf
+}
+
+// terms returns the first variant of every phrase term, for explanations
+func (p phraseTerms) terms() []string {
+	terms := make([]string, 0, len(p.groups))
+	for _, variants := range p.groups {
+		if len(variants) > 0 {
+			terms = append(terms, variants[0])
+		}
+	}
+	return terms
+}
+
+func (p phraseTerms) collectTerms(terms map[string]bool) {
+	for _, variants := range p.groups {
+		for _, term := range variants {
+			terms[term] = true
+		}
+	}
+}
+
+// sloppyFreq weighs every occurrence by 1 / (1 + extra), like Lucene's sloppy phrase scorer
+func sloppyFreq(found []occurrence) float64 {
+	var freq float64
+	for _, o := range found {
+		freq += 1 / float64(1+o.extra)
+	}
+	return freq
+}
+
+// phraseNode matches a phrase in one field (match_phrase, phrase_prefix). With slop the
+// terms must still appear in order, like the PostgreSQL distance operators.
+type phraseNode struct {
+	field string
+	ix    *fieldIndex
+	phraseTerms
+	slop  int
+	boost float64
+}
+
+func (n *phraseNode) eval(_ *Store) scores {
+	result := make(scores)
+	if n.ix == nil {
+		return result
+	}
+	idf := n.idf(n.ix)
+	for id := range n.candidates(n.ix) {
+		if found := n.occurrences(n.ix, id, n.slop); len(found) > 0 {
+			result[id] = n.boost * idf * n.ix.tfNorm(id, sloppyFreq(found))
+		}
+	}
+	return result
+}
+
+func (n *phraseNode) explain(_ *Store, id uuid.UUID) (dquery.Explanation, bool) {
+	if n.ix == nil {
+		return dquery.Explanation{}, false
+	}
+	found := n.occurrences(n.ix, id, n.slop)
+	if len(found) == 0 {
+		return dquery.Explanation{}, false
+	}
+	return boosted(n.ix.explainBM25(id, n.field, n.terms(), n.idf(n.ix), sloppyFreq(found)), n.boost), true
+}
+
+// nearNode matches two terms or phrases in either order with at most distance words
+// between them, in one field (NEAR/N, an unordered intervals query)
+type nearNode struct {
+	field       string
+	ix          *fieldIndex
+	left, right phraseTerms
+	distance    int
+	boost       float64
+}
+
+// freq counts the occurrences of the left operand with the right one close enough
+func (n *nearNode) freq(id uuid.UUID) float64 {
+	rights := n.right.occurrences(n.ix, id, 0)
+	var freq float64
+	for _, l := range n.left.occurrences(n.ix, id, 0) {
+		for _, r := range rights {
+			gap := r.start - l.end - 1
+			if r.start < l.start {
+				gap = l.start - r.end - 1
+			}
+			if gap >= 0 && gap <= n.distance {
+				freq++
+				break
+			}
+		}
+	}
+	return freq
+}
+
+func (n *nearNode) operands() phraseTerms {
+	return phraseTerms{groups: slices.Concat(n.left.groups, n.right.groups)}
+}
+
+func (n *nearNode) eval(_ *Store) scores {
+	result := make(scores)
+	if n.ix == nil {
+		return result
+	}
+	both := n.operands()
+	idf := both.idf(n.ix)
+	for id := range both.candidates(n.ix) {
+		if freq := n.freq(id); freq > 0 {
+			result[id] = n.boost * idf * n.ix.tfNorm(id, freq)
+		}
+	}
+	return result
+}
+
+func (n *nearNode) explain(_ *Store, id uuid.UUID) (dquery.Explanation, bool) {
+	if n.ix == nil {
+		return dquery.Explanation{}, false
+	}
+	freq := n.freq(id)
+	if freq == 0 {
+		return dquery.Explanation{}, false
+	}
+	both := n.operands()
+	return boosted(n.ix.explainBM25(id, n.field, both.terms(), both.idf(n.ix), freq), n.boost), true
+}
+
+func (n *nearNode) collectTerms(terms map[string]bool) {
+	n.left.collectTerms(terms)
+	n.right.collectTerms(terms)
+}
+
+// disMaxNode matches when any child matches and scores the best matching child
+// (multi_match best_fields with tie_breaker 0)
+type disMaxNode struct {
+	children []node
+	boost    float64
+}
+
+func (n *disMaxNode) eval(s *Store) scores {
+	result := make(scores)
+	for _, c := range n.children {
+		for id, score := range c.eval(s) {
+			if current, ok := result[id]; !ok || score > current {
+				result[id] = score
+			}
+		}
+	}
+	for id := range result {
+		result[id] *= n.boost
+	}
+	return result
+}
+
+func (n *disMaxNode) explain(s *Store, id uuid.UUID) (dquery.Explanation, bool) {
+	e := dquery.Explanation{Description: "max of:"}
+	for _, c := range n.children {
+		if d, ok := c.explain(s, id); ok {
+			e.Value = max(e.Value, d.Value)
+			e.Details = append(e.Details, d)
+		}
+	}
+	if len(e.Details) == 0 {
+		return dquery.Explanation{}, false
+	}
+	return boosted(collapse(e), n.boost), true
+}
+
+func (n *disMaxNode) collectTerms(terms map[string]bool) {
+	for _, c := range n.children {
+		c.collectTerms(terms)
+	}
+}
+
+// boolNode combines clauses with Elasticsearch bool semantics: must and filter clauses
+// are required, at least minimumShouldMatch should clauses must match and no must_not
+// clause may match. Must and should clauses sum up to the score. A bool node with
+// must_not clauses only matches every other article with a zero score.
+type boolNode struct {
+	must, should, mustNot, filter []node
+	minimumShouldMatch            int
+	boost                         float64
+}
+
+// newBoolNode creates a bool node; a zero minimumShouldMatch defaults to 1 when the
+// query has should clauses but no must or filter clauses (see dquery.Bool)
+func newBoolNode(must, should, mustNot, filter []node, minimumShouldMatch int) *boolNode {
+	if minimumShouldMatch == 0 && len(should) > 0 && len(must) == 0 && len(filter) == 0 {
+		minimumShouldMatch = 1
+	}
+	return &boolNode{
+		must:               must,
+		should:             should,
+		mustNot:            mustNot,
+		filter:             filter,
+		minimumShouldMatch: minimumShouldMatch,
+		boost:              1,
+	}
+}
+
+func (n *boolNode) eval(s *Store) scores {
+	var result scores
+	for i, clauses := range [][]node{n.must, n.filter} {
+		for _, c := range clauses {
+			matched := c.eval(s)
+			if result == nil {
+				result = make(scores, len(matched))
+				for id := range matched {
+					result[id] = 0
+				}
+			}
+			for id := range result {
+				score, ok := matched[id]
+				if !ok {
+					delete(result, id)
+					continue
+				}
+				// filter clauses do not score
+				if i == 0 {
+					result[id] += score
+				}
+			}
+		}
+	}
+
+	if len(n.should) > 0 {
+		should := make(scores)
+		matched := make(map[uuid.UUID]int)
+		for _, c := range n.should {
+			for id, score := range c.eval(s) {
+				should[id] += score
+				matched[id]++
+			}
+		}
+		if result == nil {
+			result = make(scores, len(should))
+			for id := range should {
+				result[id] = 0
+			}
+		}
+		for id := range result {
+			if matched[id] < n.minimumShouldMatch {
+				delete(result, id)
+				continue
+			}
+			result[id] += should[id]
+		}
+	}
+
+	if result == nil {
+		result = make(scores, len(s.docs))
+		for id := range s.docs {
+			result[id] = 0
+		}
+	}
+	for _, c := range n.mustNot {
+		for id := range c.eval(s) {
+			delete(result, id)
+		}
+	}
+
+	for id := range result {
+		result[id] *= n.boost
+	}
+	return result
+}
+
+func (n *boolNode) explain(s *Store, id uuid.UUID) (dquery.Explanation, bool) {
+	e := dquery.Explanation{Description: "sum of:"}
+	for _, c := range n.must {
+		d, ok := c.explain(s, id)
+		if !ok {
+			return dquery.Explanation{}, false
+		}
+		e.Value += d.Value
+		e.Details = append(e.Details, d)
+	}
+	for _, c := range n.filter {
+		if _, ok := c.explain(s, id); !ok {
+			return dquery.Explanation{}, false
+		}
+	}
+	matched := 0
+	for _, c := range n.should {
+		if d, ok := c.explain(s, id); ok {
+			matched++
+			e.Value += d.Value
+			e.Details = append(e.Details, d)
+		}
+	}
+	if matched < n.minimumShouldMatch {
+		return dquery.Explanation{}, false
+	}
+	for _, c := range n.mustNot {
+		if _, ok := c.explain(s, id); ok {
+			return dquery.Explanation{}, false
+		}
+	}
+	if _, ok := s.docs[id]; !ok {
+		return dquery.Explanation{}, false
+	}
+
+	if len(e.Details) == 0 {
+		e.Description = "match without scoring clauses"
+	}
+	return boosted(collapse(e), n.boost), true
+}
+
+// collectTerms adds the terms of the clauses that match on terms; must_not terms are not highlighted
+func (n *boolNode) collectTerms(terms map[string]bool) {
+	for _, clauses := range [][]node{n.must, n.should, n.filter} {
+		for _, c := range clauses {
+			c.collectTerms(terms)
+		}
+	}
+}
+
+// explainBM25 explains the BM25 score of terms matched freq times in a document's field
+func (ix *fieldIndex) explainBM25(id uuid.UUID, field string, terms []string, idf, freq float64) dquery.Explanation {
+	tf := ix.tfNorm(id, freq)
+	return dquery.Explanation{
+		Value:       idf * tf,
+		Description: fmt.Sprintf("weight(%s:%s) BM25, product of:", field, strings.Join(terms, " ")),
+		Field:       field,
+		Terms:       terms,
+		Details: []dquery.Explanation{
+			{
+				Value:       idf,
+				Description: fmt.Sprintf("idf, computed as ln(1 + (N - n + 0.5) / (n + 0.5)) with N = %d documents with the field", ix.docCount()),
+			},
+			{
+				Value: tf,
+				Description: fmt.Sprintf("tf, computed as freq * (k1 + 1) / (freq + k1 * (1 - b + b * dl / avgdl)) with freq = %g, k1 = %g, b = %g, dl = %d, avgdl = %g",
+					freq, bm25K1, bm25B, ix.lengths[id], ix.avgLength()),
+			},
+		},
+	}
+}
+
+// boosted multiplies an explanation by a query boost
+func boosted(e dquery.Explanation, boost float64) dquery.Explanation {
+	if boost == 1 {
+		return e
+	}
+	return dquery.Explanation{
+		Value:       e.Value * boost,
+		Description: "product of:",
+		Details:     []dquery.Explanation{{Value: boost, Description: "boost"}, e},
+	}
+}
+
+// collapse replaces a sum or max with a single detail by that detail
+func collapse(e dquery.Explanation) dquery.Explanation {
+	if len(e.Details) == 1 {
+		return e.Details[0]
+	}
+	return e
+}
@@ -0,0 +1,39 @@
+package in_mem
+
+import (
+	"context"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	"github.com/google/uuid"
+)
+
+// Reader reads stored articles by id
+type Reader struct {
+	store *Store
+}
+
+func NewReader(store *Store) *Reader {
+	return &Reader{store: store}
+}
+
+// GetByIDs returns the stored articles with the given ids in request order; unknown ids are skipped
+func (r *Reader) GetByIDs(_ context.Context, ids []uuid.UUID) ([]document.Article, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	articles := make([]document.Article, 0, len(ids))
+	for _, id := range ids {
+		if a, ok := r.store.docs[id]; ok {
+			articles = append(articles, a)
+		}
+	}
+	return articles, nil
+}
+
+// Compile-time interface assertions
+var _ storage.Reader = (*Reader)(nil)
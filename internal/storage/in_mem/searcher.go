@@ -0,0 +1,246 @@
+package in_mem
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+)
+
+// Searcher runs full-text queries against the inverted index of a Store.
+// Queries compile to the same structure as their Elasticsearch translation and score
+// with BM25, so rankings are comparable with the Elasticsearch backend on the same data.
+type Searcher struct {
+	store *Store
+}
+
+func NewSearcher(store *Store) *Searcher {
+	return &Searcher{store: store}
+}
+
+// SearchStringQuery implements storage.FtsSearcher interface
+func (s *Searcher) SearchStringQuery(ctx context.Context, query *dquery.String, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing in-memory query_string search",
+		"query", query.Query,
+		"language", query.GetLanguage(),
+		"operator", query.GetDefaultOperator(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"size", baseOpts.Size)
+
+	return s.execute(ctx, &dquery.Base{Kind: dquery.StringType, QueryString: query}, baseOpts)
+}
+
+// SearchField implements storage.FtsSearcher interface
+func (s *Searcher) SearchField(ctx context.Context, query *dquery.Match, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing in-memory match search",
+		"query", query.Query,
+		"field", query.Field,
+		"operator", query.GetOperator(),
+		"fuzziness", query.Fuzziness,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"size", baseOpts.Size)
+
+	return s.execute(ctx, &dquery.Base{Kind: dquery.MatchType, Match: query}, baseOpts)
+}
+
+// SearchFields implements storage.FtsSearcher interface
+func (s *Searcher) SearchFields(ctx context.Context, query *dquery.MultiMatch, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing in-memory multi_match search",
+		"query", query.Query,
+		"fields", query.Fields,
+		"operator", query.GetOperator(),
+		"fuzziness", query.Fuzziness,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"size", baseOpts.Size)
+
+	return s.execute(ctx, &dquery.Base{Kind: dquery.MultiMatchType, MultiMatch: query}, baseOpts)
+}
+
+// SearchPhrase implements storage.FtsSearcher interface
+func (s *Searcher) SearchPhrase(ctx context.Context, query *dquery.Phrase, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing in-memory phrase search",
+		"query", query.Query,
+		"fields", query.Fields,
+		"slop", query.GetSlop(),
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"size", baseOpts.Size)
+
+	return s.execute(ctx, &dquery.Base{Kind: dquery.PhraseType, Phrase: query}, baseOpts)
+}
+
+// SearchBoolean implements storage.FtsSearcher interface
+func (s *Searcher) SearchBoolean(ctx context.Context, query *dquery.Boolean, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing in-memory boolean search",
+		"expression", query.Expression,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"size", baseOpts.Size)
+
+	return s.execute(ctx, &dquery.Base{Kind: dquery.BooleanType, Boolean: query}, baseOpts)
+}
+
+// SearchBool implements storage.FtsSearcher interface
+func (s *Searcher) SearchBool(ctx context.Context, query *dquery.Bool, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing in-memory bool search",
+		"must", len(query.Must),
+		"should", len(query.Should),
+		"must_not", len(query.MustNot),
+		"filter", len(query.Filter),
+		"has_cursor", baseOpts.Cursor != nil,
+		"size", baseOpts.Size)
+
+	return s.execute(ctx, &dquery.Base{Kind: dquery.BoolType, Bool: query}, baseOpts)
+}
+
+// hit is a matching article with its sort position
+type hit struct {
+	article  document.Article
+	rawScore float64
+	cursor   *dquery.Cursor
+}
+
+// execute evaluates a full-text query and pages through its matches.
+// Rescoring factors multiply the text score, so max scores, sorting and cursors all see
+// the rescored score. Statistics, total and aggregations cover every filtered match.
+func (s *Searcher) execute(ctx context.Context, query *dquery.Base, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	cursor, size := baseOpts.Cursor, baseOpts.Size
+	if cursor != nil && !cursor.Continues(baseOpts.Sort) {
+		return nil, fmt.Errorf("cursor was issued for sort %q, not %q", cursor.Sort, baseOpts.Sort)
+	}
+
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	c := &compiler{store: s.store}
+	root, err := c.compile(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []hit
+	var matched []document.Article
+	var scores []float64
+	for id, textScore := range root.eval(s.store) {
+		article := s.store.docs[id]
+		if !matchesFilters(article, baseOpts.Filters) {
+			continue
+		}
+		rawScore := rescore(baseOpts.Rescoring, article, textScore)
+		hits = append(hits, hit{
+			article:  article,
+			rawScore: rawScore,
+			cursor:   storage.NextCursor(baseOpts.Sort, dto.ArticleFromDocument(article), rawScore),
+		})
+		matched = append(matched, article)
+		scores = append(scores, rawScore)
+	}
+
+	stats := ranking.StatsOf(scores)
+	if stats.Count == 0 {
+		return &storage.SearchResult{
+			Aggregations: dquery.EmptyAggregationResults(baseOpts.Aggregations),
+			Expansions:   c.expansions,
+		}, nil
+	}
+	slog.Info("Computed global max score", "max_score", stats.Max, "total_matches", stats.Count)
+
+	sort.Slice(hits, func(i, j int) bool {
+		return compareCursors(baseOpts.Sort, hits[i].cursor, hits[j].cursor) < 0
+	})
+	if cursor != nil {
+		hits = hits[sort.Search(len(hits), func(i int) bool {
+			return compareCursors(baseOpts.Sort, hits[i].cursor, cursor) > 0
+		}):]
+	}
+	hasMore := len(hits) > size
+	hits = hits[:min(len(hits), size)]
+
+	var terms map[string]bool
+	if baseOpts.Highlight != nil {
+		terms = make(map[string]bool)
+		root.collectTerms(terms)
+	}
+
+	articles := make([]dto.ArticleSearchResult, 0, len(hits))
+	rawScores := make([]float64, 0, len(hits))
+	for _, h := range hits {
+		searchResult := dto.ArticleSearchResult{
+			Article: dto.ArticleFromDocument(h.article),
+			Score:   utils.RoundFloat64(h.rawScore, dquery.ScoreDecimalPlaces),
+		}
+		if baseOpts.Highlight != nil {
+			searchResult.Highlight = s.store.highlightArticle(baseOpts.Highlight, h.article, query.GetLanguage(), terms)
+		}
+		storage.ExplainRescoring(baseOpts.Rescoring, &searchResult, h.rawScore)
+		if baseOpts.Explain {
+			text, _ := root.explain(s.store, h.article.ID)
+			storage.ExplainHit(baseOpts.Rescoring, &searchResult, h.rawScore, text)
+		}
+
+		articles = append(articles, searchResult)
+		rawScores = append(rawScores, h.rawScore)
+	}
+
+	result := &storage.SearchResult{
+		Hits:         articles,
+		HasMore:      hasMore,
+		MaxScore:     utils.RoundFloat64(stats.Max, dquery.ScoreDecimalPlaces),
+		TotalMatches: stats.Count,
+		Aggregations: aggregate(baseOpts.Aggregations, matched),
+		Expansions:   c.expansions,
+	}
+	if len(articles) == 0 {
+		return result, nil
+	}
+
+	offset := storage.PageOffset(cursor)
+	storage.NormalizeScores(baseOpts.Normalization, articles, rawScores, stats, offset)
+	result.PageMaxScore = utils.RoundFloat64(slices.Max(rawScores), dquery.ScoreDecimalPlaces)
+	if hasMore {
+		result.NextCursor = hits[len(hits)-1].cursor
+		result.NextCursor.Offset = offset + int64(len(articles))
+	}
+
+	slog.Info("In-memory search results fetched",
+		"kind", query.Kind,
+		"total_page_matches", len(articles),
+		"global_max_score", stats.Max)
+
+	return result, nil
+}
+
+// rescore multiplies a text score by the rescoring factors of an article
+func rescore(r *dquery.Rescoring, a document.Article, score float64) float64 {
+	if r == nil {
+		return score
+	}
+	if r.Recency != nil {
+		score *= r.Recency.Factor(a.Metadata.PublishedAt)
+	}
+	if len(r.SourceWeights) > 0 {
+		score *= r.SourceWeight(a.Metadata.SourceName)
+	}
+	for _, f := range r.FieldValueFactors {
+		if f.Field == dquery.ScoreFieldLanguageConfidence {
+			score *= f.Apply(a.Metadata.LanguageConfidence)
+		}
+	}
+	return score
+}
+
+// Compile-time interface assertions
+var _ storage.FtsSearcher = (*Searcher)(nil)
@@ -0,0 +1,311 @@
+package in_mem
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+)
+
+// testArticles is a small corpus; ids are ordered so ties break predictably (higher id first)
+var testArticles = []document.Article{
+	{
+		ID:          uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		Title:       "Climate summit reaches agreement on emissions",
+		Description: "World leaders agree to cut carbon emissions",
+		Content:     "The climate summit in Paris ended with an agreement on emissions targets.",
+		Author:      "Jane Doe",
+		Language:    "english",
+		Metadata:    document.ArticleMetadata{SourceName: "Reuters", Category: "environment", PublishedAt: time.Date(2024, 5, 10, 8, 0, 0, 0, time.UTC)},
+	},
+	{
+		ID:          uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+		Title:       "Markets rally as inflation cools",
+		Description: "Stocks rise after inflation data",
+		Content:     "Investors welcomed news that inflation cooled, and the summit of central bankers was calm.",
+		Author:      "John Smith",
+		Language:    "english",
+		Metadata:    document.ArticleMetadata{SourceName: "Bloomberg", Category: "business", PublishedAt: time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC)},
+	},
+	{
+		ID:          uuid.MustParse("00000000-0000-0000-0000-000000000003"),
+		Title:       "Summit on climate policy postponed",
+		Description: "Climate talks delayed",
+		Content:     "Officials postponed the summit; climate policy remains uncertain and carbon taxes are debated.",
+		Author:      "Jane Doe",
+		Language:    "english",
+		Metadata:    document.ArticleMetadata{SourceName: "Reuters", Category: "environment", PublishedAt: time.Date(2024, 6, 20, 8, 0, 0, 0, time.UTC)},
+	},
+	{
+		ID:          uuid.MustParse("00000000-0000-0000-0000-000000000004"),
+		Title:       "Energy prices and the climate",
+		Description: "Energy markets react to climate rules",
+		Content:     "Renewable energy investment grows as climate rules tighten.",
+		Language:    "english",
+		Metadata:    document.ArticleMetadata{SourceName: "BBC", Category: "business"},
+	},
+}
+
+func newTestSearcher(t *testing.T) (*Store, *Searcher) {
+	t.Helper()
+	store := NewStore()
+	if err := NewIndexer(store).SaveBulk(context.Background(), testArticles); err != nil {
+		t.Fatalf("SaveBulk: %v", err)
+	}
+	return store, NewSearcher(store)
+}
+
+func hitIDs(result *storage.SearchResult) []string {
+	ids := make([]string, 0, len(result.Hits))
+	for _, h := range result.Hits {
+		ids = append(ids, h.ID.String()[len(h.ID.String())-1:])
+	}
+	return ids
+}
+
+func TestSearcherQueries(t *testing.T) {
+	_, searcher := newTestSearcher(t)
+	ctx := context.Background()
+	opts := func() *dquery.BaseOptions { return &dquery.BaseOptions{Size: 10} }
+
+	tests := []struct {
+		name   string
+		search func() (*storage.SearchResult, error)
+		want   []string
+	}{
+		{
+			name: "match any term",
+			search: func() (*storage.SearchResult, error) {
+				q := dquery.NewMatch("title", "climate summit", dquery.WithMatchOperator(operator.Or))
+				return searcher.SearchField(ctx, q, opts())
+			},
+			// the shorter title ranks first
+			want: []string{"3", "1", "4"},
+		},
+		{
+			name: "match all terms by default",
+			search: func() (*storage.SearchResult, error) {
+				return searcher.SearchField(ctx, dquery.NewMatch("content", "summit carbon"), opts())
+			},
+			want: []string{"3"},
+		},
+		{
+			name: "exact phrase",
+			search: func() (*storage.SearchResult, error) {
+				q, _ := dquery.NewPhrase("climate summit", []string{"content"})
+				return searcher.SearchPhrase(ctx, q, opts())
+			},
+			want: []string{"1"},
+		},
+		{
+			name: "phrase with slop keeps order",
+			search: func() (*storage.SearchResult, error) {
+				q, _ := dquery.NewPhrase("summit policy", []string{"content"}, dquery.WithPhraseSlop(2))
+				return searcher.SearchPhrase(ctx, q, opts())
+			},
+			want: []string{"3"},
+		},
+		{
+			name: "query_string prefix and exclusion",
+			search: func() (*storage.SearchResult, error) {
+				return searcher.SearchStringQuery(ctx, dquery.NewQueryString("energ* -renewable"), opts())
+			},
+			want: []string{},
+		},
+		{
+			name: "query_string field scope",
+			search: func() (*storage.SearchResult, error) {
+				return searcher.SearchStringQuery(ctx, dquery.NewQueryString(`author:doe +carbon`), opts())
+			},
+			want: []string{"3", "1"},
+		},
+		{
+			name: "boolean and not",
+			search: func() (*storage.SearchResult, error) {
+				return searcher.SearchBoolean(ctx, &dquery.Boolean{Expression: "climate AND NOT energy"}, opts())
+			},
+			want: []string{"3", "1"},
+		},
+		{
+			name: "boolean near",
+			search: func() (*storage.SearchResult, error) {
+				return searcher.SearchBoolean(ctx, &dquery.Boolean{Expression: `agreement NEAR/2 summit`}, opts())
+			},
+			want: []string{"1"},
+		},
+		{
+			name: "bool with filter and must_not",
+			search: func() (*storage.SearchResult, error) {
+				return searcher.SearchBool(ctx, &dquery.Bool{
+					Filter:  []dquery.Clause{{Match: dquery.NewMatch("content", "summit")}},
+					MustNot: []dquery.Clause{{Match: dquery.NewMatch("title", "markets")}},
+				}, opts())
+			},
+			want: []string{"3", "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.search()
+			if err != nil {
+				t.Fatalf("search: %v", err)
+			}
+			if got := hitIDs(result); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("hits = %v, want %v", got, tt.want)
+			}
+			if int(result.TotalMatches) != len(tt.want) {
+				t.Errorf("total matches = %d, want %d", result.TotalMatches, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSearcherRanksByBM25(t *testing.T) {
+	_, searcher := newTestSearcher(t)
+
+	q, _ := dquery.NewMultiMatchQuery("climate", []string{"title^3", "content"})
+	result, err := searcher.SearchFields(context.Background(), q, &dquery.BaseOptions{Size: 10, Explain: true})
+	if err != nil {
+		t.Fatalf("SearchFields: %v", err)
+	}
+	if len(result.Hits) != 3 {
+		t.Fatalf("got %d hits, want 3", len(result.Hits))
+	}
+	for i := 1; i < len(result.Hits); i++ {
+		if result.Hits[i].Score > result.Hits[i-1].Score {
+			t.Errorf("hits are not ordered by score: %v", hitIDs(result))
+		}
+	}
+	if result.MaxScore != result.Hits[0].Score {
+		t.Errorf("max score = %v, want the top score %v", result.MaxScore, result.Hits[0].Score)
+	}
+	for _, h := range result.Hits {
+		if h.Explanation == nil {
+			t.Fatalf("hit %s has no explanation", h.ID)
+		}
+		if math.Abs(h.Explanation.Value-h.Score) > 1e-3 {
+			t.Errorf("explanation value %v does not match score %v", h.Explanation.Value, h.Score)
+		}
+	}
+}
+
+func TestSearcherFuzzyExpansions(t *testing.T) {
+	_, searcher := newTestSearcher(t)
+
+	q := dquery.NewMatch("content", "climte", dquery.WithMatchFuzziness("AUTO"))
+	result, err := searcher.SearchField(context.Background(), q, &dquery.BaseOptions{Size: 10})
+	if err != nil {
+		t.Fatalf("SearchField: %v", err)
+	}
+	if result.TotalMatches != 3 {
+		t.Errorf("total matches = %d, want 3", result.TotalMatches)
+	}
+	if len(result.Expansions) != 1 || result.Expansions[0].Term != "climte" || result.Expansions[0].Expansions[0] != "climate" {
+		t.Errorf("expansions = %+v, want climte → climate", result.Expansions)
+	}
+}
+
+func TestSearcherPagination(t *testing.T) {
+	_, searcher := newTestSearcher(t)
+	ctx := context.Background()
+	q := dquery.NewMatch("content", "climate summit energy", dquery.WithMatchOperator(operator.Or))
+	sort, _ := dquery.NewSort("published_at")
+
+	var seen []string
+	var cursor *dquery.Cursor
+	for page := 0; page < 5; page++ {
+		result, err := searcher.SearchField(ctx, q, &dquery.BaseOptions{Size: 1, Cursor: cursor, Sort: sort})
+		if err != nil {
+			t.Fatalf("SearchField: %v", err)
+		}
+		seen = append(seen, hitIDs(result)...)
+		if !result.HasMore {
+			break
+		}
+		cursor = result.NextCursor
+	}
+
+	// newest first; the article without a publish date is last
+	if got := strings.Join(seen, ","); got != "3,2,1,4" {
+		t.Errorf("pages = %s, want 3,2,1,4", got)
+	}
+}
+
+func TestSearcherFiltersAndAggregations(t *testing.T) {
+	_, searcher := newTestSearcher(t)
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	result, err := searcher.SearchField(context.Background(), dquery.NewMatch("content", "summit climate", dquery.WithMatchOperator(operator.Or)), &dquery.BaseOptions{
+		Size:    10,
+		Filters: &dquery.Filters{PublishedAt: &dquery.DateRange{Gte: &from}},
+		Aggregations: []dquery.Aggregation{
+			{Name: "sources", Kind: dquery.TermsAggregation, Field: "source_name", Size: 5},
+			{Name: "months", Kind: dquery.DateHistogramAggregation, Field: "published_at", Interval: dquery.IntervalMonth},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SearchField: %v", err)
+	}
+
+	if got := strings.Join(hitIDs(result), ","); got != "3,2" {
+		t.Errorf("hits = %s, want 3,2", got)
+	}
+	sources := result.Aggregations["sources"].Buckets
+	if len(sources) != 2 || sources[0].Key != "Bloomberg" || sources[1].Key != "Reuters" {
+		t.Errorf("sources = %+v, want Bloomberg and Reuters with one article each", sources)
+	}
+	months := result.Aggregations["months"].Buckets
+	if len(months) != 1 || months[0].Key != "2024-06-01T00:00:00Z" || months[0].DocCount != 2 {
+		t.Errorf("months = %+v, want one June bucket with 2 articles", months)
+	}
+}
+
+func TestSearcherHighlight(t *testing.T) {
+	_, searcher := newTestSearcher(t)
+	h, _ := dquery.NewHighlight([]string{"title"})
+
+	q, _ := dquery.NewPhrase("climate policy", []string{"title"})
+	result, err := searcher.SearchPhrase(context.Background(), q, &dquery.BaseOptions{Size: 10, Highlight: h})
+	if err != nil {
+		t.Fatalf("SearchPhrase: %v", err)
+	}
+	if len(result.Hits) != 1 {
+		t.Fatalf("got %d hits, want 1", len(result.Hits))
+	}
+	want := "Summit on <em>climate</em> <em>policy</em> postponed"
+	if got := result.Hits[0].Highlight["title"]; len(got) != 1 || got[0] != want {
+		t.Errorf("highlight = %q, want %q", got, want)
+	}
+}
+
+func TestIndexerReplacesArticle(t *testing.T) {
+	store, searcher := newTestSearcher(t)
+	ctx := context.Background()
+
+	updated := testArticles[3]
+	updated.Title = "Solar subsidies expand"
+	updated.Description = ""
+	updated.Content = "Subsidies for solar panels expand."
+	if _, err := NewIndexer(store).Save(ctx, updated); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	result, err := searcher.SearchField(ctx, dquery.NewMatch("title", "energy"), &dquery.BaseOptions{Size: 10})
+	if err != nil {
+		t.Fatalf("SearchField: %v", err)
+	}
+	if len(result.Hits) != 0 {
+		t.Errorf("old title still matches: %v", hitIDs(result))
+	}
+	if store.Len() != len(testArticles) {
+		t.Errorf("store has %d articles, want %d", store.Len(), len(testArticles))
+	}
+}
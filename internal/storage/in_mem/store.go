@@ -0,0 +1,143 @@
+package in_mem
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+)
+
+// textFields are the analyzed fields with one sub-field per language
+var textFields = []string{"title", "subtitle", "description", "content"}
+
+// keywordTextFields are analyzed with the standard analyzer only
+var keywordTextFields = []string{"author"}
+
+// Store holds the articles, their inverted index and their embeddings in memory.
+// Indexers write to it and searchers read from it; build every component of the
+// backend on the same Store. It is safe for concurrent use.
+type Store struct {
+	mu   sync.RWMutex
+	docs map[uuid.UUID]document.Article
+	// fields maps a field to its index per language; keywordTextFields are indexed
+	// under the empty language
+	fields map[string]map[dquery.Language]*fieldIndex
+	// vectors holds the embeddings of every article per model
+	vectors map[uuid.UUID]map[string][]float32
+}
+
+func NewStore() *Store {
+	fields := make(map[string]map[dquery.Language]*fieldIndex, len(textFields)+len(keywordTextFields))
+	for _, field := range textFields {
+		fields[field] = make(map[dquery.Language]*fieldIndex, len(languageAnalyzers))
+		for lang, a := range languageAnalyzers {
+			fields[field][lang] = newFieldIndex(a)
+		}
+	}
+	for _, field := range keywordTextFields {
+		fields[field] = map[dquery.Language]*fieldIndex{"": newFieldIndex(standardAnalyzer)}
+	}
+
+	return &Store{
+		docs:    make(map[uuid.UUID]document.Article),
+		fields:  fields,
+		vectors: make(map[uuid.UUID]map[string][]float32),
+	}
+}
+
+// put indexes an article, replacing the stored version with the same ID.
+// The caller must hold the write lock.
+func (s *Store) put(article document.Article) {
+	if old, ok := s.docs[article.ID]; ok {
+		for field, indexes := range s.fields {
+			for _, ix := range indexes {
+				ix.remove(old.ID, fieldText(old, field))
+			}
+		}
+	}
+
+	article.SearchVector = nil
+	s.docs[article.ID] = article
+	for field, indexes := range s.fields {
+		for _, ix := range indexes {
+			ix.add(article.ID, fieldText(article, field))
+		}
+	}
+}
+
+// field returns the index of a field for a query language, nil for fields that are not indexed
+func (s *Store) field(name string, lang dquery.Language) *fieldIndex {
+	indexes, ok := s.fields[name]
+	if !ok {
+		return nil
+	}
+	if ix, ok := indexes[""]; ok {
+		return ix
+	}
+	if ix, ok := indexes[lang]; ok {
+		return ix
+	}
+	return indexes[dquery.DefaultLanguage]
+}
+
+// Len returns the number of stored articles
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.docs)
+}
+
+// Load indexes the articles of a newline-delimited JSON stream of document.Article.
+// Articles without an ID get a new one. Returns the number of articles loaded.
+func (s *Store) Load(r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var articles []document.Article
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var article document.Article
+		if err := json.Unmarshal(scanner.Bytes(), &article); err != nil {
+			return 0, fmt.Errorf("line %d: invalid article: %w", line, err)
+		}
+		if article.ID == uuid.Nil {
+			article.ID = document.NewArticleID()
+		}
+		articles = append(articles, article)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read articles: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, article := range articles {
+		s.put(article)
+	}
+	return len(articles), nil
+}
+
+// fieldText returns the text of an indexed field of an article
+func fieldText(a document.Article, field string) string {
+	switch field {
+	case "title":
+		return a.Title
+	case "subtitle":
+		return a.Subtitle
+	case "description":
+		return a.Description
+	case "content":
+		return a.Content
+	case "author":
+		return a.Author
+	default:
+		return ""
+	}
+}
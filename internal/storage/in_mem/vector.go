@@ -0,0 +1,270 @@
+package in_mem
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultThreshold is the default maximum cosine distance of a semantic match
+	defaultThreshold = 0.7
+	// hybridCandidateDepth bounds how many candidates each leg contributes to the fusion
+	hybridCandidateDepth = 200
+)
+
+// scored is an article with its score in a ranking: a cosine similarity or a fused score
+type scored struct {
+	article document.Article
+	score   float64
+}
+
+// nearest returns the articles with an embedding of model within maxDistance (cosine
+// distance) of vec, closest first, at most k of them. The caller must hold the read lock.
+func (s *Store) nearest(vec []float32, model string, filters *dquery.Filters, maxDistance float64, k int) []scored {
+	var found []scored
+	for id, models := range s.vectors {
+		docVec, ok := models[model]
+		if !ok {
+			continue
+		}
+		article, ok := s.docs[id]
+		if !ok || !matchesFilters(article, filters) {
+			continue
+		}
+		similarity := cosineSimilarity(vec, docVec)
+		if 1-similarity < maxDistance {
+			found = append(found, scored{article: article, score: similarity})
+		}
+	}
+
+	sortScored(found)
+	return found[:min(len(found), k)]
+}
+
+// sortScored orders a ranking by score descending, ties by id descending
+func sortScored(list []scored) {
+	sort.Slice(list, func(i, j int) bool {
+		if c := cmp.Compare(list[i].score, list[j].score); c != 0 {
+			return c > 0
+		}
+		return bytes.Compare(list[i].article.ID[:], list[j].article.ID[:]) > 0
+	})
+}
+
+// cosineSimilarity of two vectors; 0 when their dimensions differ or one is zero
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// SemanticSearcher finds the nearest stored embeddings of the query embedding by brute force
+type SemanticSearcher struct {
+	embedder *embedding.Embedder
+	store    *Store
+}
+
+func NewSemanticSearcher(embedder *embedding.Embedder, store *Store) *SemanticSearcher {
+	return &SemanticSearcher{embedder: embedder, store: store}
+}
+
+func (s *SemanticSearcher) SearchSemantic(ctx context.Context, query *dquery.Semantic, baseOpts *dquery.BaseOptions) (*storage.VectorSearchResult, error) {
+	vec, err := s.embedder.EmbedQuery(ctx, query.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := query.Threshold
+	if threshold == 0 {
+		threshold = defaultThreshold
+	}
+
+	s.store.mu.RLock()
+	found := s.store.nearest(vec.Embedding, vec.Model, baseOpts.Filters, threshold, baseOpts.Size)
+	s.store.mu.RUnlock()
+
+	hits := make([]dto.ArticleSearchResult, 0, len(found))
+	similarities := make([]float64, 0, len(found))
+	for _, n := range found {
+		hits = append(hits, dto.ArticleSearchResult{
+			Article: dto.ArticleFromDocument(n.article),
+			Score:   utils.RoundFloat64(n.score, dquery.ScoreDecimalPlaces),
+		})
+		similarities = append(similarities, n.score)
+	}
+
+	// kNN returns a single page, so the page is the match set
+	storage.NormalizeScores(baseOpts.Normalization, hits, similarities, ranking.StatsOf(similarities), 0)
+
+	return &storage.VectorSearchResult{Hits: hits}, nil
+}
+
+// HybridSearcher fuses a BM25 ranking with a vector ranking via RRF
+type HybridSearcher struct {
+	embedder *embedding.Embedder
+	store    *Store
+}
+
+func NewHybridSearcher(embedder *embedding.Embedder, store *Store) *HybridSearcher {
+	return &HybridSearcher{embedder: embedder, store: store}
+}
+
+// SearchHybrid ranks the articles by the sum of 1 / (k + rank) over the lexical and the
+// vector ranking. The lexical leg is a multi_match over the default fields, the vector leg
+// the nearest embeddings of the same model. RRF fused scores are not a stable keyset, so
+// hybrid returns a single page.
+func (s *HybridSearcher) SearchHybrid(ctx context.Context, query *dquery.Hybrid, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	size := baseOpts.Size
+	lang := query.GetLanguage()
+	k := float64(query.GetK())
+
+	slog.Info("Executing in-memory hybrid RRF search",
+		"query", query.Query,
+		"language", lang,
+		"k", k,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", size)
+
+	vec, err := s.embedder.EmbedQuery(ctx, query.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed hybrid query: %w", err)
+	}
+
+	// Each leg must contribute at least size candidates so large pages can fill.
+	legDepth := max(hybridCandidateDepth, size)
+
+	fields := make([]dquery.MultiMatchField, 0, len(dquery.DefaultFields))
+	for _, field := range dquery.DefaultFields {
+		fields = append(fields, dquery.NewMultiMatchBoostedField(field, dquery.DefaultFieldWeights[field]))
+	}
+	lexicalQuery := &dquery.MultiMatch{Query: query.Query, Fields: fields, Language: lang}
+
+	s.store.mu.RLock()
+	c := &compiler{store: s.store}
+	var lexical []scored
+	for id, score := range c.multiMatch(lexicalQuery).eval(s.store) {
+		if article := s.store.docs[id]; matchesFilters(article, baseOpts.Filters) {
+			lexical = append(lexical, scored{article: article, score: score})
+		}
+	}
+	// every stored vector is a candidate of the vector leg, however far
+	vector := s.store.nearest(vec.Embedding, vec.Model, baseOpts.Filters, math.Inf(1), legDepth)
+	s.store.mu.RUnlock()
+
+	sortScored(lexical)
+	lexical = lexical[:min(len(lexical), legDepth)]
+
+	fused := make(map[uuid.UUID]*scored)
+	for _, leg := range [][]scored{lexical, vector} {
+		for rank, n := range leg {
+			f, ok := fused[n.article.ID]
+			if !ok {
+				f = &scored{article: n.article}
+				fused[n.article.ID] = f
+			}
+			f.score += 1 / (k + float64(rank+1))
+		}
+	}
+	if len(fused) == 0 {
+		return &storage.SearchResult{}, nil
+	}
+
+	candidates := make([]scored, 0, len(fused))
+	scores := make([]float64, 0, len(fused))
+	for _, f := range fused {
+		candidates = append(candidates, *f)
+		scores = append(scores, f.score)
+	}
+	sortScored(candidates)
+	candidates = candidates[:min(len(candidates), size)]
+
+	articles := make([]dto.ArticleSearchResult, 0, len(candidates))
+	rawScores := make([]float64, 0, len(candidates))
+	for _, f := range candidates {
+		articles = append(articles, dto.ArticleSearchResult{
+			Article: dto.ArticleFromDocument(f.article),
+			Score:   utils.RoundFloat64(f.score, dquery.ScoreDecimalPlaces),
+		})
+		rawScores = append(rawScores, f.score)
+	}
+
+	// RRF scores are normalized over the fused candidates of both legs
+	stats := ranking.StatsOf(scores)
+	storage.NormalizeScores(baseOpts.Normalization, articles, rawScores, stats, 0)
+
+	slog.Info("In-memory hybrid search results fetched",
+		"total_page_matches", len(articles),
+		"total_matches", stats.Count,
+		"max_score", stats.Max)
+
+	return &storage.SearchResult{
+		Hits:         articles,
+		MaxScore:     utils.RoundFloat64(stats.Max, dquery.ScoreDecimalPlaces),
+		PageMaxScore: utils.RoundFloat64(stats.Max, dquery.ScoreDecimalPlaces),
+		TotalMatches: stats.Count,
+	}, nil
+}
+
+// VectorStore reads document embeddings of one model from a Store and embeds queries
+// with the same model. It is the in-memory implementation of storage.VectorStore.
+type VectorStore struct {
+	embedder *embedding.Embedder
+	store    *Store
+	model    string
+}
+
+func NewVectorStore(embedder *embedding.Embedder, store *Store, model string) *VectorStore {
+	return &VectorStore{embedder: embedder, store: store, model: model}
+}
+
+func (s *VectorStore) QueryVector(ctx context.Context, text string) ([]float32, error) {
+	vec, err := s.embedder.EmbedQuery(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	return vec.Embedding, nil
+}
+
+func (s *VectorStore) DocVectors(_ context.Context, ids []uuid.UUID) (map[uuid.UUID][]float32, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+
+	out := make(map[uuid.UUID][]float32, len(ids))
+	for _, id := range ids {
+		if vec, ok := s.store.vectors[id][s.model]; ok {
+			out[id] = vec
+		}
+	}
+	return out, nil
+}
+
+// Compile-time interface assertions
+var (
+	_ storage.SemanticSearcher = (*SemanticSearcher)(nil)
+	_ storage.HybridSearcher   = (*HybridSearcher)(nil)
+	_ storage.VectorStore      = (*VectorStore)(nil)
+)
@@ -0,0 +1,100 @@
+package in_mem
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+)
+
+// staticEmbedder is an embedding.Client returning a fixed vector
+type staticEmbedder struct {
+	vec []float32
+}
+
+func (s staticEmbedder) Generate(_ context.Context, _ embedding.Request) (*embedding.Response, error) {
+	return &embedding.Response{Embedding: s.vec}, nil
+}
+
+func (s staticEmbedder) GenerateBatch(_ context.Context, _ embedding.BatchRequest) (*embedding.BatchResponse, error) {
+	return &embedding.BatchResponse{Embeddings: [][]float32{s.vec}}, nil
+}
+
+// newVectorTestStore indexes the test articles with embeddings: 1 and 3 point along the
+// query vector, 2 is orthogonal to it and 4 points away from it
+func newVectorTestStore(t *testing.T) (*Store, *embedding.Embedder) {
+	t.Helper()
+	store, _ := newTestSearcher(t)
+	vectors := [][]float32{{1, 0.1}, {0, 1}, {1, 0.3}, {-1, 0}}
+	vecs := make([]*embedding.Vec, 0, len(testArticles))
+	for i, a := range testArticles {
+		vecs = append(vecs, &embedding.Vec{ID: a.ID, Model: embedding.DefaultModel, Embedding: vectors[i]})
+	}
+	// a vector of another model never matches
+	vecs = append(vecs, &embedding.Vec{ID: testArticles[1].ID, Model: "other", Embedding: []float32{1, 0}})
+	if err := NewEmbedIndexer(store).SaveBulk(context.Background(), vecs); err != nil {
+		t.Fatalf("SaveBulk: %v", err)
+	}
+	return store, embedding.NewEmbedder(staticEmbedder{vec: []float32{1, 0}})
+}
+
+func TestSemanticSearcher(t *testing.T) {
+	store, embedder := newVectorTestStore(t)
+
+	result, err := NewSemanticSearcher(embedder, store).SearchSemantic(context.Background(), dquery.NewSemantic("climate"), &dquery.BaseOptions{Size: 10})
+	if err != nil {
+		t.Fatalf("SearchSemantic: %v", err)
+	}
+
+	var ids []string
+	for _, h := range result.Hits {
+		ids = append(ids, h.ID.String()[len(h.ID.String())-1:])
+	}
+	// 2 and 4 are beyond the default cosine distance threshold
+	if got := strings.Join(ids, ","); got != "1,3" {
+		t.Errorf("hits = %s, want 1,3", got)
+	}
+	if result.Hits[0].Score <= result.Hits[1].Score {
+		t.Errorf("nearest hit does not score highest: %v, %v", result.Hits[0].Score, result.Hits[1].Score)
+	}
+}
+
+func TestHybridSearcher(t *testing.T) {
+	store, embedder := newVectorTestStore(t)
+
+	result, err := NewHybridSearcher(embedder, store).SearchHybrid(context.Background(), dquery.NewHybrid("markets"), &dquery.BaseOptions{Size: 10})
+	if err != nil {
+		t.Fatalf("SearchHybrid: %v", err)
+	}
+
+	// every article has a vector, so every article is a candidate of the vector leg.
+	// 2 and 4 match "markets" and gain a lexical rank on top of their low vector ranks.
+	var ids []string
+	for _, h := range result.Hits {
+		ids = append(ids, h.ID.String()[len(h.ID.String())-1:])
+	}
+	if got := strings.Join(ids, ","); got != "2,4,1,3" {
+		t.Errorf("hits = %s, want 2,4,1,3", got)
+	}
+	if result.TotalMatches != 4 {
+		t.Errorf("total matches = %d, want 4", result.TotalMatches)
+	}
+	if result.HasMore || result.NextCursor != nil {
+		t.Errorf("hybrid search must return a single page")
+	}
+}
+
+func TestVectorStoreDocVectors(t *testing.T) {
+	store, embedder := newVectorTestStore(t)
+
+	vectors, err := NewVectorStore(embedder, store, "other").DocVectors(context.Background(), []uuid.UUID{testArticles[0].ID, testArticles[1].ID})
+	if err != nil {
+		t.Fatalf("DocVectors: %v", err)
+	}
+	if len(vectors) != 1 || vectors[testArticles[1].ID] == nil {
+		t.Errorf("vectors = %v, want the vector of article 2 only", vectors)
+	}
+}
@@ -13,6 +13,13 @@ type Indexer interface {
 	SaveBulk(ctx context.Context, articles []document.Article) error
 }
 
+// IndexRefresher is implemented by indexers that maintain derived search structures
+// (e.g. the PostgreSQL lexeme vocabulary used for fuzzy matching). Pipelines call
+// Refresh once after a run.
+type IndexRefresher interface {
+	Refresh(ctx context.Context) error
+}
+
 type Type string
 
 const (
@@ -20,6 +27,10 @@ const (
 	PG    Type = "pg"
 	Solr  Type = "solr"
 	InMem Type = "in_mem"
+	// Parade is PostgreSQL with the ParadeDB bm25 index (db/parade_migrations)
+	Parade Type = "paradedb"
+	// Tiger is PostgreSQL with the pg_textsearch bm25 index (db/tiger_migrations)
+	Tiger Type = "tiger"
 )
 
 type StorerError string
@@ -0,0 +1,105 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// BuildAggregationSQL compiles one facet into a GROUP BY query over the rows matching where.
+// where and its positional arguments are shared with the search query, so the counts cover
+// the full match set (filters included) rather than the returned page.
+//
+// Example:
+//
+//	terms(category, size 5)       → SELECT metadata->>'category' ... GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT 5
+//	date_histogram(published_at)  → SELECT date_trunc('month', (metadata->>'publishedAt')::timestamptz, 'UTC') ... GROUP BY 1 ORDER BY 1
+func BuildAggregationSQL(agg query.Aggregation, where string, alias string) (string, error) {
+	prefix := aliasPrefix(alias)
+
+	switch agg.Kind {
+	case query.TermsAggregation:
+		column, ok := filterColumns[query.FilterField(agg.Field)]
+		if !ok {
+			return "", fmt.Errorf("unsupported terms aggregation field: %s", agg.Field)
+		}
+		col := fmt.Sprintf(column, prefix)
+		return fmt.Sprintf(`
+			SELECT %[1]s AS key, COUNT(*) AS doc_count
+			FROM articles %[2]s
+			WHERE %[3]s AND %[1]s IS NOT NULL AND %[1]s <> ''
+			GROUP BY 1
+			ORDER BY 2 DESC, 1 ASC
+			LIMIT %[4]d
+		`, col, alias, where, agg.Size), nil
+	case query.DateHistogramAggregation:
+		column, ok := dateColumns[query.DateField(agg.Field)]
+		if !ok {
+			return "", fmt.Errorf("unsupported date_histogram field: %s", agg.Field)
+		}
+		if !query.SupportedIntervals[agg.Interval] {
+			return "", fmt.Errorf("unsupported date_histogram interval: %s", agg.Interval)
+		}
+		col := fmt.Sprintf(column, prefix)
+		return fmt.Sprintf(`
+			SELECT date_trunc('%[1]s', %[2]s, 'UTC') AS key, COUNT(*) AS doc_count
+			FROM articles %[3]s
+			WHERE %[4]s AND %[2]s IS NOT NULL
+			GROUP BY 1
+			ORDER BY 1 ASC
+		`, agg.Interval, col, alias, where), nil
+	default:
+		return "", fmt.Errorf("unsupported aggregation kind: %s", agg.Kind)
+	}
+}
+
+// RunAggregations executes every requested facet against the rows matching where.
+func RunAggregations(ctx context.Context, db *pgxpool.Pool, aggs []query.Aggregation, where string, args []any) (map[string]query.AggregationResult, error) {
+	if len(aggs) == 0 {
+		return nil, nil
+	}
+
+	results := make(map[string]query.AggregationResult, len(aggs))
+	for _, agg := range aggs {
+		sql, err := BuildAggregationSQL(agg, where, "")
+		if err != nil {
+			return nil, err
+		}
+
+		buckets, err := queryBuckets(ctx, db, agg.Kind, sql, args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute aggregation %s: %w", agg.Name, err)
+		}
+		results[agg.Name] = query.AggregationResult{Buckets: buckets}
+	}
+
+	return results, nil
+}
+
+func queryBuckets(ctx context.Context, db *pgxpool.Pool, kind query.AggregationKind, sql string, args []any) ([]query.Bucket, error) {
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := make([]query.Bucket, 0)
+	for rows.Next() {
+		var bucket query.Bucket
+		if kind == query.DateHistogramAggregation {
+			var key time.Time
+			if err := rows.Scan(&key, &bucket.DocCount); err != nil {
+				return nil, err
+			}
+			bucket.Key = key.UTC().Format(time.RFC3339)
+		} else if err := rows.Scan(&bucket.Key, &bucket.DocCount); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, rows.Err()
+}
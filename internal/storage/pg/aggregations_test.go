@@ -0,0 +1,60 @@
+package pg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestBuildAggregationSQL(t *testing.T) {
+	tests := []struct {
+		name         string
+		agg          query.Aggregation
+		wantContains []string
+		wantErr      bool
+	}{
+		{
+			name: "terms on metadata field",
+			agg:  query.Aggregation{Name: "categories", Kind: query.TermsAggregation, Field: "category", Size: 5},
+			wantContains: []string{
+				"SELECT metadata->>'category' AS key",
+				"GROUP BY 1",
+				"ORDER BY 2 DESC, 1 ASC",
+				"LIMIT 5",
+			},
+		},
+		{
+			name: "date histogram on published_at",
+			agg:  query.Aggregation{Name: "per_week", Kind: query.DateHistogramAggregation, Field: "published_at", Interval: query.IntervalWeek},
+			wantContains: []string{
+				"date_trunc('week', (metadata->>'publishedAt')::timestamptz, 'UTC')",
+				"ORDER BY 1 ASC",
+			},
+		},
+		{
+			name:    "unsupported interval",
+			agg:     query.Aggregation{Name: "x", Kind: query.DateHistogramAggregation, Field: "created_at", Interval: "decade"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported field",
+			agg:     query.Aggregation{Name: "x", Kind: query.TermsAggregation, Field: "title", Size: 5},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, err := BuildAggregationSQL(tt.agg, "search_vector @@ plainto_tsquery('english', $1)", "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BuildAggregationSQL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(sql, want) {
+					t.Errorf("SQL missing %q:\n%s", want, sql)
+				}
+			}
+		})
+	}
+}
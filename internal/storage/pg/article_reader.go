@@ -2,15 +2,25 @@ package pg
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"time"
+	"log/slog"
 
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// similarMaxQueryTerms caps the lexemes taken from the source article for the lexical
+// fallback, mirroring the more_like_this max_query_terms default.
+const similarMaxQueryTerms = 25
+
 type ArticleReader struct {
 	db *pgxpool.Pool
 }
@@ -30,7 +40,7 @@ func (r *ArticleReader) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]docume
 	}
 
 	const q = `
-		SELECT id, title, subtitle, content, author, description, language, created_at
+		SELECT id, title, subtitle, content, author, description, url, language, created_at, metadata
 		FROM articles
 		WHERE id = ANY($1)
 	`
@@ -39,13 +49,55 @@ func (r *ArticleReader) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]docume
 	if err != nil {
 		return nil, fmt.Errorf("query articles by ids: %w", err)
 	}
+
+	return scanArticles(rows)
+}
+
+// Scan walks every article in id order, batchSize at a time, e.g. to rebuild a search index
+// from the database as the source of truth. It stops at the first error of fn.
+func (r *ArticleReader) Scan(ctx context.Context, batchSize int, fn func([]document.Article) error) error {
+	const q = `
+		SELECT id, title, subtitle, content, author, description, url, language, created_at, metadata
+		FROM articles
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	var after uuid.UUID
+	for {
+		rows, err := r.db.Query(ctx, q, after, batchSize)
+		if err != nil {
+			return fmt.Errorf("query articles after %s: %w", after, err)
+		}
+		articles, err := scanArticles(rows)
+		if err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+
+		if err := fn(articles); err != nil {
+			return err
+		}
+		if len(articles) < batchSize {
+			return nil
+		}
+		after = articles[len(articles)-1].ID
+	}
+}
+
+// scanArticles reads the article rows of GetByIDs and Scan
+func scanArticles(rows pgx.Rows) ([]document.Article, error) {
 	defer rows.Close()
 
 	var articles []document.Article
 	for rows.Next() {
 		var a document.Article
 		var id pgtype.UUID
-		var createdAt time.Time
+		var url *string
+		var metadataJSON []byte
 
 		if err := rows.Scan(
 			&id,
@@ -54,14 +106,23 @@ func (r *ArticleReader) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]docume
 			&a.Content,
 			&a.Author,
 			&a.Description,
+			&url,
 			&a.Language,
-			&createdAt,
+			&a.CreatedAt,
+			&metadataJSON,
 		); err != nil {
 			return nil, fmt.Errorf("scan article row: %w", err)
 		}
 
 		a.ID = uuid.UUID(id.Bytes)
-		a.CreatedAt = createdAt
+		if url != nil {
+			a.URL = *url
+		}
+		if len(metadataJSON) > 0 {
+			if err := json.Unmarshal(metadataJSON, &a.Metadata); err != nil {
+				return nil, fmt.Errorf("unmarshal article metadata: %w", err)
+			}
+		}
 		articles = append(articles, a)
 	}
 
@@ -71,3 +132,119 @@ func (r *ArticleReader) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]docume
 
 	return articles, nil
 }
+
+// SearchSimilar implements storage.SimilarSearcher.
+// Articles with a stored embedding are matched by cosine distance to it (same model only);
+// otherwise the most frequent lexemes of the article's search_vector are OR'ed into a
+// tsquery and ranked with ts_rank.
+func (r *ArticleReader) SearchSimilar(ctx context.Context, id uuid.UUID, baseOpts *dquery.BaseOptions) (*storage.SimilarResult, error) {
+	var exists, hasVector bool
+	const existsSQL = `
+		SELECT EXISTS (SELECT 1 FROM articles WHERE id = $1),
+		       EXISTS (SELECT 1 FROM article_embeddings WHERE article_id = $1)
+	`
+	if err := r.db.QueryRow(ctx, existsSQL, id).Scan(&exists, &hasVector); err != nil {
+		return nil, fmt.Errorf("check source article: %w", err)
+	}
+	if !exists {
+		return nil, storage.ErrArticleNotFound
+	}
+
+	strategy := storage.SimilarityLexical
+	sql := similarLexicalSQL
+	args := []any{id, baseOpts.Size, similarMaxQueryTerms}
+	if hasVector {
+		strategy = storage.SimilarityVector
+		sql = similarVectorSQL
+		args = []any{id, baseOpts.Size}
+	}
+
+	filterClause, filterArgs := BuildFilterClause(baseOpts.Filters, "a", len(args)+1)
+	if filterClause == "" {
+		filterClause = "TRUE"
+	}
+	args = append(args, filterArgs...)
+
+	slog.Info("Executing pg similar articles search",
+		"id", id,
+		"strategy", strategy,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	rows, err := r.db.Query(ctx, fmt.Sprintf(sql, filterClause), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute similar articles query: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []dto.ArticleSearchResult
+	var maxScore float64
+	for rows.Next() {
+		article, score, err := MapToArticle(rows)
+		if err != nil {
+			return nil, err
+		}
+		if len(hits) == 0 {
+			maxScore = float64(score)
+		}
+		hits = append(hits, dto.ArticleSearchResult{
+			Article: *article,
+			Score:   utils.RoundFloat64(float64(score), dquery.ScoreDecimalPlaces),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	normalizeBy := dquery.CalcSafeScore(&maxScore)
+	for i := range hits {
+		hits[i].ScoreNormalized = utils.RoundFloat64(hits[i].Score/normalizeBy, dquery.ScoreDecimalPlaces)
+	}
+
+	return &storage.SimilarResult{Hits: hits, Strategy: strategy}, nil
+}
+
+// similarVectorSQL orders by distance to the source embedding. The embedding is read
+// through a scalar subquery so the HNSW index can serve the ORDER BY.
+// $1 source id, $2 limit, $3.. filters.
+const similarVectorSQL = `
+	SELECT a.id, a.title, a.subtitle, a.content, a.author, a.description, a.url, a.language, a.created_at, a.metadata,
+	       (1 - (e.embedding <=> (SELECT embedding FROM article_embeddings WHERE article_id = $1 ORDER BY created_at DESC LIMIT 1)))::real AS score
+	FROM article_embeddings e
+	INNER JOIN articles a ON a.id = e.article_id
+	WHERE e.article_id <> $1
+	  AND e.model_name = (SELECT model_name FROM article_embeddings WHERE article_id = $1 ORDER BY created_at DESC LIMIT 1)
+	  AND %s
+	ORDER BY e.embedding <=> (SELECT embedding FROM article_embeddings WHERE article_id = $1 ORDER BY created_at DESC LIMIT 1)
+	LIMIT $2
+`
+
+// similarLexicalSQL builds an OR-tsquery from the most frequent lexemes of the source
+// article. Lexemes are already normalized, so the 'simple' config keeps them verbatim.
+// $1 source id, $2 limit, $3 max query terms, $4.. filters.
+const similarLexicalSQL = `
+	WITH src AS (
+		SELECT to_tsquery('simple', string_agg(quote_literal(t.lexeme), ' | ')) AS q
+		FROM (
+			SELECT u.lexeme
+			FROM articles s, unnest(s.search_vector) u
+			WHERE s.id = $1
+			ORDER BY array_length(u.positions, 1) DESC NULLS LAST, u.lexeme
+			LIMIT $3
+		) t
+	)
+	SELECT a.id, a.title, a.subtitle, a.content, a.author, a.description, a.url, a.language, a.created_at, a.metadata,
+	       ts_rank(a.search_vector, src.q)::real AS score
+	FROM articles a, src
+	WHERE a.id <> $1
+	  AND a.search_vector @@ src.q
+	  AND %s
+	ORDER BY score DESC, a.id DESC
+	LIMIT $2
+`
+
+// Compile-time interface assertions
+var (
+	_ storage.Reader          = (*ArticleReader)(nil)
+	_ storage.SimilarSearcher = (*ArticleReader)(nil)
+)
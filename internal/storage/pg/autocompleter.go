@@ -0,0 +1,94 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// autocompleteSQL matches titles whose words start with the typed words (prefix tsquery on the
+// title lexemes of search_vector, GIN idx_articles_search_vector) or that start with the typed
+// text (ILIKE on GIN idx_articles_title_trgm). Titles starting with the prefix rank first,
+// then by trigram word similarity between the prefix and the title.
+const autocompleteSQL = `
+	SELECT id, title,
+		((title ILIKE $2)::int + word_similarity($3, title))::float8 AS score
+	FROM articles
+	WHERE search_vector @@ %s
+	   OR title ILIKE $2
+	ORDER BY score DESC, id
+	LIMIT $4
+`
+
+type Autocompleter struct {
+	db *pgxpool.Pool
+}
+
+func NewAutocompleter(pool *ConnectionPool) *Autocompleter {
+	return &Autocompleter{db: pool.GetConn()}
+}
+
+// Autocomplete implements storage.Autocompleter
+func (a *Autocompleter) Autocomplete(ctx context.Context, prefix string, lang query.Language, size int) ([]query.Completion, error) {
+	prefix = query.NormalizePrefix(prefix)
+	tsQueryText := buildPrefixTsQuery(prefix)
+	if tsQueryText == "" || len([]rune(prefix)) < query.AutocompleteMinPrefixLength {
+		return []query.Completion{}, nil
+	}
+
+	sql := fmt.Sprintf(autocompleteSQL, TsQuery(ToTsQuery, lang, "$1"))
+	rows, err := a.db.Query(ctx, sql, tsQueryText, escapeLike(prefix)+"%", prefix, size*query.AutocompleteCandidateFactor)
+	if err != nil {
+		slog.Error("Failed to execute autocomplete", "error", err)
+		return nil, fmt.Errorf("failed to execute autocomplete: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []query.Completion
+	for rows.Next() {
+		var id pgtype.UUID
+		var c query.Completion
+		if err := rows.Scan(&id, &c.Title, &c.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan completion: %w", err)
+		}
+		c.ID = uuid.UUID(id.Bytes)
+		c.Score = utils.RoundFloat64(c.Score, query.ScoreDecimalPlaces)
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to execute autocomplete: %w", err)
+	}
+
+	return query.DistinctCompletions(candidates, size), nil
+}
+
+// buildPrefixTsQuery renders the prefix as to_tsquery input where every word is a prefix
+// match restricted to the title weight label. Non-alphanumeric characters separate words,
+// so the input can never contain tsquery operators.
+// Example: "Climate ch" → "Climate:*A & ch:*A"
+func buildPrefixTsQuery(prefix string) string {
+	words := strings.FieldsFunc(prefix, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = w + ":*A"
+	}
+	return strings.Join(words, " & ")
+}
+
+// escapeLike escapes LIKE pattern metacharacters
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Compile-time interface assertions
+var _ storage.Autocompleter = (*Autocompleter)(nil)
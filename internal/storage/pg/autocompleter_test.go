@@ -0,0 +1,47 @@
+package pg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestBuildPrefixTsQuery(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{prefix: "Climate ch", want: "Climate:*A & ch:*A"},
+		{prefix: "clim", want: "clim:*A"},
+		{prefix: "covid-19 vac", want: "covid:*A & 19:*A & vac:*A"},
+		{prefix: "a & !b | c:*", want: "a:*A & b:*A & c:*A"},
+		{prefix: "Ђоковић", want: "Ђоковић:*A"},
+		{prefix: "!!", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			if got := buildPrefixTsQuery(tt.prefix); got != tt.want {
+				t.Errorf("buildPrefixTsQuery(%q) = %q, want %q", tt.prefix, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	if got, want := escapeLike(`50% off_now\`), `50\% off\_now\\`; got != want {
+		t.Errorf("escapeLike() = %q, want %q", got, want)
+	}
+}
+
+func TestAutocompleteSkipsShortPrefix(t *testing.T) {
+	// no pool: a prefix below the minimum length must not reach the database
+	got, err := (&Autocompleter{}).Autocomplete(context.Background(), " c ", query.LanguageEnglish, 10)
+	if err != nil {
+		t.Fatalf("Autocomplete() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Autocomplete() = %v, want no completions", got)
+	}
+}
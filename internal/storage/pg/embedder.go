@@ -115,3 +115,49 @@ func (e *Embedder) SaveBulk(ctx context.Context, vecs []*embedding.Vec) error {
 
 	return nil
 }
+
+// Scan walks the embeddings of a model in article id order, batchSize at a time, e.g. to
+// rebuild a search index from the database. It stops at the first error of fn.
+func (e *Embedder) Scan(ctx context.Context, model string, batchSize int, fn func([]*embedding.Vec) error) error {
+	const cmd = `
+		SELECT article_id, embedding
+		FROM article_embeddings
+		WHERE model_name = $1 AND article_id > $2
+		ORDER BY article_id
+		LIMIT $3
+	`
+
+	var after uuid.UUID
+	for {
+		rows, err := e.db.Query(ctx, cmd, model, after, batchSize)
+		if err != nil {
+			return fmt.Errorf("query article embeddings after %s: %w", after, err)
+		}
+
+		var vecs []*embedding.Vec
+		for rows.Next() {
+			var id uuid.UUID
+			var vec pgvector.Vector
+			if err := rows.Scan(&id, &vec); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan article embedding: %w", err)
+			}
+			vecs = append(vecs, &embedding.Vec{ID: id, Model: model, Embedding: vec.Slice()})
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("iterate article embeddings: %w", err)
+		}
+		if len(vecs) == 0 {
+			return nil
+		}
+
+		if err := fn(vecs); err != nil {
+			return err
+		}
+		if len(vecs) < batchSize {
+			return nil
+		}
+		after = vecs[len(vecs)-1].ID
+	}
+}
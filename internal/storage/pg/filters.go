@@ -0,0 +1,93 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// filterColumns maps filter fields to SQL expressions over the articles table.
+// Source and category live in the metadata jsonb (see document.ArticleMetadata json tags).
+var filterColumns = map[query.FilterField]string{
+	query.FilterLanguage:   "%slanguage",
+	query.FilterAuthor:     "%sauthor",
+	query.FilterSourceID:   "%smetadata->>'sourceId'",
+	query.FilterSourceName: "%smetadata->>'sourceName'",
+	query.FilterCategory:   "%smetadata->>'category'",
+}
+
+// dateColumns maps date fields to timestamptz expressions over the articles table.
+var dateColumns = map[query.DateField]string{
+	query.DatePublishedAt: "(%smetadata->>'publishedAt')::timestamptz",
+	query.DateCreatedAt:   "%screated_at",
+}
+
+// BuildFilterClause compiles structured filters into SQL predicates joined with AND.
+// alias qualifies the articles columns (e.g. "a" → a.language); empty means unqualified.
+// paramStart is the first free positional parameter number; the returned args must be
+// appended to the query arguments in order.
+//
+// Filters are plain WHERE predicates, so they narrow the match set without touching ts_rank.
+//
+// Example:
+//
+//	filters{category: [science], published_at >= t}, alias "", paramStart 2
+//	→ "(metadata->>'publishedAt')::timestamptz >= $2 AND metadata->>'category' = ANY($3)"
+func BuildFilterClause(filters *query.Filters, alias string, paramStart int) (string, []any) {
+	if filters.IsEmpty() {
+		return "", nil
+	}
+
+	prefix := aliasPrefix(alias)
+
+	var predicates []string
+	var args []any
+	next := func(arg any) string {
+		args = append(args, arg)
+		return fmt.Sprintf("$%d", paramStart+len(args)-1)
+	}
+
+	addRange := func(column string, r *query.DateRange) {
+		if r.IsEmpty() {
+			return
+		}
+		col := fmt.Sprintf(column, prefix)
+		if r.Gte != nil {
+			predicates = append(predicates, fmt.Sprintf("%s >= %s", col, next(*r.Gte)))
+		}
+		if r.Lte != nil {
+			predicates = append(predicates, fmt.Sprintf("%s <= %s", col, next(*r.Lte)))
+		}
+	}
+	addRange(dateColumns[query.DatePublishedAt], filters.PublishedAt)
+	addRange(dateColumns[query.DateCreatedAt], filters.CreatedAt)
+
+	for _, field := range filters.TermFields() {
+		col := fmt.Sprintf(filterColumns[field], prefix)
+		predicates = append(predicates, fmt.Sprintf("%s = ANY(%s)", col, next(filters.Terms[field])))
+	}
+
+	if len(filters.IDs) > 0 {
+		predicates = append(predicates, fmt.Sprintf("%sid = ANY(%s)", prefix, next(filters.IDs)))
+	}
+
+	return strings.Join(predicates, " AND "), args
+}
+
+// AppendFilterClause ANDs the compiled filters onto an existing WHERE predicate and
+// returns the extended predicate with the extended argument list.
+func AppendFilterClause(where string, args []any, filters *query.Filters, alias string) (string, []any) {
+	clause, filterArgs := BuildFilterClause(filters, alias, len(args)+1)
+	if clause == "" {
+		return where, args
+	}
+	return fmt.Sprintf("%s AND %s", where, clause), append(args, filterArgs...)
+}
+
+func aliasPrefix(alias string) string {
+	if alias == "" {
+		return ""
+	}
+	return alias + "."
+}
@@ -0,0 +1,67 @@
+package pg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+)
+
+func TestBuildFilterClause(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		filters    *query.Filters
+		alias      string
+		paramStart int
+		wantClause string
+		wantArgs   int
+	}{
+		{name: "no filters", filters: nil, paramStart: 1, wantClause: "", wantArgs: 0},
+		{
+			name: "range and terms",
+			filters: &query.Filters{
+				PublishedAt: &query.DateRange{Gte: &from},
+				Terms:       map[query.FilterField][]string{query.FilterCategory: {"science"}},
+			},
+			paramStart: 2,
+			wantClause: "(metadata->>'publishedAt')::timestamptz >= $2 AND metadata->>'category' = ANY($3)",
+			wantArgs:   2,
+		},
+		{
+			name: "aliased columns",
+			filters: &query.Filters{
+				CreatedAt: &query.DateRange{Lte: &from},
+				Terms:     map[query.FilterField][]string{query.FilterLanguage: {"english"}},
+			},
+			alias:      "a",
+			paramStart: 7,
+			wantClause: "a.created_at <= $7 AND a.language = ANY($8)",
+			wantArgs:   2,
+		},
+		{
+			name: "article ids",
+			filters: &query.Filters{
+				Terms: map[query.FilterField][]string{query.FilterCategory: {"science"}},
+				IDs:   []uuid.UUID{uuid.New()},
+			},
+			paramStart: 2,
+			wantClause: "metadata->>'category' = ANY($2) AND id = ANY($3)",
+			wantArgs:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clause, args := BuildFilterClause(tt.filters, tt.alias, tt.paramStart)
+			if clause != tt.wantClause {
+				t.Errorf("clause = %q, want %q", clause, tt.wantClause)
+			}
+			if len(args) != tt.wantArgs {
+				t.Errorf("len(args) = %d, want %d", len(args), tt.wantArgs)
+			}
+		})
+	}
+}
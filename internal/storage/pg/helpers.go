@@ -1,10 +1,12 @@
 package pg
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
 	"github.com/jackc/pgx/v5"
 )
 
@@ -35,3 +37,58 @@ func MapToArticle(rows pgx.Rows) (*dto.Article, float32, error) {
 
 	return &article, distance, nil
 }
+
+// ScanSearchHit scans one article row followed by its raw rank and, when highlighting,
+// one headline column per highlighted field into headlines.
+func ScanSearchHit(rows pgx.Rows, headlines []string) (dto.Article, float64, error) {
+	var metadataJSON []byte
+	var rawScore float64
+	var article dto.Article
+
+	dest := []any{
+		&article.ID,
+		&article.Title,
+		&article.Subtitle,
+		&article.Content,
+		&article.Author,
+		&article.Description,
+		&article.URL,
+		&article.Language,
+		&article.CreatedAt,
+		&metadataJSON,
+		&rawScore,
+	}
+	for i := range headlines {
+		dest = append(dest, &headlines[i])
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return dto.Article{}, 0, fmt.Errorf("failed to scan article: %w", err)
+	}
+
+	if err := json.Unmarshal(metadataJSON, &article.Metadata); err != nil {
+		return dto.Article{}, 0, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+
+	return article, rawScore, nil
+}
+
+// RowQuerier runs single-row queries on the pool or inside a transaction
+type RowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// ScoreStats computes the statistics of the rank over the full (filtered) match set
+func ScoreStats(ctx context.Context, db RowQuerier, rank, where string, args []any) (ranking.Stats, error) {
+	var stats ranking.Stats
+	statsSQL := fmt.Sprintf(`
+		SELECT COALESCE(MAX(s.rank), 0.0), COALESCE(MIN(s.rank), 0.0),
+			COALESCE(AVG(s.rank), 0.0), COALESCE(STDDEV_POP(s.rank), 0.0), COUNT(*)
+		FROM (SELECT %s AS rank FROM articles WHERE %s) s
+	`, rank, where)
+
+	if err := db.QueryRow(ctx, statsSQL, args...).Scan(&stats.Max, &stats.Min, &stats.Mean, &stats.StdDev, &stats.Count); err != nil {
+		return ranking.Stats{}, fmt.Errorf("cannot fetch global max score: %w", err)
+	}
+	return stats, nil
+}
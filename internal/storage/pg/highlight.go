@@ -0,0 +1,67 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// headlineFragmentDelimiter separates ts_headline fragments so they can be split back into a list
+const headlineFragmentDelimiter = "␞"
+
+// approxCharsPerWord converts an Elasticsearch-style fragment size (characters) into ts_headline words
+const approxCharsPerWord = 6
+
+// BuildHeadlineOptions renders the ts_headline options string for a highlight request.
+//
+// Example (defaults):
+//
+//	StartSel="<em>", StopSel="</em>", MaxWords=16, MinWords=5, MaxFragments=5, FragmentDelimiter="␞"
+func BuildHeadlineOptions(h *query.Highlight) string {
+	maxWords := max(h.FragmentSize/approxCharsPerWord, 3)
+	minWords := max(maxWords/3, 1)
+
+	return fmt.Sprintf(`StartSel="%s", StopSel="%s", MaxWords=%d, MinWords=%d, MaxFragments=%d, FragmentDelimiter="%s"`,
+		h.PreTag, h.PostTag, maxWords, minWords, h.NumberOfFragments, headlineFragmentDelimiter)
+}
+
+// BuildHeadlineColumns returns one ts_headline select expression per highlighted field.
+// tsquery must be the unlabeled query expression used for matching so that phrase and
+// boolean queries highlight the lexemes that actually matched. optionsParam is the
+// positional parameter holding BuildHeadlineOptions.
+func BuildHeadlineColumns(h *query.Highlight, lang query.Language, tsquery string, alias string, optionsParam int) []string {
+	prefix := aliasPrefix(alias)
+	columns := make([]string, 0, len(h.Fields))
+	for _, field := range h.Fields {
+		columns = append(columns, fmt.Sprintf("ts_headline('%s'::regconfig, %s, %s, $%d)",
+			TextSearchConfig(lang), headlineDocument(lang, prefix+field), tsquery, optionsParam))
+	}
+	return columns
+}
+
+// ParseHeadlines maps ts_headline output to highlight fragments per field.
+// ts_headline falls back to the leading words of a field without matches; such
+// output carries no pre tag and is dropped, so only matching fields are returned.
+func ParseHeadlines(h *query.Highlight, headlines []string) map[string][]string {
+	result := make(map[string][]string)
+	for i, field := range h.Fields {
+		if i >= len(headlines) {
+			break
+		}
+		var fragments []string
+		for _, fragment := range strings.Split(headlines[i], headlineFragmentDelimiter) {
+			fragment = strings.TrimSpace(fragment)
+			if strings.Contains(fragment, h.PreTag) {
+				fragments = append(fragments, fragment)
+			}
+		}
+		if len(fragments) > 0 {
+			result[field] = fragments
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package pg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestBuildHeadlineOptions(t *testing.T) {
+	h, err := query.NewHighlight(nil, query.WithHighlightTags("<mark>", "</mark>"), query.WithHighlightNumberOfFragments(2))
+	if err != nil {
+		t.Fatalf("NewHighlight() error = %v", err)
+	}
+
+	got := BuildHeadlineOptions(h)
+	for _, want := range []string{`StartSel="<mark>"`, `StopSel="</mark>"`, "MaxWords=16", "MinWords=5", "MaxFragments=2"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("options %q missing %q", got, want)
+		}
+	}
+}
+
+func TestParseHeadlines(t *testing.T) {
+	h, err := query.NewHighlight([]string{"title", "content"})
+	if err != nil {
+		t.Fatalf("NewHighlight() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		headlines []string
+		want      map[string][]string
+	}{
+		{
+			name: "fragments split per field",
+			headlines: []string{
+				"<em>Climate</em> summit opens",
+				"rising <em>climate</em> costs" + headlineFragmentDelimiter + " the <em>climate</em> deal ",
+			},
+			want: map[string][]string{
+				"title":   {"<em>Climate</em> summit opens"},
+				"content": {"rising <em>climate</em> costs", "the <em>climate</em> deal"},
+			},
+		},
+		{
+			name:      "fields without matches are dropped",
+			headlines: []string{"Summit opens", "rising <em>climate</em> costs"},
+			want:      map[string][]string{"content": {"rising <em>climate</em> costs"}},
+		},
+		{
+			name:      "no matches",
+			headlines: []string{"Summit opens", "Leading words of content"},
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseHeadlines(h, tt.headlines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseHeadlines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildHeadlineColumns(t *testing.T) {
+	h, err := query.NewHighlight([]string{"title"})
+	if err != nil {
+		t.Fatalf("NewHighlight() error = %v", err)
+	}
+
+	got := BuildHeadlineColumns(h, query.LanguageEnglish, "phraseto_tsquery('english'::regconfig, $1)", "page", 4)
+	want := []string{"ts_headline('english'::regconfig, COALESCE(page.title, ''), phraseto_tsquery('english'::regconfig, $1), $4)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildHeadlineColumns() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildHeadlineColumnsSerbian(t *testing.T) {
+	h, err := query.NewHighlight([]string{"title"})
+	if err != nil {
+		t.Fatalf("NewHighlight() error = %v", err)
+	}
+
+	got := BuildHeadlineColumns(h, query.LanguageSerbian, "$1::tsquery", "", 2)
+	want := []string{"ts_headline('news_serbian'::regconfig, serbian_translit(COALESCE(title, '')), $1::tsquery, $2)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildHeadlineColumns() = %v, want %v", got, want)
+	}
+}
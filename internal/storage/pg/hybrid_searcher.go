@@ -8,6 +8,7 @@ import (
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
 	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
 	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
@@ -41,6 +42,7 @@ func (s *HybridSearcher) SearchHybrid(ctx context.Context, query *dquery.Hybrid,
 		"query", query.Query,
 		"language", lang,
 		"k", k,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
 		"size", size)
 
 	vec, err := s.embedder.EmbedQuery(ctx, query.Query)
@@ -55,21 +57,33 @@ func (s *HybridSearcher) SearchHybrid(ctx context.Context, query *dquery.Hybrid,
 		legDepth = size
 	}
 
+	// Filters restrict both legs so neither contributes candidates outside the filter.
+	// The same filter parameters ($7...) are shared by the lexical and vector CTEs.
+	filterClause, filterArgs := BuildFilterClause(baseOpts.Filters, "a", 7)
+	lexicalQuery := TsQuery(WebsearchToTsQuery, lang, "$1")
+	lexicalWhere := fmt.Sprintf("a.search_vector @@ %s", lexicalQuery)
+	vectorWhere := "e.model_name = $3"
+	if filterClause != "" {
+		lexicalWhere += " AND " + filterClause
+		vectorWhere += " AND " + filterClause
+	}
+
 	cmd := fmt.Sprintf(`
 		WITH lexical AS (
 			SELECT a.id AS article_id,
 				   ROW_NUMBER() OVER (
-					   ORDER BY ts_rank(a.search_vector, websearch_to_tsquery('%[1]s'::regconfig, $1)) DESC, a.id DESC
+					   ORDER BY ts_rank(a.search_vector, %[1]s) DESC, a.id DESC
 				   ) AS lex_rank
 			FROM articles a
-			WHERE a.search_vector @@ websearch_to_tsquery('%[1]s'::regconfig, $1)
+			WHERE %[2]s
 			LIMIT $5
 		),
 		vector AS (
 			SELECT e.article_id,
 				   ROW_NUMBER() OVER (ORDER BY e.embedding <=> $2) AS vec_rank
 			FROM article_embeddings e
-			WHERE e.model_name = $3
+			INNER JOIN articles a ON a.id = e.article_id
+			WHERE %[3]s
 			ORDER BY e.embedding <=> $2
 			LIMIT $5
 		),
@@ -82,23 +96,27 @@ func (s *HybridSearcher) SearchHybrid(ctx context.Context, query *dquery.Hybrid,
 		)
 		SELECT a.id, a.title, a.subtitle, a.content, a.author, a.description,
 			   a.url, a.language, a.created_at, a.metadata, f.rrf_score,
-			   COUNT(*) OVER () AS total_matches
+			   COUNT(*) OVER () AS total_matches,
+			   MAX(f.rrf_score) OVER () AS max_score,
+			   MIN(f.rrf_score) OVER () AS min_score,
+			   AVG(f.rrf_score) OVER () AS mean_score,
+			   STDDEV_POP(f.rrf_score) OVER () AS stddev_score
 		FROM fused f
 		INNER JOIN articles a ON a.id = f.article_id
 		ORDER BY f.rrf_score DESC, a.id DESC
 		LIMIT $6
-	`, lang)
+	`, lexicalQuery, lexicalWhere, vectorWhere)
 
-	rows, err := s.db.Query(
-		ctx,
-		cmd,
+	args := append([]any{
 		query.Query,
 		vecEncoded,
 		vec.Model,
 		k,
 		legDepth,
 		size,
-	)
+	}, filterArgs...)
+
+	rows, err := s.db.Query(ctx, cmd, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute hybrid search query: %w", err)
 	}
@@ -106,7 +124,7 @@ func (s *HybridSearcher) SearchHybrid(ctx context.Context, query *dquery.Hybrid,
 
 	var articles []dto.ArticleSearchResult
 	var rawScores []float64
-	var totalMatches int64
+	var stats ranking.Stats
 
 	for rows.Next() {
 		var metadataJSON []byte
@@ -125,7 +143,11 @@ func (s *HybridSearcher) SearchHybrid(ctx context.Context, query *dquery.Hybrid,
 			&article.CreatedAt,
 			&metadataJSON,
 			&rawScore,
-			&totalMatches,
+			&stats.Count,
+			&stats.Max,
+			&stats.Min,
+			&stats.Mean,
+			&stats.StdDev,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan article: %w", err)
 		}
@@ -149,10 +171,9 @@ func (s *HybridSearcher) SearchHybrid(ctx context.Context, query *dquery.Hybrid,
 		return &storage.SearchResult{}, nil
 	}
 
-	maxScore := rawScores[0]
-	for i := range articles {
-		articles[i].ScoreNormalized = utils.RoundFloat64(rawScores[i]/maxScore, dquery.ScoreDecimalPlaces)
-	}
+	// RRF scores are normalized over the fused candidates of both legs
+	maxScore, totalMatches := stats.Max, stats.Count
+	storage.NormalizeScores(baseOpts.Normalization, articles, rawScores, stats, 0)
 
 	slog.Info("PG hybrid search results fetched",
 		"total_page_matches", len(articles),
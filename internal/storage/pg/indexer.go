@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -120,3 +121,22 @@ func (s *Indexer) SaveBulk(ctx context.Context, articles []document.Article) err
 	}
 	return nil
 }
+
+// vocabularyViews are the materialized vocabularies derived from the articles table:
+// article_lexemes (migration 009) backs fuzzy term expansion, article_words (migration 010)
+// backs spelling suggestions.
+var vocabularyViews = []string{"article_lexemes", "article_words"}
+
+// Refresh rebuilds the vocabularies used by fuzzy matching and suggestions.
+// CONCURRENTLY keeps them readable meanwhile.
+func (s *Indexer) Refresh(ctx context.Context) error {
+	for _, view := range vocabularyViews {
+		if _, err := s.db.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY "+view); err != nil {
+			return fmt.Errorf("failed to refresh %s: %w", view, err)
+		}
+	}
+	return nil
+}
+
+// Compile-time interface assertions
+var _ storage.IndexRefresher = (*Indexer)(nil)
@@ -0,0 +1,76 @@
+package pg
+
+import (
+	"fmt"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// serbianConfig is the text search configuration created by migration 008.
+// Its input must be pre-processed with serbian_normalize (see TsQuery).
+const serbianConfig = "news_serbian"
+
+// TsQueryFunc is a PostgreSQL tsquery constructor
+type TsQueryFunc string
+
+const (
+	PlainToTsQuery     TsQueryFunc = "plainto_tsquery"
+	PhraseToTsQuery    TsQueryFunc = "phraseto_tsquery"
+	WebsearchToTsQuery TsQueryFunc = "websearch_to_tsquery"
+	ToTsQuery          TsQueryFunc = "to_tsquery"
+)
+
+// TextSearchConfig returns the text search configuration used to analyze documents and
+// queries of the given language. It mirrors article_ts_config on the indexing side.
+func TextSearchConfig(lang query.Language) string {
+	switch lang {
+	case query.LanguageSerbian:
+		return serbianConfig
+	default:
+		return string(query.LanguageEnglish)
+	}
+}
+
+// TsQuery renders a tsquery constructor call for the given language over a query expression
+// (usually a positional parameter). Serbian input is transliterated, folded and stripped of
+// stopwords the same way the indexed text is; to_tsquery input is only folded so that its
+// operators survive.
+//
+// Examples:
+//
+//	TsQuery(PlainToTsQuery, english, "$1") → "plainto_tsquery('english'::regconfig, $1)"
+//	TsQuery(PlainToTsQuery, serbian, "$1") → "plainto_tsquery('news_serbian'::regconfig, serbian_normalize($1))"
+//	TsQuery(ToTsQuery, serbian, "$1")      → "to_tsquery('news_serbian'::regconfig, serbian_fold($1))"
+func TsQuery(fn TsQueryFunc, lang query.Language, arg string) string {
+	cfg := TextSearchConfig(lang)
+	if cfg == serbianConfig {
+		if fn == ToTsQuery {
+			arg = fmt.Sprintf("serbian_fold(%s)", arg)
+		} else {
+			arg = fmt.Sprintf("serbian_normalize(%s)", arg)
+		}
+	}
+	return fmt.Sprintf("%s('%s'::regconfig, %s)", fn, cfg, arg)
+}
+
+// ToTsVector renders a to_tsvector call that analyzes text of the given language the
+// same way the search_vector trigger does.
+//
+//	ToTsVector(serbian, "$1") → "to_tsvector('news_serbian'::regconfig, serbian_normalize($1))"
+func ToTsVector(lang query.Language, arg string) string {
+	cfg := TextSearchConfig(lang)
+	if cfg == serbianConfig {
+		arg = fmt.Sprintf("serbian_normalize(%s)", arg)
+	}
+	return fmt.Sprintf("to_tsvector('%s'::regconfig, %s)", cfg, arg)
+}
+
+// headlineDocument renders the document expression passed to ts_headline. Serbian text is
+// transliterated to Latin so Cyrillic articles match the (Latin) query lexemes.
+func headlineDocument(lang query.Language, column string) string {
+	doc := fmt.Sprintf("COALESCE(%s, '')", column)
+	if TextSearchConfig(lang) == serbianConfig {
+		return fmt.Sprintf("serbian_translit(%s)", doc)
+	}
+	return doc
+}
@@ -0,0 +1,83 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestTextSearchConfig(t *testing.T) {
+	tests := []struct {
+		lang query.Language
+		want string
+	}{
+		{lang: query.LanguageEnglish, want: "english"},
+		{lang: query.LanguageSerbian, want: "news_serbian"},
+		{lang: "", want: "english"},
+		{lang: "klingon", want: "english"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.lang), func(t *testing.T) {
+			if got := TextSearchConfig(tt.lang); got != tt.want {
+				t.Errorf("TextSearchConfig(%q) = %q, want %q", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTsQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   TsQueryFunc
+		lang query.Language
+		arg  string
+		want string
+	}{
+		{
+			name: "english plain",
+			fn:   PlainToTsQuery,
+			lang: query.LanguageEnglish,
+			arg:  "$1",
+			want: "plainto_tsquery('english'::regconfig, $1)",
+		},
+		{
+			name: "english websearch",
+			fn:   WebsearchToTsQuery,
+			lang: query.LanguageEnglish,
+			arg:  "$2",
+			want: "websearch_to_tsquery('english'::regconfig, $2)",
+		},
+		{
+			name: "serbian phrase normalizes input",
+			fn:   PhraseToTsQuery,
+			lang: query.LanguageSerbian,
+			arg:  "$1",
+			want: "phraseto_tsquery('news_serbian'::regconfig, serbian_normalize($1))",
+		},
+		{
+			name: "serbian to_tsquery only folds input",
+			fn:   ToTsQuery,
+			lang: query.LanguageSerbian,
+			arg:  "$1",
+			want: "to_tsquery('news_serbian'::regconfig, serbian_fold($1))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TsQuery(tt.fn, tt.lang, tt.arg); got != tt.want {
+				t.Errorf("TsQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToTsVector(t *testing.T) {
+	if got, want := ToTsVector(query.LanguageEnglish, "$1"), "to_tsvector('english'::regconfig, $1)"; got != want {
+		t.Errorf("ToTsVector(english) = %q, want %q", got, want)
+	}
+	if got, want := ToTsVector(query.LanguageSerbian, "$1"), "to_tsvector('news_serbian'::regconfig, serbian_normalize($1))"; got != want {
+		t.Errorf("ToTsVector(serbian) = %q, want %q", got, want)
+	}
+}
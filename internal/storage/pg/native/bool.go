@@ -0,0 +1,212 @@
+package native
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// zeroRank is the rank of bool queries without scoring clauses (only filter / must_not)
+const zeroRank = "0::real"
+
+// boolClause is a compiled bool clause: a match predicate and its rank expression
+type boolClause struct {
+	where string
+	rank  string
+}
+
+// boolCompiler compiles a bool query tree into a single ftsQuery. Leaf clauses are compiled
+// by the same builders as the standalone match, multi_match and phrase queries; their
+// arguments are appended to one positional argument list.
+type boolCompiler struct {
+	r *Searcher
+	// args are the positional arguments of all compiled leaves ($1..$len(args))
+	args []any
+	// tsqueries are the unlabeled tsqueries of scoring leaves, OR-ed for highlighting
+	tsqueries  []string
+	expansions []dquery.TermExpansion
+}
+
+// SearchBool implements storage.FtsSearcher interface
+// Translates the compound query to one WHERE expression:
+//
+//	must / filter → (clause) AND ...
+//	must_not      → NOT (clause)
+//	should        → (s1 OR s2) for minimum_should_match 1, otherwise ((s1)::int + (s2)::int) >= N
+//
+// The rank is the sum of the must ranks and the ranks of the matching should clauses;
+// filter and must_not clauses do not score (Elasticsearch filter context).
+func (r *Searcher) SearchBool(ctx context.Context, query *dquery.Bool, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	lang := query.GetLanguage()
+
+	slog.Info("Executing pool bool search",
+		"must", len(query.Must),
+		"should", len(query.Should),
+		"must_not", len(query.MustNot),
+		"filter", len(query.Filter),
+		"minimum_should_match", query.GetMinimumShouldMatch(),
+		"language", lang,
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	q, expansions, err := r.boolQuery(ctx, query)
+	if err != nil {
+		slog.Error("Failed to compile bool query", "error", err)
+		return nil, err
+	}
+
+	result, err := r.execute(ctx, dquery.BoolType, q, baseOpts)
+	if err != nil {
+		return nil, err
+	}
+	result.Expansions = expansions
+	return result, nil
+}
+
+// boolQuery compiles a bool query tree to one ftsQuery and reports the fuzzy term expansions
+// of its leaves
+func (r *Searcher) boolQuery(ctx context.Context, query *dquery.Bool) (ftsQuery, []dquery.TermExpansion, error) {
+	compiler := &boolCompiler{r: r}
+	clause, err := compiler.compileBool(ctx, query, true)
+	if err != nil {
+		return ftsQuery{}, nil, err
+	}
+
+	tsquery := "''::tsquery"
+	if len(compiler.tsqueries) > 0 {
+		tsquery = "(" + strings.Join(compiler.tsqueries, " || ") + ")"
+	}
+
+	return ftsQuery{
+		where:   clause.where,
+		rank:    clause.rank,
+		tsquery: tsquery,
+		lang:    query.GetLanguage(),
+		args:    compiler.args,
+	}, compiler.expansions, nil
+}
+
+// compileBool compiles a bool query. scoring is false inside filter and must_not
+// clauses, where no rank is needed and matched terms are not highlighted.
+func (c *boolCompiler) compileBool(ctx context.Context, q *dquery.Bool, scoring bool) (boolClause, error) {
+	var conds, ranks []string
+
+	for _, clause := range q.Must {
+		compiled, err := c.compileClause(ctx, clause, scoring)
+		if err != nil {
+			return boolClause{}, err
+		}
+		conds = append(conds, "("+compiled.where+")")
+		ranks = append(ranks, compiled.rank)
+	}
+
+	for _, clause := range q.Filter {
+		compiled, err := c.compileClause(ctx, clause, false)
+		if err != nil {
+			return boolClause{}, err
+		}
+		conds = append(conds, "("+compiled.where+")")
+	}
+
+	for _, clause := range q.MustNot {
+		compiled, err := c.compileClause(ctx, clause, false)
+		if err != nil {
+			return boolClause{}, err
+		}
+		conds = append(conds, "NOT ("+compiled.where+")")
+	}
+
+	if len(q.Should) > 0 {
+		shouldConds := make([]string, 0, len(q.Should))
+		for _, clause := range q.Should {
+			compiled, err := c.compileClause(ctx, clause, scoring)
+			if err != nil {
+				return boolClause{}, err
+			}
+			shouldConds = append(shouldConds, "("+compiled.where+")")
+			ranks = append(ranks, fmt.Sprintf("CASE WHEN %s THEN %s ELSE %s END", compiled.where, compiled.rank, zeroRank))
+		}
+
+		switch msm := q.GetMinimumShouldMatch(); {
+		case msm == 1:
+			conds = append(conds, "("+strings.Join(shouldConds, " OR ")+")")
+		case msm > 1:
+			conds = append(conds, fmt.Sprintf("(%s) >= %d", strings.Join(shouldConds, "::int + ")+"::int", msm))
+		}
+	}
+
+	where := "TRUE"
+	if len(conds) > 0 {
+		where = strings.Join(conds, " AND ")
+	}
+	rank := zeroRank
+	if len(ranks) > 0 {
+		rank = "(" + strings.Join(ranks, " + ") + ")"
+	}
+
+	return boolClause{where: where, rank: rank}, nil
+}
+
+// compileClause compiles one clause, binding its arguments after the ones compiled so far
+func (c *boolCompiler) compileClause(ctx context.Context, clause dquery.Clause, scoring bool) (boolClause, error) {
+	if clause.Bool != nil {
+		return c.compileBool(ctx, clause.Bool, scoring)
+	}
+
+	paramNum := len(c.args) + 1
+	var q ftsQuery
+	var err error
+	switch {
+	case clause.Match != nil:
+		fieldBoosts := []FieldWeight{{Field: clause.Match.Field, Weight: 1.0}}
+		q, err = c.compileMatch(ctx, clause.Match.Query, fieldBoosts, clause.Match.GetLanguage(), clause.Match.GetOperator(), clause.Match.GetFuzziness(), paramNum)
+	case clause.MultiMatch != nil:
+		fieldBoosts := make([]FieldWeight, 0, len(clause.MultiMatch.Fields))
+		for _, f := range clause.MultiMatch.Fields {
+			fieldBoosts = append(fieldBoosts, FieldWeight{Field: f.Name, Weight: f.Weight})
+		}
+		q, err = c.compileMatch(ctx, clause.MultiMatch.Query, fieldBoosts, clause.MultiMatch.GetLanguage(), clause.MultiMatch.GetOperator(), clause.MultiMatch.GetFuzziness(), paramNum)
+	case clause.Phrase != nil:
+		q, err = c.r.phraseQuery(ctx, clause.Phrase, paramNum)
+	default:
+		return boolClause{}, fmt.Errorf("empty bool clause")
+	}
+	if err != nil {
+		return boolClause{}, err
+	}
+
+	c.args = append(c.args, q.args...)
+	if scoring {
+		c.tsqueries = append(c.tsqueries, q.tsquery)
+	}
+	return boolClause{where: q.where, rank: q.rank}, nil
+}
+
+// compileMatch compiles a match / multi_match leaf, expanding fuzzy terms when requested
+func (c *boolCompiler) compileMatch(
+	ctx context.Context,
+	text string,
+	fieldBoosts []FieldWeight,
+	lang dquery.Language,
+	op operator.Operator,
+	fuzziness dquery.Fuzziness,
+	paramNum int,
+) (ftsQuery, error) {
+	if fuzziness.IsEnabled() {
+		q, expansions, ok, err := c.r.fuzzyQuery(ctx, text, fieldBoosts, lang, op, fuzziness, paramNum)
+		if err != nil {
+			return ftsQuery{}, err
+		}
+		if ok {
+			c.expansions = append(c.expansions, expansions...)
+			return q, nil
+		}
+	}
+	return matchQuery(text, fieldBoosts, lang, op, paramNum), nil
+}
@@ -0,0 +1,113 @@
+package native
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// Leaves without fuzziness or slop compile without a database round trip
+func TestBoolCompiler_CompileBool(t *testing.T) {
+	title := dquery.Clause{Match: dquery.NewMatch("title", "climate", dquery.WithMatchOperator(operator.And))}
+	content := dquery.Clause{Match: dquery.NewMatch("content", "emissions")}
+	author := dquery.Clause{Match: dquery.NewMatch("author", "smith")}
+	phrase, err := dquery.NewPhrase("carbon tax", []string{"title"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := &dquery.Bool{
+		Must:    []dquery.Clause{title},
+		Should:  []dquery.Clause{content, {Phrase: phrase}},
+		MustNot: []dquery.Clause{author},
+		Filter:  []dquery.Clause{{Bool: &dquery.Bool{Should: []dquery.Clause{content}}}},
+	}
+
+	c := &boolCompiler{}
+	got, err := c.compileBool(context.Background(), q, true)
+	if err != nil {
+		t.Fatalf("compileBool() error = %v", err)
+	}
+
+	// Arguments are bound in compile order: must, filter, must_not, should
+	wantArgs := []any{"climate", "emissions", "smith", "emissions", "carbon tax"}
+	if !reflect.DeepEqual(c.args, wantArgs) {
+		t.Errorf("args = %v, want %v", c.args, wantArgs)
+	}
+
+	for _, want := range []string{
+		"(search_vector @@ (plainto_tsquery('english'::regconfig, $1)::text || ':A')::tsquery)",
+		"(((search_vector @@ (plainto_tsquery('english'::regconfig, $2)::text || ':C')::tsquery)))",
+		"NOT (search_vector @@ (plainto_tsquery('english'::regconfig, $3)::text || ':D')::tsquery)",
+	} {
+		if !strings.Contains(got.where, want) {
+			t.Errorf("where = %s\nmissing %s", got.where, want)
+		}
+	}
+
+	// must + matched should clauses score; filter ($2) and must_not ($3) do not
+	if n := strings.Count(got.rank, "CASE WHEN"); n != 2 {
+		t.Errorf("rank has %d should terms, want 2: %s", n, got.rank)
+	}
+	if strings.Contains(got.rank, "$2") || strings.Contains(got.rank, "$3") {
+		t.Errorf("rank references non-scoring clauses: %s", got.rank)
+	}
+	if len(c.tsqueries) != 3 {
+		t.Errorf("tsqueries = %v, want the must and should leaves", c.tsqueries)
+	}
+}
+
+func TestBoolCompiler_MinimumShouldMatch(t *testing.T) {
+	a := dquery.Clause{Match: dquery.NewMatch("title", "climate")}
+	b := dquery.Clause{Match: dquery.NewMatch("content", "emissions")}
+
+	tests := []struct {
+		name      string
+		q         *dquery.Bool
+		wantWhere string
+	}{
+		{
+			name:      "only should defaults to one",
+			q:         &dquery.Bool{Should: []dquery.Clause{a, b}},
+			wantWhere: ") OR (",
+		},
+		{
+			name:      "explicit two",
+			q:         &dquery.Bool{Should: []dquery.Clause{a, b}, MinimumShouldMatch: 2},
+			wantWhere: "::int) >= 2",
+		},
+		{
+			name:      "only must_not",
+			q:         &dquery.Bool{MustNot: []dquery.Clause{a}},
+			wantWhere: "NOT (",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&boolCompiler{}).compileBool(context.Background(), tt.q, true)
+			if err != nil {
+				t.Fatalf("compileBool() error = %v", err)
+			}
+			if !strings.Contains(got.where, tt.wantWhere) {
+				t.Errorf("where = %s, want containing %q", got.where, tt.wantWhere)
+			}
+		})
+	}
+
+	// should clauses only boost when must is present
+	got, err := (&boolCompiler{}).compileBool(context.Background(), &dquery.Bool{Must: []dquery.Clause{a}, Should: []dquery.Clause{b}}, true)
+	if err != nil {
+		t.Fatalf("compileBool() error = %v", err)
+	}
+	if strings.Contains(got.where, "$2") {
+		t.Errorf("where = %s, should clause must not filter", got.where)
+	}
+	if got, _ := (&boolCompiler{}).compileBool(context.Background(), &dquery.Bool{MustNot: []dquery.Clause{a}}, true); got.rank != zeroRank {
+		t.Errorf("rank = %s, want %s", got.rank, zeroRank)
+	}
+}
@@ -2,89 +2,103 @@ package native
 
 import (
 	"fmt"
-	"log/slog"
 	"strings"
-	"unicode"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/token"
 )
 
+// BooleanParser compiles boolean expressions to to_tsquery input.
+// Expressions are parsed by token.BooleanParser, the parser the Elasticsearch searcher
+// compiles from as well.
 type BooleanParser struct {
-	tokenizer *token.BoolTokenizer
+	parser *token.BooleanParser
 }
 
 func NewBooleanParser() *BooleanParser {
 	return &BooleanParser{
-		tokenizer: token.NewBoolTokenizer(),
+		parser: token.NewBooleanParser(),
 	}
 }
 
 func (p *BooleanParser) Parse(expression string) (string, error) {
-	tokens := p.tokenizer.Tokenize(expression)
-	return p.convertToTsquery(tokens)
-}
-
-func (p *BooleanParser) convertToTsquery(tokens []token.Token) (string, error) {
-	if err := p.tokenizer.Validate(tokens); err != nil {
+	root, err := p.parser.Parse(expression)
+	if err != nil {
 		return "", err
 	}
+	return booleanTsQuery(root), nil
+}
 
-	var parts []string
-	prevType := token.EOF
-
-	for _, tok := range tokens {
-		if tok.Type == token.EOF {
-			break
+// booleanTsQuery renders the AST as to_tsquery input. Parentheses are only added where
+// tsquery precedence (! over <-> over & over |) differs from the tree.
+// Examples:
+//
+//	AND(OR(renewable, sustainable), energy) → "( renewable | sustainable ) & energy"
+//	AND(climate, NOT(politics))             → "climate & ! politics"
+//	"climate change"                        → "climate <-> change"
+func booleanTsQuery(node token.Node) string {
+	switch n := node.(type) {
+	case *token.Term:
+		return n.Value
+	case *token.Phrase:
+		return strings.Join(strings.Fields(n.Value), " <-> ")
+	case *token.And:
+		parts := make([]string, 0, len(n.Operands))
+		for _, operand := range n.Operands {
+			part := booleanTsQuery(operand)
+			if _, ok := operand.(*token.Or); ok {
+				part = "( " + part + " )"
+			}
+			parts = append(parts, part)
 		}
-
-		if needsImplicitAnd(prevType, tok.Type) {
-			parts = append(parts, "&")
+		return strings.Join(parts, " & ")
+	case *token.Or:
+		parts := make([]string, 0, len(n.Operands))
+		for _, operand := range n.Operands {
+			parts = append(parts, booleanTsQuery(operand))
 		}
-
-		switch tok.Type {
-		case token.WORD:
-			words := strings.Fields(sanitizeTerm(tok.Value))
-			if len(words) > 1 {
-				parts = append(parts, strings.Join(words, " <-> "))
-			} else if len(words) == 1 {
-				parts = append(parts, words[0])
-			}
-		case token.AND:
-			parts = append(parts, "&")
-		case token.OR:
-			parts = append(parts, "|")
-		case token.NOT:
-			parts = append(parts, "!")
-		case token.LPAREN:
-			parts = append(parts, "(")
-		case token.RPAREN:
-			parts = append(parts, ")")
-		default:
-			slog.Error("unknown token type", "type", tok.Type, "value", tok.Value)
+		return strings.Join(parts, " | ")
+	case *token.Not:
+		operand := booleanTsQuery(n.Operand)
+		switch n.Operand.(type) {
+		case *token.And, *token.Or, *token.Phrase:
+			operand = "( " + operand + " )"
 		}
-
-		prevType = tok.Type
-	}
-
-	result := strings.Join(parts, " ")
-	if result == "" {
-		return "", fmt.Errorf("empty boolean expression")
+		return "! " + operand
+	case *token.Near:
+		return nearTsQuery(n)
+	default:
+		return ""
 	}
-	return result, nil
 }
 
-func needsImplicitAnd(prev, curr token.Type) bool {
-	prevIsValue := prev == token.WORD || prev == token.RPAREN
-	currIsValue := curr == token.WORD || curr == token.LPAREN || curr == token.NOT
-	return prevIsValue && currIsValue
-}
+// nearTsQuery expands NEAR/N to the distance operators <1> (<->) .. <N+1> in both
+// orders; a phrase operand is matched as a whole, its distance counted from its last word.
+// The GIN index narrows candidates to rows containing all operand lexemes, so only those
+// rows evaluate the 2(N+1) alternatives (token.MaxNearDistance caps N).
+// Example: climate NEAR/1 summit →
+//
+//	"( climate <-> summit | summit <-> climate | climate <2> summit | summit <2> climate )"
+func nearTsQuery(n *token.Near) string {
+	left, right := nearOperand(n.Left), nearOperand(n.Right)
 
-func sanitizeTerm(word string) string {
-	var b strings.Builder
-	for _, r := range word {
-		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == ' ' {
-			b.WriteRune(r)
+	alternatives := make([]string, 0, 2*(n.Distance+1))
+	for distance := 1; distance <= n.Distance+1; distance++ {
+		op := "<->"
+		if distance > 1 {
+			op = fmt.Sprintf("<%d>", distance)
 		}
+		alternatives = append(alternatives,
+			left+" "+op+" "+right,
+			right+" "+op+" "+left,
+		)
+	}
+	return "( " + strings.Join(alternatives, " | ") + " )"
+}
+
+func nearOperand(node token.Node) string {
+	operand := booleanTsQuery(node)
+	if _, ok := node.(*token.Phrase); ok {
+		return "( " + operand + " )"
 	}
-	return b.String()
+	return operand
 }
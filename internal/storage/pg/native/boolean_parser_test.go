@@ -1,7 +1,12 @@
 package native
 
 import (
+	"math/rand"
+	"strings"
 	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token/tokentest"
 )
 
 func TestBooleanParser_Parse(t *testing.T) {
@@ -98,3 +103,132 @@ func TestBooleanParser_Parse(t *testing.T) {
 		})
 	}
 }
+
+// The compiled tsquery must match exactly the documents the parsed expression matches:
+// random expressions are evaluated against random term matches by the reference
+// evaluator of the AST and by a tsquery evaluator (es: TestBuildBooleanQuery_AgreesWithAST)
+func TestBooleanTsQuery_AgreesWithAST(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	parser := token.NewBooleanParser()
+
+	for i := 0; i < 500; i++ {
+		expression := tokentest.RandomExpression(r, 4)
+		node, err := parser.Parse(expression)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", expression, err)
+		}
+		tsquery := booleanTsQuery(node)
+
+		for j := 0; j < 16; j++ {
+			matches := tokentest.RandomMatch(r)
+			want := tokentest.Eval(node, matches)
+			if got := evalTsQuery(t, tsquery, matches); got != want {
+				t.Fatalf("%q compiled to %q: matches %v = %v, want %v (AST %s)",
+					expression, tsquery, matches, got, want, tokentest.Format(node))
+			}
+		}
+	}
+}
+
+// evalTsQuery evaluates space-separated to_tsquery input with tsquery precedence
+// (! over <-> over & over |); a <-> chain is looked up as one phrase
+func evalTsQuery(t *testing.T, tsquery string, matches map[string]bool) bool {
+	t.Helper()
+	e := &tsQueryEvaluator{tokens: strings.Fields(tsquery), matches: matches}
+	result := e.or()
+	if e.pos != len(e.tokens) {
+		t.Fatalf("tsquery %q: unexpected %q", tsquery, e.tokens[e.pos])
+	}
+	return result
+}
+
+type tsQueryEvaluator struct {
+	tokens  []string
+	pos     int
+	matches map[string]bool
+}
+
+func (e *tsQueryEvaluator) accept(tok string) bool {
+	if e.pos < len(e.tokens) && e.tokens[e.pos] == tok {
+		e.pos++
+		return true
+	}
+	return false
+}
+
+func (e *tsQueryEvaluator) or() bool {
+	result := e.and()
+	for e.accept("|") {
+		result = e.and() || result
+	}
+	return result
+}
+
+func (e *tsQueryEvaluator) and() bool {
+	result := e.not()
+	for e.accept("&") {
+		result = e.not() && result
+	}
+	return result
+}
+
+func (e *tsQueryEvaluator) not() bool {
+	if e.accept("!") {
+		return !e.not()
+	}
+	if e.accept("(") {
+		result := e.or()
+		e.accept(")")
+		return result
+	}
+	words := []string{e.tokens[e.pos]}
+	e.pos++
+	for e.accept("<->") {
+		words = append(words, e.tokens[e.pos])
+		e.pos++
+	}
+	return e.matches[strings.Join(words, " ")]
+}
+
+func TestBooleanParser_Near(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "adjacent in either order",
+			input:    "climate NEAR/0 summit",
+			expected: "( climate <-> summit | summit <-> climate )",
+		},
+		{
+			name:     "distance",
+			input:    `"climate" NEAR/2 "summit"`,
+			expected: "( climate <-> summit | summit <-> climate | climate <2> summit | summit <2> climate | climate <3> summit | summit <3> climate )",
+		},
+		{
+			name:     "phrase operand",
+			input:    `"carbon tax" NEAR/1 reform`,
+			expected: "( ( carbon <-> tax ) <-> reform | reform <-> ( carbon <-> tax ) | ( carbon <-> tax ) <2> reform | reform <2> ( carbon <-> tax ) )",
+		},
+		{
+			name:     "inside boolean expression",
+			input:    "energy AND NOT coal NEAR/0 plant",
+			expected: "energy & ! ( coal <-> plant | plant <-> coal )",
+		},
+	}
+
+	p := NewBooleanParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := p.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
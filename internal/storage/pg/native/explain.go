@@ -0,0 +1,204 @@
+package native
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+)
+
+// defaultRankWeights are PostgreSQL's default ts_rank weights {D, C, B, A}
+var defaultRankWeights = [4]float64{0.1, 0.2, 0.4, 1.0}
+
+// rankNormalization is the ts_rank normalization every rank expression uses (the default)
+const rankNormalization = 0
+
+// weightLabels lists the search_vector weight labels with the fields they index
+var weightLabels = []struct {
+	label  string
+	fields []string
+}{
+	{"A", []string{"title"}},
+	{"B", []string{"description"}},
+	{"C", []string{"content"}},
+	{"D", []string{"subtitle", "author"}},
+}
+
+// normalizationFlags describes the ts_rank normalization bits
+var normalizationFlags = []struct {
+	bit         int
+	description string
+}{
+	{1, "divided by 1 + log(document length)"},
+	{2, "divided by the document length"},
+	{4, "divided by the mean harmonic distance between extents"},
+	{8, "divided by the number of unique words"},
+	{16, "divided by 1 + log(number of unique words)"},
+	{32, "divided by itself + 1"},
+}
+
+// tsqueryLexeme matches a quoted lexeme of a tsquery text with its optional prefix and label
+// suffix, e.g. 'climat', 'energ':* or 'chang':AB
+var tsqueryLexeme = regexp.MustCompile(`'((?:[^']|'')*)'(?::(\*?)[A-D]*)?`)
+
+// rankWeights returns the ts_rank weights of a rank expression built by buildRankExpression
+func rankWeights(fieldBoosts []FieldWeight) *[4]float64 {
+	weights := defaultRankWeights
+	if len(fieldBoosts) > 0 {
+		weights = labelWeights(fieldBoosts)
+	}
+	return &weights
+}
+
+// labelRank is the rank of the query over the lexemes of one weight label and the query
+// lexemes found under the label
+type labelRank struct {
+	rank    float64
+	lexemes []string
+}
+
+// explainHits attaches a score explanation to every hit: the rank of the query computed over
+// the lexemes of each weight label alone, with the lexemes of the query found under the label.
+// Labels are ranked with the query's own rank expression (same weights and normalization), so
+// the label ranks show where the score comes from; ts_rank does not add up across labels.
+func (r *Searcher) explainHits(ctx context.Context, q ftsQuery, hits []dto.ArticleSearchResult, rawScores []float64, rescoring *dquery.Rescoring) error {
+	if len(hits) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+
+	// The WHERE clause references every query argument, so the statements bind all of them
+	args := append([]any{}, q.args...)
+	idParam := len(args) + 1
+	var tsqueryText string
+	tsquerySQL := fmt.Sprintf(`SELECT (%s)::text FROM articles WHERE id = ANY($%d) AND %s LIMIT 1`, q.tsquery, idParam, q.where)
+	if err := r.db.QueryRow(ctx, tsquerySQL, append(args, ids)...).Scan(&tsqueryText); err != nil {
+		return fmt.Errorf("failed to render explained tsquery: %w", err)
+	}
+	exact, prefixes := queryLexemes(tsqueryText)
+
+	labelVector := `ts_filter(search_vector, ARRAY[l.label])`
+	explainSQL := fmt.Sprintf(`
+		SELECT id, upper(l.label::text), %s,
+			ARRAY(
+				SELECT u.lexeme FROM unnest(%s) u
+				WHERE u.lexeme = ANY($%d) OR EXISTS (SELECT 1 FROM unnest($%d::text[]) p WHERE starts_with(u.lexeme, p))
+			)
+		FROM articles CROSS JOIN unnest('{a,b,c,d}'::"char"[]) AS l(label)
+		WHERE id = ANY($%d) AND %s
+	`, strings.ReplaceAll(q.rank, "search_vector", labelVector), labelVector, idParam+1, idParam+2, idParam, q.where)
+
+	rows, err := r.db.Query(ctx, explainSQL, append(args, ids, exact, prefixes)...)
+	if err != nil {
+		return fmt.Errorf("failed to explain hits: %w", err)
+	}
+	defer rows.Close()
+
+	ranks := make(map[uuid.UUID]map[string]labelRank, len(hits))
+	for rows.Next() {
+		var id uuid.UUID
+		var label string
+		var lr labelRank
+		if err := rows.Scan(&id, &label, &lr.rank, &lr.lexemes); err != nil {
+			return fmt.Errorf("failed to scan label rank: %w", err)
+		}
+		if ranks[id] == nil {
+			ranks[id] = make(map[string]labelRank, len(weightLabels))
+		}
+		ranks[id][label] = lr
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating label ranks: %w", err)
+	}
+
+	for i := range hits {
+		storage.ExplainHit(rescoring, &hits[i], rawScores[i], textExplanation(q.weights, ranks[hits[i].ID]))
+	}
+
+	slog.Debug("Explained PG hits", "hits", len(hits), "lexemes", exact, "prefixes", prefixes)
+	return nil
+}
+
+// textExplanation explains a ts_rank score by weight label; labels without query lexemes
+// and without rank are left out. The value is set by storage.ExplainHit.
+func textExplanation(weights *[4]float64, ranks map[string]labelRank) dquery.Explanation {
+	e := dquery.Explanation{Description: "sum of the clause ranks, ranked per weight label:"}
+	if weights != nil {
+		e.Description = fmt.Sprintf("ts_rank with weights {D, C, B, A} = %s, ranked per weight label:", formatWeights(*weights))
+	}
+
+	for _, wl := range weightLabels {
+		lr := ranks[wl.label]
+		if lr.rank == 0 && len(lr.lexemes) == 0 {
+			continue
+		}
+
+		fields := strings.Join(wl.fields, ", ")
+		node := dquery.Explanation{
+			Value:       lr.rank,
+			Description: fmt.Sprintf("label %s (%s): rank of the query over the %s lexemes", wl.label, fields, fields),
+			Terms:       lr.lexemes,
+		}
+		if weights != nil {
+			node.Description = fmt.Sprintf("label %s (%s), weight %.2f: ts_rank over the %s lexemes",
+				wl.label, fields, weights[labelToPosition[wl.label]], fields)
+		}
+		if len(wl.fields) == 1 {
+			node.Field = wl.fields[0]
+		}
+		e.Details = append(e.Details, node)
+	}
+
+	e.Details = append(e.Details, dquery.Explanation{
+		Value:       rankNormalization,
+		Description: describeNormalization(rankNormalization),
+	})
+	return e
+}
+
+// describeNormalization describes ts_rank normalization flags
+// Example: 0 → "normalization flags 0: none, the rank ignores the document length"
+func describeNormalization(flags int) string {
+	var parts []string
+	for _, f := range normalizationFlags {
+		if flags&f.bit != 0 {
+			parts = append(parts, f.description)
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("normalization flags %d: none, the rank ignores the document length", flags)
+	}
+	return fmt.Sprintf("normalization flags %d: rank %s", flags, strings.Join(parts, ", then "))
+}
+
+// queryLexemes extracts the distinct lexemes of a tsquery text, split into exact lexemes
+// and prefixes (lexeme:*)
+// Example: "'climat' & ( 'energ':* | 'chang':AB )" → [climat chang], [energ]
+func queryLexemes(tsqueryText string) (exact, prefixes []string) {
+	exact, prefixes = []string{}, []string{}
+	seen := make(map[string]bool)
+	for _, m := range tsqueryLexeme.FindAllStringSubmatch(tsqueryText, -1) {
+		lexeme := strings.ReplaceAll(m[1], "''", "'")
+		key := m[2] + lexeme
+		if lexeme == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if m[2] == "*" {
+			prefixes = append(prefixes, lexeme)
+		} else {
+			exact = append(exact, lexeme)
+		}
+	}
+	return exact, prefixes
+}
@@ -0,0 +1,57 @@
+package native
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQueryLexemes(t *testing.T) {
+	exact, prefixes := queryLexemes("'climat' & ( 'energ':* | 'chang':AB ) & !'climat' & 'o''brien'")
+
+	if want := []string{"climat", "chang", "o'brien"}; !reflect.DeepEqual(exact, want) {
+		t.Errorf("exact = %v, want %v", exact, want)
+	}
+	if want := []string{"energ"}; !reflect.DeepEqual(prefixes, want) {
+		t.Errorf("prefixes = %v, want %v", prefixes, want)
+	}
+}
+
+func TestTextExplanation(t *testing.T) {
+	weights := rankWeights([]FieldWeight{{Field: "title", Weight: 3}, {Field: "content", Weight: 1}})
+	ranks := map[string]labelRank{
+		"A": {rank: 0.9, lexemes: []string{"climat"}},
+		"B": {},
+		"C": {rank: 0.3, lexemes: []string{"climat", "chang"}},
+	}
+
+	e := textExplanation(weights, ranks)
+	if !strings.Contains(e.Description, "{0.00, 1.00, 0.00, 3.00}") {
+		t.Errorf("description %q should list the ts_rank weights", e.Description)
+	}
+	if len(e.Details) != 3 {
+		t.Fatalf("expected labels A and C and the normalization flags, got %+v", e.Details)
+	}
+
+	title, content, normalization := e.Details[0], e.Details[1], e.Details[2]
+	if title.Field != "title" || title.Value != 0.9 || !strings.Contains(title.Description, "label A (title), weight 3.00") {
+		t.Errorf("title = %+v", title)
+	}
+	if content.Field != "content" || !reflect.DeepEqual(content.Terms, []string{"climat", "chang"}) {
+		t.Errorf("content = %+v", content)
+	}
+	if normalization.Description != "normalization flags 0: none, the rank ignores the document length" {
+		t.Errorf("normalization = %q", normalization.Description)
+	}
+
+	composite := textExplanation(nil, map[string]labelRank{"D": {rank: 0.1, lexemes: []string{"smith"}}})
+	if d := composite.Details[0]; d.Field != "" || !strings.HasPrefix(d.Description, "label D (subtitle, author): rank") {
+		t.Errorf("label D = %+v, want no single field and no weight for composite ranks", d)
+	}
+}
+
+func TestDescribeNormalization(t *testing.T) {
+	if got, want := describeNormalization(33), "normalization flags 33: rank divided by 1 + log(document length), then divided by itself + 1"; got != want {
+		t.Errorf("describeNormalization(33) = %q, want %q", got, want)
+	}
+}
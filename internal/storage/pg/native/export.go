@@ -0,0 +1,187 @@
+package native
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// exportCursor names the server-side cursor of an export; it lives in the export's transaction
+const exportCursor = "article_export"
+
+// Export implements storage.Exporter interface
+// Declares a server-side cursor over the full (filtered, rescored) match set inside a
+// REPEATABLE READ, READ ONLY transaction and fetches it in batches of baseOpts.Size, so
+// every batch reads the snapshot the score statistics were computed on.
+func (r *Searcher) Export(ctx context.Context, query *dquery.Base, baseOpts *dquery.BaseOptions, fn storage.ExportFunc) error {
+	size := baseOpts.Size
+	if size <= 0 {
+		size = storage.ExportBatchSize
+	}
+
+	slog.Info("Executing pool export",
+		"kind", query.Kind,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"batch_size", size)
+
+	q, err := r.compile(ctx, query)
+	if err != nil {
+		return err
+	}
+	rank, args := pg.AppendRescoring(q.rank, q.args, baseOpts.Rescoring, "")
+	where, args := pg.AppendFilterClause(q.where, args, baseOpts.Filters, "")
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("failed to begin export transaction: %w", err)
+	}
+	// The request context is cancelled when the client disconnects; the rollback must still run
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	stats, err := pg.ScoreStats(ctx, tx, rank, where, args)
+	if err != nil {
+		return err
+	}
+	if stats.Count == 0 {
+		return nil
+	}
+
+	declareSQL := fmt.Sprintf(`
+		DECLARE %s NO SCROLL CURSOR FOR
+			SELECT
+				id, title, subtitle, content, author, description, url, language, created_at, metadata,
+				%s as rank
+			FROM articles
+			WHERE %s
+			ORDER BY %s
+	`, exportCursor, rank, where, pg.BuildOrderBy(baseOpts.Sort, "rank", ""))
+	if _, err := tx.Exec(ctx, declareSQL, args...); err != nil {
+		return fmt.Errorf("failed to declare %s export cursor: %w", query.Kind, err)
+	}
+
+	fetchSQL := fmt.Sprintf("FETCH FORWARD %d FROM %s", size, exportCursor)
+	var exported int64
+	for {
+		hits, rawScores, err := fetchExportBatch(ctx, tx, fetchSQL, size)
+		if err != nil {
+			return err
+		}
+
+		storage.NormalizeScores(baseOpts.Normalization, hits, rawScores, stats, exported)
+		for i := range hits {
+			storage.ExplainRescoring(baseOpts.Rescoring, &hits[i], rawScores[i])
+			if err := fn(hits[i]); err != nil {
+				return err
+			}
+		}
+		exported += int64(len(hits))
+
+		if len(hits) < size {
+			break
+		}
+	}
+
+	slog.Info("PG export finished", "kind", query.Kind, "exported", exported, "total_matches", stats.Count)
+	return nil
+}
+
+// fetchExportBatch fetches the next batch of the export cursor. Rows are closed before the
+// hits are handed out, so a slow consumer does not hold a result set open.
+func fetchExportBatch(ctx context.Context, tx pgx.Tx, fetchSQL string, size int) ([]dto.ArticleSearchResult, []float64, error) {
+	rows, err := tx.Query(ctx, fetchSQL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch export batch: %w", err)
+	}
+	defer rows.Close()
+
+	hits := make([]dto.ArticleSearchResult, 0, size)
+	rawScores := make([]float64, 0, size)
+	for rows.Next() {
+		article, rawScore, err := pg.ScanSearchHit(rows, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		hits = append(hits, dto.ArticleSearchResult{
+			Article: article,
+			Score:   utils.RoundFloat64(rawScore, dquery.ScoreDecimalPlaces),
+		})
+		rawScores = append(rawScores, rawScore)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating export rows: %w", err)
+	}
+	return hits, rawScores, nil
+}
+
+// compile compiles a full-text query exactly as the matching Search method does
+func (r *Searcher) compile(ctx context.Context, query *dquery.Base) (ftsQuery, error) {
+	switch query.Kind {
+	case dquery.StringType:
+		parser := token.NewQueryStringParser(
+			token.WithDefaultOperator(query.QueryString.GetDefaultOperator()),
+			token.WithFields(dquery.QueryStringFields...),
+		)
+		root, err := parser.Parse(query.QueryString.Query)
+		if err != nil {
+			return ftsQuery{}, fmt.Errorf("failed to parse query_string: %w", err)
+		}
+		compiler := &queryStringCompiler{lang: query.QueryString.GetLanguage()}
+		return compiler.compile(root), nil
+
+	case dquery.MatchType:
+		m := query.Match
+		fieldBoosts := []FieldWeight{{Field: m.Field, Weight: 1.0}}
+		return r.compileMatch(ctx, m.Query, fieldBoosts, m.GetLanguage(), m.GetOperator(), m.GetFuzziness())
+
+	case dquery.MultiMatchType:
+		m := query.MultiMatch
+		fieldBoosts := make([]FieldWeight, 0, len(m.Fields))
+		for _, f := range m.Fields {
+			fieldBoosts = append(fieldBoosts, FieldWeight{Field: f.Name, Weight: f.Weight})
+		}
+		return r.compileMatch(ctx, m.Query, fieldBoosts, m.GetLanguage(), m.GetOperator(), m.GetFuzziness())
+
+	case dquery.PhraseType:
+		return r.phraseQuery(ctx, query.Phrase, 1)
+
+	case dquery.BooleanType:
+		return booleanQuery(query.Boolean)
+
+	case dquery.BoolType:
+		q, _, err := r.boolQuery(ctx, query.Bool)
+		return q, err
+
+	default:
+		return ftsQuery{}, fmt.Errorf("unsupported full-text query kind %q", query.Kind)
+	}
+}
+
+// compileMatch compiles a (multi-)field match query, expanding fuzzy terms when enabled
+func (r *Searcher) compileMatch(
+	ctx context.Context,
+	text string,
+	fieldBoosts []FieldWeight,
+	lang dquery.Language,
+	op operator.Operator,
+	fuzziness dquery.Fuzziness,
+) (ftsQuery, error) {
+	if fuzziness.IsEnabled() {
+		q, _, ok, err := r.fuzzyQuery(ctx, text, fieldBoosts, lang, op, fuzziness, 1)
+		if err != nil || ok {
+			return q, err
+		}
+	}
+	return matchQuery(text, fieldBoosts, lang, op, 1), nil
+}
+
+// Compile-time interface assertions
+var _ storage.Exporter = (*Searcher)(nil)
@@ -6,6 +6,7 @@ import (
 	"math"
 	"strings"
 
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg"
 	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
 	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
 )
@@ -72,6 +73,23 @@ func buildWeightLabels(fields []string) string {
 //	[]FieldWeight{{"title", 3.0}, {"description", 1.5}}
 //	→ "{0.00, 0.00, 1.50, 3.00}"  (D=0.0, C=0.0, B=1.5, A=3.0)
 func buildWeightsArray(fieldBoosts []FieldWeight) string {
+	weights := labelWeights(fieldBoosts)
+	result := formatWeights(weights)
+
+	// Log for debugging
+	slog.Debug("Built weights array",
+		"weights_dcba", result,
+		"D", weights[0],
+		"C", weights[1],
+		"B", weights[2],
+		"A", weights[3],
+		"field_boosts", fieldBoosts)
+
+	return result
+}
+
+// labelWeights computes the ts_rank weights {D, C, B, A} of field boosts
+func labelWeights(fieldBoosts []FieldWeight) [4]float64 {
 	// Initialize with zeros - only specified fields will have non-zero weights
 	weights := [4]float64{0.0, 0.0, 0.0, 0.0} // {D, C, B, A}
 
@@ -87,20 +105,13 @@ func buildWeightsArray(fieldBoosts []FieldWeight) string {
 			}
 		}
 	}
+	return weights
+}
 
-	result := fmt.Sprintf("{%.2f, %.2f, %.2f, %.2f}",
+// formatWeights renders ts_rank weights {D, C, B, A} as an array literal
+func formatWeights(weights [4]float64) string {
+	return fmt.Sprintf("{%.2f, %.2f, %.2f, %.2f}",
 		weights[0], weights[1], weights[2], weights[3])
-
-	// Log for debugging
-	slog.Debug("Built weights array",
-		"weights_dcba", result,
-		"D", weights[0],
-		"C", weights[1],
-		"B", weights[2],
-		"A", weights[3],
-		"field_boosts", fieldBoosts)
-
-	return result
 }
 
 // buildTsQuery constructs a PostgreSQL tsquery expression based on operator
@@ -110,12 +121,12 @@ func buildTsQuery(op operator.Operator, lang query.Language, paramNum int) strin
 
 	if op.IsOr() {
 		// websearch_to_tsquery supports OR operator via "term1 OR term2" syntax
-		return fmt.Sprintf("websearch_to_tsquery('%s'::regconfig, $%d)", lang, paramNum)
+		return pg.TsQuery(pg.WebsearchToTsQuery, lang, fmt.Sprintf("$%d", paramNum))
 	}
 
 	// plainto_tsquery uses AND by default for simple searches
 	// "climate change" -> "climat & chang"
-	return fmt.Sprintf("plainto_tsquery('%s'::regconfig, $%d)", lang, paramNum)
+	return pg.TsQuery(pg.PlainToTsQuery, lang, fmt.Sprintf("$%d", paramNum))
 }
 
 // buildRankExpression constructs a ts_rank expression with custom field weights
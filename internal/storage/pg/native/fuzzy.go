@@ -0,0 +1,194 @@
+package native
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// fuzzyExpansionSQL expands query lexemes to indexed lexemes within their edit distance.
+// article_lexemes is the ts_stat vocabulary of search_vector (migration 009); the length
+// predicate is a cheap pre-filter before levenshtein_less_equal (fuzzystrmatch).
+// Closest and most frequent variants win when a term has more than $3 candidates.
+const fuzzyExpansionSQL = `
+	SELECT q.term, v.word
+	FROM unnest($1::text[], $2::int[]) WITH ORDINALITY AS q(term, max_edits, ord)
+	CROSS JOIN LATERAL (
+		SELECT l.word, l.ndoc, levenshtein_less_equal(l.word, q.term, q.max_edits) AS distance
+		FROM article_lexemes l
+		WHERE char_length(l.word) BETWEEN char_length(q.term) - q.max_edits AND char_length(q.term) + q.max_edits
+		  AND l.word <> q.term
+		  AND levenshtein_less_equal(l.word, q.term, q.max_edits) <= q.max_edits
+		ORDER BY distance, l.ndoc DESC, l.word
+		LIMIT $3
+	) v
+	ORDER BY q.ord, v.distance, v.ndoc DESC, v.word
+`
+
+// fuzzyTerm is an analyzed query lexeme with the indexed lexemes it matches
+type fuzzyTerm struct {
+	lexeme   string
+	variants []string
+}
+
+// fuzzyQuery compiles a match query with typo tolerance: the text is analyzed into lexemes,
+// each lexeme is expanded to indexed lexemes within the allowed edit distance, and the
+// expanded tsquery is matched against the labels of the requested fields. The labeled and
+// unlabeled (highlighting) tsqueries are bound to $paramNum and $paramNum+1.
+// Returns ok=false when the text has no lexemes (e.g. only stopwords) so the caller can
+// fall back to the exact query.
+func (r *Searcher) fuzzyQuery(
+	ctx context.Context,
+	text string,
+	fieldBoosts []FieldWeight,
+	lang dquery.Language,
+	op operator.Operator,
+	fuzziness dquery.Fuzziness,
+	paramNum int,
+) (ftsQuery, []dquery.TermExpansion, bool, error) {
+	terms, err := r.expandTerms(ctx, text, lang, fuzziness)
+	if err != nil {
+		return ftsQuery{}, nil, false, err
+	}
+	if len(terms) == 0 {
+		return ftsQuery{}, nil, false, nil
+	}
+
+	fields := make([]string, 0, len(fieldBoosts))
+	for _, fb := range fieldBoosts {
+		fields = append(fields, fb.Field)
+	}
+
+	labeled := fmt.Sprintf("$%d::tsquery", paramNum)
+	rank := fmt.Sprintf("ts_rank(search_vector, %s)", labeled)
+	if len(fieldBoosts) > 0 {
+		rank = fmt.Sprintf("ts_rank('%s', search_vector, %s)", buildWeightsArray(fieldBoosts), labeled)
+	}
+
+	q := ftsQuery{
+		where:   "search_vector @@ " + labeled,
+		rank:    rank,
+		tsquery: fmt.Sprintf("$%d::tsquery", paramNum+1),
+		weights: rankWeights(fieldBoosts),
+		lang:    lang,
+		args: []any{
+			buildExpandedTsQuery(terms, op, buildWeightLabels(fields)),
+			buildExpandedTsQuery(terms, op, ""),
+		},
+	}
+
+	return q, termExpansions(terms), true, nil
+}
+
+// expandTerms analyzes text with the language config and expands every lexeme that
+// allows edits. Lexemes keep their query order.
+func (r *Searcher) expandTerms(ctx context.Context, text string, lang dquery.Language, fuzziness dquery.Fuzziness) ([]fuzzyTerm, error) {
+	analyzeSQL := fmt.Sprintf("SELECT lexeme FROM unnest(%s) ORDER BY positions[1]", pg.ToTsVector(lang, "$1"))
+	rows, err := r.db.Query(ctx, analyzeSQL, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze fuzzy query: %w", err)
+	}
+	var terms []fuzzyTerm
+	for rows.Next() {
+		var lexeme string
+		if err := rows.Scan(&lexeme); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan query lexeme: %w", err)
+		}
+		terms = append(terms, fuzzyTerm{lexeme: lexeme})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to analyze fuzzy query: %w", err)
+	}
+
+	var lexemes []string
+	var maxEdits []int32
+	index := make(map[string]int, len(terms))
+	for i, t := range terms {
+		if edits := fuzziness.MaxEdits(t.lexeme); edits > 0 {
+			lexemes = append(lexemes, t.lexeme)
+			maxEdits = append(maxEdits, int32(edits))
+			index[t.lexeme] = i
+		}
+	}
+	if len(lexemes) == 0 {
+		return terms, nil
+	}
+
+	rows, err = r.db.Query(ctx, fuzzyExpansionSQL, lexemes, maxEdits, dquery.DefaultFuzzyMaxExpansions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand fuzzy terms: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var term, variant string
+		if err := rows.Scan(&term, &variant); err != nil {
+			return nil, fmt.Errorf("failed to scan fuzzy expansion: %w", err)
+		}
+		i := index[term]
+		terms[i].variants = append(terms[i].variants, variant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to expand fuzzy terms: %w", err)
+	}
+
+	slog.Debug("Expanded fuzzy query terms", "fuzziness", fuzziness, "terms", len(terms), "expanded", len(lexemes))
+	return terms, nil
+}
+
+// buildExpandedTsQuery renders expanded terms as tsquery text. Each term matches itself
+// or any of its variants; terms are combined with the query operator. labels restricts
+// every lexeme to the weight labels of the searched fields.
+//
+// Example (operator and, labels "A"):
+//
+//	[{climat [climb clima]} {chang []}] → "('climat':A | 'climb':A | 'clima':A) & 'chang':A"
+func buildExpandedTsQuery(terms []fuzzyTerm, op operator.Operator, labels string) string {
+	suffix := ""
+	if labels != "" {
+		suffix = ":" + labels
+	}
+
+	groups := make([]string, 0, len(terms))
+	for _, t := range terms {
+		alternatives := make([]string, 0, len(t.variants)+1)
+		for _, lexeme := range append([]string{t.lexeme}, t.variants...) {
+			alternatives = append(alternatives, quoteLexeme(lexeme)+suffix)
+		}
+		group := strings.Join(alternatives, " | ")
+		if len(alternatives) > 1 {
+			group = "(" + group + ")"
+		}
+		groups = append(groups, group)
+	}
+
+	joiner := " | "
+	if op.IsAnd() {
+		joiner = " & "
+	}
+	return strings.Join(groups, joiner)
+}
+
+// quoteLexeme quotes a lexeme as a tsquery literal
+func quoteLexeme(lexeme string) string {
+	escaped := strings.ReplaceAll(lexeme, `\`, `\\`)
+	return "'" + strings.ReplaceAll(escaped, "'", "''") + "'"
+}
+
+// termExpansions reports the terms that were expanded to at least one variant
+func termExpansions(terms []fuzzyTerm) []dquery.TermExpansion {
+	var expansions []dquery.TermExpansion
+	for _, t := range terms {
+		if len(t.variants) > 0 {
+			expansions = append(expansions, dquery.TermExpansion{Term: t.lexeme, Expansions: t.variants})
+		}
+	}
+	return expansions
+}
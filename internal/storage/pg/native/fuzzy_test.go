@@ -0,0 +1,66 @@
+package native
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestBuildExpandedTsQuery(t *testing.T) {
+	terms := []fuzzyTerm{
+		{lexeme: "climat", variants: []string{"climb", "clima"}},
+		{lexeme: "chang"},
+	}
+
+	tests := []struct {
+		name   string
+		op     operator.Operator
+		labels string
+		want   string
+	}{
+		{
+			name:   "and with labels",
+			op:     operator.And,
+			labels: "A",
+			want:   "('climat':A | 'climb':A | 'clima':A) & 'chang':A",
+		},
+		{
+			name: "or without labels",
+			op:   operator.Or,
+			want: "('climat' | 'climb' | 'clima') | 'chang'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildExpandedTsQuery(terms, tt.op, tt.labels); got != tt.want {
+				t.Errorf("buildExpandedTsQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteLexeme(t *testing.T) {
+	for in, want := range map[string]string{
+		"climat": "'climat'",
+		"o'neil": "'o''neil'",
+		`a\b`:    `'a\\b'`,
+	} {
+		if got := quoteLexeme(in); got != want {
+			t.Errorf("quoteLexeme(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTermExpansions(t *testing.T) {
+	got := termExpansions([]fuzzyTerm{
+		{lexeme: "climat", variants: []string{"clima"}},
+		{lexeme: "chang"},
+	})
+	want := []dquery.TermExpansion{{Term: "climat", Expansions: []string{"clima"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("termExpansions() = %v, want %v", got, want)
+	}
+}
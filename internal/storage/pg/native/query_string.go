@@ -0,0 +1,148 @@
+package native
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// queryStringCompiler compiles a parsed query_string into one ftsQuery. Every term and
+// phrase becomes a to_tsquery predicate bound to its own argument; groups combine the
+// predicates with the occurrence semantics of token.Occur:
+//
+//	+a      → (a) AND ...
+//	-a      → NOT (a)
+//	a b     → (a OR b) when the group has no required clause; otherwise rank only
+//
+// The rank is the sum of the boosted ranks of all terms and phrases outside
+// prohibited clauses.
+type queryStringCompiler struct {
+	lang dquery.Language
+	// args are the to_tsquery inputs of all compiled leaves ($1..$len(args))
+	args  []any
+	ranks []string
+	// tsqueries are the tsqueries of scoring leaves, OR-ed for highlighting
+	tsqueries []string
+}
+
+// compile compiles the root group of a query_string
+func (c *queryStringCompiler) compile(root *token.Group) ftsQuery {
+	where := c.compileGroup(root, 1, true)
+
+	rank := zeroRank
+	if len(c.ranks) > 0 {
+		rank = "(" + strings.Join(c.ranks, " + ") + ")"
+	}
+	tsquery := "''::tsquery"
+	if len(c.tsqueries) > 0 {
+		tsquery = "(" + strings.Join(c.tsqueries, " || ") + ")"
+	}
+
+	return ftsQuery{
+		where:   where,
+		rank:    rank,
+		tsquery: tsquery,
+		lang:    c.lang,
+		args:    c.args,
+	}
+}
+
+// compileGroup compiles a group; boost is the product of the enclosing group boosts.
+// scoring is false inside prohibited clauses.
+func (c *queryStringCompiler) compileGroup(g *token.Group, boost float64, scoring bool) string {
+	var conds, shoulds []string
+	hasMust := false
+
+	for _, clause := range g.Clauses {
+		where := c.compileNode(clause.Node, boost*g.Boost, scoring && clause.Occur != token.MustNot)
+		switch clause.Occur {
+		case token.Must:
+			hasMust = true
+			conds = append(conds, "("+where+")")
+		case token.MustNot:
+			conds = append(conds, "NOT ("+where+")")
+		default:
+			shoulds = append(shoulds, "("+where+")")
+		}
+	}
+
+	if !hasMust && len(shoulds) > 0 {
+		conds = append(conds, "("+strings.Join(shoulds, " OR ")+")")
+	}
+	if len(conds) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(conds, " AND ")
+}
+
+func (c *queryStringCompiler) compileNode(node token.Node, boost float64, scoring bool) string {
+	switch n := node.(type) {
+	case *token.Term:
+		return c.compileLeaf(leafTsQuery(n.Value, n.Field, n.Prefix, " & "), boost*n.Boost, scoring)
+	case *token.Phrase:
+		return c.compileLeaf(leafTsQuery(n.Value, n.Field, false, " <-> "), boost*n.Boost, scoring)
+	case *token.Group:
+		return c.compileGroup(n, boost, scoring)
+	default:
+		return "FALSE"
+	}
+}
+
+// compileLeaf binds the to_tsquery input of a term or phrase. Input without words
+// (e.g. only punctuation) matches nothing, like an analyzed query without terms.
+func (c *queryStringCompiler) compileLeaf(tsQueryText string, boost float64, scoring bool) string {
+	if tsQueryText == "" {
+		return "FALSE"
+	}
+
+	c.args = append(c.args, tsQueryText)
+	queryExpr := pg.TsQuery(pg.ToTsQuery, c.lang, fmt.Sprintf("$%d", len(c.args)))
+
+	if scoring {
+		rank := fmt.Sprintf("ts_rank(search_vector, %s)", queryExpr)
+		if boost != 1 {
+			rank = fmt.Sprintf("%s * %s", strconv.FormatFloat(boost, 'g', -1, 64), rank)
+		}
+		c.ranks = append(c.ranks, rank)
+		c.tsqueries = append(c.tsqueries, queryExpr)
+	}
+
+	return fmt.Sprintf("search_vector @@ %s", queryExpr)
+}
+
+// leafTsQuery renders a term or phrase as to_tsquery input. Words are joined with joiner
+// (& for terms, <-> for phrases) and restricted to the weight label of the field, or of
+// the default fields when the leaf is not scoped. A prefix term matches its last word as
+// a prefix. Only letters and digits are kept, so the input never contains tsquery operators.
+// Examples:
+//
+//	title:energ*              → "energ:*A"
+//	"climate change"          → "climate:ABC <-> change:ABC"
+//	covid-19                  → "covid:ABC & 19:ABC"
+func leafTsQuery(text, field string, prefix bool, joiner string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+
+	labels := buildWeightLabels(dquery.DefaultFields)
+	if field != "" {
+		labels = buildWeightLabels([]string{field})
+	}
+
+	for i, w := range words {
+		weight := ":" + labels
+		if prefix && i == len(words)-1 {
+			weight = ":*" + labels
+		}
+		words[i] = w + weight
+	}
+	return strings.Join(words, joiner)
+}
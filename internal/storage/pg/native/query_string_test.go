@@ -0,0 +1,150 @@
+package native
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestLeafTsQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		field  string
+		prefix bool
+		joiner string
+		want   string
+	}{
+		{name: "unscoped term searches default fields", text: "climate", joiner: " & ", want: "climate:ABC"},
+		{name: "field scoped term", text: "climate", field: "title", joiner: " & ", want: "climate:A"},
+		{name: "prefix", text: "energ", field: "content", prefix: true, joiner: " & ", want: "energ:*C"},
+		{name: "phrase", text: "climate change", field: "title", joiner: " <-> ", want: "climate:A <-> change:A"},
+		{name: "punctuation splits words", text: "covid-19", joiner: " & ", want: "covid:ABC & 19:ABC"},
+		{name: "tsquery operators are dropped", text: "a&!b:*", field: "author", joiner: " & ", want: "a:D & b:D"},
+		{name: "no words", text: "!?", joiner: " & ", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := leafTsQuery(tt.text, tt.field, tt.prefix, tt.joiner); got != tt.want {
+				t.Errorf("leafTsQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryStringCompiler_Compile(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		op        operator.Operator
+		wantWhere string
+		wantArgs  []any
+		wantRanks int
+	}{
+		{
+			name:      "default operator OR",
+			input:     "climate change",
+			op:        operator.Or,
+			wantWhere: "((search_vector @@ to_tsquery('english'::regconfig, $1)) OR (search_vector @@ to_tsquery('english'::regconfig, $2)))",
+			wantArgs:  []any{"climate:ABC", "change:ABC"},
+			wantRanks: 2,
+		},
+		{
+			name:      "default operator AND",
+			input:     "climate change",
+			op:        operator.And,
+			wantWhere: "(search_vector @@ to_tsquery('english'::regconfig, $1)) AND (search_vector @@ to_tsquery('english'::regconfig, $2))",
+			wantArgs:  []any{"climate:ABC", "change:ABC"},
+			wantRanks: 2,
+		},
+		{
+			name:      "optional clauses only score next to required ones",
+			input:     `+title:"climate change" -politics energ*`,
+			op:        operator.Or,
+			wantWhere: "(search_vector @@ to_tsquery('english'::regconfig, $1)) AND NOT (search_vector @@ to_tsquery('english'::regconfig, $2))",
+			wantArgs:  []any{"climate:A <-> change:A", "politics:ABC", "energ:*ABC"},
+			wantRanks: 2,
+		},
+		{
+			name:      "prohibited group",
+			input:     "solar -(coal oil)",
+			op:        operator.Or,
+			wantWhere: "NOT (((search_vector @@ to_tsquery('english'::regconfig, $2)) OR (search_vector @@ to_tsquery('english'::regconfig, $3)))) AND ((search_vector @@ to_tsquery('english'::regconfig, $1)))",
+			wantArgs:  []any{"solar:ABC", "coal:ABC", "oil:ABC"},
+			wantRanks: 1,
+		},
+		{
+			name:      "leaf without words matches nothing",
+			input:     `"?!" climate`,
+			op:        operator.And,
+			wantWhere: "(FALSE) AND (search_vector @@ to_tsquery('english'::regconfig, $1))",
+			wantArgs:  []any{"climate:ABC"},
+			wantRanks: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := token.NewQueryStringParser(token.WithDefaultOperator(tt.op)).Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			c := &queryStringCompiler{lang: dquery.LanguageEnglish}
+			got := c.compile(root)
+
+			if got.where != tt.wantWhere {
+				t.Errorf("where = %s\nwant    %s", got.where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(got.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", got.args, tt.wantArgs)
+			}
+			if n := strings.Count(got.rank, "ts_rank("); n != tt.wantRanks {
+				t.Errorf("rank has %d terms, want %d: %s", n, tt.wantRanks, got.rank)
+			}
+		})
+	}
+}
+
+// Boosts multiply down from enclosing groups; prohibited clauses neither score nor highlight
+func TestQueryStringCompiler_Boosts(t *testing.T) {
+	root, err := token.NewQueryStringParser().Parse("(solar^2 wind)^1.5 -coal")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	c := &queryStringCompiler{lang: dquery.LanguageEnglish}
+	got := c.compile(root)
+
+	wantRank := "(3 * ts_rank(search_vector, to_tsquery('english'::regconfig, $1)) + " +
+		"1.5 * ts_rank(search_vector, to_tsquery('english'::regconfig, $2)))"
+	if got.rank != wantRank {
+		t.Errorf("rank = %s\nwant   %s", got.rank, wantRank)
+	}
+	if strings.Contains(got.tsquery, "$3") {
+		t.Errorf("tsquery highlights the prohibited clause: %s", got.tsquery)
+	}
+}
+
+// A purely negative query matches every article without the prohibited terms
+func TestQueryStringCompiler_OnlyProhibited(t *testing.T) {
+	root, err := token.NewQueryStringParser().Parse("-coal")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	c := &queryStringCompiler{lang: dquery.LanguageEnglish}
+	got := c.compile(root)
+
+	if got.where != "NOT (search_vector @@ to_tsquery('english'::regconfig, $1))" {
+		t.Errorf("where = %s", got.where)
+	}
+	if got.rank != zeroRank || got.tsquery != "''::tsquery" {
+		t.Errorf("rank = %s, tsquery = %s, want no scoring", got.rank, got.tsquery)
+	}
+}
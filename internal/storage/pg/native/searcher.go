@@ -2,13 +2,16 @@ package native
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
 	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
 	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -22,283 +25,79 @@ func NewReader(pool *pg.ConnectionPool) (*Searcher, error) {
 	return &Searcher{db: pool.GetConn()}, nil
 }
 
+// ftsQuery is a compiled full-text query: a match predicate and a rank expression
+// sharing the same positional arguments ($1..$len(args)).
+// tsquery is the unlabeled query expression, used by ts_headline for highlighting.
+// weights are the ts_rank weights {D, C, B, A} of the rank, nil when the rank sums several
+// ts_rank calls; they label the score explanation.
+type ftsQuery struct {
+	where   string
+	rank    string
+	tsquery string
+	weights *[4]float64
+	lang    dquery.Language
+	args    []any
+}
+
 // SearchStringQuery implements storage.FtsSearcher interface
-// Performs simple string-based search using PostgreSQL's tsvector and plainto_tsquery
-// Application determines optimal fields and weights based on index configuration
+// Parses the query_string syntax (field scopes, phrases, prefixes, boosts, +/- and groups)
+// and compiles it to to_tsquery predicates; unscoped terms search the default fields
 func (r *Searcher) SearchStringQuery(ctx context.Context, query *dquery.String, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
-	cursor, size := baseOpts.Cursor, baseOpts.Size
-	slog.Info("Executing pool query_string search", "query", query.Query, "has_cursor", cursor != nil, "size", size)
-
-	var globalMaxScore float64
-	var count int64
-	maxSQL := `
-			SELECT COALESCE(MAX(ts_rank(search_vector, plainto_tsquery('english', $1))), 0.0) as max_score, COUNT(*)
-			FROM articles
-			WHERE search_vector @@ plainto_tsquery('english', $1)
-		`
-	if err := r.db.QueryRow(ctx, maxSQL, query.Query).Scan(&globalMaxScore, &count); err != nil {
-		slog.Error("Failed to fetch global max score", "error", err)
-		return nil, fmt.Errorf("cannot fetch global max score: %w", err)
-	}
-	if count == 0 {
-		return &storage.SearchResult{}, nil
-	}
-	slog.Info("Computed global max score", "max_score", globalMaxScore, "total_matches", count)
-
-	var searchSQL string
-	var args []interface{}
-
-	if cursor == nil {
-		searchSQL = `
-			SELECT
-				id, title, subtitle, content, author, description, url, language, created_at, metadata,
-				ts_rank(search_vector, plainto_tsquery('english', $1)) as rank
-			FROM articles
-			WHERE search_vector @@ plainto_tsquery('english', $1)
-			ORDER BY rank DESC, id DESC
-			LIMIT $2
-		`
-		args = []interface{}{query.Query, size + 1}
-	} else {
-		searchSQL = `
-			SELECT
-				id, title, subtitle, content, author, description, url, language, created_at, metadata,
-				ts_rank(search_vector, plainto_tsquery('english', $1)) as rank
-			FROM articles
-			WHERE search_vector @@ plainto_tsquery('english', $1)
-			  AND (ts_rank(search_vector, plainto_tsquery('english', $1)), id) < ($2, $3)
-			ORDER BY rank DESC, id DESC
-			LIMIT $4
-		`
-		args = []interface{}{query.Query, cursor.Score, cursor.ID, size + 1}
-	}
+	lang := query.GetLanguage()
 
-	rows, err := r.db.Query(ctx, searchSQL, args...)
+	slog.Info("Executing pool query_string search",
+		"query", query.Query,
+		"language", lang,
+		"operator", query.GetDefaultOperator(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	parser := token.NewQueryStringParser(
+		token.WithDefaultOperator(query.GetDefaultOperator()),
+		token.WithFields(dquery.QueryStringFields...),
+	)
+	root, err := parser.Parse(query.Query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute search query: %w", err)
+		return nil, fmt.Errorf("failed to parse query_string: %w", err)
 	}
-	defer rows.Close()
-
-	var articles []dto.ArticleSearchResult
-	var rawScores []float64
 
-	for rows.Next() {
-		var metadataJSON []byte
-		var rawScore float64
-		var article dto.Article
-
-		if err := rows.Scan(
-			&article.ID,
-			&article.Title,
-			&article.Subtitle,
-			&article.Content,
-			&article.Author,
-			&article.Description,
-			&article.URL,
-			&article.Language,
-			&article.CreatedAt,
-			&metadataJSON,
-			&rawScore,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan article: %w", err)
-		}
-
-		if err := json.Unmarshal(metadataJSON, &article.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
+	slog.Debug("Parsed query_string", "input", query.Query, "parsed", root.String())
 
-		searchResult := dto.ArticleSearchResult{
-			Article:         article,
-			Score:           utils.RoundFloat64(rawScore, dquery.ScoreDecimalPlaces),
-			ScoreNormalized: utils.RoundFloat64(rawScore/globalMaxScore, dquery.ScoreDecimalPlaces),
-		}
-
-		articles = append(articles, searchResult)
-		rawScores = append(rawScores, rawScore)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
-	}
-
-	slog.Info("PG search results fetched",
-		"total_page_matches", len(articles),
-		"global_max_score", globalMaxScore)
-
-	hasMore := len(articles) > size
-	if hasMore {
-		articles = articles[:size]
-		rawScores = rawScores[:size]
-	}
-
-	var nextCursor *dquery.Cursor
-	if hasMore && len(articles) > 0 {
-		nextCursor = &dquery.Cursor{
-			Score: rawScores[len(rawScores)-1],
-			ID:    articles[len(articles)-1].Article.ID,
-		}
-	}
-
-	return &storage.SearchResult{
-		Hits:         articles,
-		NextCursor:   nextCursor,
-		HasMore:      hasMore,
-		MaxScore:     utils.RoundFloat64(globalMaxScore, dquery.ScoreDecimalPlaces),
-		PageMaxScore: utils.RoundFloat64(rawScores[0], dquery.ScoreDecimalPlaces),
-		TotalMatches: count,
-	}, nil
+	compiler := &queryStringCompiler{lang: lang}
+	return r.execute(ctx, dquery.StringType, compiler.compile(root), baseOpts)
 }
 
 // SearchField implements storage.SingleMatchSearcher interface
 // Performs single-field match query using PostgreSQL's tsvector
 func (r *Searcher) SearchField(ctx context.Context, query *dquery.Match, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
-	cursor, size := baseOpts.Cursor, baseOpts.Size
+	lang := query.GetLanguage()
+	operator := query.GetOperator()
+
 	slog.Info("Executing pool match search",
 		"query", query.Query,
 		"field", query.Field,
-		"operator", query.GetOperator(),
-		"language", query.GetLanguage(),
-		"has_cursor", cursor != nil,
-		"size", size)
-
-	lang := query.GetLanguage()
-	operator := query.GetOperator()
+		"operator", operator,
+		"fuzziness", query.Fuzziness,
+		"language", lang,
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
 
 	// Build FieldWeight for single field
 	fieldBoosts := []FieldWeight{{Field: query.Field, Weight: 1.0}}
-	whereClause := buildTsWhereClause(fieldBoosts, lang, operator, 1)
-	rankExpr := buildRankExpression(fieldBoosts, lang, operator, 1)
-
-	slog.Debug("PostgreSQL match query components",
-		"where", whereClause,
-		"rank", rankExpr)
-
-	// Get global max score and total count
-	var globalMaxScore float64
-	var count int64
-	maxSQL := fmt.Sprintf(`
-		SELECT COALESCE(MAX(%s), 0.0) as max_score, COUNT(*)
-		FROM articles
-		WHERE %s
-	`, rankExpr, whereClause)
-
-	if err := r.db.QueryRow(ctx, maxSQL, query.Query).Scan(&globalMaxScore, &count); err != nil {
-		slog.Error("Failed to fetch global max score", "error", err)
-		return nil, fmt.Errorf("cannot fetch global max score: %w", err)
+	if fuzziness := query.GetFuzziness(); fuzziness.IsEnabled() {
+		return r.executeFuzzy(ctx, dquery.MatchType, query.Query, fieldBoosts, lang, operator, fuzziness, baseOpts)
 	}
-	if count == 0 {
-		return &storage.SearchResult{}, nil
-	}
-	slog.Info("Computed global max score", "max_score", globalMaxScore, "total_matches", count)
 
-	var searchSQL string
-	var args []interface{}
+	q := matchQuery(query.Query, fieldBoosts, lang, operator, 1)
 
-	if cursor == nil {
-		searchSQL = fmt.Sprintf(`
-			SELECT
-				id, title, subtitle, content, author, description, url, language, created_at, metadata,
-				%s as rank
-			FROM articles
-			WHERE %s
-			ORDER BY rank DESC, id DESC
-			LIMIT $2
-		`, rankExpr, whereClause)
-		args = []interface{}{query.Query, size + 1}
-	} else {
-		searchSQL = fmt.Sprintf(`
-			SELECT
-				id, title, subtitle, content, author, description, url, language, created_at, metadata,
-				%s as rank
-			FROM articles
-			WHERE %s
-			  AND (%s, id) < ($2, $3)
-			ORDER BY rank DESC, id DESC
-			LIMIT $4
-		`, rankExpr, whereClause, rankExpr)
-		args = []interface{}{query.Query, cursor.Score, cursor.ID, size + 1}
-	}
-
-	rows, err := r.db.Query(ctx, searchSQL, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute match search query: %w", err)
-	}
-	defer rows.Close()
-
-	var articles []dto.ArticleSearchResult
-	var rawScores []float64
-
-	for rows.Next() {
-		var metadataJSON []byte
-		var rawScore float64
-		var article dto.Article
-
-		if err := rows.Scan(
-			&article.ID,
-			&article.Title,
-			&article.Subtitle,
-			&article.Content,
-			&article.Author,
-			&article.Description,
-			&article.URL,
-			&article.Language,
-			&article.CreatedAt,
-			&metadataJSON,
-			&rawScore,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan article: %w", err)
-		}
-
-		if err := json.Unmarshal(metadataJSON, &article.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
-
-		searchResult := dto.ArticleSearchResult{
-			Article:         article,
-			Score:           utils.RoundFloat64(rawScore, dquery.ScoreDecimalPlaces),
-			ScoreNormalized: utils.RoundFloat64(rawScore/globalMaxScore, dquery.ScoreDecimalPlaces),
-		}
-
-		articles = append(articles, searchResult)
-		rawScores = append(rawScores, rawScore)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
-	}
-
-	slog.Info("PG match search results fetched",
-		"total_page_matches", len(articles),
-		"global_max_score", globalMaxScore)
-
-	hasMore := len(articles) > size
-	if hasMore {
-		articles = articles[:size]
-		rawScores = rawScores[:size]
-	}
-
-	var nextCursor *dquery.Cursor
-	if hasMore && len(articles) > 0 {
-		nextCursor = &dquery.Cursor{
-			Score: rawScores[len(rawScores)-1],
-			ID:    articles[len(articles)-1].Article.ID,
-		}
-	}
-
-	return &storage.SearchResult{
-		Hits:         articles,
-		NextCursor:   nextCursor,
-		HasMore:      hasMore,
-		MaxScore:     utils.RoundFloat64(globalMaxScore, dquery.ScoreDecimalPlaces),
-		PageMaxScore: utils.RoundFloat64(rawScores[0], dquery.ScoreDecimalPlaces),
-		TotalMatches: count,
-	}, nil
+	return r.execute(ctx, dquery.MatchType, q, baseOpts)
 }
 
 // SearchFields implements storage.MultiMatchSearcher interface
 // Performs multi-field match query using PostgreSQL's weighted tsvector
 func (r *Searcher) SearchFields(ctx context.Context, query *dquery.MultiMatch, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
-	cursor, size := baseOpts.Cursor, baseOpts.Size
 	lang := query.GetLanguage()
 	operator := query.GetOperator()
 
@@ -315,146 +114,65 @@ func (r *Searcher) SearchFields(ctx context.Context, query *dquery.MultiMatch, b
 		"query", query.Query,
 		"fields", query.Fields,
 		"operator", operator,
+		"fuzziness", query.Fuzziness,
 		"language", lang,
-		"has_cursor", cursor != nil,
-		"size", size)
-
-	// Use helper functions with FieldWeight
-	whereClause := buildTsWhereClause(fieldBoosts, lang, operator, 1)
-	rankExpr := buildRankExpression(fieldBoosts, lang, operator, 1)
-
-	slog.Info("PostgreSQL multi_match query components",
-		"where_clause", whereClause,
-		"rank_expression", rankExpr,
-		"field_boosts", fieldBoosts,
-		"num_fields", len(fieldBoosts))
-
-	// Get global max score and total count
-	var globalMaxScore float64
-	var count int64
-	maxSQL := fmt.Sprintf(`
-		SELECT COALESCE(MAX(%s), 0.0) as max_score, COUNT(*)
-		FROM articles
-		WHERE %s
-	`, rankExpr, whereClause)
-
-	if err := r.db.QueryRow(ctx, maxSQL, query.Query).Scan(&globalMaxScore, &count); err != nil {
-		slog.Error("Failed to fetch global max score", "error", err)
-		return nil, fmt.Errorf("cannot fetch global max score: %w", err)
-	}
-	if count == 0 {
-		return &storage.SearchResult{}, nil
-	}
-	slog.Info("Computed global max score", "max_score", globalMaxScore, "total_matches", count)
-
-	var searchSQL string
-	var args []interface{}
-
-	if cursor == nil {
-		searchSQL = fmt.Sprintf(`
-			SELECT
-				id, title, subtitle, content, author, description, url, language, created_at, metadata,
-				%s as rank
-			FROM articles
-			WHERE %s
-			ORDER BY rank DESC, id DESC
-			LIMIT $2
-		`, rankExpr, whereClause)
-		args = []interface{}{query.Query, size + 1}
-	} else {
-		searchSQL = fmt.Sprintf(`
-			SELECT
-				id, title, subtitle, content, author, description, url, language, created_at, metadata,
-				%s as rank
-			FROM articles
-			WHERE %s
-			  AND (%s, id) < ($2, $3)
-			ORDER BY rank DESC, id DESC
-			LIMIT $4
-		`, rankExpr, whereClause, rankExpr)
-		args = []interface{}{query.Query, cursor.Score, cursor.ID, size + 1}
-	}
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
 
-	rows, err := r.db.Query(ctx, searchSQL, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute multi_match search query: %w", err)
+	if fuzziness := query.GetFuzziness(); fuzziness.IsEnabled() {
+		return r.executeFuzzy(ctx, dquery.MultiMatchType, query.Query, fieldBoosts, lang, operator, fuzziness, baseOpts)
 	}
-	defer rows.Close()
 
-	var articles []dto.ArticleSearchResult
-	var rawScores []float64
-
-	for rows.Next() {
-		var metadataJSON []byte
-		var rawScore float64
-		var article dto.Article
-
-		if err := rows.Scan(
-			&article.ID,
-			&article.Title,
-			&article.Subtitle,
-			&article.Content,
-			&article.Author,
-			&article.Description,
-			&article.URL,
-			&article.Language,
-			&article.CreatedAt,
-			&metadataJSON,
-			&rawScore,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan article: %w", err)
-		}
+	q := matchQuery(query.Query, fieldBoosts, lang, operator, 1)
 
-		if err := json.Unmarshal(metadataJSON, &article.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
-
-		searchResult := dto.ArticleSearchResult{
-			Article:         article,
-			Score:           utils.RoundFloat64(rawScore, dquery.ScoreDecimalPlaces),
-			ScoreNormalized: utils.RoundFloat64(rawScore/globalMaxScore, dquery.ScoreDecimalPlaces),
-		}
+	return r.execute(ctx, dquery.MultiMatchType, q, baseOpts)
+}
 
-		articles = append(articles, searchResult)
-		rawScores = append(rawScores, rawScore)
+// matchQuery compiles a (multi-)field match query whose text is bound to $paramNum
+func matchQuery(text string, fieldBoosts []FieldWeight, lang dquery.Language, op operator.Operator, paramNum int) ftsQuery {
+	return ftsQuery{
+		where:   buildTsWhereClause(fieldBoosts, lang, op, paramNum),
+		rank:    buildRankExpression(fieldBoosts, lang, op, paramNum),
+		tsquery: buildTsQuery(op, lang, paramNum),
+		weights: rankWeights(fieldBoosts),
+		lang:    lang,
+		args:    []any{text},
 	}
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
+// executeFuzzy runs a match query with typo tolerance and reports the term expansions.
+// Text without lexemes falls back to the exact query, which matches nothing as well.
+func (r *Searcher) executeFuzzy(
+	ctx context.Context,
+	kind dquery.Kind,
+	text string,
+	fieldBoosts []FieldWeight,
+	lang dquery.Language,
+	op operator.Operator,
+	fuzziness dquery.Fuzziness,
+	baseOpts *dquery.BaseOptions,
+) (*storage.SearchResult, error) {
+	q, expansions, ok, err := r.fuzzyQuery(ctx, text, fieldBoosts, lang, op, fuzziness, 1)
+	if err != nil {
+		slog.Error("Failed to build fuzzy query", "error", err, "kind", kind)
+		return nil, err
 	}
-
-	slog.Info("PG multi_match search results fetched",
-		"total_page_matches", len(articles),
-		"global_max_score", globalMaxScore)
-
-	hasMore := len(articles) > size
-	if hasMore {
-		articles = articles[:size]
-		rawScores = rawScores[:size]
+	if !ok {
+		q = matchQuery(text, fieldBoosts, lang, op, 1)
 	}
 
-	var nextCursor *dquery.Cursor
-	if hasMore && len(articles) > 0 {
-		nextCursor = &dquery.Cursor{
-			Score: rawScores[len(rawScores)-1],
-			ID:    articles[len(articles)-1].Article.ID,
-		}
+	result, err := r.execute(ctx, kind, q, baseOpts)
+	if err != nil {
+		return nil, err
 	}
-
-	return &storage.SearchResult{
-		Hits:         articles,
-		NextCursor:   nextCursor,
-		HasMore:      hasMore,
-		MaxScore:     utils.RoundFloat64(globalMaxScore, dquery.ScoreDecimalPlaces),
-		PageMaxScore: utils.RoundFloat64(rawScores[0], dquery.ScoreDecimalPlaces),
-		TotalMatches: count,
-	}, nil
+	result.Expansions = expansions
+	return result, nil
 }
 
 // SearchPhrase implements storage.FtsSearcher interface
 // Performs phrase search with optional slop using PostgreSQL's phraseto_tsquery or to_tsquery
 func (r *Searcher) SearchPhrase(ctx context.Context, query *dquery.Phrase, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
-	cursor, size := baseOpts.Cursor, baseOpts.Size
 	lang := query.GetLanguage()
 	slop := query.GetSlop()
 
@@ -463,295 +181,171 @@ func (r *Searcher) SearchPhrase(ctx context.Context, query *dquery.Phrase, baseO
 		"fields", query.Fields,
 		"slop", slop,
 		"language", lang,
-		"has_cursor", cursor != nil,
-		"size", size)
-
-	// Build the phrase tsquery based on slop
-	var phraseQueryExpr string
-	var whereClause string
-	var rankExpr string
-	labels := buildWeightLabels(query.Fields)
-
-	if slop == 0 {
-		// Exact phrase matching using phraseto_tsquery
-		phraseQueryExpr = fmt.Sprintf("phraseto_tsquery('%s'::regconfig, $1)", lang)
-
-		if labels != "" {
-			whereClause = fmt.Sprintf("search_vector @@ (%s::text || ':%s')::tsquery", phraseQueryExpr, labels)
-		} else {
-			whereClause = fmt.Sprintf("search_vector @@ %s", phraseQueryExpr)
-		}
-		rankExpr = fmt.Sprintf("ts_rank(search_vector, %s)", phraseQueryExpr)
-	} else {
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	q, err := r.phraseQuery(ctx, query, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.execute(ctx, dquery.PhraseType, q, baseOpts)
+}
+
+// phraseQuery compiles a phrase query whose argument is bound to $paramNum.
+// Slop > 0 tokenizes the phrase first and matches it with distance operators.
+func (r *Searcher) phraseQuery(ctx context.Context, query *dquery.Phrase, paramNum int) (ftsQuery, error) {
+	lang := query.GetLanguage()
+	slop := query.GetSlop()
+	param := fmt.Sprintf("$%d", paramNum)
+
+	// Exact phrase matching using phraseto_tsquery
+	phraseQueryExpr := pg.TsQuery(pg.PhraseToTsQuery, lang, param)
+	phraseArg := query.Query
+
+	if slop > 0 {
 		// Slop > 0: Build OR query with distance operators
 		// First, get the lexemes from the phrase using plainto_tsquery
 		var lexemesStr string
-		lexemeSQL := fmt.Sprintf("SELECT plainto_tsquery('%s'::regconfig, $1)::text", lang)
+		lexemeSQL := fmt.Sprintf("SELECT %s::text", pg.TsQuery(pg.PlainToTsQuery, lang, "$1"))
 		if err := r.db.QueryRow(ctx, lexemeSQL, query.Query).Scan(&lexemesStr); err != nil {
 			slog.Error("Failed to tokenize phrase", "error", err)
-			return nil, fmt.Errorf("failed to tokenize phrase: %w", err)
+			return ftsQuery{}, fmt.Errorf("failed to tokenize phrase: %w", err)
 		}
 
 		// Extract lexemes from the tsquery string (e.g., "'climat' & 'chang'" -> ["climat", "chang"])
-		lexemes := extractLexemesFromTsquery(lexemesStr)
-
-		if len(lexemes) < 2 {
-			// Single word or empty - fall back to simple phrase query
-			phraseQueryExpr = fmt.Sprintf("phraseto_tsquery('%s'::regconfig, $1)", lang)
-		} else {
+		// Single word or empty - keep the simple phrase query
+		if lexemes := extractLexemesFromTsquery(lexemesStr); len(lexemes) >= 2 {
 			// Build slop query: term1 <-> term2 | term1 <2> term2 | term1 <3> term2 ...
-			slopQueryStr := buildPhraseSlopQuery(lexemes, slop)
-			phraseQueryExpr = fmt.Sprintf("to_tsquery('%s'::regconfig, '%s')", lang, slopQueryStr)
-		}
-
-		if labels != "" {
-			whereClause = fmt.Sprintf("search_vector @@ (%s::text || ':%s')::tsquery", phraseQueryExpr, labels)
-		} else {
-			whereClause = fmt.Sprintf("search_vector @@ %s", phraseQueryExpr)
+			phraseQueryExpr = pg.TsQuery(pg.ToTsQuery, lang, param)
+			phraseArg = buildPhraseSlopQuery(lexemes, slop)
 		}
-		rankExpr = fmt.Sprintf("ts_rank(search_vector, %s)", phraseQueryExpr)
 	}
 
-	slog.Debug("PostgreSQL phrase query components",
-		"where", whereClause,
-		"rank", rankExpr,
-		"slop", slop)
-
-	// Get global max score and total count
-	var globalMaxScore float64
-	var count int64
-	var maxSQL string
-	var maxArgs []interface{}
-
-	if slop == 0 {
-		maxSQL = fmt.Sprintf(`
-			SELECT COALESCE(MAX(%s), 0.0) as max_score, COUNT(*)
-			FROM articles
-			WHERE %s
-		`, rankExpr, whereClause)
-		maxArgs = []interface{}{query.Query}
-	} else {
-		// For slop>0, query is already embedded in the expression
-		maxSQL = fmt.Sprintf(`
-			SELECT COALESCE(MAX(%s), 0.0) as max_score, COUNT(*)
-			FROM articles
-			WHERE %s
-		`, rankExpr, whereClause)
-		maxArgs = []interface{}{}
-	}
-
-	if err := r.db.QueryRow(ctx, maxSQL, maxArgs...).Scan(&globalMaxScore, &count); err != nil {
-		slog.Error("Failed to fetch global max score", "error", err)
-		return nil, fmt.Errorf("cannot fetch global max score: %w", err)
-	}
-	if count == 0 {
-		return &storage.SearchResult{}, nil
+	whereClause := fmt.Sprintf("search_vector @@ %s", phraseQueryExpr)
+	if labels := buildWeightLabels(query.Fields); labels != "" {
+		whereClause = fmt.Sprintf("search_vector @@ (%s::text || ':%s')::tsquery", phraseQueryExpr, labels)
 	}
-	slog.Info("Computed global max score", "max_score", globalMaxScore, "total_matches", count)
-
-	var searchSQL string
-	var args []interface{}
-
-	if slop == 0 {
-		// Exact phrase - use parameterized query
-		if cursor == nil {
-			searchSQL = fmt.Sprintf(`
-				SELECT
-					id, title, subtitle, content, author, description, url, language, created_at, metadata,
-					%s as rank
-				FROM articles
-				WHERE %s
-				ORDER BY rank DESC, id DESC
-				LIMIT $2
-			`, rankExpr, whereClause)
-			args = []interface{}{query.Query, size + 1}
-		} else {
-			searchSQL = fmt.Sprintf(`
-				SELECT
-					id, title, subtitle, content, author, description, url, language, created_at, metadata,
-					%s as rank
-				FROM articles
-				WHERE %s
-				  AND (%s, id) < ($2, $3)
-				ORDER BY rank DESC, id DESC
-				LIMIT $4
-			`, rankExpr, whereClause, rankExpr)
-			args = []interface{}{query.Query, cursor.Score, cursor.ID, size + 1}
-		}
-	} else {
-		// Slop > 0 - query is embedded in expression
-		if cursor == nil {
-			searchSQL = fmt.Sprintf(`
-				SELECT
-					id, title, subtitle, content, author, description, url, language, created_at, metadata,
-					%s as rank
-				FROM articles
-				WHERE %s
-				ORDER BY rank DESC, id DESC
-				LIMIT $1
-			`, rankExpr, whereClause)
-			args = []interface{}{size + 1}
-		} else {
-			searchSQL = fmt.Sprintf(`
-				SELECT
-					id, title, subtitle, content, author, description, url, language, created_at, metadata,
-					%s as rank
-				FROM articles
-				WHERE %s
-				  AND (%s, id) < ($1, $2)
-				ORDER BY rank DESC, id DESC
-				LIMIT $3
-			`, rankExpr, whereClause, rankExpr)
-			args = []interface{}{cursor.Score, cursor.ID, size + 1}
-		}
-	}
-
-	rows, err := r.db.Query(ctx, searchSQL, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute phrase search query: %w", err)
-	}
-	defer rows.Close()
 
-	var articles []dto.ArticleSearchResult
-	var rawScores []float64
-
-	for rows.Next() {
-		var metadataJSON []byte
-		var rawScore float64
-		var article dto.Article
-
-		if err := rows.Scan(
-			&article.ID,
-			&article.Title,
-			&article.Subtitle,
-			&article.Content,
-			&article.Author,
-			&article.Description,
-			&article.URL,
-			&article.Language,
-			&article.CreatedAt,
-			&metadataJSON,
-			&rawScore,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan article: %w", err)
-		}
-
-		if err := json.Unmarshal(metadataJSON, &article.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
-
-		searchResult := dto.ArticleSearchResult{
-			Article:         article,
-			Score:           utils.RoundFloat64(rawScore, dquery.ScoreDecimalPlaces),
-			ScoreNormalized: utils.RoundFloat64(rawScore/globalMaxScore, dquery.ScoreDecimalPlaces),
-		}
-
-		articles = append(articles, searchResult)
-		rawScores = append(rawScores, rawScore)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating rows: %w", err)
-	}
-
-	slog.Info("PG phrase search results fetched",
-		"total_page_matches", len(articles),
-		"global_max_score", globalMaxScore)
-
-	hasMore := len(articles) > size
-	if hasMore {
-		articles = articles[:size]
-		rawScores = rawScores[:size]
-	}
-
-	var nextCursor *dquery.Cursor
-	if hasMore && len(articles) > 0 {
-		nextCursor = &dquery.Cursor{
-			Score: rawScores[len(rawScores)-1],
-			ID:    articles[len(articles)-1].Article.ID,
-		}
-	}
-
-	return &storage.SearchResult{
-		Hits:         articles,
-		NextCursor:   nextCursor,
-		HasMore:      hasMore,
-		MaxScore:     utils.RoundFloat64(globalMaxScore, dquery.ScoreDecimalPlaces),
-		PageMaxScore: utils.RoundFloat64(rawScores[0], dquery.ScoreDecimalPlaces),
-		TotalMatches: count,
+	return ftsQuery{
+		where:   whereClause,
+		rank:    fmt.Sprintf("ts_rank(search_vector, %s)", phraseQueryExpr),
+		tsquery: phraseQueryExpr,
+		weights: rankWeights(nil),
+		lang:    lang,
+		args:    []any{phraseArg},
 	}, nil
 }
 
 func (r *Searcher) SearchBoolean(ctx context.Context, query *dquery.Boolean, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
-	cursor, size := baseOpts.Cursor, baseOpts.Size
 	lang := query.GetLanguage()
 
 	slog.Info("Executing pool boolean search",
 		"expression", query.Expression,
 		"language", lang,
-		"has_cursor", cursor != nil,
-		"size", size)
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	q, err := booleanQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.execute(ctx, dquery.BooleanType, q, baseOpts)
+}
+
+// booleanQuery compiles a boolean expression to a to_tsquery predicate bound to $1
+func booleanQuery(query *dquery.Boolean) (ftsQuery, error) {
+	lang := query.GetLanguage()
 
 	boolParser := NewBooleanParser()
 	tsqueryStr, err := boolParser.Parse(query.Expression)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse boolean expression: %w", err)
+		return ftsQuery{}, fmt.Errorf("failed to parse boolean expression: %w", err)
 	}
 
 	slog.Debug("Parsed boolean expression", "input", query.Expression, "tsquery", tsqueryStr)
 
-	queryExpr := fmt.Sprintf("to_tsquery('%s'::regconfig, $1)", lang)
-	whereClause := fmt.Sprintf("search_vector @@ %s", queryExpr)
-	rankExpr := fmt.Sprintf("ts_rank(search_vector, %s)", queryExpr)
-
-	var globalMaxScore float64
-	var count int64
-	maxSQL := fmt.Sprintf(`
-		SELECT COALESCE(MAX(%s), 0.0) as max_score, COUNT(*)
-		FROM articles
-		WHERE %s
-	`, rankExpr, whereClause)
-
-	if err := r.db.QueryRow(ctx, maxSQL, tsqueryStr).Scan(&globalMaxScore, &count); err != nil {
-		slog.Error("Failed to fetch global max score", "error", err)
-		return nil, fmt.Errorf("cannot fetch global max score: %w", err)
+	queryExpr := pg.TsQuery(pg.ToTsQuery, lang, "$1")
+	return ftsQuery{
+		where:   fmt.Sprintf("search_vector @@ %s", queryExpr),
+		rank:    fmt.Sprintf("ts_rank(search_vector, %s)", queryExpr),
+		tsquery: queryExpr,
+		weights: rankWeights(nil),
+		lang:    lang,
+		args:    []any{tsqueryStr},
+	}, nil
+}
+
+// execute runs a compiled full-text query with filters and keyset pagination.
+// Rescoring factors multiply the rank, so max scores, sorting and cursors all see the rescored rank.
+// The score statistics (for normalization) and total count are computed over the full (filtered)
+// match set, then one extra row is fetched to detect whether another page exists.
+func (r *Searcher) execute(ctx context.Context, kind dquery.Kind, q ftsQuery, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	cursor, size := baseOpts.Cursor, baseOpts.Size
+	rank, args := pg.AppendRescoring(q.rank, q.args, baseOpts.Rescoring, "")
+	where, args := pg.AppendFilterClause(q.where, args, baseOpts.Filters, "")
+
+	slog.Debug("PostgreSQL query components",
+		"kind", kind,
+		"where", where,
+		"rank", rank)
+
+	// Get the score statistics and total count
+	stats, err := pg.ScoreStats(ctx, r.db, rank, where, args)
+	if err != nil {
+		slog.Error("Failed to fetch global max score", "error", err, "kind", kind)
+		return nil, err
 	}
+	globalMaxScore, count := stats.Max, stats.Count
 	if count == 0 {
-		return &storage.SearchResult{}, nil
+		return &storage.SearchResult{Aggregations: dquery.EmptyAggregationResults(baseOpts.Aggregations)}, nil
 	}
 	slog.Info("Computed global max score", "max_score", globalMaxScore, "total_matches", count)
 
-	var searchSQL string
-	var args []interface{}
+	aggregations, err := pg.RunAggregations(ctx, r.db, baseOpts.Aggregations, where, args)
+	if err != nil {
+		slog.Error("Failed to compute aggregations", "error", err, "kind", kind)
+		return nil, err
+	}
 
-	if cursor == nil {
-		searchSQL = fmt.Sprintf(`
+	pageWhere := where
+	pageArgs := append([]any{}, args...)
+	if cursor != nil {
+		keyset, keysetArgs := pg.BuildKeysetClause(baseOpts.Sort, cursor, rank, "", len(pageArgs)+1)
+		pageWhere = fmt.Sprintf("%s\n\t\t\t  AND %s", where, keyset)
+		pageArgs = append(pageArgs, keysetArgs...)
+	}
+	pageArgs = append(pageArgs, size+1)
+
+	searchSQL := fmt.Sprintf(`
 			SELECT
 				id, title, subtitle, content, author, description, url, language, created_at, metadata,
 				%s as rank
 			FROM articles
 			WHERE %s
-			ORDER BY rank DESC, id DESC
-			LIMIT $2
-		`, rankExpr, whereClause)
-		args = []interface{}{tsqueryStr, size + 1}
-	} else {
+			ORDER BY %s
+			LIMIT $%d
+		`, rank, pageWhere, pg.BuildOrderBy(baseOpts.Sort, "rank", ""), len(pageArgs))
+
+	// Headlines are computed on the page only: the page query is wrapped so that
+	// ts_headline runs after LIMIT instead of on every candidate row.
+	highlight := baseOpts.Highlight
+	if highlight != nil {
+		pageArgs = append(pageArgs, pg.BuildHeadlineOptions(highlight))
+		headlines := pg.BuildHeadlineColumns(highlight, q.lang, q.tsquery, "page", len(pageArgs))
 		searchSQL = fmt.Sprintf(`
-			SELECT
-				id, title, subtitle, content, author, description, url, language, created_at, metadata,
-				%s as rank
-			FROM articles
-			WHERE %s
-			  AND (%s, id) < ($2, $3)
-			ORDER BY rank DESC, id DESC
-			LIMIT $4
-		`, rankExpr, whereClause, rankExpr)
-		args = []interface{}{tsqueryStr, cursor.Score, cursor.ID, size + 1}
+			SELECT page.*, %s
+			FROM (%s) page
+			ORDER BY %s
+		`, strings.Join(headlines, ", "), searchSQL, pg.BuildOrderBy(baseOpts.Sort, "page.rank", "page"))
 	}
 
-	rows, err := r.db.Query(ctx, searchSQL, args...)
+	rows, err := r.db.Query(ctx, searchSQL, pageArgs...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute boolean search query: %w", err)
+		return nil, fmt.Errorf("failed to execute %s search query: %w", kind, err)
 	}
 	defer rows.Close()
 
@@ -759,35 +353,23 @@ func (r *Searcher) SearchBoolean(ctx context.Context, query *dquery.Boolean, bas
 	var rawScores []float64
 
 	for rows.Next() {
-		var metadataJSON []byte
-		var rawScore float64
-		var article dto.Article
-
-		if err := rows.Scan(
-			&article.ID,
-			&article.Title,
-			&article.Subtitle,
-			&article.Content,
-			&article.Author,
-			&article.Description,
-			&article.URL,
-			&article.Language,
-			&article.CreatedAt,
-			&metadataJSON,
-			&rawScore,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan article: %w", err)
+		var headlines []string
+		if highlight != nil {
+			headlines = make([]string, len(highlight.Fields))
 		}
-
-		if err := json.Unmarshal(metadataJSON, &article.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		article, rawScore, err := pg.ScanSearchHit(rows, headlines)
+		if err != nil {
+			return nil, err
 		}
 
 		searchResult := dto.ArticleSearchResult{
-			Article:         article,
-			Score:           utils.RoundFloat64(rawScore, dquery.ScoreDecimalPlaces),
-			ScoreNormalized: utils.RoundFloat64(rawScore/globalMaxScore, dquery.ScoreDecimalPlaces),
+			Article: article,
+			Score:   utils.RoundFloat64(rawScore, dquery.ScoreDecimalPlaces),
 		}
+		if highlight != nil {
+			searchResult.Highlight = pg.ParseHeadlines(highlight, headlines)
+		}
+		storage.ExplainRescoring(baseOpts.Rescoring, &searchResult, rawScore)
 
 		articles = append(articles, searchResult)
 		rawScores = append(rawScores, rawScore)
@@ -797,22 +379,35 @@ func (r *Searcher) SearchBoolean(ctx context.Context, query *dquery.Boolean, bas
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
-	slog.Info("PG boolean search results fetched",
+	slog.Info("PG search results fetched",
+		"kind", kind,
 		"total_page_matches", len(articles),
 		"global_max_score", globalMaxScore)
 
+	if len(articles) == 0 {
+		return &storage.SearchResult{TotalMatches: count, Aggregations: aggregations}, nil
+	}
+
 	hasMore := len(articles) > size
 	if hasMore {
 		articles = articles[:size]
 		rawScores = rawScores[:size]
 	}
 
+	offset := storage.PageOffset(cursor)
+	storage.NormalizeScores(baseOpts.Normalization, articles, rawScores, stats, offset)
+
+	if baseOpts.Explain {
+		if err := r.explainHits(ctx, q, articles, rawScores, baseOpts.Rescoring); err != nil {
+			slog.Error("Failed to explain hits", "error", err, "kind", kind)
+			return nil, err
+		}
+	}
+
 	var nextCursor *dquery.Cursor
 	if hasMore && len(articles) > 0 {
-		nextCursor = &dquery.Cursor{
-			Score: rawScores[len(rawScores)-1],
-			ID:    articles[len(articles)-1].Article.ID,
-		}
+		nextCursor = storage.NextCursor(baseOpts.Sort, articles[len(articles)-1].Article, rawScores[len(rawScores)-1])
+		nextCursor.Offset = offset + int64(len(articles))
 	}
 
 	return &storage.SearchResult{
@@ -820,8 +415,9 @@ func (r *Searcher) SearchBoolean(ctx context.Context, query *dquery.Boolean, bas
 		NextCursor:   nextCursor,
 		HasMore:      hasMore,
 		MaxScore:     utils.RoundFloat64(globalMaxScore, dquery.ScoreDecimalPlaces),
-		PageMaxScore: utils.RoundFloat64(rawScores[0], dquery.ScoreDecimalPlaces),
+		PageMaxScore: utils.RoundFloat64(slices.Max(rawScores), dquery.ScoreDecimalPlaces),
 		TotalMatches: count,
+		Aggregations: aggregations,
 	}, nil
 }
 
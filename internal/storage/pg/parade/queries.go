@@ -0,0 +1,431 @@
+package parade
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// indexedFields are the text fields of the bm25 index (db/parade_migrations/002).
+// Field names are written into the query as literals, so only these are accepted.
+var indexedFields = map[string]bool{
+	"title":       true,
+	"subtitle":    true,
+	"description": true,
+	"content":     true,
+}
+
+// matchAll matches every document; Tantivy boolean queries without a positive clause match nothing
+const matchAll = "paradedb.all()"
+
+// paradeQuery is a compiled full-text query: a paradedb query builder expression matched
+// with id @@@ expr, sharing the positional arguments $1..$len(args).
+// words are the words of the scoring clauses, highlighted in the hits.
+type paradeQuery struct {
+	expr  string
+	args  []any
+	words []string
+}
+
+// compiler builds paradedb query builder expressions. Query text is always bound as a
+// positional argument; only whitelisted field names and numbers are rendered inline.
+type compiler struct {
+	args  []any
+	words []string
+}
+
+func (c *compiler) query(expr string) paradeQuery {
+	return paradeQuery{expr: expr, args: c.args, words: c.words}
+}
+
+// arg binds a value to the next positional parameter
+func (c *compiler) arg(v any) string {
+	c.args = append(c.args, v)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+// field validates a field name and renders it as a literal
+func field(name string) (string, error) {
+	if !indexedFields[name] {
+		return "", apperr.NewValidation(fmt.Sprintf("field %q is not in the ParadeDB bm25 index", name))
+	}
+	return "'" + name + "'", nil
+}
+
+// match compiles a match query on one field. The text is tokenized by the index tokenizer;
+// and requires every token (conjunction_mode). Fuzzy matching binds one match per word,
+// since the AUTO edit distance depends on the length of each word.
+//
+// Example:
+//
+//	"climate change", and, no fuzziness
+//	→ paradedb.match(field => 'title', value => $1, conjunction_mode => true)
+func (c *compiler) match(fieldName, text string, and bool, fuzziness dquery.Fuzziness, boost float64) (string, error) {
+	f, err := field(fieldName)
+	if err != nil {
+		return "", err
+	}
+	tokens := tokenize(text)
+	c.words = append(c.words, tokens...)
+
+	if !fuzziness.IsEnabled() || len(tokens) == 0 {
+		return boosted(fmt.Sprintf("paradedb.match(field => %s, value => %s, conjunction_mode => %t)", f, c.arg(text), and), boost), nil
+	}
+
+	clauses := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		m := fmt.Sprintf("paradedb.match(field => %s, value => %s", f, c.arg(t))
+		if distance := fuzziness.MaxEdits(t); distance > 0 {
+			m += fmt.Sprintf(", distance => %d", distance)
+		}
+		clauses = append(clauses, m+")")
+	}
+	if len(clauses) == 1 {
+		return boosted(clauses[0], boost), nil
+	}
+	if and {
+		return boosted(boolean(clauses, nil, nil), boost), nil
+	}
+	return boosted(boolean(nil, clauses, nil), boost), nil
+}
+
+// multiMatch compiles a multi_match query to the best matching field (Elasticsearch best_fields)
+func (c *compiler) multiMatch(query *dquery.MultiMatch) (string, error) {
+	and := query.GetOperator().IsAnd()
+	disjuncts := make([]string, 0, len(query.GetFields()))
+	for _, f := range query.GetFields() {
+		m, err := c.match(f.Name, query.Query, and, query.GetFuzziness(), f.Weight)
+		if err != nil {
+			return "", err
+		}
+		disjuncts = append(disjuncts, m)
+	}
+	return disjunctionMax(disjuncts), nil
+}
+
+// phrase compiles a phrase on one field: the tokens in order with at most slop moves.
+// Tantivy phrase queries need two terms, so a single token is a term query.
+//
+// Example:
+//
+//	"climate change" slop 2 → paradedb.phrase(field => 'title', phrases => $1::text[], slop => 2)
+func (c *compiler) phrase(fieldName, text string, slop int, boost float64) (string, error) {
+	f, err := field(fieldName)
+	if err != nil {
+		return "", err
+	}
+	tokens := tokenize(text)
+	c.words = append(c.words, tokens...)
+
+	switch len(tokens) {
+	case 0:
+		// no token matches nothing, like an analyzed phrase without terms
+		return boosted(fmt.Sprintf("paradedb.match(field => %s, value => %s)", f, c.arg(text)), boost), nil
+	case 1:
+		return boosted(fmt.Sprintf("paradedb.term(field => %s, value => %s)", f, c.arg(tokens[0])), boost), nil
+	}
+	expr := fmt.Sprintf("paradedb.phrase(field => %s, phrases => %s::text[]", f, c.arg(tokens))
+	if slop > 0 {
+		expr += fmt.Sprintf(", slop => %d", slop)
+	}
+	return boosted(expr+")", boost), nil
+}
+
+// prefix compiles a query_string prefix term: the last token is a prefix
+func (c *compiler) prefix(fieldName, text string, boost float64) (string, error) {
+	f, err := field(fieldName)
+	if err != nil {
+		return "", err
+	}
+	tokens := tokenize(text)
+	c.words = append(c.words, tokens...)
+
+	switch len(tokens) {
+	case 0:
+		return boosted(fmt.Sprintf("paradedb.match(field => %s, value => %s)", f, c.arg(text)), boost), nil
+	case 1:
+		// tokens are letters and digits only, so the pattern needs no escaping
+		return boosted(fmt.Sprintf("paradedb.regex(field => %s, pattern => %s)", f, c.arg(tokens[0]+".*")), boost), nil
+	}
+	return boosted(fmt.Sprintf("paradedb.phrase_prefix(field => %s, phrases => %s::text[])", f, c.arg(tokens)), boost), nil
+}
+
+// phraseQuery compiles a phrase query: at least one field must contain the phrase
+func (c *compiler) phraseQuery(query *dquery.Phrase) (string, error) {
+	should := make([]string, 0, len(query.Fields))
+	for _, f := range query.Fields {
+		p, err := c.phrase(f, query.Query, query.GetSlop(), 1)
+		if err != nil {
+			return "", err
+		}
+		should = append(should, p)
+	}
+	return boolean(nil, should, nil), nil
+}
+
+// bool compiles a compound query. Tantivy applies the Elasticsearch defaults to should
+// clauses (optional next to a must clause, otherwise at least one), so only an explicit
+// minimum_should_match needs translating: 1 requires the should clauses as one must
+// clause, all of them requires each. Filter clauses are must clauses scoring 0.
+func (c *compiler) bool(query *dquery.Bool) (string, error) {
+	must, err := c.clauses(query.Must)
+	if err != nil {
+		return "", err
+	}
+	should, err := c.clauses(query.Should)
+	if err != nil {
+		return "", err
+	}
+	mustNot, err := c.clauses(query.MustNot)
+	if err != nil {
+		return "", err
+	}
+	filter, err := c.clauses(query.Filter)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range filter {
+		must = append(must, fmt.Sprintf("paradedb.const_score(score => 0, query => %s)", f))
+	}
+
+	msm := query.GetMinimumShouldMatch()
+	switch {
+	case msm == 0 || len(must) == 0 && msm == 1:
+	case msm == len(should):
+		must, should = append(must, should...), nil
+	case msm == 1:
+		must, should = append(must, boolean(nil, should, nil)), nil
+	default:
+		return "", apperr.NewValidation(fmt.Sprintf("minimum_should_match %d of %d should clauses is not supported by ParadeDB", msm, len(should)))
+	}
+	if len(must) == 0 && len(should) == 0 {
+		must = []string{matchAll}
+	}
+	return boolean(must, should, mustNot), nil
+}
+
+func (c *compiler) clauses(clauses []dquery.Clause) ([]string, error) {
+	exprs := make([]string, 0, len(clauses))
+	for _, clause := range clauses {
+		var expr string
+		var err error
+		switch {
+		case clause.Match != nil:
+			m := clause.Match
+			expr, err = c.match(m.Field, m.Query, m.GetOperator().IsAnd(), m.GetFuzziness(), 1)
+		case clause.MultiMatch != nil:
+			expr, err = c.multiMatch(clause.MultiMatch)
+		case clause.Phrase != nil:
+			expr, err = c.phraseQuery(clause.Phrase)
+		case clause.Bool != nil:
+			expr, err = c.bool(clause.Bool)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+// queryString compiles a parsed query_string group: + clauses are must, - clauses must_not
+// and the others should. Unscoped terms and phrases match the best default field; the
+// words of one term must all match.
+func (c *compiler) queryString(group *token.Group) (string, error) {
+	var must, should, mustNot []string
+	for _, clause := range group.Clauses {
+		expr, err := c.queryStringNode(clause.Node)
+		if err != nil {
+			return "", err
+		}
+		switch clause.Occur {
+		case token.Must:
+			must = append(must, expr)
+		case token.MustNot:
+			mustNot = append(mustNot, expr)
+		default:
+			should = append(should, expr)
+		}
+	}
+	if len(must) == 0 && len(should) == 0 {
+		must = []string{matchAll}
+	}
+	return boosted(boolean(must, should, mustNot), group.Boost), nil
+}
+
+func (c *compiler) queryStringNode(node token.Node) (string, error) {
+	switch n := node.(type) {
+	case *token.Group:
+		return c.queryString(n)
+	case *token.Phrase:
+		return c.scoped(n.Field, n.Boost, func(field string, boost float64) (string, error) {
+			return c.phrase(field, n.Value, 0, boost)
+		})
+	case *token.Term:
+		return c.scoped(n.Field, n.Boost, func(field string, boost float64) (string, error) {
+			if n.Prefix {
+				return c.prefix(field, n.Value, boost)
+			}
+			return c.match(field, n.Value, true, "", boost)
+		})
+	default:
+		return "", fmt.Errorf("unsupported query_string node %T", node)
+	}
+}
+
+// scoped compiles a leaf on its field, or on the best default field when unscoped
+func (c *compiler) scoped(field string, boost float64, leaf func(field string, boost float64) (string, error)) (string, error) {
+	if field != "" {
+		return leaf(field, boost)
+	}
+	expr, err := c.defaultFields(dquery.DefaultFieldWeights, leaf)
+	if err != nil {
+		return "", err
+	}
+	return boosted(expr, boost), nil
+}
+
+// boolean compiles a parsed boolean expression: AND → must (NOT operands → must_not),
+// OR → should, NOT → must_not of all documents. Terms and phrases match the best default
+// field with its recommended weight.
+func (c *compiler) boolean(node token.Node) (string, error) {
+	switch n := node.(type) {
+	case *token.Term:
+		return c.defaultFields(dquery.RecommendedFieldWeights, func(field string, weight float64) (string, error) {
+			return c.match(field, n.Value, false, "", weight)
+		})
+	case *token.Phrase:
+		return c.defaultFields(dquery.RecommendedFieldWeights, func(field string, weight float64) (string, error) {
+			return c.phrase(field, n.Value, 0, weight)
+		})
+	case *token.And:
+		var must, mustNot []string
+		for _, operand := range n.Operands {
+			if not, ok := operand.(*token.Not); ok {
+				expr, err := c.boolean(not.Operand)
+				if err != nil {
+					return "", err
+				}
+				mustNot = append(mustNot, expr)
+				continue
+			}
+			expr, err := c.boolean(operand)
+			if err != nil {
+				return "", err
+			}
+			must = append(must, expr)
+		}
+		if len(must) == 0 {
+			must = []string{matchAll}
+		}
+		return boolean(must, nil, mustNot), nil
+	case *token.Or:
+		should := make([]string, 0, len(n.Operands))
+		for _, operand := range n.Operands {
+			expr, err := c.boolean(operand)
+			if err != nil {
+				return "", err
+			}
+			should = append(should, expr)
+		}
+		return boolean(nil, should, nil), nil
+	case *token.Not:
+		expr, err := c.boolean(n.Operand)
+		if err != nil {
+			return "", err
+		}
+		return boolean([]string{matchAll}, nil, []string{expr}), nil
+	case *token.Near:
+		return c.near(n)
+	default:
+		return "", fmt.Errorf("unsupported boolean node %T", node)
+	}
+}
+
+// near compiles NEAR/N to a sloppy phrase of both operands, in either order, on each
+// default field. Tantivy slop spreads over every gap of the phrase, so the words of a
+// phrase operand may be apart as well, unlike the gapless operands of Elasticsearch.
+func (c *compiler) near(near *token.Near) (string, error) {
+	left, right := nearOperand(near.Left), nearOperand(near.Right)
+	return c.defaultFields(dquery.RecommendedFieldWeights, func(field string, weight float64) (string, error) {
+		orders := make([]string, 0, 2)
+		for _, text := range []string{left + " " + right, right + " " + left} {
+			p, err := c.phrase(field, text, near.Distance, 1)
+			if err != nil {
+				return "", err
+			}
+			orders = append(orders, p)
+		}
+		return boosted(boolean(nil, orders, nil), weight), nil
+	})
+}
+
+func nearOperand(node token.Node) string {
+	switch n := node.(type) {
+	case *token.Term:
+		return n.Value
+	case *token.Phrase:
+		return n.Value
+	default:
+		return ""
+	}
+}
+
+// defaultFields compiles a leaf per default field with its weight and keeps the best
+// matching field
+func (c *compiler) defaultFields(weights map[string]float64, leaf func(field string, weight float64) (string, error)) (string, error) {
+	disjuncts := make([]string, 0, len(dquery.DefaultFields))
+	for _, f := range dquery.DefaultFields {
+		expr, err := leaf(f, weights[f])
+		if err != nil {
+			return "", err
+		}
+		disjuncts = append(disjuncts, expr)
+	}
+	return disjunctionMax(disjuncts), nil
+}
+
+// boolean renders a paradedb.boolean query, omitting empty occurrence lists
+func boolean(must, should, mustNot []string) string {
+	var parts []string
+	for _, occ := range []struct {
+		name    string
+		clauses []string
+	}{{"must", must}, {"should", should}, {"must_not", mustNot}} {
+		if len(occ.clauses) > 0 {
+			parts = append(parts, fmt.Sprintf("%s => ARRAY[%s]", occ.name, strings.Join(occ.clauses, ", ")))
+		}
+	}
+	return fmt.Sprintf("paradedb.boolean(%s)", strings.Join(parts, ", "))
+}
+
+// disjunctionMax scores a document by its best matching disjunct (tie breaker 0)
+func disjunctionMax(disjuncts []string) string {
+	if len(disjuncts) == 1 {
+		return disjuncts[0]
+	}
+	return fmt.Sprintf("paradedb.disjunction_max(disjuncts => ARRAY[%s])", strings.Join(disjuncts, ", "))
+}
+
+// boosted wraps a query in paradedb.boost, unless the boost is neutral
+func boosted(expr string, boost float64) string {
+	if boost == 0 || boost == 1 {
+		return expr
+	}
+	return fmt.Sprintf("paradedb.boost(factor => %s, query => %s)", strconv.FormatFloat(boost, 'f', -1, 64), expr)
+}
+
+// tokenize splits text like the default tokenizer of the bm25 index: lowercased runs of
+// letters and digits
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
@@ -0,0 +1,263 @@
+package parade
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestCompilerMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     string
+		text      string
+		and       bool
+		fuzziness dquery.Fuzziness
+		boost     float64
+		wantExpr  string
+		wantArgs  []any
+	}{
+		{
+			name:     "conjunction",
+			field:    "title",
+			text:     "Climate change",
+			and:      true,
+			boost:    1,
+			wantExpr: "paradedb.match(field => 'title', value => $1, conjunction_mode => true)",
+			wantArgs: []any{"Climate change"},
+		},
+		{
+			name:     "boosted disjunction",
+			field:    "content",
+			text:     "climate",
+			boost:    2.5,
+			wantExpr: "paradedb.boost(factor => 2.5, query => paradedb.match(field => 'content', value => $1, conjunction_mode => false))",
+			wantArgs: []any{"climate"},
+		},
+		{
+			name:      "fuzzy AUTO binds a match per word",
+			field:     "title",
+			text:      "the climate",
+			and:       true,
+			fuzziness: dquery.FuzzinessAuto,
+			boost:     1,
+			wantExpr:  "paradedb.boolean(must => ARRAY[paradedb.match(field => 'title', value => $1, distance => 1), paradedb.match(field => 'title', value => $2, distance => 2)])",
+			wantArgs:  []any{"the", "climate"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &compiler{}
+			got, err := c.match(tt.field, tt.text, tt.and, tt.fuzziness, tt.boost)
+			if err != nil {
+				t.Fatalf("match() error = %v", err)
+			}
+			if got != tt.wantExpr {
+				t.Errorf("match() =\n%s\nwant\n%s", got, tt.wantExpr)
+			}
+			if !reflect.DeepEqual(c.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", c.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCompilerRejectsUnindexedField(t *testing.T) {
+	var ve *apperr.ValidationError
+	c := &compiler{}
+	if _, err := c.match("author", "smith", false, "", 1); !errors.As(err, &ve) {
+		t.Errorf("match() on author error = %v, want a validation error", err)
+	}
+	if _, err := c.match("title'); DROP TABLE articles; --", "x", false, "", 1); !errors.As(err, &ve) {
+		t.Errorf("match() on an injected field name error = %v, want a validation error", err)
+	}
+
+	root, err := token.NewQueryStringParser(
+		token.WithDefaultOperator(operator.Or),
+		token.WithFields(dquery.QueryStringFields...),
+	).Parse(`climate author:smith`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := (&compiler{}).queryString(root); !errors.As(err, &ve) {
+		t.Errorf("queryString() on author error = %v, want a validation error", err)
+	}
+}
+
+func TestCompilerPhrase(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		slop     int
+		wantExpr string
+		wantArgs []any
+	}{
+		{
+			name:     "tokens in order",
+			text:     "Climate-change summit",
+			wantExpr: "paradedb.phrase(field => 'title', phrases => $1::text[])",
+			wantArgs: []any{[]string{"climate", "change", "summit"}},
+		},
+		{
+			name:     "slop",
+			text:     "climate summit",
+			slop:     2,
+			wantExpr: "paradedb.phrase(field => 'title', phrases => $1::text[], slop => 2)",
+			wantArgs: []any{[]string{"climate", "summit"}},
+		},
+		{
+			name:     "single token is a term",
+			text:     "Climate!",
+			wantExpr: "paradedb.term(field => 'title', value => $1)",
+			wantArgs: []any{"climate"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &compiler{}
+			got, err := c.phrase("title", tt.text, tt.slop, 1)
+			if err != nil {
+				t.Fatalf("phrase() error = %v", err)
+			}
+			if got != tt.wantExpr {
+				t.Errorf("phrase() =\n%s\nwant\n%s", got, tt.wantExpr)
+			}
+			if !reflect.DeepEqual(c.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", c.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCompilerBool(t *testing.T) {
+	title := func(text string) dquery.Clause {
+		return dquery.Clause{Match: dquery.NewMatch("title", text, dquery.WithMatchOperator(operator.Or))}
+	}
+	const (
+		m1 = "paradedb.match(field => 'title', value => $1, conjunction_mode => false)"
+		m2 = "paradedb.match(field => 'title', value => $2, conjunction_mode => false)"
+		m3 = "paradedb.match(field => 'title', value => $3, conjunction_mode => false)"
+	)
+
+	tests := []struct {
+		name    string
+		query   *dquery.Bool
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "should only",
+			query: &dquery.Bool{Should: []dquery.Clause{title("a"), title("b")}},
+			want:  "paradedb.boolean(should => ARRAY[" + m1 + ", " + m2 + "])",
+		},
+		{
+			name:  "filter scores 0 and must_not alone matches everything else",
+			query: &dquery.Bool{Filter: []dquery.Clause{title("a")}, MustNot: []dquery.Clause{title("b")}},
+			want:  "paradedb.boolean(must => ARRAY[paradedb.const_score(score => 0, query => " + m2 + ")], must_not => ARRAY[" + m1 + "])",
+		},
+		{
+			name:  "must_not only",
+			query: &dquery.Bool{MustNot: []dquery.Clause{title("a")}},
+			want:  "paradedb.boolean(must => ARRAY[paradedb.all()], must_not => ARRAY[" + m1 + "])",
+		},
+		{
+			name:  "minimum_should_match 1 next to must",
+			query: &dquery.Bool{Must: []dquery.Clause{title("a")}, Should: []dquery.Clause{title("b"), title("c")}, MinimumShouldMatch: 1},
+			want:  "paradedb.boolean(must => ARRAY[" + m1 + ", paradedb.boolean(should => ARRAY[" + m2 + ", " + m3 + "])])",
+		},
+		{
+			name:  "minimum_should_match of every should clause",
+			query: &dquery.Bool{Should: []dquery.Clause{title("a"), title("b")}, MinimumShouldMatch: 2},
+			want:  "paradedb.boolean(must => ARRAY[" + m1 + ", " + m2 + "])",
+		},
+		{
+			name:    "partial minimum_should_match",
+			query:   &dquery.Bool{Should: []dquery.Clause{title("a"), title("b"), title("c")}, MinimumShouldMatch: 2},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&compiler{}).bool(tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("bool() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("bool() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("bool() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompilerQueryString(t *testing.T) {
+	root, err := token.NewQueryStringParser(
+		token.WithDefaultOperator(operator.Or),
+		token.WithFields(dquery.QueryStringFields...),
+	).Parse(`+title:energ* -"coal plant"^2`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	c := &compiler{}
+	got, err := c.queryString(root)
+	if err != nil {
+		t.Fatalf("queryString() error = %v", err)
+	}
+
+	want := "paradedb.boolean(" +
+		"must => ARRAY[paradedb.regex(field => 'title', pattern => $1)], " +
+		"must_not => ARRAY[paradedb.boost(factor => 2, query => paradedb.disjunction_max(disjuncts => ARRAY[" +
+		"paradedb.phrase(field => 'title', phrases => $2::text[]), " +
+		"paradedb.phrase(field => 'description', phrases => $3::text[]), " +
+		"paradedb.phrase(field => 'content', phrases => $4::text[])]))])"
+	if got != want {
+		t.Errorf("queryString() =\n%s\nwant\n%s", got, want)
+	}
+	if c.args[0] != "energ.*" {
+		t.Errorf("prefix pattern = %v, want energ.*", c.args[0])
+	}
+}
+
+func TestCompilerBooleanNear(t *testing.T) {
+	root, err := token.NewBooleanParser().Parse(`climate NEAR/3 summit`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	c := &compiler{}
+	got, err := c.boolean(root)
+	if err != nil {
+		t.Fatalf("boolean() error = %v", err)
+	}
+
+	want := "paradedb.disjunction_max(disjuncts => ARRAY[" +
+		"paradedb.boost(factor => 3, query => paradedb.boolean(should => ARRAY[" +
+		"paradedb.phrase(field => 'title', phrases => $1::text[], slop => 3), " +
+		"paradedb.phrase(field => 'title', phrases => $2::text[], slop => 3)])), " +
+		"paradedb.boost(factor => 2, query => paradedb.boolean(should => ARRAY[" +
+		"paradedb.phrase(field => 'description', phrases => $3::text[], slop => 3), " +
+		"paradedb.phrase(field => 'description', phrases => $4::text[], slop => 3)])), " +
+		"paradedb.boolean(should => ARRAY[" +
+		"paradedb.phrase(field => 'content', phrases => $5::text[], slop => 3), " +
+		"paradedb.phrase(field => 'content', phrases => $6::text[], slop => 3)])])"
+	if got != want {
+		t.Errorf("boolean() =\n%s\nwant\n%s", got, want)
+	}
+	if !reflect.DeepEqual(c.args[:2], []any{[]string{"climate", "summit"}, []string{"summit", "climate"}}) {
+		t.Errorf("args = %v, want both operand orders", c.args[:2])
+	}
+}
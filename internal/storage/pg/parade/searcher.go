@@ -0,0 +1,315 @@
+package parade
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// scoreExpr is the BM25 score of a matching row; id is the key field of the bm25 index
+const scoreExpr = "pdb.score(id)"
+
+// Searcher runs full-text queries against the ParadeDB bm25 index of the articles table
+// (db/parade_migrations). Queries compile to paradedb query builder functions matched with
+// the @@@ operator, so they score with Tantivy's BM25 like the Elasticsearch translation.
+// The index uses its default tokenizer for every language, so the query language does not
+// change matching.
+type Searcher struct {
+	db *pgxpool.Pool
+}
+
+func NewSearcher(pool *pg.ConnectionPool) (*Searcher, error) {
+	return &Searcher{db: pool.GetConn()}, nil
+}
+
+// SearchStringQuery implements storage.FtsSearcher interface
+func (s *Searcher) SearchStringQuery(ctx context.Context, query *dquery.String, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing ParadeDB query_string search",
+		"query", query.Query,
+		"language", query.GetLanguage(),
+		"operator", query.GetDefaultOperator(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	parser := token.NewQueryStringParser(
+		token.WithDefaultOperator(query.GetDefaultOperator()),
+		token.WithFields(dquery.QueryStringFields...),
+	)
+	root, err := parser.Parse(query.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query_string: %w", err)
+	}
+
+	c := &compiler{}
+	expr, err := c.queryString(root)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.StringType, c.query(expr), baseOpts)
+}
+
+// SearchField implements storage.FtsSearcher interface
+func (s *Searcher) SearchField(ctx context.Context, query *dquery.Match, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing ParadeDB match search",
+		"query", query.Query,
+		"field", query.Field,
+		"operator", query.GetOperator(),
+		"fuzziness", query.Fuzziness,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := &compiler{}
+	expr, err := c.match(query.Field, query.Query, query.GetOperator().IsAnd(), query.GetFuzziness(), 1)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.MatchType, c.query(expr), baseOpts)
+}
+
+// SearchFields implements storage.FtsSearcher interface
+func (s *Searcher) SearchFields(ctx context.Context, query *dquery.MultiMatch, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing ParadeDB multi_match search",
+		"query", query.Query,
+		"fields", query.Fields,
+		"operator", query.GetOperator(),
+		"fuzziness", query.Fuzziness,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := &compiler{}
+	expr, err := c.multiMatch(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.MultiMatchType, c.query(expr), baseOpts)
+}
+
+// SearchPhrase implements storage.FtsSearcher interface
+func (s *Searcher) SearchPhrase(ctx context.Context, query *dquery.Phrase, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing ParadeDB phrase search",
+		"query", query.Query,
+		"fields", query.Fields,
+		"slop", query.GetSlop(),
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := &compiler{}
+	expr, err := c.phraseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.PhraseType, c.query(expr), baseOpts)
+}
+
+// SearchBoolean implements storage.FtsSearcher interface
+func (s *Searcher) SearchBoolean(ctx context.Context, query *dquery.Boolean, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing ParadeDB boolean search",
+		"expression", query.Expression,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	root, err := token.NewBooleanParser().Parse(query.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid boolean query expression: %w", err)
+	}
+
+	c := &compiler{}
+	expr, err := c.boolean(root)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.BooleanType, c.query(expr), baseOpts)
+}
+
+// SearchBool implements storage.FtsSearcher interface
+func (s *Searcher) SearchBool(ctx context.Context, query *dquery.Bool, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing ParadeDB bool search",
+		"must", len(query.Must),
+		"should", len(query.Should),
+		"must_not", len(query.MustNot),
+		"filter", len(query.Filter),
+		"minimum_should_match", query.GetMinimumShouldMatch(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := &compiler{}
+	expr, err := c.bool(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.BoolType, c.query(expr), baseOpts)
+}
+
+// execute runs a compiled query with filters and keyset pagination, like the native searcher.
+// Rescoring factors multiply the BM25 score, so max scores, sorting and cursors all see the
+// rescored score. The page query keeps pdb.score out of WHERE: the search runs in a subquery
+// (OFFSET 0 stops the planner from flattening it) and the keyset applies to its rank column.
+func (s *Searcher) execute(ctx context.Context, kind dquery.Kind, q paradeQuery, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	cursor, size := baseOpts.Cursor, baseOpts.Size
+	rank, args := pg.AppendRescoring(scoreExpr, q.args, baseOpts.Rescoring, "")
+	where, args := pg.AppendFilterClause("id @@@ "+q.expr, args, baseOpts.Filters, "")
+
+	slog.Debug("ParadeDB query components",
+		"kind", kind,
+		"where", where,
+		"rank", rank)
+
+	stats, err := pg.ScoreStats(ctx, s.db, rank, where, args)
+	if err != nil {
+		slog.Error("Failed to fetch global max score", "error", err, "kind", kind)
+		return nil, err
+	}
+	if stats.Count == 0 {
+		return &storage.SearchResult{Aggregations: dquery.EmptyAggregationResults(baseOpts.Aggregations)}, nil
+	}
+	slog.Info("Computed global max score", "max_score", stats.Max, "total_matches", stats.Count)
+
+	aggregations, err := pg.RunAggregations(ctx, s.db, baseOpts.Aggregations, where, args)
+	if err != nil {
+		slog.Error("Failed to compute aggregations", "error", err, "kind", kind)
+		return nil, err
+	}
+
+	pageArgs := append([]any{}, args...)
+	keyset := ""
+	if cursor != nil {
+		clause, keysetArgs := pg.BuildKeysetClause(baseOpts.Sort, cursor, "hits.rank", "hits", len(pageArgs)+1)
+		keyset = "WHERE " + clause
+		pageArgs = append(pageArgs, keysetArgs...)
+	}
+	pageArgs = append(pageArgs, size+1)
+
+	searchSQL := fmt.Sprintf(`
+			SELECT hits.*
+			FROM (
+				SELECT
+					id, title, subtitle, content, author, description, url, language, created_at, metadata,
+					%s as rank
+				FROM articles
+				WHERE %s
+				OFFSET 0
+			) hits
+			%s
+			ORDER BY %s
+			LIMIT $%d
+		`, rank, where, keyset, pg.BuildOrderBy(baseOpts.Sort, "hits.rank", "hits"), len(pageArgs))
+
+	// The bm25 index tokenizes every language the same way; ts_headline highlights the query
+	// words with the English configuration, which exists on every PostgreSQL.
+	highlight := baseOpts.Highlight
+	if highlight != nil && len(q.words) > 0 {
+		pageArgs = append(pageArgs, strings.Join(q.words, " | "))
+		tsquery := pg.TsQuery(pg.ToTsQuery, dquery.LanguageEnglish, fmt.Sprintf("$%d", len(pageArgs)))
+		pageArgs = append(pageArgs, pg.BuildHeadlineOptions(highlight))
+		headlines := pg.BuildHeadlineColumns(highlight, dquery.LanguageEnglish, tsquery, "page", len(pageArgs))
+		searchSQL = fmt.Sprintf(`
+			SELECT page.*, %s
+			FROM (%s) page
+			ORDER BY %s
+		`, strings.Join(headlines, ", "), searchSQL, pg.BuildOrderBy(baseOpts.Sort, "page.rank", "page"))
+	} else {
+		highlight = nil
+	}
+
+	rows, err := s.db.Query(ctx, searchSQL, pageArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s search query: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var articles []dto.ArticleSearchResult
+	var rawScores []float64
+
+	for rows.Next() {
+		var headlines []string
+		if highlight != nil {
+			headlines = make([]string, len(highlight.Fields))
+		}
+		article, rawScore, err := pg.ScanSearchHit(rows, headlines)
+		if err != nil {
+			return nil, err
+		}
+
+		searchResult := dto.ArticleSearchResult{
+			Article: article,
+			Score:   utils.RoundFloat64(rawScore, dquery.ScoreDecimalPlaces),
+		}
+		if highlight != nil {
+			searchResult.Highlight = pg.ParseHeadlines(highlight, headlines)
+		}
+		storage.ExplainRescoring(baseOpts.Rescoring, &searchResult, rawScore)
+
+		articles = append(articles, searchResult)
+		rawScores = append(rawScores, rawScore)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	slog.Info("ParadeDB search results fetched",
+		"kind", kind,
+		"total_page_matches", len(articles),
+		"global_max_score", stats.Max)
+
+	if len(articles) == 0 {
+		return &storage.SearchResult{TotalMatches: stats.Count, Aggregations: aggregations}, nil
+	}
+
+	hasMore := len(articles) > size
+	if hasMore {
+		articles = articles[:size]
+		rawScores = rawScores[:size]
+	}
+
+	offset := storage.PageOffset(cursor)
+	storage.NormalizeScores(baseOpts.Normalization, articles, rawScores, stats, offset)
+
+	if baseOpts.Explain {
+		// ParadeDB exposes the BM25 score of a hit, not its breakdown
+		for i := range articles {
+			storage.ExplainHit(baseOpts.Rescoring, &articles[i], rawScores[i], dquery.Explanation{
+				Description: "BM25 score of the ParadeDB bm25 index (pdb.score)",
+			})
+		}
+	}
+
+	var nextCursor *dquery.Cursor
+	if hasMore {
+		nextCursor = storage.NextCursor(baseOpts.Sort, articles[len(articles)-1].Article, rawScores[len(rawScores)-1])
+		nextCursor.Offset = offset + int64(len(articles))
+	}
+
+	return &storage.SearchResult{
+		Hits:         articles,
+		NextCursor:   nextCursor,
+		HasMore:      hasMore,
+		MaxScore:     utils.RoundFloat64(stats.Max, dquery.ScoreDecimalPlaces),
+		PageMaxScore: utils.RoundFloat64(slices.Max(rawScores), dquery.ScoreDecimalPlaces),
+		TotalMatches: stats.Count,
+		Aggregations: aggregations,
+	}, nil
+}
+
+// Compile-time interface assertions
+var _ storage.FtsSearcher = (*Searcher)(nil)
@@ -0,0 +1,72 @@
+package pg
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// minDecayExponent clamps the exponent of the recency factor. PostgreSQL raises "value out
+// of range: underflow" when EXP of float8 rounds to 0 (below about -745), which old or
+// undated articles under a short scale reach; EXP(-700) is 0 for any ranking purpose.
+const minDecayExponent = -700
+
+// scoreColumns maps score fields to numeric SQL expressions over the articles table.
+var scoreColumns = map[query.ScoreField]string{
+	query.ScoreFieldLanguageConfidence: "(%smetadata->>'languageConfidence')::float8",
+}
+
+// AppendRescoring multiplies the rank expression by the rescoring factors and returns
+// the rescored rank with the extended argument list. The factors use the formulas of
+// query.Rescoring, so they agree with Elasticsearch function_score and with the score
+// breakdown. alias qualifies the articles columns; a nil rescoring returns rank unchanged.
+//
+// Example:
+//
+//	ts_rank(...), recency exp 7d, source weights {"Reuters": 2}
+//	→ "(ts_rank(...)) * EXP(GREATEST($2::float8 * POWER(GREATEST(ABS(EXTRACT(EPOCH FROM (<published_at> - $3::timestamptz))::float8) - $4::float8, 0) / $5::float8, $6::float8), -700))
+//	   * COALESCE(($7::jsonb ->> COALESCE(metadata->>'sourceName', ''))::float8, 1)"
+func AppendRescoring(rank string, args []any, r *query.Rescoring, alias string) (string, []any) {
+	if r == nil {
+		return rank, args
+	}
+
+	prefix := aliasPrefix(alias)
+	next := func(arg any) string {
+		args = append(args, arg)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	factors := []string{"(" + rank + ")"}
+	if d := r.Recency; d != nil {
+		published := fmt.Sprintf(sortColumns[query.SortPublishedAt], prefix)
+		distance := fmt.Sprintf("GREATEST(ABS(EXTRACT(EPOCH FROM (%s - %s::timestamptz))::float8) - %s::float8, 0)",
+			published, next(d.Origin), next(d.Offset.Seconds()))
+		factors = append(factors, fmt.Sprintf("EXP(GREATEST(%s::float8 * POWER(%s / %s::float8, %s::float8), %d))",
+			next(math.Log(d.Decay)), distance, next(d.Scale.Seconds()), next(d.Exponent()), minDecayExponent))
+	}
+	if len(r.SourceWeights) > 0 {
+		// Weights are validated numbers, so the map always marshals
+		weights, _ := json.Marshal(r.SourceWeights)
+		factors = append(factors, fmt.Sprintf("COALESCE((%s::jsonb ->> COALESCE(%smetadata->>'sourceName', ''))::float8, 1)",
+			next(string(weights)), prefix))
+	}
+	for _, f := range r.FieldValueFactors {
+		value := fmt.Sprintf("%s::float8 * COALESCE(%s, %s::float8)",
+			next(f.Factor), fmt.Sprintf(scoreColumns[f.Field], prefix), next(f.Missing))
+		switch f.Modifier {
+		case query.ModifierLog1p:
+			value = fmt.Sprintf("LOG(1 + %s)", value)
+		case query.ModifierSqrt:
+			value = fmt.Sprintf("SQRT(%s)", value)
+		default:
+			value = "(" + value + ")"
+		}
+		factors = append(factors, value)
+	}
+
+	return strings.Join(factors, " * "), args
+}
@@ -0,0 +1,83 @@
+package pg
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestAppendRescoring(t *testing.T) {
+	origin := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		rescoring *query.Rescoring
+		alias     string
+		wantRank  string
+		wantArgs  int
+	}{
+		{name: "no rescoring", rescoring: nil, wantRank: "ts_rank(v, q)", wantArgs: 1},
+		{
+			name: "recency",
+			rescoring: &query.Rescoring{Recency: &query.RecencyDecay{
+				Function: query.DecayGauss, Origin: origin, Scale: 24 * time.Hour, Decay: 0.5,
+			}},
+			wantRank: "(ts_rank(v, q)) * EXP(GREATEST($4::float8 * POWER(GREATEST(ABS(EXTRACT(EPOCH FROM (" + publishedAtColumn +
+				" - $2::timestamptz))::float8) - $3::float8, 0) / $5::float8, $6::float8), -700))",
+			wantArgs: 6,
+		},
+		{
+			name: "source weights and field value factor",
+			rescoring: &query.Rescoring{
+				SourceWeights: map[string]float64{"Reuters": 2},
+				FieldValueFactors: []query.FieldValueFactor{{
+					Field: query.ScoreFieldLanguageConfidence, Factor: 1, Modifier: query.ModifierSqrt, Missing: 1,
+				}},
+			},
+			alias: "a",
+			wantRank: "(ts_rank(v, q)) * COALESCE(($2::jsonb ->> COALESCE(a.metadata->>'sourceName', ''))::float8, 1)" +
+				" * SQRT($3::float8 * COALESCE((a.metadata->>'languageConfidence')::float8, $4::float8))",
+			wantArgs: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank, args := AppendRescoring("ts_rank(v, q)", []any{"q"}, tt.rescoring, tt.alias)
+			if rank != tt.wantRank {
+				t.Errorf("rank = %q, want %q", rank, tt.wantRank)
+			}
+			if len(args) != tt.wantArgs {
+				t.Errorf("len(args) = %d, want %d", len(args), tt.wantArgs)
+			}
+		})
+	}
+}
+
+// A gauss decay with a 1d scale puts an article older than about a month below the float8
+// range of EXP; undated articles (coalesced to year 1) always are. The exponent is clamped
+// so the factor is ~0 instead of an underflow error.
+func TestAppendRescoring_ClampsDecayUnderflow(t *testing.T) {
+	origin := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	rescoring := &query.Rescoring{Recency: &query.RecencyDecay{
+		Function: query.DecayGauss, Origin: origin, Scale: 24 * time.Hour, Decay: 0.5,
+	}}
+
+	rank, args := AppendRescoring("ts_rank(v, q)", []any{"q"}, rescoring, "")
+
+	want := "EXP(GREATEST($4::float8 * POWER(GREATEST(ABS(EXTRACT(EPOCH FROM (" + publishedAtColumn +
+		" - $2::timestamptz))::float8) - $3::float8, 0) / $5::float8, $6::float8), -700))"
+	if !strings.Contains(rank, want) {
+		t.Fatalf("rank = %q, want the clamped factor %q", rank, want)
+	}
+
+	// The unclamped exponent of an undated article is far below the float8 range of EXP
+	lnDecay, scale, exponent := args[3].(float64), args[4].(float64), args[5].(float64)
+	distance := float64(origin.Year()-1) * 365.25 * 24 * time.Hour.Seconds()
+	if got := lnDecay * math.Pow(distance/scale, exponent); got >= minDecayExponent {
+		t.Fatalf("exponent = %v, want below %d for the test to cover the clamp", got, minDecayExponent)
+	}
+}
@@ -2,12 +2,15 @@ package pg
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
 	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
 	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
-	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/pgvector/pgvector-go"
 )
@@ -26,63 +29,76 @@ func NewSemanticSearcher(embedder *embedding.Embedder, pool *ConnectionPool) *Se
 	}
 }
 
-func (s *SemanticSearcher) SearchSemantic(ctx context.Context, query *query.Semantic, baseOpts *query.BaseOptions) (*storage.VectorSearchResult, error) {
+func (s *SemanticSearcher) SearchSemantic(ctx context.Context, query *dquery.Semantic, baseOpts *dquery.BaseOptions) (*storage.VectorSearchResult, error) {
 	vec, err := s.embedder.EmbedQuery(ctx, query.Query)
 	if err != nil {
 		return nil, err
 	}
 	vecEncoded := pgvector.NewVector(vec.Embedding)
 
-	cmd := `
+	threshold := query.Threshold
+	if threshold == 0 {
+		threshold = defaultThreshold
+	}
+
+	// Filters are evaluated against the joined article before the kNN limit,
+	// so a filtered search still returns up to size neighbours.
+	where, args := AppendFilterClause(
+		"e.embedding <=> $1 < $2",
+		[]any{vecEncoded, threshold},
+		baseOpts.Filters,
+		"a",
+	)
+	args = append(args, baseOpts.Size)
+
+	cmd := fmt.Sprintf(`
 		SELECT a.id,
 			   a.title,
 			   a.subtitle,
 			   a.content,
-			   a.description,
 			   a.author,
+			   a.description,
 			   a.url,
 			   a.language,
 			   a.created_at,
 			   a.metadata,
-			   e.distance
-		FROM (
-			SELECT article_id,
-				   embedding <=> $1 AS distance
-			FROM article_embeddings
-			WHERE embedding <=> $1 < $2
-			ORDER BY embedding <=> $1
-			LIMIT $3
-		) e
+			   e.embedding <=> $1 AS distance
+		FROM article_embeddings e
 		INNER JOIN articles a ON a.id = e.article_id
-		ORDER BY e.distance;
-	`
+		WHERE %s
+		ORDER BY e.embedding <=> $1
+		LIMIT $%d;
+	`, where, len(args))
 
-	threshold := query.Threshold
-	if threshold == 0 {
-		threshold = defaultThreshold
-	}
-
-	rows, err := s.db.Query(
-		ctx,
-		cmd,
-		vecEncoded,
-		threshold,
-		baseOpts.Size,
-	)
+	rows, err := s.db.Query(ctx, cmd, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var hits []dto.Article
+	var hits []dto.ArticleSearchResult
+	var similarities []float64
 	for rows.Next() {
 		article, dist, err := MapToArticle(rows)
 		if err != nil {
 			return nil, err
 		}
 		slog.Debug("Semantic search hit", "article_id", article.ID, "distance", dist)
-		hits = append(hits, *article)
+
+		// <=> is the cosine distance; the score is the cosine similarity
+		similarity := 1 - float64(dist)
+		hits = append(hits, dto.ArticleSearchResult{
+			Article: *article,
+			Score:   utils.RoundFloat64(similarity, dquery.ScoreDecimalPlaces),
+		})
+		similarities = append(similarities, similarity)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	// kNN returns a single page, so the page is the match set
+	storage.NormalizeScores(baseOpts.Normalization, hits, similarities, ranking.StatsOf(similarities), 0)
 
 	return &storage.VectorSearchResult{
 		Hits:       hits,
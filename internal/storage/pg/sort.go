@@ -0,0 +1,134 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// sortColumns maps sort fields to SQL expressions over the articles table.
+// Missing values are coalesced to the zero values search hits report (see storage.NextCursor),
+// so a cursor value always compares equal to the row it was read from. Source names compare
+// bytewise, like Elasticsearch keywords.
+var sortColumns = map[query.SortField]string{
+	query.SortPublishedAt: "COALESCE((%smetadata->>'publishedAt')::timestamptz, '0001-01-01T00:00:00Z')",
+	query.SortCreatedAt:   "%screated_at",
+	query.SortSourceName:  `COALESCE(%smetadata->>'sourceName', '') COLLATE "C"`,
+}
+
+// sortTerm is one ORDER BY expression with its direction; the id tiebreaker has no field
+type sortTerm struct {
+	field query.SortField
+	expr  string
+	desc  bool
+}
+
+// sortTerms lists the ORDER BY expressions of the sort followed by the id tiebreaker.
+// rank is the relevance expression; alias qualifies the articles columns.
+func sortTerms(sort query.Sort, rank, alias string) []sortTerm {
+	if len(sort) == 0 {
+		sort = query.DefaultSort
+	}
+	prefix := aliasPrefix(alias)
+
+	terms := make([]sortTerm, 0, len(sort)+1)
+	for _, k := range sort {
+		expr := rank
+		if k.Field != query.SortRelevance {
+			expr = fmt.Sprintf(sortColumns[k.Field], prefix)
+		}
+		terms = append(terms, sortTerm{field: k.Field, expr: expr, desc: k.Order == query.SortDesc})
+	}
+	return append(terms, sortTerm{expr: prefix + "id", desc: true})
+}
+
+// BuildOrderBy renders the ORDER BY list of the sort, ending with the id tiebreaker.
+// rank is the relevance expression, usually the rank output column.
+//
+// Example:
+//
+//	published_at:desc,relevance:desc, rank "rank", alias ""
+//	→ "COALESCE((metadata->>'publishedAt')::timestamptz, '0001-01-01T00:00:00Z') DESC, rank DESC, id DESC"
+func BuildOrderBy(sort query.Sort, rank, alias string) string {
+	terms := sortTerms(sort, rank, alias)
+	parts := make([]string, 0, len(terms))
+	for _, t := range terms {
+		dir := "ASC"
+		if t.desc {
+			dir = "DESC"
+		}
+		parts = append(parts, t.expr+" "+dir)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// BuildKeysetClause compiles the predicate selecting the rows that follow the cursor in
+// sort order. rank is the relevance expression; WHERE cannot reference the rank output column.
+// paramStart is the first free positional parameter number; the returned args must be
+// appended to the query arguments in order.
+//
+// All-descending sorts compare as one row value; mixed directions expand to the
+// lexicographic OR of "equal on the previous keys, after on this key".
+//
+// Example:
+//
+//	relevance:desc, paramStart 3 → "(ts_rank(...), id) < ($3, $4)"
+//	source_name:asc              → "(src > $3 OR (src = $3 AND id < $4))"
+func BuildKeysetClause(sort query.Sort, cursor *query.Cursor, rank, alias string, paramStart int) (string, []any) {
+	terms := sortTerms(sort, rank, alias)
+
+	args := make([]any, 0, len(terms))
+	values := cursor.Values
+	for _, t := range terms {
+		switch {
+		case t.field == "":
+			args = append(args, cursor.ID)
+		case t.field == query.SortRelevance:
+			args = append(args, cursor.Score)
+		case values[0].Time != nil:
+			// timestamptz keeps microseconds
+			args = append(args, values[0].Time.Round(time.Microsecond))
+			values = values[1:]
+		default:
+			args = append(args, *values[0].Keyword)
+			values = values[1:]
+		}
+	}
+
+	params := make([]string, len(terms))
+	allDesc := true
+	for i, t := range terms {
+		params[i] = fmt.Sprintf("$%d", paramStart+i)
+		allDesc = allDesc && t.desc
+	}
+
+	if allDesc {
+		exprs := make([]string, len(terms))
+		for i, t := range terms {
+			exprs[i] = t.expr
+		}
+		return fmt.Sprintf("(%s) < (%s)", strings.Join(exprs, ", "), strings.Join(params, ", ")), args
+	}
+
+	alternatives := make([]string, 0, len(terms))
+	for i, t := range terms {
+		conds := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, fmt.Sprintf("%s = %s", terms[j].expr, params[j]))
+		}
+		op := ">"
+		if t.desc {
+			op = "<"
+		}
+		conds = append(conds, fmt.Sprintf("%s %s %s", t.expr, op, params[i]))
+
+		alternative := strings.Join(conds, " AND ")
+		if len(conds) > 1 {
+			alternative = "(" + alternative + ")"
+		}
+		alternatives = append(alternatives, alternative)
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
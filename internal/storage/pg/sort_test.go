@@ -0,0 +1,100 @@
+package pg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+)
+
+const publishedAtColumn = "COALESCE((metadata->>'publishedAt')::timestamptz, '0001-01-01T00:00:00Z')"
+
+func TestBuildOrderBy(t *testing.T) {
+	tests := []struct {
+		name  string
+		sort  string
+		rank  string
+		alias string
+		want  string
+	}{
+		{name: "default", sort: "", rank: "rank", want: "rank DESC, id DESC"},
+		{name: "newest first", sort: "published_at,relevance", rank: "rank", want: publishedAtColumn + " DESC, rank DESC, id DESC"},
+		{
+			name:  "aliased columns",
+			sort:  "source_name,created_at:asc",
+			rank:  "page.rank",
+			alias: "page",
+			want:  `COALESCE(page.metadata->>'sourceName', '') COLLATE "C" ASC, page.created_at ASC, page.id DESC`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort, err := query.ParseSort(tt.sort)
+			if err != nil {
+				t.Fatalf("ParseSort() error = %v", err)
+			}
+			if got := BuildOrderBy(sort, tt.rank, tt.alias); got != tt.want {
+				t.Errorf("BuildOrderBy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildKeysetClause(t *testing.T) {
+	id := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	published := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		sort       string
+		values     []query.SortValue
+		wantClause string
+		wantArgs   []any
+	}{
+		{
+			name:       "relevance",
+			sort:       "relevance",
+			wantClause: "(ts_rank(search_vector, q), id) < ($3, $4)",
+			wantArgs:   []any{0.5, id},
+		},
+		{
+			name:       "descending fields compare as a row",
+			sort:       "published_at:desc,relevance",
+			values:     []query.SortValue{query.TimeSortValue(published)},
+			wantClause: "(" + publishedAtColumn + ", ts_rank(search_vector, q), id) < ($3, $4, $5)",
+			wantArgs:   []any{published, 0.5, id},
+		},
+		{
+			name:       "mixed directions expand",
+			sort:       "source_name:asc",
+			values:     []query.SortValue{query.KeywordSortValue("BBC")},
+			wantClause: `(COALESCE(metadata->>'sourceName', '') COLLATE "C" > $3 OR (COALESCE(metadata->>'sourceName', '') COLLATE "C" = $3 AND id < $4))`,
+			wantArgs:   []any{"BBC", id},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort, err := query.ParseSort(tt.sort)
+			if err != nil {
+				t.Fatalf("ParseSort() error = %v", err)
+			}
+			cursor := &query.Cursor{Score: 0.5, ID: id, Sort: sort, Values: tt.values}
+
+			clause, args := BuildKeysetClause(sort, cursor, "ts_rank(search_vector, q)", "", 3)
+			if clause != tt.wantClause {
+				t.Errorf("clause = %q, want %q", clause, tt.wantClause)
+			}
+			if len(args) != len(tt.wantArgs) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+			for i := range args {
+				if args[i] != tt.wantArgs[i] {
+					t.Errorf("args[%d] = %v, want %v", i, args[i], tt.wantArgs[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// suggestSQL finds corrections for query words missing from the article_words vocabulary
+// (migration 010). Candidates come from the trigram index (word % term, pg_trgm similarity
+// threshold) and are ranked by similarity, then by document frequency.
+const suggestSQL = `
+	SELECT q.term, w.word, w.similarity, w.ndoc
+	FROM unnest($1::text[]) WITH ORDINALITY AS q(term, ord)
+	CROSS JOIN LATERAL (
+		SELECT v.word, similarity(v.word, q.term) AS similarity, v.ndoc
+		FROM article_words v
+		WHERE v.word % q.term
+		  AND v.word <> q.term
+		ORDER BY similarity DESC, v.ndoc DESC, v.word
+		LIMIT $2
+	) w
+	WHERE NOT EXISTS (SELECT 1 FROM article_words e WHERE e.word = q.term)
+	ORDER BY q.ord, w.similarity DESC, w.ndoc DESC, w.word
+`
+
+// Suggester provides "did you mean" corrections from the indexed word vocabulary
+type Suggester struct {
+	db *pgxpool.Pool
+}
+
+func NewSuggester(pool *ConnectionPool) *Suggester {
+	return &Suggester{db: pool.GetConn()}
+}
+
+// Suggest implements storage.Suggester. Only words that do not occur in any article are
+// corrected, mirroring the Elasticsearch term suggester "missing" mode.
+func (s *Suggester) Suggest(ctx context.Context, text string, size int) (*query.Suggest, error) {
+	terms := query.SuggestTerms(text)
+	if len(terms) == 0 {
+		return &query.Suggest{}, nil
+	}
+
+	rows, err := s.db.Query(ctx, suggestSQL, terms, size)
+	if err != nil {
+		slog.Error("Failed to fetch suggestions", "error", err)
+		return nil, fmt.Errorf("failed to fetch suggestions: %w", err)
+	}
+	defer rows.Close()
+
+	var suggestions []query.TermSuggestion
+	for rows.Next() {
+		var term string
+		var option query.SuggestOption
+		var similarity float32
+		if err := rows.Scan(&term, &option.Text, &similarity, &option.Freq); err != nil {
+			return nil, fmt.Errorf("failed to scan suggestion: %w", err)
+		}
+		option.Score = utils.RoundFloat64(float64(similarity), query.ScoreDecimalPlaces)
+
+		if n := len(suggestions); n > 0 && suggestions[n-1].Term == term {
+			suggestions[n-1].Options = append(suggestions[n-1].Options, option)
+		} else {
+			suggestions = append(suggestions, query.TermSuggestion{Term: term, Options: []query.SuggestOption{option}})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fetch suggestions: %w", err)
+	}
+
+	return query.NewSuggest(text, suggestions), nil
+}
+
+// Compile-time interface assertions
+var _ storage.Suggester = (*Suggester)(nil)
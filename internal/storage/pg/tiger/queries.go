@@ -0,0 +1,488 @@
+package tiger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg/native"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+const (
+	// indexName is the bm25 index on search_text (db/tiger_migrations/002)
+	indexName = "idx_articles_bm25"
+	// textConfig is the text_config of the index; match predicates analyze text the same way
+	textConfig = "english"
+	// searchText is the generated column concatenating title, subtitle, description and content
+	searchText = "search_text"
+	// matchNone is the predicate of a query without words
+	matchNone = "FALSE"
+	// zeroRank is the rank of clauses that do not score
+	zeroRank = "0::float8"
+)
+
+// fieldColumns are the columns concatenated into search_text, the columns a query may be
+// restricted to. Column names are written into the query, so only these are accepted.
+var fieldColumns = map[string]bool{
+	"title":       true,
+	"subtitle":    true,
+	"description": true,
+	"content":     true,
+}
+
+// tigerQuery is a compiled full-text query: a match predicate and a rank expression sharing
+// the positional arguments $1..$len(args). words are the words of the scoring clauses,
+// highlighted in the hits. candidates is the text whose bm25 index candidates where is
+// narrowed to, empty when the query scans the table.
+type tigerQuery struct {
+	where      string
+	rank       string
+	args       []any
+	words      []string
+	candidates string
+}
+
+// clause is a compiled query part: a match predicate and its rank expression. bounded is
+// set when every article the predicate matches contains one of the clause words, so the
+// bm25 index finds all of them.
+type clause struct {
+	where   string
+	rank    string
+	bounded bool
+}
+
+// compiler compiles queries to one predicate and one rank. pg_textsearch indexes a single
+// column and ranks bag-of-words OR queries, so every query scores with the BM25 of its words
+// over search_text, while tsvector predicates on the article columns decide what matches:
+// operators, field restrictions, phrases and exclusions are post-filters of the ranking.
+// With a candidateLimit, the post-filters only run on the best BM25 candidates.
+type compiler struct {
+	args           []any
+	words          []string
+	candidateLimit int
+}
+
+// query narrows a bounded clause to the bm25 index candidates of its words, so the tsvector
+// predicates only run on them. Unbounded clauses (prefix terms, exclusions only) and
+// compilers without a candidateLimit scan the table.
+//
+//	→ id IN (SELECT id FROM articles ORDER BY search_text <@> to_bm25query($n, ..) LIMIT 10000) AND (<where>)
+func (c *compiler) query(cl clause) tigerQuery {
+	q := tigerQuery{where: cl.where, rank: cl.rank, words: c.words}
+	if c.candidateLimit > 0 && cl.bounded && len(c.words) > 0 && cl.where != matchNone {
+		q.candidates = strings.Join(c.words, " ")
+		q.where = fmt.Sprintf("id IN (%s) AND (%s)", candidatesSQL(c.arg(q.candidates), c.candidateLimit), cl.where)
+	}
+	q.args = c.args
+	return q
+}
+
+// candidatesSQL selects the ids of the best limit articles of a bm25 query text parameter,
+// read in order from the bm25 index
+func candidatesSQL(text string, limit int) string {
+	return fmt.Sprintf("SELECT id FROM articles ORDER BY %s <@> to_bm25query(%s, '%s') LIMIT %d",
+		searchText, text, indexName, limit)
+}
+
+// arg binds a value to the next positional parameter
+func (c *compiler) arg(v any) string {
+	c.args = append(c.args, v)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+// bm25 renders the BM25 score of text over search_text. <@> returns the negated score
+// (lower is better, for ascending index scans), so the rank negates it back.
+//
+//	bm25("climate change") → -(search_text <@> to_bm25query($1, 'idx_articles_bm25'))
+func (c *compiler) bm25(text string) string {
+	return fmt.Sprintf("-(%s <@> to_bm25query(%s, '%s'))", searchText, c.arg(text), indexName)
+}
+
+// matches renders the predicate of a to_tsquery expression on a column
+//
+//	matches("title", "climate & change") → to_tsvector('english', COALESCE(title, '')) @@ to_tsquery('english', $1)
+func (c *compiler) matches(column, tsquery string) string {
+	return fmt.Sprintf("to_tsvector('%s', COALESCE(%s, '')) @@ to_tsquery('%s', %s)", textConfig, column, textConfig, c.arg(tsquery))
+}
+
+// column validates a field name; an empty field searches search_text
+func column(field string) (string, error) {
+	if field == "" {
+		return searchText, nil
+	}
+	if !fieldColumns[field] {
+		return "", apperr.NewValidation(fmt.Sprintf("field %q is not in the pg_textsearch bm25 index", field))
+	}
+	return field, nil
+}
+
+// fieldsMatch compiles a (multi-)field match: the tsquery must match one of the fields, and
+// field weights are emulated by multiplying the BM25 score by the weight of the best matching
+// field (the index scores the fields as one document).
+//
+// Example:
+//
+//	"climate change", {title: 3, content: 1}, OR
+//	→ where: (to_tsvector(.. title ..) @@ to_tsquery(.., $1) OR to_tsvector(.. content ..) @@ to_tsquery(.., $2))
+//	→ rank:  -(search_text <@> to_bm25query($3, ..)) * GREATEST(CASE WHEN <title> THEN 3 ELSE 0 END, CASE WHEN <content> THEN 1 ELSE 0 END)
+func (c *compiler) fieldsMatch(text string, fields []dquery.MultiMatchField, tsquery string) (clause, error) {
+	if tsquery == "" {
+		return clause{where: matchNone, rank: zeroRank, bounded: true}, nil
+	}
+
+	predicates := make([]string, 0, len(fields))
+	weights := make([]string, 0, len(fields))
+	weighted := false
+	for _, f := range fields {
+		col, err := column(f.Name)
+		if err != nil {
+			return clause{}, err
+		}
+		p := c.matches(col, tsquery)
+		predicates = append(predicates, p)
+		weights = append(weights, fmt.Sprintf("CASE WHEN %s THEN %s ELSE 0 END", p, formatWeight(f.Weight)))
+		weighted = weighted || f.Weight != 1
+	}
+
+	rank := c.bm25(text)
+	if weighted {
+		rank = fmt.Sprintf("%s * GREATEST(%s)", rank, strings.Join(weights, ", "))
+	}
+	return clause{where: or(predicates), rank: rank, bounded: true}, nil
+}
+
+// match compiles a single-field match
+func (c *compiler) match(query *dquery.Match) (clause, error) {
+	if query.GetFuzziness().IsEnabled() {
+		return clause{}, apperr.NewValidation("fuzziness is not supported by pg_textsearch")
+	}
+	tokens := c.tokens(query.Query)
+	fields := []dquery.MultiMatchField{{Name: query.Field, Weight: 1}}
+	return c.fieldsMatch(query.Query, fields, wordsTsQuery(tokens, query.GetOperator().IsAnd()))
+}
+
+// multiMatch compiles a multi_match query
+func (c *compiler) multiMatch(query *dquery.MultiMatch) (clause, error) {
+	if query.GetFuzziness().IsEnabled() {
+		return clause{}, apperr.NewValidation("fuzziness is not supported by pg_textsearch")
+	}
+	tokens := c.tokens(query.Query)
+	return c.fieldsMatch(query.Query, query.GetFields(), wordsTsQuery(tokens, query.GetOperator().IsAnd()))
+}
+
+// phrase compiles a phrase query: BM25 ranks the phrase words, and one of the fields must
+// contain them in order (post-filter), at most slop words apart
+func (c *compiler) phrase(query *dquery.Phrase) (clause, error) {
+	tokens := c.tokens(query.Query)
+	fields := make([]dquery.MultiMatchField, 0, len(query.Fields))
+	for _, f := range query.Fields {
+		fields = append(fields, dquery.MultiMatchField{Name: f, Weight: 1})
+	}
+	if len(fields) == 0 {
+		fields = []dquery.MultiMatchField{{Weight: 1}}
+	}
+	return c.fieldsMatch(query.Query, fields, phraseTsQuery(tokens, query.GetSlop()))
+}
+
+// bool compiles a compound query like the native searcher: must and filter predicates are
+// AND-ed, must_not predicates negated and minimum_should_match counts the matching should
+// predicates. The rank sums the must ranks and the ranks of the matching should clauses.
+// It is bounded by a bounded required clause, or by required should clauses all bounded.
+func (c *compiler) bool(query *dquery.Bool) (clause, error) {
+	var predicates, ranks []string
+	bounded := false
+
+	for _, q := range query.Must {
+		cl, err := c.clause(q)
+		if err != nil {
+			return clause{}, err
+		}
+		predicates = append(predicates, "("+cl.where+")")
+		ranks = append(ranks, "("+cl.rank+")")
+		bounded = bounded || cl.bounded
+	}
+	for _, q := range query.Filter {
+		cl, err := c.clause(q)
+		if err != nil {
+			return clause{}, err
+		}
+		predicates = append(predicates, "("+cl.where+")")
+		bounded = bounded || cl.bounded
+	}
+
+	should := make([]string, 0, len(query.Should))
+	shouldBounded := len(query.Should) > 0
+	for _, q := range query.Should {
+		cl, err := c.clause(q)
+		if err != nil {
+			return clause{}, err
+		}
+		should = append(should, cl.where)
+		ranks = append(ranks, fmt.Sprintf("CASE WHEN %s THEN %s ELSE 0 END", cl.where, cl.rank))
+		shouldBounded = shouldBounded && cl.bounded
+	}
+	msm := query.GetMinimumShouldMatch()
+	bounded = bounded || (msm > 0 && shouldBounded)
+	switch {
+	case msm == 1:
+		predicates = append(predicates, or(should))
+	case msm > 1:
+		counts := make([]string, 0, len(should))
+		for _, p := range should {
+			counts = append(counts, fmt.Sprintf("(%s)::int", p))
+		}
+		predicates = append(predicates, fmt.Sprintf("(%s) >= %d", strings.Join(counts, " + "), msm))
+	}
+
+	for _, q := range query.MustNot {
+		n := len(c.words)
+		cl, err := c.clause(q)
+		if err != nil {
+			return clause{}, err
+		}
+		c.words = c.words[:n]
+		predicates = append(predicates, "NOT ("+cl.where+")")
+	}
+
+	return clause{where: and(predicates), rank: sum(ranks), bounded: bounded}, nil
+}
+
+func (c *compiler) clause(q dquery.Clause) (clause, error) {
+	switch {
+	case q.Match != nil:
+		return c.match(q.Match)
+	case q.MultiMatch != nil:
+		return c.multiMatch(q.MultiMatch)
+	case q.Phrase != nil:
+		return c.phrase(q.Phrase)
+	case q.Bool != nil:
+		return c.bool(q.Bool)
+	default:
+		return clause{where: matchNone, rank: zeroRank}, nil
+	}
+}
+
+// queryString compiles a parsed query_string group: + clauses are AND-ed, - clauses negated
+// and, without + clauses, one of the others must match. Every scoring clause adds the BM25 of
+// its words; boosts multiply it. Unscoped terms match search_text.
+func (c *compiler) queryString(group *token.Group) (clause, error) {
+	var must, should, mustNot, ranks []string
+	mustBounded, shouldBounded := false, true
+	for _, cl := range group.Clauses {
+		n := len(c.words)
+		compiled, err := c.queryStringNode(cl.Node)
+		if err != nil {
+			return clause{}, err
+		}
+		switch cl.Occur {
+		case token.Must:
+			must = append(must, "("+compiled.where+")")
+			ranks = append(ranks, "("+compiled.rank+")")
+			mustBounded = mustBounded || compiled.bounded
+		case token.MustNot:
+			c.words = c.words[:n]
+			mustNot = append(mustNot, "NOT ("+compiled.where+")")
+		default:
+			should = append(should, compiled.where)
+			ranks = append(ranks, fmt.Sprintf("CASE WHEN %s THEN %s ELSE 0 END", compiled.where, compiled.rank))
+			shouldBounded = shouldBounded && compiled.bounded
+		}
+	}
+	bounded := mustBounded
+	if len(must) == 0 && len(should) > 0 {
+		must = append(must, or(should))
+		bounded = shouldBounded
+	}
+	return clause{
+		where:   and(append(must, mustNot...)),
+		rank:    boost(sum(ranks), group.Boost),
+		bounded: bounded,
+	}, nil
+}
+
+func (c *compiler) queryStringNode(node token.Node) (clause, error) {
+	switch n := node.(type) {
+	case *token.Group:
+		return c.queryString(n)
+	case *token.Term:
+		tokens := c.tokens(n.Value)
+		tsquery := wordsTsQuery(tokens, true)
+		if n.Prefix && tsquery != "" {
+			// the index holds whole stemmed words, so it cannot find the completions
+			cl, err := c.leaf(n.Field, n.Value, tsquery+":*", n.Boost)
+			cl.bounded = false
+			return cl, err
+		}
+		return c.leaf(n.Field, n.Value, tsquery, n.Boost)
+	case *token.Phrase:
+		return c.leaf(n.Field, n.Value, phraseTsQuery(c.tokens(n.Value), 0), n.Boost)
+	default:
+		return clause{}, fmt.Errorf("unsupported query_string node %T", node)
+	}
+}
+
+func (c *compiler) leaf(field, text, tsquery string, weight float64) (clause, error) {
+	col, err := column(field)
+	if err != nil {
+		return clause{}, err
+	}
+	if tsquery == "" {
+		return clause{where: matchNone, rank: zeroRank, bounded: true}, nil
+	}
+	return clause{where: c.matches(col, tsquery), rank: boost(c.bm25(text), weight), bounded: true}, nil
+}
+
+// boolean compiles a boolean expression: the native to_tsquery translation of the expression
+// filters search_text, and BM25 ranks the words outside NOT operands
+func (c *compiler) boolean(expression string) (clause, error) {
+	tsquery, err := native.NewBooleanParser().Parse(expression)
+	if err != nil {
+		return clause{}, fmt.Errorf("failed to parse boolean expression: %w", err)
+	}
+	root, err := token.NewBooleanParser().Parse(expression)
+	if err != nil {
+		return clause{}, fmt.Errorf("failed to parse boolean expression: %w", err)
+	}
+
+	where := c.matches(searchText, tsquery)
+	words := positiveWords(root)
+	c.words = append(c.words, words...)
+	if len(words) == 0 {
+		// only exclusions: every article without them matches, unscored
+		return clause{where: where, rank: zeroRank}, nil
+	}
+	return clause{where: where, rank: c.bm25(strings.Join(words, " ")), bounded: boundedNode(root)}, nil
+}
+
+// boundedNode reports whether every match of a boolean expression contains one of its
+// positive words: a term or phrase, an AND with such an operand or an OR of only such operands
+func boundedNode(node token.Node) bool {
+	switch n := node.(type) {
+	case *token.Term, *token.Phrase, *token.Near:
+		return true
+	case *token.And:
+		for _, operand := range n.Operands {
+			if boundedNode(operand) {
+				return true
+			}
+		}
+		return false
+	case *token.Or:
+		for _, operand := range n.Operands {
+			if !boundedNode(operand) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// positiveWords lists the words of the terms and phrases outside NOT operands
+func positiveWords(node token.Node) []string {
+	switch n := node.(type) {
+	case *token.Term:
+		return tokenize(n.Value)
+	case *token.Phrase:
+		return tokenize(n.Value)
+	case *token.And:
+		var words []string
+		for _, operand := range n.Operands {
+			words = append(words, positiveWords(operand)...)
+		}
+		return words
+	case *token.Or:
+		var words []string
+		for _, operand := range n.Operands {
+			words = append(words, positiveWords(operand)...)
+		}
+		return words
+	case *token.Near:
+		return append(positiveWords(n.Left), positiveWords(n.Right)...)
+	default:
+		return nil
+	}
+}
+
+// tokens splits text into words and records them for highlighting
+func (c *compiler) tokens(text string) []string {
+	tokens := tokenize(text)
+	c.words = append(c.words, tokens...)
+	return tokens
+}
+
+// wordsTsQuery joins words with & (and) or | (or); empty without words
+func wordsTsQuery(tokens []string, and bool) string {
+	if and {
+		return strings.Join(tokens, " & ")
+	}
+	return strings.Join(tokens, " | ")
+}
+
+// phraseTsQuery matches the words in order with at most slop words between adjacent words.
+// The distance operator <N> is exact, so every distance up to slop is an alternative.
+//
+// Example:
+//
+//	["climate", "change"], slop 1 → "climate <-> change | climate <2> change"
+func phraseTsQuery(tokens []string, slop int) string {
+	if len(tokens) < 2 {
+		return strings.Join(tokens, "")
+	}
+
+	alternatives := make([]string, 0, slop+1)
+	for distance := 1; distance <= slop+1; distance++ {
+		op := "<->"
+		if distance > 1 {
+			op = fmt.Sprintf("<%d>", distance)
+		}
+		alternatives = append(alternatives, strings.Join(tokens, " "+op+" "))
+	}
+	return strings.Join(alternatives, " | ")
+}
+
+// tokenize splits text into lowercased runs of letters and digits. Only these reach
+// to_tsquery, so query text cannot inject tsquery operators.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func or(predicates []string) string {
+	if len(predicates) == 1 {
+		return predicates[0]
+	}
+	return "(" + strings.Join(predicates, " OR ") + ")"
+}
+
+func and(predicates []string) string {
+	if len(predicates) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(predicates, " AND ")
+}
+
+func sum(ranks []string) string {
+	if len(ranks) == 0 {
+		return zeroRank
+	}
+	return strings.Join(ranks, " + ")
+}
+
+// boost multiplies a rank by a weight, unless the weight is neutral
+func boost(rank string, weight float64) string {
+	if weight == 0 || weight == 1 {
+		return rank
+	}
+	return fmt.Sprintf("(%s) * %s", rank, formatWeight(weight))
+}
+
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'f', -1, 64)
+}
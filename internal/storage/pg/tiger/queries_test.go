@@ -0,0 +1,343 @@
+package tiger
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+func TestCompilerMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     *dquery.Match
+		wantWhere string
+		wantArgs  []any
+	}{
+		{
+			name:      "conjunction",
+			query:     dquery.NewMatch("title", "Climate change", dquery.WithMatchOperator(operator.And)),
+			wantWhere: "to_tsvector('english', COALESCE(title, '')) @@ to_tsquery('english', $1)",
+			wantArgs:  []any{"climate & change", "Climate change"},
+		},
+		{
+			name:      "disjunction",
+			query:     dquery.NewMatch("content", "climate, change!", dquery.WithMatchOperator(operator.Or)),
+			wantWhere: "to_tsvector('english', COALESCE(content, '')) @@ to_tsquery('english', $1)",
+			wantArgs:  []any{"climate | change", "climate, change!"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &compiler{}
+			got, err := c.match(tt.query)
+			if err != nil {
+				t.Fatalf("match() error = %v", err)
+			}
+			if got.where != tt.wantWhere {
+				t.Errorf("where =\n%s\nwant\n%s", got.where, tt.wantWhere)
+			}
+			if want := "-(search_text <@> to_bm25query($2, 'idx_articles_bm25'))"; got.rank != want {
+				t.Errorf("rank =\n%s\nwant\n%s", got.rank, want)
+			}
+			if !reflect.DeepEqual(c.args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", c.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCompilerRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *dquery.Match
+	}{
+		{name: "unindexed field", query: dquery.NewMatch("author", "smith")},
+		{name: "injected field name", query: dquery.NewMatch("title'); DROP TABLE articles; --", "x")},
+		{name: "fuzziness", query: dquery.NewMatch("title", "climate", dquery.WithMatchFuzziness(string(dquery.FuzzinessAuto)))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := (&compiler{}).match(tt.query)
+			var ve *apperr.ValidationError
+			if !errors.As(err, &ve) {
+				t.Errorf("match() error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestCompilerQueryStringRejectsAuthor(t *testing.T) {
+	root, err := token.NewQueryStringParser(
+		token.WithDefaultOperator(operator.Or),
+		token.WithFields(dquery.QueryStringFields...),
+	).Parse(`climate author:smith`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	_, err = (&compiler{}).queryString(root)
+	var ve *apperr.ValidationError
+	if !errors.As(err, &ve) {
+		t.Errorf("queryString() error = %v, want a validation error", err)
+	}
+}
+
+func TestCompilerMultiMatchWeights(t *testing.T) {
+	query := &dquery.MultiMatch{Query: "climate", Fields: []dquery.MultiMatchField{{Name: "title", Weight: 3}, {Name: "content", Weight: 1}}}
+
+	got, err := (&compiler{}).multiMatch(query)
+	if err != nil {
+		t.Fatalf("multiMatch() error = %v", err)
+	}
+
+	title := "to_tsvector('english', COALESCE(title, '')) @@ to_tsquery('english', $1)"
+	content := "to_tsvector('english', COALESCE(content, '')) @@ to_tsquery('english', $2)"
+	if want := "(" + title + " OR " + content + ")"; got.where != want {
+		t.Errorf("where =\n%s\nwant\n%s", got.where, want)
+	}
+	want := "-(search_text <@> to_bm25query($3, 'idx_articles_bm25')) * GREATEST(" +
+		"CASE WHEN " + title + " THEN 3 ELSE 0 END, " +
+		"CASE WHEN " + content + " THEN 1 ELSE 0 END)"
+	if got.rank != want {
+		t.Errorf("rank =\n%s\nwant\n%s", got.rank, want)
+	}
+}
+
+func TestPhraseTsQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens []string
+		slop   int
+		want   string
+	}{
+		{name: "adjacent", tokens: []string{"climate", "change", "summit"}, want: "climate <-> change <-> summit"},
+		{name: "slop", tokens: []string{"climate", "summit"}, slop: 2, want: "climate <-> summit | climate <2> summit | climate <3> summit"},
+		{name: "single token", tokens: []string{"climate"}, slop: 1, want: "climate"},
+		{name: "no token", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := phraseTsQuery(tt.tokens, tt.slop); got != tt.want {
+				t.Errorf("phraseTsQuery() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompilerBool(t *testing.T) {
+	title := func(text string) dquery.Clause {
+		return dquery.Clause{Match: dquery.NewMatch("title", text, dquery.WithMatchOperator(operator.Or))}
+	}
+	const (
+		p1 = "to_tsvector('english', COALESCE(title, '')) @@ to_tsquery('english', $1)"
+		p3 = "to_tsvector('english', COALESCE(title, '')) @@ to_tsquery('english', $3)"
+		p5 = "to_tsvector('english', COALESCE(title, '')) @@ to_tsquery('english', $5)"
+		r2 = "-(search_text <@> to_bm25query($2, 'idx_articles_bm25'))"
+		r4 = "-(search_text <@> to_bm25query($4, 'idx_articles_bm25'))"
+	)
+
+	tests := []struct {
+		name      string
+		query     *dquery.Bool
+		wantWhere string
+		wantRank  string
+		wantWords []string
+	}{
+		{
+			name:      "should only",
+			query:     &dquery.Bool{Should: []dquery.Clause{title("a"), title("b")}},
+			wantWhere: "(" + p1 + " OR " + p3 + ")",
+			wantRank:  "CASE WHEN " + p1 + " THEN " + r2 + " ELSE 0 END + CASE WHEN " + p3 + " THEN " + r4 + " ELSE 0 END",
+			wantWords: []string{"a", "b"},
+		},
+		{
+			name:      "filter does not score and must_not words are not highlighted",
+			query:     &dquery.Bool{Must: []dquery.Clause{title("a")}, Filter: []dquery.Clause{title("b")}, MustNot: []dquery.Clause{title("c")}},
+			wantWhere: "(" + p1 + ") AND (" + p3 + ") AND NOT (" + p5 + ")",
+			wantRank:  "(" + r2 + ")",
+			wantWords: []string{"a", "b"},
+		},
+		{
+			name:      "partial minimum_should_match counts the matching clauses",
+			query:     &dquery.Bool{Should: []dquery.Clause{title("a"), title("b")}, MinimumShouldMatch: 2},
+			wantWhere: "((" + p1 + ")::int + (" + p3 + ")::int) >= 2",
+			wantRank:  "CASE WHEN " + p1 + " THEN " + r2 + " ELSE 0 END + CASE WHEN " + p3 + " THEN " + r4 + " ELSE 0 END",
+			wantWords: []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &compiler{}
+			got, err := c.bool(tt.query)
+			if err != nil {
+				t.Fatalf("bool() error = %v", err)
+			}
+			if got.where != tt.wantWhere {
+				t.Errorf("where =\n%s\nwant\n%s", got.where, tt.wantWhere)
+			}
+			if got.rank != tt.wantRank {
+				t.Errorf("rank =\n%s\nwant\n%s", got.rank, tt.wantRank)
+			}
+			if !reflect.DeepEqual(c.words, tt.wantWords) {
+				t.Errorf("words = %v, want %v", c.words, tt.wantWords)
+			}
+		})
+	}
+}
+
+func TestCompilerQueryString(t *testing.T) {
+	root, err := token.NewQueryStringParser(
+		token.WithDefaultOperator(operator.Or),
+		token.WithFields(dquery.QueryStringFields...),
+	).Parse(`+title:energ* "coal plant"^2`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	c := &compiler{}
+	got, err := c.queryString(root)
+	if err != nil {
+		t.Fatalf("queryString() error = %v", err)
+	}
+
+	phrase := "to_tsvector('english', COALESCE(search_text, '')) @@ to_tsquery('english', $3)"
+	if want := "(to_tsvector('english', COALESCE(title, '')) @@ to_tsquery('english', $1))"; got.where != want {
+		t.Errorf("where =\n%s\nwant\n%s", got.where, want)
+	}
+	want := "(-(search_text <@> to_bm25query($2, 'idx_articles_bm25'))) + " +
+		"CASE WHEN " + phrase + " THEN (-(search_text <@> to_bm25query($4, 'idx_articles_bm25'))) * 2 ELSE 0 END"
+	if got.rank != want {
+		t.Errorf("rank =\n%s\nwant\n%s", got.rank, want)
+	}
+	if c.args[0] != "energ:*" || c.args[2] != "coal <-> plant" {
+		t.Errorf("tsqueries = %v, %v, want energ:* and coal <-> plant", c.args[0], c.args[2])
+	}
+}
+
+func TestCompilerBoolean(t *testing.T) {
+	c := &compiler{}
+	got, err := c.boolean(`climate AND NOT coal`)
+	if err != nil {
+		t.Fatalf("boolean() error = %v", err)
+	}
+
+	if want := "to_tsvector('english', COALESCE(search_text, '')) @@ to_tsquery('english', $1)"; got.where != want {
+		t.Errorf("where =\n%s\nwant\n%s", got.where, want)
+	}
+	if got.rank != "-(search_text <@> to_bm25query($2, 'idx_articles_bm25'))" {
+		t.Errorf("rank = %s", got.rank)
+	}
+	if c.args[1] != "climate" {
+		t.Errorf("ranked words = %v, want climate", c.args[1])
+	}
+	if !reflect.DeepEqual(c.words, []string{"climate"}) {
+		t.Errorf("words = %v, want [climate]", c.words)
+	}
+}
+
+func TestCompilerQueryCandidates(t *testing.T) {
+	candidates := regexp.MustCompile(`^id IN \(SELECT id FROM articles ORDER BY search_text <@> to_bm25query\(\$(\d+), 'idx_articles_bm25'\) LIMIT 500\) AND \((.*)\)$`)
+
+	queryString := func(input string) func(*compiler) (clause, error) {
+		return func(c *compiler) (clause, error) {
+			root, err := token.NewQueryStringParser(
+				token.WithDefaultOperator(operator.Or),
+				token.WithFields(dquery.QueryStringFields...),
+			).Parse(input)
+			if err != nil {
+				return clause{}, err
+			}
+			return c.queryString(root)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		compile        func(*compiler) (clause, error)
+		noLimit        bool
+		wantCandidates bool
+	}{
+		{
+			name: "match",
+			compile: func(c *compiler) (clause, error) {
+				return c.match(dquery.NewMatch("title", "climate change"))
+			},
+			wantCandidates: true,
+		},
+		{
+			name: "match without candidate limit",
+			compile: func(c *compiler) (clause, error) {
+				return c.match(dquery.NewMatch("title", "climate change"))
+			},
+			noLimit: true,
+		},
+		{name: "query_string terms", compile: queryString(`+title:climate -coal`), wantCandidates: true},
+		{name: "query_string prefix", compile: queryString(`+title:energ* coal`)},
+		{
+			name: "boolean",
+			compile: func(c *compiler) (clause, error) {
+				return c.boolean(`climate AND NOT coal`)
+			},
+			wantCandidates: true,
+		},
+		{
+			name: "boolean or with exclusion",
+			compile: func(c *compiler) (clause, error) {
+				return c.boolean(`climate OR NOT coal`)
+			},
+		},
+		{
+			name: "boolean exclusions only",
+			compile: func(c *compiler) (clause, error) {
+				return c.boolean(`NOT coal`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &compiler{candidateLimit: 500}
+			if tt.noLimit {
+				c.candidateLimit = 0
+			}
+			cl, err := tt.compile(c)
+			if err != nil {
+				t.Fatalf("compile error = %v", err)
+			}
+			got := c.query(cl)
+
+			m := candidates.FindStringSubmatch(got.where)
+			if narrowed := m != nil; narrowed != tt.wantCandidates {
+				t.Fatalf("where =\n%s\nnarrowed to candidates = %v, want %v", got.where, narrowed, tt.wantCandidates)
+			}
+			if m == nil {
+				if got.candidates != "" {
+					t.Errorf("candidates = %q, want none", got.candidates)
+				}
+				if got.where != cl.where {
+					t.Errorf("where =\n%s\nwant the clause predicate\n%s", got.where, cl.where)
+				}
+				return
+			}
+			if m[2] != cl.where {
+				t.Errorf("post-filter =\n%s\nwant the clause predicate\n%s", m[2], cl.where)
+			}
+			n, _ := strconv.Atoi(m[1])
+			if want := strings.Join(c.words, " "); got.args[n-1] != want || got.candidates != want {
+				t.Errorf("candidate words = %v (%q), want %v", got.args[n-1], got.candidates, want)
+			}
+		})
+	}
+}
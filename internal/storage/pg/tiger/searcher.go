@@ -0,0 +1,360 @@
+package tiger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage/pg"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Searcher runs full-text queries against the pg_textsearch bm25 index on the search_text
+// column of the articles table (db/tiger_migrations). The index scores one column with
+// OR-only BM25, so the searcher ranks by the BM25 of the query words (<@>) and matches with
+// English tsvector predicates on the article columns: operators, fields, phrases and
+// exclusions filter the ranked rows, and field weights scale the score by the best matching
+// field. The index analyzes every article as English, so the query language does not change
+// matching.
+type Searcher struct {
+	db             *pgxpool.Pool
+	candidateLimit int
+}
+
+// DefaultCandidateLimit is the number of bm25 index candidates the match predicates filter
+const DefaultCandidateLimit = 10_000
+
+type SearcherOption func(*Searcher)
+
+// WithCandidateLimit sets how many of the best BM25 candidates of the query words the match
+// predicates filter, instead of the whole table. Matches ranked below them are not found and
+// results report their total as a lower bound; 0 disables the limit and scans the table.
+func WithCandidateLimit(limit int) SearcherOption {
+	return func(s *Searcher) {
+		s.candidateLimit = limit
+	}
+}
+
+func NewSearcher(pool *pg.ConnectionPool, opts ...SearcherOption) (*Searcher, error) {
+	s := &Searcher{db: pool.GetConn(), candidateLimit: DefaultCandidateLimit}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.candidateLimit < 0 {
+		return nil, fmt.Errorf("candidate limit must not be negative, got %d", s.candidateLimit)
+	}
+	return s, nil
+}
+
+func (s *Searcher) compiler() *compiler {
+	return &compiler{candidateLimit: s.candidateLimit}
+}
+
+// SearchStringQuery implements storage.FtsSearcher interface
+func (s *Searcher) SearchStringQuery(ctx context.Context, query *dquery.String, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing pg_textsearch query_string search",
+		"query", query.Query,
+		"language", query.GetLanguage(),
+		"operator", query.GetDefaultOperator(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	parser := token.NewQueryStringParser(
+		token.WithDefaultOperator(query.GetDefaultOperator()),
+		token.WithFields(dquery.QueryStringFields...),
+	)
+	root, err := parser.Parse(query.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query_string: %w", err)
+	}
+
+	c := s.compiler()
+	cl, err := c.queryString(root)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.StringType, c.query(cl), baseOpts)
+}
+
+// SearchField implements storage.FtsSearcher interface
+func (s *Searcher) SearchField(ctx context.Context, query *dquery.Match, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing pg_textsearch match search",
+		"query", query.Query,
+		"field", query.Field,
+		"operator", query.GetOperator(),
+		"fuzziness", query.Fuzziness,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := s.compiler()
+	cl, err := c.match(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.MatchType, c.query(cl), baseOpts)
+}
+
+// SearchFields implements storage.FtsSearcher interface
+func (s *Searcher) SearchFields(ctx context.Context, query *dquery.MultiMatch, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing pg_textsearch multi_match search",
+		"query", query.Query,
+		"fields", query.Fields,
+		"operator", query.GetOperator(),
+		"fuzziness", query.Fuzziness,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := s.compiler()
+	cl, err := c.multiMatch(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.MultiMatchType, c.query(cl), baseOpts)
+}
+
+// SearchPhrase implements storage.FtsSearcher interface
+func (s *Searcher) SearchPhrase(ctx context.Context, query *dquery.Phrase, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing pg_textsearch phrase search",
+		"query", query.Query,
+		"fields", query.Fields,
+		"slop", query.GetSlop(),
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := s.compiler()
+	cl, err := c.phrase(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.PhraseType, c.query(cl), baseOpts)
+}
+
+// SearchBoolean implements storage.FtsSearcher interface
+func (s *Searcher) SearchBoolean(ctx context.Context, query *dquery.Boolean, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing pg_textsearch boolean search",
+		"expression", query.Expression,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := s.compiler()
+	cl, err := c.boolean(query.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.BooleanType, c.query(cl), baseOpts)
+}
+
+// SearchBool implements storage.FtsSearcher interface
+func (s *Searcher) SearchBool(ctx context.Context, query *dquery.Bool, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing pg_textsearch bool search",
+		"must", len(query.Must),
+		"should", len(query.Should),
+		"must_not", len(query.MustNot),
+		"filter", len(query.Filter),
+		"minimum_should_match", query.GetMinimumShouldMatch(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := s.compiler()
+	cl, err := c.bool(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.BoolType, c.query(cl), baseOpts)
+}
+
+// execute runs a compiled query with filters and keyset pagination, like the native searcher.
+// Rescoring factors multiply the BM25 score, so max scores, sorting and cursors all see the
+// rescored score. The search runs in a subquery (OFFSET 0 stops the planner from flattening
+// it), so the rank is computed once per row and the keyset applies to its rank column.
+func (s *Searcher) execute(ctx context.Context, kind dquery.Kind, q tigerQuery, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	cursor, size := baseOpts.Cursor, baseOpts.Size
+	rank, args := pg.AppendRescoring(q.rank, q.args, baseOpts.Rescoring, "")
+	where, args := pg.AppendFilterClause(q.where, args, baseOpts.Filters, "")
+
+	slog.Debug("pg_textsearch query components",
+		"kind", kind,
+		"where", where,
+		"rank", rank)
+
+	stats, err := pg.ScoreStats(ctx, s.db, rank, where, args)
+	if err != nil {
+		slog.Error("Failed to fetch global max score", "error", err, "kind", kind)
+		return nil, err
+	}
+	totalRelation, err := s.totalRelation(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Count == 0 {
+		return &storage.SearchResult{
+			TotalRelation: totalRelation,
+			Aggregations:  dquery.EmptyAggregationResults(baseOpts.Aggregations),
+		}, nil
+	}
+	slog.Info("Computed global max score", "max_score", stats.Max, "total_matches", stats.Count, "total_relation", totalRelation)
+
+	aggregations, err := pg.RunAggregations(ctx, s.db, baseOpts.Aggregations, where, args)
+	if err != nil {
+		slog.Error("Failed to compute aggregations", "error", err, "kind", kind)
+		return nil, err
+	}
+
+	pageArgs := append([]any{}, args...)
+	keyset := ""
+	if cursor != nil {
+		clause, keysetArgs := pg.BuildKeysetClause(baseOpts.Sort, cursor, "hits.rank", "hits", len(pageArgs)+1)
+		keyset = "WHERE " + clause
+		pageArgs = append(pageArgs, keysetArgs...)
+	}
+	pageArgs = append(pageArgs, size+1)
+
+	searchSQL := fmt.Sprintf(`
+			SELECT hits.*
+			FROM (
+				SELECT
+					id, title, subtitle, content, author, description, url, language, created_at, metadata,
+					%s as rank
+				FROM articles
+				WHERE %s
+				OFFSET 0
+			) hits
+			%s
+			ORDER BY %s
+			LIMIT $%d
+		`, rank, where, keyset, pg.BuildOrderBy(baseOpts.Sort, "hits.rank", "hits"), len(pageArgs))
+
+	// ts_headline highlights the query words with the English configuration of the index
+	highlight := baseOpts.Highlight
+	if highlight != nil && len(q.words) > 0 {
+		pageArgs = append(pageArgs, strings.Join(q.words, " | "))
+		tsquery := pg.TsQuery(pg.ToTsQuery, dquery.LanguageEnglish, fmt.Sprintf("$%d", len(pageArgs)))
+		pageArgs = append(pageArgs, pg.BuildHeadlineOptions(highlight))
+		headlines := pg.BuildHeadlineColumns(highlight, dquery.LanguageEnglish, tsquery, "page", len(pageArgs))
+		searchSQL = fmt.Sprintf(`
+			SELECT page.*, %s
+			FROM (%s) page
+			ORDER BY %s
+		`, strings.Join(headlines, ", "), searchSQL, pg.BuildOrderBy(baseOpts.Sort, "page.rank", "page"))
+	} else {
+		highlight = nil
+	}
+
+	rows, err := s.db.Query(ctx, searchSQL, pageArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute %s search query: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var articles []dto.ArticleSearchResult
+	var rawScores []float64
+
+	for rows.Next() {
+		var headlines []string
+		if highlight != nil {
+			headlines = make([]string, len(highlight.Fields))
+		}
+		article, rawScore, err := pg.ScanSearchHit(rows, headlines)
+		if err != nil {
+			return nil, err
+		}
+
+		searchResult := dto.ArticleSearchResult{
+			Article: article,
+			Score:   utils.RoundFloat64(rawScore, dquery.ScoreDecimalPlaces),
+		}
+		if highlight != nil {
+			searchResult.Highlight = pg.ParseHeadlines(highlight, headlines)
+		}
+		storage.ExplainRescoring(baseOpts.Rescoring, &searchResult, rawScore)
+
+		articles = append(articles, searchResult)
+		rawScores = append(rawScores, rawScore)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	slog.Info("pg_textsearch search results fetched",
+		"kind", kind,
+		"total_page_matches", len(articles),
+		"global_max_score", stats.Max)
+
+	if len(articles) == 0 {
+		return &storage.SearchResult{TotalMatches: stats.Count, TotalRelation: totalRelation, Aggregations: aggregations}, nil
+	}
+
+	hasMore := len(articles) > size
+	if hasMore {
+		articles = articles[:size]
+		rawScores = rawScores[:size]
+	}
+
+	offset := storage.PageOffset(cursor)
+	storage.NormalizeScores(baseOpts.Normalization, articles, rawScores, stats, offset)
+
+	if baseOpts.Explain {
+		// pg_textsearch exposes the BM25 score of a document, not its breakdown
+		for i := range articles {
+			storage.ExplainHit(baseOpts.Rescoring, &articles[i], rawScores[i], dquery.Explanation{
+				Description: "BM25 score of the query words on search_text (pg_textsearch <@>)",
+			})
+		}
+	}
+
+	var nextCursor *dquery.Cursor
+	if hasMore {
+		nextCursor = storage.NextCursor(baseOpts.Sort, articles[len(articles)-1].Article, rawScores[len(rawScores)-1])
+		nextCursor.Offset = offset + int64(len(articles))
+	}
+
+	return &storage.SearchResult{
+		Hits:          articles,
+		NextCursor:    nextCursor,
+		HasMore:       hasMore,
+		MaxScore:      utils.RoundFloat64(stats.Max, dquery.ScoreDecimalPlaces),
+		PageMaxScore:  utils.RoundFloat64(slices.Max(rawScores), dquery.ScoreDecimalPlaces),
+		TotalMatches:  stats.Count,
+		TotalRelation: totalRelation,
+		Aggregations:  aggregations,
+	}, nil
+}
+
+// totalRelation reports a lower bound total when the query is narrowed to bm25 index
+// candidates and the index holds more of them than the limit, so matches may be missed
+func (s *Searcher) totalRelation(ctx context.Context, q tigerQuery) (string, error) {
+	if q.candidates == "" {
+		return "", nil
+	}
+
+	var candidates int
+	countSQL := fmt.Sprintf("SELECT count(*) FROM (%s) candidates", candidatesSQL("$1", s.candidateLimit+1))
+	if err := s.db.QueryRow(ctx, countSQL, q.candidates).Scan(&candidates); err != nil {
+		return "", fmt.Errorf("failed to count bm25 candidates: %w", err)
+	}
+	if candidates > s.candidateLimit {
+		return storage.TotalRelationGte, nil
+	}
+	return "", nil
+}
+
+// Compile-time interface assertions
+var _ storage.FtsSearcher = (*Searcher)(nil)
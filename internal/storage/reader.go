@@ -2,11 +2,45 @@ package storage
 
 import (
 	"context"
+	"errors"
 
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
 	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
 	"github.com/google/uuid"
 )
 
+// ErrArticleNotFound is returned when an operation targets an article id that does not exist
+var ErrArticleNotFound = errors.New("article not found")
+
 type Reader interface {
+	// GetByIDs returns the stored articles for the given ids.
+	// Missing ids are simply absent from the result; order is not guaranteed.
 	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]document.Article, error)
 }
+
+// SimilarityStrategy names how related articles were found
+type SimilarityStrategy string
+
+const (
+	// SimilarityVector: kNN over the stored embedding of the source article
+	SimilarityVector SimilarityStrategy = "vector"
+
+	// SimilarityLexical: term overlap with the source article, used when it has no embedding
+	// ES: more_like_this query
+	// PG: OR-tsquery over the most frequent lexemes of its search_vector
+	SimilarityLexical SimilarityStrategy = "lexical"
+)
+
+type SimilarResult struct {
+	Hits     []dto.ArticleSearchResult `json:"hits"`
+	Strategy SimilarityStrategy        `json:"strategy"`
+}
+
+// SimilarSearcher finds articles related to a stored article ("more like this")
+type SimilarSearcher interface {
+	// SearchSimilar returns up to baseOpts.Size articles related to id, excluding id itself.
+	// Filters are honoured; cursors are not (single page, like kNN search).
+	// Returns ErrArticleNotFound when id does not exist.
+	SearchSimilar(ctx context.Context, id uuid.UUID, baseOpts *query.BaseOptions) (*SimilarResult, error)
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
 	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
 )
 
+// TotalRelationGte marks a TotalMatches that counts at least that many matches
+const TotalRelationGte = "gte"
+
 // SearchResult represents search results with cursor-based pagination
 // Contains types objects - no encoding/decoding at this layer
 type SearchResult struct {
@@ -16,12 +21,99 @@ type SearchResult struct {
 	MaxScore     float64                   `json:"max_score"`
 	PageMaxScore float64                   `json:"page_max_score,omitempty"`
 	TotalMatches int64                     `json:"total_matches,omitempty"`
+	// TotalRelation is TotalRelationGte when TotalMatches is a lower bound because the backend
+	// stopped counting; empty when TotalMatches is exact
+	TotalRelation string `json:"total_relation,omitempty"`
+	// Aggregations: facet results keyed by aggregation name, computed over the full match set
+	Aggregations map[string]query.AggregationResult `json:"aggregations,omitempty"`
+	// Expansions: indexed terms that fuzzy query terms were expanded to, when the backend reports them
+	Expansions []query.TermExpansion `json:"expansions,omitempty"`
+}
+
+// NextCursor builds the cursor after the last hit of a page: its raw score, ID and
+// its value of every field sort key. Values are read from the article itself, so the
+// backends agree on them: a missing publication date is the zero time, a missing source "".
+func NextCursor(sort query.Sort, last dto.Article, rawScore float64) *query.Cursor {
+	c := &query.Cursor{Score: rawScore, ID: last.ID, Sort: sort}
+	for _, k := range sort.FieldKeys() {
+		switch k.Field {
+		case query.SortPublishedAt:
+			c.Values = append(c.Values, query.TimeSortValue(last.Metadata.PublishedAt))
+		case query.SortCreatedAt:
+			c.Values = append(c.Values, query.TimeSortValue(last.CreatedAt))
+		case query.SortSourceName:
+			c.Values = append(c.Values, query.KeywordSortValue(last.Metadata.SourceName))
+		}
+	}
+	return c
+}
+
+// NormalizeScores sets the normalized score of every hit with the requested normalization.
+// stats describe the raw scores of the whole match set and offset counts the hits ranked
+// before the page, so rank normalization continues across pages.
+func NormalizeScores(spec ranking.Spec, hits []dto.ArticleSearchResult, rawScores []float64, stats ranking.Stats, offset int64) {
+	n := spec.Normalizer(stats)
+	for i := range hits {
+		hits[i].ScoreNormalized = utils.RoundFloat64(n.Normalize(rawScores[i], offset+int64(i)+1), query.ScoreDecimalPlaces)
+	}
+}
+
+// PageOffset is the number of hits ranked before the page the cursor continues
+func PageOffset(c *query.Cursor) int64 {
+	if c == nil {
+		return 0
+	}
+	return c.Offset
+}
+
+// ExplainRescoring attaches the rescoring factors of a hit when the breakdown was requested.
+// Factors are computed from the article with the formulas both backends score with.
+func ExplainRescoring(r *query.Rescoring, hit *dto.ArticleSearchResult, rawScore float64) {
+	if r == nil || !r.Breakdown {
+		return
+	}
+	b := rescoringBreakdown(r, hit, rawScore)
+
+	round := func(v float64) float64 { return utils.RoundFloat64(v, query.ScoreDecimalPlaces) }
+	b.Text, b.Total = round(b.Text), round(b.Total)
+	for _, factor := range []*float64{b.Recency, b.Source} {
+		if factor != nil {
+			*factor = round(*factor)
+		}
+	}
+	for field, factor := range b.FieldValues {
+		b.FieldValues[field] = round(factor)
+	}
+	hit.ScoreBreakdown = b
+}
+
+// ExplainHit attaches a score explanation to a hit for backends that explain the text score
+// only. text explains the score before rescoring; its value is set here, and rescored hits
+// are explained as the product of text and the rescoring factors.
+func ExplainHit(r *query.Rescoring, hit *dto.ArticleSearchResult, rawScore float64, text query.Explanation) {
+	e := text
+	e.Value = rawScore
+	if r != nil {
+		b := rescoringBreakdown(r, hit, rawScore)
+		e.Value = b.Text
+		e = b.Rescored(e)
+	}
+	e.Round(query.ScoreDecimalPlaces)
+	hit.Explanation = &e
+}
+
+func rescoringBreakdown(r *query.Rescoring, hit *dto.ArticleSearchResult, rawScore float64) *query.ScoreBreakdown {
+	return r.Explain(rawScore, hit.Metadata.PublishedAt, hit.Metadata.SourceName, map[query.ScoreField]float64{
+		query.ScoreFieldLanguageConfidence: hit.Metadata.LanguageConfidence,
+	})
 }
 
+// VectorSearchResult holds the nearest articles; hits score the cosine similarity of the
+// article to the query, on every backend
 type VectorSearchResult struct {
-	Hits       []dto.Article `json:"hits"`
-	NextCursor *query.Cursor `json:"-"`
-	HasMore    bool          `json:"has_more"`
+	Hits       []dto.ArticleSearchResult `json:"hits"`
+	NextCursor *query.Cursor             `json:"-"`
+	HasMore    bool                      `json:"has_more"`
 }
 
 // FtsSearcher is the full text API interface
@@ -40,6 +132,10 @@ type FtsSearcher interface {
 	SearchPhrase(ctx context.Context, query *query.Phrase, baseOpts *query.BaseOptions) (*SearchResult, error)
 	// SearchBoolean performs complex boolean queries combining multiple conditions
 	SearchBoolean(ctx context.Context, query *query.Boolean, baseOpts *query.BaseOptions) (*SearchResult, error)
+	// SearchBool performs a compound bool query (must/should/must_not/filter clauses)
+	// Elasticsearch: Uses a native bool query
+	// PostgreSQL: Composes the clause predicates in WHERE and sums the clause ranks
+	SearchBool(ctx context.Context, query *query.Bool, baseOpts *query.BaseOptions) (*SearchResult, error)
 }
 
 type EmbedResult struct {
@@ -54,6 +150,41 @@ type SemanticSearcher interface {
 	SearchSemantic(ctx context.Context, query *query.Semantic, baseOpts *query.BaseOptions) (*VectorSearchResult, error)
 }
 
+// MultiSearchItem is one full-text search of a batch
+type MultiSearchItem struct {
+	Query   *query.Base
+	Options *query.BaseOptions
+}
+
+// MultiSearchResult is the outcome of one search of a batch: its result or its error
+type MultiSearchResult struct {
+	Result *SearchResult
+	Err    error
+}
+
+// MultiSearcher runs a batch of full-text searches in one round trip
+// Elasticsearch: _msearch
+// Results are returned in item order; an error is returned only when the whole batch failed.
+type MultiSearcher interface {
+	MultiSearch(ctx context.Context, items []MultiSearchItem) ([]MultiSearchResult, error)
+}
+
+// ExportBatchSize is the number of hits an Exporter fetches per round trip
+const ExportBatchSize = 1000
+
+// ExportFunc receives the hits of an export one at a time, in ranking order.
+// An error stops the export and is returned by Export.
+type ExportFunc func(hit dto.ArticleSearchResult) error
+
+// Exporter streams every hit of a full-text query from one consistent snapshot, so hits
+// indexed or deleted during the export neither appear nor shift the pages.
+// baseOpts.Size is the batch size: memory is bounded by one batch, whatever the match count.
+// Elasticsearch: point in time paged with search_after
+// PostgreSQL: server-side cursor in a REPEATABLE READ, READ ONLY transaction
+type Exporter interface {
+	Export(ctx context.Context, query *query.Base, baseOpts *query.BaseOptions, fn ExportFunc) error
+}
+
 // HybridSearcher combines lexical FTS with vector similarity via RRF.
 type HybridSearcher interface {
 	SearchHybrid(ctx context.Context, query *query.Hybrid, baseOpts *query.BaseOptions) (*SearchResult, error)
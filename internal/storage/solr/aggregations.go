@@ -0,0 +1,140 @@
+package solr
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// scoreStatsFacet names the facet computing the score statistics of the match set,
+// requested only by normalizations that need the score distribution
+const scoreStatsFacet = "_score_stats"
+
+// buildFacets compiles aggregations to a JSON Facet API request (json.facet): one terms
+// facet per terms aggregation, ordered by count desc then value asc like Elasticsearch, and
+// the score statistics when requested. Date histograms have no Solr counterpart aligned to
+// calendar weeks and quarters, so they are rejected. Returns nil when no facet is needed.
+func buildFacets(aggs []dquery.Aggregation, scoreStats bool) (map[string]any, error) {
+	if len(aggs) == 0 && !scoreStats {
+		return nil, nil
+	}
+
+	facets := make(map[string]any, len(aggs)+1)
+	for _, agg := range aggs {
+		if agg.Kind != dquery.TermsAggregation {
+			return nil, fmt.Errorf("aggregation %s: %s aggregations are not supported by Solr", agg.Name, agg.Kind)
+		}
+		facets[agg.Name] = map[string]any{
+			"type":  "terms",
+			"field": filterFields[dquery.FilterField(agg.Field)],
+			"limit": agg.Size,
+			"sort":  "count desc",
+		}
+	}
+	if scoreStats {
+		// query($q) is the score of the main query, rescoring included
+		facets[scoreStatsFacet] = map[string]any{
+			"type":  "query",
+			"q":     "*:*",
+			"facet": map[string]string{"min": "min(query($q))", "max": "max(query($q))", "avg": "avg(query($q))", "sumsq": "sumsq(query($q))"},
+		}
+	}
+	return facets, nil
+}
+
+// scoreStats is the facet response of the score statistics
+type scoreStats struct {
+	Count int64    `json:"count"`
+	Min   *float64 `json:"min"`
+	Max   *float64 `json:"max"`
+	Avg   *float64 `json:"avg"`
+	SumSq *float64 `json:"sumsq"`
+}
+
+// mapScoreStats reads the score statistics of the match set. Without the score stats
+// facet, only the size and max score of the match set are known.
+func mapScoreStats(facets map[string]json.RawMessage, total int64, maxScore float64) (ranking.Stats, error) {
+	stats := ranking.Stats{Count: total, Max: maxScore, Min: maxScore, Mean: maxScore}
+	raw, ok := facets[scoreStatsFacet]
+	if !ok {
+		return stats, nil
+	}
+
+	var parsed scoreStats
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return ranking.Stats{}, fmt.Errorf("failed to decode score stats: %w", err)
+	}
+	for _, v := range []struct {
+		dst *float64
+		src *float64
+	}{{&stats.Min, parsed.Min}, {&stats.Max, parsed.Max}, {&stats.Mean, parsed.Avg}} {
+		if v.src != nil {
+			*v.dst = *v.src
+		}
+	}
+	if parsed.SumSq != nil && parsed.Count > 0 {
+		// population variance: E[x²] - E[x]²
+		stats.StdDev = math.Sqrt(math.Max(*parsed.SumSq/float64(parsed.Count)-stats.Mean*stats.Mean, 0))
+	}
+	return stats, nil
+}
+
+// facetBuckets is the response of a terms facet
+type facetBuckets struct {
+	Buckets []struct {
+		Val   json.RawMessage `json:"val"`
+		Count int64           `json:"count"`
+	} `json:"buckets"`
+}
+
+// mapAggregations converts terms facet responses into backend-neutral buckets
+func mapAggregations(aggs []dquery.Aggregation, facets map[string]json.RawMessage) (map[string]dquery.AggregationResult, error) {
+	if len(aggs) == 0 {
+		return nil, nil
+	}
+
+	results := dquery.EmptyAggregationResults(aggs)
+	for _, agg := range aggs {
+		raw, ok := facets[agg.Name]
+		if !ok {
+			continue
+		}
+
+		var parsed facetBuckets
+		if err := json.Unmarshal(raw, &parsed); err != nil {
+			return nil, fmt.Errorf("failed to decode aggregation %s: %w", agg.Name, err)
+		}
+
+		buckets := make([]dquery.Bucket, 0, len(parsed.Buckets))
+		for _, b := range parsed.Buckets {
+			key, err := bucketKey(b.Val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bucket key in aggregation %s: %w", agg.Name, err)
+			}
+			buckets = append(buckets, dquery.Bucket{Key: key, DocCount: b.Count})
+		}
+		results[agg.Name] = dquery.AggregationResult{Buckets: buckets}
+	}
+
+	return results, nil
+}
+
+// bucketKey renders a bucket value: strings as they are, numbers in their shortest form
+func bucketKey(raw json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return "", err
+	}
+	if i, err := n.Int64(); err == nil {
+		return strconv.FormatInt(i, 10), nil
+	}
+	return n.String(), nil
+}
@@ -0,0 +1,118 @@
+package solr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type ClientConfig struct {
+	// URL is the base URL of the Solr node, including the /solr context (http://localhost:8983/solr)
+	URL        string
+	Collection string
+	Username   string
+	Password   string
+}
+
+// client calls the HTTP API of one collection
+type client struct {
+	http    *http.Client
+	baseURL string
+	config  ClientConfig
+}
+
+func newClient(config ClientConfig) (*client, error) {
+	if config.URL == "" || config.Collection == "" {
+		return nil, fmt.Errorf("solr configuration is incomplete: URL or collection is missing")
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid solr URL %q: %w", config.URL, err)
+	}
+	return &client{
+		http:    &http.Client{Timeout: 60 * time.Second},
+		baseURL: strings.TrimRight(config.URL, "/") + "/" + url.PathEscape(config.Collection),
+		config:  config,
+	}, nil
+}
+
+// errorResponse is the error body of a failed Solr request
+type errorResponse struct {
+	Error struct {
+		Msg  string `json:"msg"`
+		Code int    `json:"code"`
+	} `json:"error"`
+}
+
+// get sends a GET request to a handler of the collection (e.g. /schema/fields) and decodes
+// the JSON response into out
+func (c *client) get(ctx context.Context, handler string, params url.Values, out any) error {
+	return c.do(ctx, http.MethodGet, handler, params, nil, "", out)
+}
+
+// postJSON sends a JSON body to a handler of the collection (e.g. /update)
+func (c *client) postJSON(ctx context.Context, handler string, params url.Values, body any, out any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal solr request: %w", err)
+	}
+	return c.do(ctx, http.MethodPost, handler, params, bytes.NewReader(b), "application/json", out)
+}
+
+// postForm sends request parameters as a form body, so long queries and vectors do not
+// hit URL length limits
+func (c *client) postForm(ctx context.Context, handler string, params url.Values, out any) error {
+	return c.do(ctx, http.MethodPost, handler, nil, strings.NewReader(params.Encode()), "application/x-www-form-urlencoded", out)
+}
+
+func (c *client) do(ctx context.Context, method, handler string, params url.Values, body io.Reader, contentType string, out any) error {
+	u := c.baseURL + handler
+	q := url.Values{"wt": {"json"}}
+	for k, v := range params {
+		q[k] = v
+	}
+	u += "?" + q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return fmt.Errorf("failed to create solr request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.config.Username != "" && c.config.Password != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("solr request %s %s failed: %w", method, handler, err)
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read solr response: %w", err)
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		var e errorResponse
+		if json.Unmarshal(data, &e) == nil && e.Error.Msg != "" {
+			return fmt.Errorf("solr %s %s: %s (status %d)", method, handler, e.Error.Msg, res.StatusCode)
+		}
+		return fmt.Errorf("solr %s %s: status %d", method, handler, res.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode solr response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package solr
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// request is a request received by the Solr stand-in: its handler, its query and form
+// parameters merged, and its raw body when it is not a form
+type request struct {
+	method  string
+	handler string
+	params  url.Values
+	body    []byte
+}
+
+// fakeSolr is an httptest stand-in of the HTTP API of one collection. Responses are keyed
+// by handler (/select, /schema/fields, ...); unknown handlers answer {}.
+type fakeSolr struct {
+	t         *testing.T
+	mu        sync.Mutex
+	requests  []request
+	responses map[string]any
+}
+
+func newFakeSolr(t *testing.T, responses map[string]any) (*fakeSolr, ClientConfig) {
+	t.Helper()
+
+	if responses == nil {
+		responses = make(map[string]any)
+	}
+	f := &fakeSolr{t: t, responses: responses}
+	srv := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(srv.Close)
+
+	return f, ClientConfig{URL: srv.URL + "/solr", Collection: "articles"}
+}
+
+func (f *fakeSolr) serve(w http.ResponseWriter, r *http.Request) {
+	handler, ok := strings.CutPrefix(r.URL.Path, "/solr/articles")
+	if !ok {
+		http.Error(w, `{"error":{"msg":"no such collection","code":404}}`, http.StatusNotFound)
+		return
+	}
+
+	req := request{method: r.Method, handler: handler, params: r.URL.Query()}
+	if r.Header.Get("Content-Type") == "application/x-www-form-urlencoded" {
+		if err := r.ParseForm(); err != nil {
+			f.t.Errorf("parse form: %v", err)
+		}
+		for k, v := range r.PostForm {
+			req.params[k] = v
+		}
+	} else {
+		req.body, _ = io.ReadAll(r.Body)
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	res, ok := f.responses[handler]
+	f.mu.Unlock()
+
+	if !ok {
+		res = map[string]any{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		f.t.Errorf("encode response: %v", err)
+	}
+}
+
+// respond sets the response of a handler
+func (f *fakeSolr) respond(handler string, res any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[handler] = res
+}
+
+// received returns the requests sent to a handler, in order
+func (f *fakeSolr) received(handler string) []request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var matched []request
+	for _, r := range f.requests {
+		if r.handler == handler {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+func TestClient_ErrorResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"msg":"undefined field title_xx","code":400}}`))
+	}))
+	defer srv.Close()
+
+	c, err := newClient(ClientConfig{URL: srv.URL, Collection: "articles"})
+	if err != nil {
+		t.Fatalf("newClient: %v", err)
+	}
+
+	err = c.get(context.Background(), "/select", url.Values{"q": {"*:*"}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "undefined field title_xx") {
+		t.Fatalf("error = %v, want the Solr error message", err)
+	}
+}
@@ -0,0 +1,187 @@
+package solr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+)
+
+// EmbeddingDims is the vector width produced by the embedding model and declared on the
+// knn_vector field type. Must match the loaded embeddings file.
+const EmbeddingDims = 1024
+
+// ArticleDocument represents the document structure for Solr. Dates are sent in UTC,
+// the only form pdate fields accept.
+type ArticleDocument struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Subtitle    string    `json:"subtitle"`
+	Description string    `json:"description"`
+	Content     string    `json:"content"`
+	Author      string    `json:"author"`
+	URL         string    `json:"url"`
+	Language    string    `json:"language"`
+	CreatedAt   time.Time `json:"created_at"`
+	SourceId    string    `json:"source_id"`
+	SourceName  string    `json:"source_name"`
+	PublishedAt time.Time `json:"published_at"`
+	Category    string    `json:"category"`
+	ImportedAt  time.Time `json:"imported_at"`
+	IndexedAt   time.Time `json:"indexed_at"`
+
+	DetectedLanguage   string  `json:"detected_language,omitempty"`
+	LanguageConfidence float64 `json:"language_confidence,omitempty"`
+	LanguageMismatch   bool    `json:"language_mismatch,omitempty"`
+}
+
+// storedFields are the stored fields of an article, the field list (fl) of every search
+var storedFields = []string{
+	"id", "title", "subtitle", "description", "content", "author", "url", "language",
+	"created_at", "source_id", "source_name", "published_at", "category", "imported_at",
+	"detected_language", "language_confidence", "language_mismatch",
+}
+
+// languageSuffixes maps query languages to the suffix of the per-language copies of
+// analyzed text fields (e.g. serbian → title_sr).
+var languageSuffixes = map[dquery.Language]string{
+	dquery.LanguageEnglish: "en",
+	dquery.LanguageSerbian: "sr",
+}
+
+// languageTextFields are the text fields copied to per-language fields
+var languageTextFields = []string{"title", "subtitle", "description", "content"}
+
+// languageField returns the per-language copy of an analyzed text field
+// (title + serbian → title_sr). Other fields are returned unchanged.
+func languageField(field string, lang dquery.Language) string {
+	suffix, ok := languageSuffixes[lang]
+	if !ok {
+		return field
+	}
+	for _, f := range languageTextFields {
+		if f == field {
+			return field + "_" + suffix
+		}
+	}
+	return field
+}
+
+// baseField strips a per-language suffix from a field name (title_sr → title)
+func baseField(field string) string {
+	for _, suffix := range languageSuffixes {
+		if base, ok := strings.CutSuffix(field, "_"+suffix); ok {
+			return base
+		}
+	}
+	return field
+}
+
+func (b *SchemaBuilder) mapToSolrDocument(article document.Article) ArticleDocument {
+	if article.ID == uuid.Nil {
+		article.ID = uuid.New()
+	}
+	return ArticleDocument{
+		ID:          article.ID.String(),
+		Title:       article.Title,
+		Subtitle:    article.Subtitle,
+		Description: article.Description,
+		Content:     article.Content,
+		Author:      article.Author,
+		URL:         article.URL,
+		Language:    b.normalizeLanguage(article.Language),
+		CreatedAt:   article.CreatedAt.UTC(),
+		SourceId:    article.Metadata.SourceId,
+		SourceName:  article.Metadata.SourceName,
+		PublishedAt: article.Metadata.PublishedAt.UTC(),
+		Category:    article.Metadata.Category,
+		ImportedAt:  article.Metadata.ImportedAt.UTC(),
+		IndexedAt:   time.Now().UTC(),
+
+		DetectedLanguage:   article.Metadata.DetectedLanguage,
+		LanguageConfidence: article.Metadata.LanguageConfidence,
+		LanguageMismatch:   article.Metadata.LanguageMismatch,
+	}
+}
+
+// normalizeLanguage maps an article language to a supported query language
+// ("sr" → serbian, "" → default).
+func (b *SchemaBuilder) normalizeLanguage(lang string) string {
+	switch l := dquery.Language(strings.ToLower(strings.TrimSpace(lang))); l {
+	case "":
+		return b.defaultLanguage
+	case "sr", "srpski":
+		return string(dquery.LanguageSerbian)
+	case "en":
+		return string(dquery.LanguageEnglish)
+	default:
+		if dquery.SupportedLanguages[l] {
+			return string(l)
+		}
+		return lang
+	}
+}
+
+// toDomain maps a stored Solr document back to the domain article
+func (d ArticleDocument) toDomain() (document.Article, error) {
+	id, err := uuid.Parse(d.ID)
+	if err != nil {
+		return document.Article{}, fmt.Errorf("parse document id %q: %w", d.ID, err)
+	}
+	return document.Article{
+		ID:          id,
+		Title:       d.Title,
+		Subtitle:    d.Subtitle,
+		Content:     d.Content,
+		Author:      d.Author,
+		Description: d.Description,
+		Language:    d.Language,
+		CreatedAt:   d.CreatedAt,
+		URL:         d.URL,
+		Metadata: document.ArticleMetadata{
+			SourceId:    d.SourceId,
+			SourceName:  d.SourceName,
+			PublishedAt: d.PublishedAt,
+			Category:    d.Category,
+			ImportedAt:  d.ImportedAt,
+
+			DetectedLanguage:   d.DetectedLanguage,
+			LanguageConfidence: d.LanguageConfidence,
+			LanguageMismatch:   d.LanguageMismatch,
+		},
+	}, nil
+}
+
+// toDTO maps a stored Solr document to the article of a search hit
+func (d ArticleDocument) toDTO() (dto.Article, error) {
+	id, err := uuid.Parse(d.ID)
+	if err != nil {
+		return dto.Article{}, fmt.Errorf("parse document id %q: %w", d.ID, err)
+	}
+	return dto.Article{
+		ID:          id,
+		Title:       d.Title,
+		Subtitle:    d.Subtitle,
+		Content:     d.Content,
+		Author:      d.Author,
+		Description: d.Description,
+		URL:         d.URL,
+		Language:    d.Language,
+		CreatedAt:   d.CreatedAt,
+		Metadata: dto.ArticleMetadata{
+			SourceId:    d.SourceId,
+			SourceName:  d.SourceName,
+			PublishedAt: d.PublishedAt,
+			Category:    d.Category,
+			ImportedAt:  d.ImportedAt,
+
+			DetectedLanguage:   d.DetectedLanguage,
+			LanguageConfidence: d.LanguageConfidence,
+			LanguageMismatch:   d.LanguageMismatch,
+		},
+	}, nil
+}
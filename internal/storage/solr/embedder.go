@@ -0,0 +1,88 @@
+package solr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/google/uuid"
+)
+
+// Embedder is the Solr implementation of storage.EmbedIndexer. Like Elasticsearch, it
+// stores the vector as a dense vector field on the existing article document (id == article
+// UUID). Writes are atomic updates, so they are idempotent and re-runnable; _version_ 1
+// requires the article to exist and failOnVersionConflicts=false skips updates of missing
+// articles (orphans) instead of failing the batch.
+type Embedder struct {
+	client       *client
+	batchSize    int
+	commitWithin int64
+}
+
+func NewEmbedder(ctx context.Context, config ClientConfig) (*Embedder, error) {
+	c, err := newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solr client: %w", err)
+	}
+
+	if err := ensureSchema(ctx, c, NewSchemaBuilder()); err != nil {
+		return nil, fmt.Errorf("failed to ensure schema exists: %w", err)
+	}
+	return &Embedder{
+		client:       c,
+		batchSize:    defaultBatchSize,
+		commitWithin: defaultCommitWithin.Milliseconds(),
+	}, nil
+}
+
+// embeddingUpdate is the atomic update of a document:
+// {"id": ..., "embedding": {"set": [...]}, "embedding_model": {"set": ...}, "_version_": 1}
+type embeddingUpdate struct {
+	ID        string            `json:"id"`
+	Embedding map[string]any    `json:"embedding"`
+	Model     map[string]string `json:"embedding_model"`
+	Version   int64             `json:"_version_"`
+}
+
+func (e *Embedder) Save(ctx context.Context, vec *embedding.Vec) (uuid.UUID, error) {
+	if err := e.SaveBulk(ctx, []*embedding.Vec{vec}); err != nil {
+		return uuid.Nil, err
+	}
+	return vec.ID, nil
+}
+
+func (e *Embedder) SaveBulk(ctx context.Context, vecs []*embedding.Vec) error {
+	if len(vecs) == 0 {
+		return nil
+	}
+
+	params := url.Values{
+		"commitWithin":           {strconv.FormatInt(e.commitWithin, 10)},
+		"failOnVersionConflicts": {"false"},
+	}
+	for start := 0; start < len(vecs); start += e.batchSize {
+		end := min(start+e.batchSize, len(vecs))
+
+		updates := make([]embeddingUpdate, 0, end-start)
+		for _, vec := range vecs[start:end] {
+			updates = append(updates, embeddingUpdate{
+				ID:        vec.ID.String(),
+				Embedding: map[string]any{"set": vec.Embedding},
+				Model:     map[string]string{"set": vec.Model},
+				Version:   1,
+			})
+		}
+		if err := e.client.postJSON(ctx, "/update", params, updates, nil); err != nil {
+			return fmt.Errorf("failed to update %d out of %d embeddings: %w", len(vecs)-start, len(vecs), err)
+		}
+	}
+
+	slog.Info("Bulk embedding update completed", "total", len(vecs), "collection", e.client.config.Collection)
+	return nil
+}
+
+var _ storage.EmbedIndexer = (*Embedder)(nil)
@@ -0,0 +1,51 @@
+package solr
+
+import (
+	"regexp"
+	"strings"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// weightDescription matches Lucene term and phrase weight leaves,
+// e.g. `weight(title_en:climat in 12) [SchemaSimilarity], result of:` or
+// `weight(content_en:"climat chang"~2 in 7) [SchemaSimilarity], result of:`
+var weightDescription = regexp.MustCompile(`^weight\(([\w.]+):("[^"]*"|\S+?)(?:~\d+)? in \d+\)`)
+
+// explanation is a node of a structured explanation (debug.explain.structured=true)
+type explanation struct {
+	Match       bool          `json:"match"`
+	Value       float64       `json:"value"`
+	Description string        `json:"description"`
+	Details     []explanation `json:"details"`
+}
+
+// mapExplanation normalizes the Lucene explanation of a hit into an explanation tree,
+// like the Elasticsearch backend: sums left with a single scoring detail collapse and
+// weight(...) leaves name their field (without the language suffix) and terms. Filter
+// queries run apart from q, so they never appear in the explanation.
+func mapExplanation(e explanation) *dquery.Explanation {
+	node := normalizeExplanation(e)
+	node.Round(dquery.ScoreDecimalPlaces)
+	return &node
+}
+
+func normalizeExplanation(e explanation) dquery.Explanation {
+	node := dquery.Explanation{Value: e.Value, Description: e.Description}
+	if m := weightDescription.FindStringSubmatch(e.Description); m != nil {
+		node.Field = baseField(m[1])
+		node.Terms = strings.Fields(strings.Trim(m[2], `"`))
+	}
+
+	for _, d := range e.Details {
+		if !d.Match && d.Value == 0 {
+			continue
+		}
+		node.Details = append(node.Details, normalizeExplanation(d))
+	}
+
+	if len(node.Details) == 1 && len(e.Details) > 1 && node.Details[0].Value == node.Value {
+		return node.Details[0]
+	}
+	return node
+}
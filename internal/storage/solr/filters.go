@@ -0,0 +1,80 @@
+package solr
+
+import (
+	"strings"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// solrTime is the form of dates in range queries: UTC, at the millisecond resolution of pdate fields
+const solrTime = "2006-01-02T15:04:05.999Z"
+
+// filterFields maps filter fields to string fields of the schema (see SchemaBuilder.buildFields).
+// Text fields with a string copy are filtered on the copy for exact matching.
+var filterFields = map[dquery.FilterField]string{
+	dquery.FilterLanguage:   "language",
+	dquery.FilterSourceID:   "source_id",
+	dquery.FilterSourceName: "source_name_str",
+	dquery.FilterCategory:   "category",
+	dquery.FilterAuthor:     "author_str",
+}
+
+// buildFilterQueries compiles structured filters into filter queries (fq): ranges on date
+// fields, OR-ed values on string fields and the id restriction. Each constraint is its own
+// filter query, so Solr caches them independently. Returns nil when no filter is set.
+//
+// Example:
+//
+//	published_at >= 2024-01-01, source_id in (bbc, cnn)
+//	→ ["published_at:[\"2024-01-01T00:00:00Z\" TO *]", "source_id:(\"bbc\" OR \"cnn\")"]
+func buildFilterQueries(filters *dquery.Filters) []string {
+	if filters.IsEmpty() {
+		return nil
+	}
+
+	var fq []string
+
+	addRange := func(field string, r *dquery.DateRange) {
+		if r.IsEmpty() {
+			return
+		}
+		gte, lte := "*", "*"
+		if r.Gte != nil {
+			gte = quoteTerm(r.Gte.UTC().Format(solrTime))
+		}
+		if r.Lte != nil {
+			lte = quoteTerm(r.Lte.UTC().Format(solrTime))
+		}
+		fq = append(fq, field+":["+gte+" TO "+lte+"]")
+	}
+	addRange("published_at", filters.PublishedAt)
+	addRange("created_at", filters.CreatedAt)
+
+	for _, field := range filters.TermFields() {
+		fq = append(fq, termsQuery(filterFields[field], filters.Terms[field]))
+	}
+
+	if len(filters.IDs) > 0 {
+		ids := make([]string, 0, len(filters.IDs))
+		for _, id := range filters.IDs {
+			ids = append(ids, id.String())
+		}
+		fq = append(fq, termsQuery("id", ids))
+	}
+
+	return fq
+}
+
+// termsQuery matches any of the values of a string field
+func termsQuery(field string, values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, quoteTerm(v))
+	}
+	return field + ":(" + strings.Join(quoted, " OR ") + ")"
+}
+
+// quoteTerm quotes a value for the standard query parser, escaping quotes and backslashes
+func quoteTerm(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
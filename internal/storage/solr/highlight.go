@@ -0,0 +1,46 @@
+package solr
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// buildHighlight adds the unified highlighter parameters of a highlight request.
+// requireFieldMatch is disabled so requested fields are highlighted even when the query
+// targeted other fields, matching ts_headline behaviour of the PG backend. The per-language
+// copies are not stored, so the stored fields are highlighted with a highlight query (hl.q)
+// of the query words over the same fields. Nothing is added when highlighting is not
+// requested or the query has no words.
+func buildHighlight(params url.Values, h *dquery.Highlight, words []string) {
+	if h == nil || len(words) == 0 {
+		return
+	}
+
+	params.Set("hl", "true")
+	params.Set("hl.method", "unified")
+	params.Set("hl.fl", strings.Join(h.Fields, ","))
+	params.Set("hl.q", "{!edismax qf='"+strings.Join(h.Fields, " ")+"' mm=1 v=$hlq}")
+	params.Set("hlq", strings.Join(words, " "))
+	params.Set("hl.requireFieldMatch", "false")
+	params.Set("hl.fragsize", strconv.Itoa(h.FragmentSize))
+	params.Set("hl.snippets", strconv.Itoa(h.NumberOfFragments))
+	params.Set("hl.tag.pre", h.PreTag)
+	params.Set("hl.tag.post", h.PostTag)
+}
+
+// mapHighlight drops the fields without fragments; Solr lists every highlighted field
+func mapHighlight(highlight map[string][]string) map[string][]string {
+	result := make(map[string][]string, len(highlight))
+	for field, fragments := range highlight {
+		if len(fragments) > 0 {
+			result[field] = fragments
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
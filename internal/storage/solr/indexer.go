@@ -0,0 +1,141 @@
+package solr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultBatchSize is the number of documents per update request of SaveBulk
+	defaultBatchSize = 500
+	// defaultCommitWithin bounds the delay before indexed documents become searchable.
+	// Solr merges the commits requested within the window, so batches do not commit one by one.
+	defaultCommitWithin = 10 * time.Second
+)
+
+// Indexer writes articles through the JSON update handler (/update). Updates ask Solr to
+// commit within commitWithin instead of committing each request; Refresh commits explicitly.
+type Indexer struct {
+	client       *client
+	builder      *SchemaBuilder
+	batchSize    int
+	commitWithin time.Duration
+}
+
+type IndexerOption func(*Indexer)
+
+// WithBatchSize sets the number of documents per update request
+func WithBatchSize(n int) IndexerOption {
+	return func(i *Indexer) {
+		if n > 0 {
+			i.batchSize = n
+		}
+	}
+}
+
+// WithCommitWithin sets the maximum delay before indexed documents become searchable
+func WithCommitWithin(d time.Duration) IndexerOption {
+	return func(i *Indexer) {
+		if d > 0 {
+			i.commitWithin = d
+		}
+	}
+}
+
+func NewIndexer(ctx context.Context, config ClientConfig, opts ...IndexerOption) (*Indexer, error) {
+	c, err := newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solr client: %w", err)
+	}
+	indexer := &Indexer{
+		client:       c,
+		builder:      NewSchemaBuilder(),
+		batchSize:    defaultBatchSize,
+		commitWithin: defaultCommitWithin,
+	}
+	for _, opt := range opts {
+		opt(indexer)
+	}
+
+	if err := indexer.EnsureSchema(ctx); err != nil {
+		return nil, fmt.Errorf("failed to ensure schema exists: %w", err)
+	}
+
+	return indexer, nil
+}
+
+// EnsureSchema adds the article fields to the collection schema when they are missing
+func (i *Indexer) EnsureSchema(ctx context.Context) error {
+	return ensureSchema(ctx, i.client, i.builder)
+}
+
+func (i *Indexer) Save(ctx context.Context, article document.Article) (uuid.UUID, error) {
+	doc := i.builder.mapToSolrDocument(article)
+
+	if err := i.update(ctx, []ArticleDocument{doc}); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to index document: %w", err)
+	}
+
+	articleID, err := uuid.Parse(doc.ID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to parse article ID: %w", err)
+	}
+
+	slog.Info("document indexed successfully", "id", doc.ID, "collection", i.client.config.Collection)
+	return articleID, nil
+}
+
+// SaveBulk indexes the articles in batches of batchSize documents. A failed batch stops
+// the run: Solr rejects a whole update request when one of its documents is invalid.
+func (i *Indexer) SaveBulk(ctx context.Context, articles []document.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	indexed := 0
+	for start := 0; start < len(articles); start += i.batchSize {
+		end := min(start+i.batchSize, len(articles))
+
+		docs := make([]ArticleDocument, 0, end-start)
+		for _, article := range articles[start:end] {
+			docs = append(docs, i.builder.mapToSolrDocument(article))
+		}
+		if err := i.update(ctx, docs); err != nil {
+			return fmt.Errorf("failed to index %d out of %d articles: %w", len(articles)-indexed, len(articles), err)
+		}
+		indexed += len(docs)
+	}
+
+	slog.Info("Bulk indexing completed",
+		"indexed", indexed,
+		"total", len(articles),
+		"collection", i.client.config.Collection)
+
+	return nil
+}
+
+// update sends documents to the JSON update handler, to be committed within commitWithin
+func (i *Indexer) update(ctx context.Context, docs []ArticleDocument) error {
+	params := url.Values{"commitWithin": {strconv.FormatInt(i.commitWithin.Milliseconds(), 10)}}
+	return i.client.postJSON(ctx, "/update", params, docs, nil)
+}
+
+// Refresh commits the pending documents, so a finished ingest run is searchable at once
+func (i *Indexer) Refresh(ctx context.Context) error {
+	if err := i.client.postJSON(ctx, "/update", url.Values{"commit": {"true"}}, map[string]any{}, nil); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// Compile-time interface assertions
+var _ storage.Indexer = (*Indexer)(nil)
+var _ storage.IndexRefresher = (*Indexer)(nil)
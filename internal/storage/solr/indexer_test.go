@@ -0,0 +1,138 @@
+package solr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	"github.com/google/uuid"
+)
+
+// fullSchema answers the schema handlers with every definition of the builder
+func fullSchema() map[string]any {
+	b := NewSchemaBuilder()
+	var types, fields []map[string]string
+	for _, t := range b.buildFieldTypes() {
+		types = append(types, map[string]string{"name": t.Name})
+	}
+	for _, f := range b.buildFields() {
+		fields = append(fields, map[string]string{"name": f.Name})
+	}
+	return map[string]any{
+		"/schema/fieldtypes": map[string]any{"fieldTypes": types},
+		"/schema/fields":     map[string]any{"fields": fields},
+		"/schema/copyfields": map[string]any{"copyFields": b.buildCopyFields()},
+	}
+}
+
+func TestEnsureSchema_AddsMissingDefinitions(t *testing.T) {
+	responses := fullSchema()
+	responses["/schema/fields"] = map[string]any{"fields": []map[string]string{{"name": "id"}, {"name": "title"}}}
+	responses["/schema/copyfields"] = map[string]any{"copyFields": []schemaCopyField{{Source: "author", Dest: "author_str"}}}
+	solr, cfg := newFakeSolr(t, responses)
+
+	if _, err := NewIndexer(context.Background(), cfg); err != nil {
+		t.Fatalf("NewIndexer: %v", err)
+	}
+
+	posts := solr.received("/schema")
+	if len(posts) != 1 {
+		t.Fatalf("schema updates = %d, want 1", len(posts))
+	}
+	var update schemaUpdate
+	if err := json.Unmarshal(posts[0].body, &update); err != nil {
+		t.Fatalf("decode schema update: %v", err)
+	}
+
+	if len(update.AddFieldType) != 0 {
+		t.Errorf("add-field-type = %v, want none (all types exist)", update.AddFieldType)
+	}
+	b := NewSchemaBuilder()
+	if want := len(b.buildFields()) - 1; len(update.AddField) != want {
+		t.Errorf("add-field = %d fields, want %d (all but title)", len(update.AddField), want)
+	}
+	for _, f := range update.AddField {
+		if f.Name == "title" {
+			t.Errorf("existing field title added again")
+		}
+	}
+	if want := len(b.buildCopyFields()) - 1; len(update.AddCopyField) != want {
+		t.Errorf("add-copy-field = %d, want %d", len(update.AddCopyField), want)
+	}
+}
+
+func TestEnsureSchema_UpToDate(t *testing.T) {
+	solr, cfg := newFakeSolr(t, fullSchema())
+
+	if _, err := NewIndexer(context.Background(), cfg); err != nil {
+		t.Fatalf("NewIndexer: %v", err)
+	}
+	if posts := solr.received("/schema"); len(posts) != 0 {
+		t.Errorf("schema updates = %d, want none", len(posts))
+	}
+}
+
+func TestIndexer_SaveBulk_BatchesWithCommitWithin(t *testing.T) {
+	solr, cfg := newFakeSolr(t, fullSchema())
+	indexer, err := NewIndexer(context.Background(), cfg, WithBatchSize(2), WithCommitWithin(5*time.Second))
+	if err != nil {
+		t.Fatalf("NewIndexer: %v", err)
+	}
+
+	published := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	articles := make([]document.Article, 5)
+	for i := range articles {
+		articles[i] = document.Article{
+			ID:       uuid.New(),
+			Title:    "climate",
+			Language: "sr",
+			Metadata: document.ArticleMetadata{SourceName: "BBC", PublishedAt: published},
+		}
+	}
+	if err := indexer.SaveBulk(context.Background(), articles); err != nil {
+		t.Fatalf("SaveBulk: %v", err)
+	}
+
+	updates := solr.received("/update")
+	if len(updates) != 3 {
+		t.Fatalf("update requests = %d, want 3 batches", len(updates))
+	}
+	var total int
+	for _, u := range updates {
+		if got := u.params.Get("commitWithin"); got != "5000" {
+			t.Errorf("commitWithin = %q, want 5000", got)
+		}
+		if u.params.Get("commit") != "" {
+			t.Errorf("batch commits explicitly")
+		}
+		var docs []ArticleDocument
+		if err := json.Unmarshal(u.body, &docs); err != nil {
+			t.Fatalf("decode update: %v", err)
+		}
+		total += len(docs)
+	}
+	if total != len(articles) {
+		t.Errorf("indexed %d documents, want %d", total, len(articles))
+	}
+
+	var first []map[string]any
+	if err := json.Unmarshal(updates[0].body, &first); err != nil {
+		t.Fatalf("decode update: %v", err)
+	}
+	if got := first[0]["published_at"]; got != "2024-03-01T11:00:00Z" {
+		t.Errorf("published_at = %v, want UTC 2024-03-01T11:00:00Z", got)
+	}
+	if got := first[0]["language"]; got != "serbian" {
+		t.Errorf("language = %v, want serbian", got)
+	}
+
+	if err := indexer.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	updates = solr.received("/update")
+	if got := updates[len(updates)-1].params.Get("commit"); got != "true" {
+		t.Errorf("Refresh commit = %q, want true", got)
+	}
+}
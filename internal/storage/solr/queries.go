@@ -0,0 +1,481 @@
+package solr
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+const (
+	// matchAll matches every document; Lucene boolean queries without a positive clause match nothing
+	matchAll = "*:*"
+	// matchNone matches no document, the query of text without words. Lucene drops purely
+	// negative nested queries, so the exclusion needs a positive clause.
+	matchNone = "(*:* -*:*)"
+)
+
+// solrQuery is a compiled full-text query: q, usually a local params query, and the
+// parameters it dereferences ($v1, $v2, ...). words are the words of the scoring clauses,
+// highlighted in the hits.
+type solrQuery struct {
+	q      string
+	params url.Values
+	words  []string
+}
+
+// compiler builds Solr queries. Query text is always bound as a dereferenced parameter and
+// reduced to words (runs of letters and digits), so it cannot inject query syntax; only
+// field names of the schema and numbers are rendered inline.
+type compiler struct {
+	lang   dquery.Language
+	params url.Values
+	words  []string
+}
+
+func newCompiler(lang dquery.Language) *compiler {
+	return &compiler{lang: lang, params: url.Values{}}
+}
+
+func (c *compiler) query(q string) solrQuery {
+	return solrQuery{q: q, params: c.params, words: c.words}
+}
+
+// param binds a value to the next dereferenced parameter
+func (c *compiler) param(v string) string {
+	name := fmt.Sprintf("v%d", len(c.params)+1)
+	c.params.Set(name, v)
+	return "$" + name
+}
+
+// field validates a query field and returns its per-language copy
+func (c *compiler) field(name string, weight float64) (string, error) {
+	if !queryFields[name] {
+		return "", fmt.Errorf("field %q cannot be searched in Solr", name)
+	}
+	return boostedField(languageField(name, c.lang), weight), nil
+}
+
+// queryFields are the text fields of the schema a query may target
+var queryFields = map[string]bool{
+	"title":       true,
+	"subtitle":    true,
+	"description": true,
+	"content":     true,
+	"author":      true,
+}
+
+// edismax renders an edismax query over boosted fields (qf). The tie breaker is 0, so a
+// document scores with its best matching field like an Elasticsearch best_fields query.
+//
+// Example:
+//
+//	["title_en^3", "content_en"], "climate change", mm 100%
+//	→ {!edismax qf='title_en^3 content_en' mm=100% v=$v1}
+func (c *compiler) edismax(qf []string, text string, mm string, extra string) string {
+	local := fmt.Sprintf("{!edismax qf='%s'", strings.Join(qf, " "))
+	if mm != "" {
+		local += " mm=" + mm
+	}
+	if extra != "" {
+		local += " " + extra
+	}
+	return local + " v=" + c.param(text) + "}"
+}
+
+// match compiles a match query: and requires every word (mm=100%). Fuzzy words are edit
+// distance queries (word~N), N following the fuzziness of each word.
+func (c *compiler) match(fieldName, text string, and bool, fuzziness dquery.Fuzziness) (string, error) {
+	f, err := c.field(fieldName, 1)
+	if err != nil {
+		return "", err
+	}
+	return c.terms([]string{f}, text, and, fuzziness, 1), nil
+}
+
+// multiMatch compiles a multi_match query to edismax over the boosted fields
+func (c *compiler) multiMatch(query *dquery.MultiMatch) (string, error) {
+	qf := make([]string, 0, len(query.GetFields()))
+	for _, f := range query.GetFields() {
+		field, err := c.field(f.Name, f.Weight)
+		if err != nil {
+			return "", err
+		}
+		qf = append(qf, field)
+	}
+	return c.terms(qf, query.Query, query.GetOperator().IsAnd(), query.GetFuzziness(), 1), nil
+}
+
+func (c *compiler) terms(qf []string, text string, and bool, fuzziness dquery.Fuzziness, boost float64) string {
+	tokens := c.tokens(text)
+	if len(tokens) == 0 {
+		return matchNone
+	}
+	words := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if distance := fuzziness.MaxEdits(t); fuzziness.IsEnabled() && distance > 0 {
+			t += "~" + strconv.Itoa(distance)
+		}
+		words = append(words, t)
+	}
+	mm := "1"
+	if and {
+		mm = "100%"
+	}
+	return c.edismax(qf, strings.Join(words, " "), mm, boostParam(boost))
+}
+
+// phrase compiles a phrase query: one of the fields (the default fields when none is set)
+// must contain the words in order, at most slop moves apart. The phrase is quoted in q, so
+// its slop is qs; ps is the same slop for the pf phrase boosts, set so both agree.
+//
+// Example:
+//
+//	"climate change" on title and content, slop 2
+//	→ {!edismax qf='title_en content_en' qs=2 ps=2 v=$v1} with $v1 = "climate change"
+func (c *compiler) phrase(query *dquery.Phrase) (string, error) {
+	qf := make([]string, 0, len(query.Fields))
+	for _, f := range query.Fields {
+		field, err := c.field(f, 1)
+		if err != nil {
+			return "", err
+		}
+		qf = append(qf, field)
+	}
+	if len(qf) == 0 {
+		qf = c.defaultFields(dquery.DefaultFieldWeights)
+	}
+	tokens := c.tokens(query.Query)
+	if len(tokens) == 0 {
+		return matchNone, nil
+	}
+	slop := query.GetSlop()
+	return c.edismax(qf, quote(tokens), "", fmt.Sprintf("qs=%d ps=%d", slop, slop)), nil
+}
+
+// bool compiles a compound query to the bool query parser. Lucene applies the Elasticsearch
+// defaults to should clauses (optional next to a must clause, otherwise at least one), so
+// only an explicit minimum_should_match needs translating: 1 requires the should clauses as
+// one must clause, all of them requires each. Filter clauses do not score.
+func (c *compiler) bool(query *dquery.Bool) (string, error) {
+	must, err := c.clauses(query.Must)
+	if err != nil {
+		return "", err
+	}
+	should, err := c.clauses(query.Should)
+	if err != nil {
+		return "", err
+	}
+	n := len(c.words)
+	mustNot, err := c.clauses(query.MustNot)
+	if err != nil {
+		return "", err
+	}
+	c.words = c.words[:n]
+	filter, err := c.clauses(query.Filter)
+	if err != nil {
+		return "", err
+	}
+
+	msm := query.GetMinimumShouldMatch()
+	switch {
+	case msm == 0 || len(must) == 0 && len(filter) == 0 && msm == 1:
+	case msm == len(should):
+		must, should = append(must, should...), nil
+	case msm == 1:
+		must, should = append(must, c.boolQuery(nil, should, nil, nil)), nil
+	default:
+		return "", fmt.Errorf("minimum_should_match %d of %d should clauses is not supported by Solr", msm, len(should))
+	}
+	if len(must) == 0 && len(should) == 0 && len(filter) == 0 {
+		must = []string{matchAll}
+	}
+	return c.boolQuery(must, should, mustNot, filter), nil
+}
+
+func (c *compiler) clauses(clauses []dquery.Clause) ([]string, error) {
+	queries := make([]string, 0, len(clauses))
+	for _, clause := range clauses {
+		var q string
+		var err error
+		switch {
+		case clause.Match != nil:
+			m := clause.Match
+			q, err = c.match(m.Field, m.Query, m.GetOperator().IsAnd(), m.GetFuzziness())
+		case clause.MultiMatch != nil:
+			q, err = c.multiMatch(clause.MultiMatch)
+		case clause.Phrase != nil:
+			q, err = c.phrase(clause.Phrase)
+		case clause.Bool != nil:
+			q, err = c.bool(clause.Bool)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+	return queries, nil
+}
+
+// boolQuery renders a bool query parser query; every clause is a dereferenced parameter
+//
+//	{!bool must=$v1 should=$v2 must_not=$v3 filter=$v4}
+func (c *compiler) boolQuery(must, should, mustNot, filter []string) string {
+	var parts []string
+	for _, occ := range []struct {
+		name    string
+		clauses []string
+	}{{"must", must}, {"should", should}, {"must_not", mustNot}, {"filter", filter}} {
+		for _, q := range occ.clauses {
+			parts = append(parts, occ.name+"="+c.param(q))
+		}
+	}
+	return "{!bool " + strings.Join(parts, " ") + "}"
+}
+
+// queryString compiles a parsed query_string group: + clauses are must, - clauses must_not
+// and the others should. Unscoped terms and phrases are edismax queries over the default
+// fields; the words of one term must all match.
+func (c *compiler) queryString(group *token.Group) (string, error) {
+	var must, should, mustNot []string
+	for _, clause := range group.Clauses {
+		n := len(c.words)
+		q, err := c.queryStringNode(clause.Node)
+		if err != nil {
+			return "", err
+		}
+		switch clause.Occur {
+		case token.Must:
+			must = append(must, q)
+		case token.MustNot:
+			c.words = c.words[:n]
+			mustNot = append(mustNot, q)
+		default:
+			should = append(should, q)
+		}
+	}
+	if len(must) == 0 && len(should) == 0 {
+		must = []string{matchAll}
+	}
+	q := c.boolQuery(must, should, mustNot, nil)
+	if group.Boost != 0 && group.Boost != 1 {
+		q = fmt.Sprintf("{!boost b=%s v=%s}", formatWeight(group.Boost), c.param(q))
+	}
+	return q, nil
+}
+
+func (c *compiler) queryStringNode(node token.Node) (string, error) {
+	switch n := node.(type) {
+	case *token.Group:
+		return c.queryString(n)
+	case *token.Phrase:
+		qf, err := c.scope(n.Field)
+		if err != nil {
+			return "", err
+		}
+		tokens := c.tokens(n.Value)
+		if len(tokens) == 0 {
+			return matchNone, nil
+		}
+		return c.edismax(qf, quote(tokens), "", boostParam(n.Boost)), nil
+	case *token.Term:
+		qf, err := c.scope(n.Field)
+		if err != nil {
+			return "", err
+		}
+		if !n.Prefix {
+			return c.terms(qf, n.Value, true, "", n.Boost), nil
+		}
+		tokens := c.tokens(n.Value)
+		if len(tokens) == 0 {
+			return matchNone, nil
+		}
+		return c.edismax(qf, strings.Join(tokens, " ")+"*", "100%", boostParam(n.Boost)), nil
+	default:
+		return "", fmt.Errorf("unsupported query_string node %T", node)
+	}
+}
+
+// scope returns the query fields of a query_string leaf: its field, or the default fields
+// with their default weights when unscoped
+func (c *compiler) scope(field string) ([]string, error) {
+	if field != "" {
+		f, err := c.field(field, 1)
+		if err != nil {
+			return nil, err
+		}
+		return []string{f}, nil
+	}
+	return c.defaultFields(dquery.DefaultFieldWeights), nil
+}
+
+func (c *compiler) defaultFields(weights map[string]float64) []string {
+	fields := make([]string, 0, len(dquery.DefaultFields))
+	for _, f := range dquery.DefaultFields {
+		fields = append(fields, boostedField(languageField(f, c.lang), weights[f]))
+	}
+	return fields
+}
+
+// boolean compiles a parsed boolean expression to the standard (lucene) query parser:
+// AND, OR and NOT keep their operators, terms and phrases match the best default field
+// with its recommended weight and NEAR/N is a sloppy phrase of both operands in either
+// order.
+//
+// Example:
+//
+//	climate AND NOT coal
+//	→ {!lucene v=$v1} with $v1 = ((title_en:climate^3 OR description_en:climate^2 OR content_en:climate) AND NOT (...coal...))
+func (c *compiler) boolean(node token.Node) (string, error) {
+	expr, err := c.booleanNode(node)
+	if err != nil {
+		return "", err
+	}
+	return "{!lucene v=" + c.param(expr) + "}", nil
+}
+
+func (c *compiler) booleanNode(node token.Node) (string, error) {
+	switch n := node.(type) {
+	case *token.Term:
+		return c.booleanLeaf(c.tokens(n.Value)), nil
+	case *token.Phrase:
+		return c.booleanLeaf(c.tokens(n.Value)), nil
+	case *token.And:
+		operands := make([]string, 0, len(n.Operands))
+		for _, operand := range n.Operands {
+			if not, ok := operand.(*token.Not); ok {
+				words := len(c.words)
+				expr, err := c.booleanNode(not.Operand)
+				if err != nil {
+					return "", err
+				}
+				c.words = c.words[:words]
+				operands = append(operands, "NOT "+expr)
+				continue
+			}
+			expr, err := c.booleanNode(operand)
+			if err != nil {
+				return "", err
+			}
+			operands = append(operands, expr)
+		}
+		if strings.HasPrefix(operands[0], "NOT ") {
+			operands = append([]string{matchAll}, operands...)
+		}
+		return "(" + strings.Join(operands, " AND ") + ")", nil
+	case *token.Or:
+		operands := make([]string, 0, len(n.Operands))
+		for _, operand := range n.Operands {
+			expr, err := c.booleanNode(operand)
+			if err != nil {
+				return "", err
+			}
+			operands = append(operands, expr)
+		}
+		return "(" + strings.Join(operands, " OR ") + ")", nil
+	case *token.Not:
+		words := len(c.words)
+		expr, err := c.booleanNode(n.Operand)
+		if err != nil {
+			return "", err
+		}
+		c.words = c.words[:words]
+		return "(" + matchAll + " NOT " + expr + ")", nil
+	case *token.Near:
+		left, right := c.tokens(nearOperand(n.Left)), c.tokens(nearOperand(n.Right))
+		forward := quote(append(append([]string{}, left...), right...))
+		backward := quote(append(append([]string{}, right...), left...))
+		slop := fmt.Sprintf("~%d", n.Distance)
+		return c.booleanFields(func(field string) []string {
+			return []string{field + ":" + forward + slop, field + ":" + backward + slop}
+		}), nil
+	default:
+		return "", fmt.Errorf("unsupported boolean node %T", node)
+	}
+}
+
+// booleanLeaf matches words on the default fields: a single word as a term, several as a phrase
+func (c *compiler) booleanLeaf(tokens []string) string {
+	if len(tokens) == 0 {
+		return matchNone
+	}
+	value := tokens[0]
+	if len(tokens) > 1 {
+		value = quote(tokens)
+	}
+	return c.booleanFields(func(field string) []string {
+		return []string{field + ":" + value}
+	})
+}
+
+// booleanFields joins the clauses of every default field with OR, boosted with the
+// recommended field weight
+func (c *compiler) booleanFields(clauses func(field string) []string) string {
+	var parts []string
+	for _, f := range dquery.DefaultFields {
+		boost := ""
+		if w := dquery.RecommendedFieldWeights[f]; w != 1 {
+			boost = "^" + formatWeight(w)
+		}
+		for _, clause := range clauses(languageField(f, c.lang)) {
+			parts = append(parts, clause+boost)
+		}
+	}
+	return "(" + strings.Join(parts, " OR ") + ")"
+}
+
+func nearOperand(node token.Node) string {
+	switch n := node.(type) {
+	case *token.Term:
+		return n.Value
+	case *token.Phrase:
+		return n.Value
+	default:
+		return ""
+	}
+}
+
+// tokens splits text into words and records them for highlighting
+func (c *compiler) tokens(text string) []string {
+	tokens := tokenize(text)
+	c.words = append(c.words, tokens...)
+	return tokens
+}
+
+// tokenize splits text into lowercased runs of letters and digits, none of which are
+// special characters of the query parsers
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func quote(tokens []string) string {
+	return `"` + strings.Join(tokens, " ") + `"`
+}
+
+// boostedField renders a field with an optional boost ("title_en^3")
+func boostedField(field string, weight float64) string {
+	if weight == 0 || weight == 1 {
+		return field
+	}
+	return field + "^" + formatWeight(weight)
+}
+
+// boostParam renders the multiplicative edismax boost of a leaf, unless it is neutral
+func boostParam(boost float64) string {
+	if boost == 0 || boost == 1 {
+		return ""
+	}
+	return "boost=" + formatWeight(boost)
+}
+
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'f', -1, 64)
+}
@@ -0,0 +1,281 @@
+package solr
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// expand resolves the dereferenced parameters of a compiled query, so tests read the whole
+// query at once: {!bool must=$v1} with v1=x → {!bool must=(x)}
+func expand(q string, params url.Values) string {
+	for i := len(params); i > 0; i-- {
+		name := "$v" + strconv.Itoa(i)
+		if strings.Contains(q, name) {
+			q = strings.ReplaceAll(q, name, "("+expand(params.Get(name[1:]), params)+")")
+		}
+	}
+	return q
+}
+
+func TestCompiler_Leaves(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(c *compiler) (string, error)
+		want string
+	}{
+		{
+			name: "match or",
+			fn: func(c *compiler) (string, error) {
+				return c.match("title", "Climate Change!", false, "")
+			},
+			want: "{!edismax qf='title_en' mm=1 v=(climate change)}",
+		},
+		{
+			name: "match and with fuzziness",
+			fn: func(c *compiler) (string, error) {
+				return c.match("content", "climate ab", true, dquery.FuzzinessAuto)
+			},
+			want: "{!edismax qf='content_en' mm=100% v=(climate~2 ab)}",
+		},
+		{
+			name: "multi_match with weights",
+			fn: func(c *compiler) (string, error) {
+				return c.multiMatch(&dquery.MultiMatch{
+					Query:  "climate",
+					Fields: []dquery.MultiMatchField{{Name: "title", Weight: 3}, {Name: "content", Weight: 1}},
+				})
+			},
+			want: "{!edismax qf='title_en^3 content_en' mm=100% v=(climate)}",
+		},
+		{
+			name: "phrase with slop",
+			fn: func(c *compiler) (string, error) {
+				return c.phrase(&dquery.Phrase{Query: "climate change", Fields: []string{"title"}, Slop: 2})
+			},
+			want: `{!edismax qf='title_en' qs=2 ps=2 v=("climate change")}`,
+		},
+		{
+			name: "phrase without fields",
+			fn: func(c *compiler) (string, error) {
+				return c.phrase(&dquery.Phrase{Query: "climate change"})
+			},
+			want: `{!edismax qf='title_en description_en content_en' qs=0 ps=0 v=("climate change")}`,
+		},
+		{
+			name: "no words",
+			fn: func(c *compiler) (string, error) {
+				return c.match("title", "!!", false, "")
+			},
+			want: matchNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCompiler(dquery.LanguageEnglish)
+			q, err := tt.fn(c)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := expand(q, c.params); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompiler_SerbianFields(t *testing.T) {
+	c := newCompiler(dquery.LanguageSerbian)
+	q, err := c.match("title", "Đoković", false, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := expand(q, c.params), "{!edismax qf='title_sr' mm=1 v=(đoković)}"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestCompiler_UnknownField(t *testing.T) {
+	c := newCompiler(dquery.LanguageEnglish)
+	if _, err := c.match("url", "climate", false, ""); err == nil {
+		t.Fatal("expected an error for a field outside the schema text fields")
+	}
+}
+
+func TestCompiler_Bool(t *testing.T) {
+	match := func(field, text string) dquery.Clause {
+		return dquery.Clause{Match: &dquery.Match{Field: field, Query: text}}
+	}
+	leaf := func(qf, text string) string {
+		return "({!edismax qf='" + qf + "' mm=100% v=(" + text + ")})"
+	}
+
+	tests := []struct {
+		name    string
+		query   *dquery.Bool
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "must, should and must_not",
+			query: &dquery.Bool{
+				Must:    []dquery.Clause{match("title", "climate")},
+				Should:  []dquery.Clause{match("content", "summit")},
+				MustNot: []dquery.Clause{match("author", "smith")},
+			},
+			want: "{!bool must=" + leaf("title_en", "climate") + " should=" + leaf("content_en", "summit") + " must_not=" + leaf("author", "smith") + "}",
+		},
+		{
+			name: "should only",
+			query: &dquery.Bool{
+				Should: []dquery.Clause{match("title", "climate"), match("content", "summit")},
+			},
+			want: "{!bool should=" + leaf("title_en", "climate") + " should=" + leaf("content_en", "summit") + "}",
+		},
+		{
+			name: "one should required next to must",
+			query: &dquery.Bool{
+				Must:               []dquery.Clause{match("title", "climate")},
+				Should:             []dquery.Clause{match("content", "summit"), match("content", "treaty")},
+				MinimumShouldMatch: 1,
+			},
+			want: "{!bool must=" + leaf("title_en", "climate") + " must=({!bool should=" + leaf("content_en", "summit") + " should=" + leaf("content_en", "treaty") + "})}",
+		},
+		{
+			name: "every should required",
+			query: &dquery.Bool{
+				Should:             []dquery.Clause{match("title", "climate"), match("content", "summit")},
+				MinimumShouldMatch: 2,
+			},
+			want: "{!bool must=" + leaf("title_en", "climate") + " must=" + leaf("content_en", "summit") + "}",
+		},
+		{
+			name: "must_not only",
+			query: &dquery.Bool{
+				MustNot: []dquery.Clause{match("title", "climate")},
+			},
+			want: "{!bool must=(*:*) must_not=" + leaf("title_en", "climate") + "}",
+		},
+		{
+			name: "filter clauses do not score",
+			query: &dquery.Bool{
+				Filter: []dquery.Clause{match("title", "climate")},
+			},
+			want: "{!bool filter=" + leaf("title_en", "climate") + "}",
+		},
+		{
+			name: "unsupported minimum_should_match",
+			query: &dquery.Bool{
+				Should:             []dquery.Clause{match("title", "a"), match("title", "b"), match("title", "c")},
+				MinimumShouldMatch: 2,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCompiler(dquery.LanguageEnglish)
+			q, err := c.bool(tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := expand(q, c.params); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompiler_QueryString(t *testing.T) {
+	parser := token.NewQueryStringParser(
+		token.WithDefaultOperator(operator.Or),
+		token.WithFields(dquery.QueryStringFields...),
+	)
+	root, err := parser.Parse(`title:climat* +"paris agreement" -coal`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	c := newCompiler(dquery.LanguageEnglish)
+	q, err := c.queryString(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defaults := "qf='title_en description_en content_en'"
+	want := "{!bool must=({!edismax " + defaults + ` v=("paris agreement")})` +
+		" should=({!edismax qf='title_en' mm=100% v=(climat*)})" +
+		" must_not=({!edismax " + defaults + " mm=100% v=(coal)})}"
+	if got := expand(q, c.params); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	if want := []string{"climat", "paris", "agreement"}; !reflect.DeepEqual(c.words, want) {
+		t.Errorf("words = %v, want %v (excluded words are not highlighted)", c.words, want)
+	}
+}
+
+func TestCompiler_Boolean(t *testing.T) {
+	fields := func(value string) string {
+		return "(title_en:" + value + "^3 OR description_en:" + value + "^2 OR content_en:" + value + ")"
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+	}{
+		{
+			name:       "and not",
+			expression: "climate AND NOT coal",
+			want:       "(" + fields("climate") + " AND NOT " + fields("coal") + ")",
+		},
+		{
+			name:       "or with phrase",
+			expression: `"climate change" OR warming`,
+			want:       "(" + fields(`"climate change"`) + " OR " + fields("warming") + ")",
+		},
+		{
+			name:       "leading not",
+			expression: "NOT coal",
+			want:       "(*:* NOT " + fields("coal") + ")",
+		},
+		{
+			name:       "near in either order",
+			expression: "climate NEAR/3 summit",
+			want: `(title_en:"climate summit"~3^3 OR title_en:"summit climate"~3^3 OR ` +
+				`description_en:"climate summit"~3^2 OR description_en:"summit climate"~3^2 OR ` +
+				`content_en:"climate summit"~3 OR content_en:"summit climate"~3)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := token.NewBooleanParser().Parse(tt.expression)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			c := newCompiler(dquery.LanguageEnglish)
+			q, err := c.boolean(root)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, want := expand(q, c.params), "{!lucene v=("+tt.want+")}"; got != want {
+				t.Errorf("got  %s\nwant %s", got, want)
+			}
+		})
+	}
+}
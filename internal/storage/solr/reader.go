@@ -0,0 +1,63 @@
+package solr
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+	"github.com/google/uuid"
+)
+
+// Reader is the Solr implementation of storage.Reader. Articles are read with the real-time
+// get handler (/get), which also returns documents that are not committed yet.
+type Reader struct {
+	client *client
+}
+
+func NewReader(config ClientConfig) (*Reader, error) {
+	c, err := newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solr client: %w", err)
+	}
+	return &Reader{client: c}, nil
+}
+
+func (r *Reader) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]document.Article, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	idStrs := make([]string, len(ids))
+	for i, id := range ids {
+		idStrs[i] = id.String()
+	}
+
+	params := url.Values{
+		"ids": {strings.Join(idStrs, ",")},
+		"fl":  {strings.Join(storedFields, ",")},
+	}
+	var res struct {
+		Response struct {
+			Docs []ArticleDocument `json:"docs"`
+		} `json:"response"`
+	}
+	if err := r.client.get(ctx, "/get", params, &res); err != nil {
+		return nil, fmt.Errorf("get articles by ids: %w", err)
+	}
+
+	articles := make([]document.Article, 0, len(res.Response.Docs))
+	for _, doc := range res.Response.Docs {
+		article, err := doc.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, article)
+	}
+
+	return articles, nil
+}
+
+var _ storage.Reader = (*Reader)(nil)
@@ -0,0 +1,68 @@
+package solr
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// scoreFields maps score fields to numeric fields of the schema (see SchemaBuilder.buildFields).
+var scoreFields = map[dquery.ScoreField]string{
+	dquery.ScoreFieldLanguageConfidence: "language_confidence",
+}
+
+// withRescoring wraps the query in a boost query multiplying the query score by the product
+// of the rescoring functions, computed with the Elasticsearch formulas (see dquery.Rescoring):
+//
+//	recency: decay^((max(0, |ms(published_at) - origin| - offset) / scale)^exponent)
+//	source:  if(termfreq(source_name_str, source), weight, 1), one per weighted source
+//	field:   modifier(factor × def(field, missing)); log1p is log(1 + x), in base 10
+//
+// The query and the source names are dereferenced parameters (rq, rs1, ...). A nil
+// rescoring returns the query unchanged.
+func withRescoring(q string, params url.Values, r *dquery.Rescoring) string {
+	if r == nil {
+		return q
+	}
+
+	var functions []string
+	if d := r.Recency; d != nil {
+		distance := fmt.Sprintf("max(0,sub(abs(sub(ms(published_at),%d)),%d))", d.Origin.UnixMilli(), d.Offset.Milliseconds())
+		exponent := fmt.Sprintf("div(%s,%d)", distance, d.Scale.Milliseconds())
+		if d.Exponent() != 1 {
+			exponent = fmt.Sprintf("pow(%s,%s)", exponent, formatWeight(d.Exponent()))
+		}
+		functions = append(functions, fmt.Sprintf("pow(%s,%s)", formatWeight(d.Decay), exponent))
+	}
+
+	sources := make([]string, 0, len(r.SourceWeights))
+	for source := range r.SourceWeights {
+		sources = append(sources, source)
+	}
+	slices.Sort(sources)
+	for i, source := range sources {
+		name := "rs" + strconv.Itoa(i+1)
+		params.Set(name, source)
+		functions = append(functions, fmt.Sprintf("if(termfreq(%s,$%s),%s,1)",
+			filterFields[dquery.FilterSourceName], name, formatWeight(r.SourceWeights[source])))
+	}
+
+	for _, f := range r.FieldValueFactors {
+		value := fmt.Sprintf("product(%s,def(%s,%s))", formatWeight(f.Factor), scoreFields[f.Field], formatWeight(f.Missing))
+		switch f.Modifier {
+		case dquery.ModifierLog1p:
+			value = fmt.Sprintf("log(sum(1,%s))", value)
+		case dquery.ModifierSqrt:
+			value = fmt.Sprintf("sqrt(%s)", value)
+		}
+		functions = append(functions, value)
+	}
+
+	params.Set("rq", q)
+	params.Set("rb", "product("+strings.Join(functions, ",")+")")
+	return "{!boost b=$rb v=$rq}"
+}
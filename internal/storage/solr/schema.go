@@ -0,0 +1,259 @@
+package solr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/document"
+)
+
+const (
+	multilingualType = "text_multilingual"
+	englishType      = "text_english"
+	serbianType      = "text_serbian"
+	vectorType       = "knn_vector"
+)
+
+// languageTypes lists the per-language field suffixes with their field types
+var languageTypes = []struct {
+	suffix    string
+	fieldType string
+}{
+	{"en", englishType},
+	{"sr", serbianType},
+}
+
+// schemaFieldType is a field type of the Schema API (add-field-type)
+type schemaFieldType struct {
+	Name     string          `json:"name"`
+	Class    string          `json:"class"`
+	Analyzer *schemaAnalyzer `json:"analyzer,omitempty"`
+	// VectorDimension and SimilarityFunction configure solr.DenseVectorField
+	VectorDimension    int    `json:"vectorDimension,omitempty"`
+	SimilarityFunction string `json:"similarityFunction,omitempty"`
+}
+
+type schemaAnalyzer struct {
+	Tokenizer schemaFactory   `json:"tokenizer"`
+	Filters   []schemaFactory `json:"filters,omitempty"`
+}
+
+// schemaFactory is a tokenizer or token filter with its attributes
+type schemaFactory map[string]string
+
+// schemaField is a field of the Schema API (add-field)
+type schemaField struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Stored    bool   `json:"stored"`
+	Indexed   bool   `json:"indexed"`
+	DocValues bool   `json:"docValues"`
+}
+
+// schemaCopyField copies a field to another at index time (add-copy-field)
+type schemaCopyField struct {
+	Source string `json:"source"`
+	Dest   string `json:"dest"`
+}
+
+// SchemaBuilder is the Solr counterpart of es.IndexBuilder: it maps articles to documents
+// and defines the field types, fields and copy fields of the collection schema. The schema
+// extends the _default configset, which provides the string, pdate, pfloat and boolean types.
+type SchemaBuilder struct {
+	defaultLanguage string
+}
+
+func NewSchemaBuilder() *SchemaBuilder {
+	return &SchemaBuilder{
+		defaultLanguage: document.ArticleDefaultLanguage,
+	}
+}
+
+// buildFieldTypes defines the analyzed text types and the vector type.
+//
+//	text_multilingual: standard → lowercase
+//	text_english:      standard → lowercase → stop (lang/stopwords_en.txt) → porter stem
+//	text_serbian:      standard → lowercase → serbian normalization (bald haircut)
+//
+// The bald serbian normalization transliterates Cyrillic to Latin and folds diacritics,
+// so "Ђоковић", "Đoković" and "Djokovic" produce the same term. The vectors are
+// L2-normalised, so cosine similarity is the right metric.
+func (b *SchemaBuilder) buildFieldTypes() []schemaFieldType {
+	tokenizer := schemaFactory{"class": "solr.StandardTokenizerFactory"}
+	lowercase := schemaFactory{"class": "solr.LowerCaseFilterFactory"}
+	return []schemaFieldType{
+		{
+			Name:     multilingualType,
+			Class:    "solr.TextField",
+			Analyzer: &schemaAnalyzer{Tokenizer: tokenizer, Filters: []schemaFactory{lowercase}},
+		},
+		{
+			Name:  englishType,
+			Class: "solr.TextField",
+			Analyzer: &schemaAnalyzer{Tokenizer: tokenizer, Filters: []schemaFactory{
+				lowercase,
+				{"class": "solr.StopFilterFactory", "words": "lang/stopwords_en.txt", "ignoreCase": "true"},
+				{"class": "solr.PorterStemFilterFactory"},
+			}},
+		},
+		{
+			Name:  serbianType,
+			Class: "solr.TextField",
+			Analyzer: &schemaAnalyzer{Tokenizer: tokenizer, Filters: []schemaFactory{
+				lowercase,
+				{"class": "solr.SerbianNormalizationFilterFactory", "haircut": "bald"},
+			}},
+		},
+		{
+			Name:               vectorType,
+			Class:              "solr.DenseVectorField",
+			VectorDimension:    EmbeddingDims,
+			SimilarityFunction: "cosine",
+		},
+	}
+}
+
+// buildFields defines the article fields. Text fields keep the language-neutral analysis;
+// their per-language copies (title_en, title_sr) are indexed only. author and source_name
+// have string copies (author_str) for filtering, sorting and facets.
+func (b *SchemaBuilder) buildFields() []schemaField {
+	text := func(name string) schemaField {
+		return schemaField{Name: name, Type: multilingualType, Stored: true, Indexed: true}
+	}
+	keyword := func(name string, stored bool) schemaField {
+		return schemaField{Name: name, Type: "string", Stored: stored, Indexed: true, DocValues: true}
+	}
+	date := func(name string) schemaField {
+		return schemaField{Name: name, Type: "pdate", Stored: true, Indexed: true, DocValues: true}
+	}
+
+	fields := []schemaField{
+		text("title"),
+		text("subtitle"),
+		text("description"),
+		text("content"),
+		text("author"),
+		keyword("author_str", false),
+		keyword("url", true),
+		keyword("language", true),
+		date("created_at"),
+		keyword("source_id", true),
+		text("source_name"),
+		keyword("source_name_str", false),
+		date("published_at"),
+		keyword("category", true),
+		date("imported_at"),
+		date("indexed_at"),
+		// Ingest language detection (see ingest.WithLanguageDetector)
+		keyword("detected_language", true),
+		{Name: "language_confidence", Type: "pfloat", Stored: true, Indexed: true, DocValues: true},
+		{Name: "language_mismatch", Type: "boolean", Stored: true, Indexed: true, DocValues: true},
+		// Document embedding lives on the article doc (see embedder.go). Stored, so atomic
+		// updates of the embedding keep it.
+		{Name: "embedding", Type: vectorType, Stored: true, Indexed: true},
+		keyword("embedding_model", true),
+	}
+	for _, field := range languageTextFields {
+		for _, lt := range languageTypes {
+			fields = append(fields, schemaField{Name: field + "_" + lt.suffix, Type: lt.fieldType, Indexed: true})
+		}
+	}
+	return fields
+}
+
+func (b *SchemaBuilder) buildCopyFields() []schemaCopyField {
+	copies := []schemaCopyField{
+		{Source: "author", Dest: "author_str"},
+		{Source: "source_name", Dest: "source_name_str"},
+	}
+	for _, field := range languageTextFields {
+		for _, lt := range languageTypes {
+			copies = append(copies, schemaCopyField{Source: field, Dest: field + "_" + lt.suffix})
+		}
+	}
+	return copies
+}
+
+// schemaUpdate is a Schema API request; commands run in order, so types precede the
+// fields using them
+type schemaUpdate struct {
+	AddFieldType []schemaFieldType `json:"add-field-type,omitempty"`
+	AddField     []schemaField     `json:"add-field,omitempty"`
+	AddCopyField []schemaCopyField `json:"add-copy-field,omitempty"`
+}
+
+func (u schemaUpdate) isEmpty() bool {
+	return len(u.AddFieldType) == 0 && len(u.AddField) == 0 && len(u.AddCopyField) == 0
+}
+
+// ensureSchema adds the field types, fields and copy fields of the builder that the
+// collection schema lacks. Existing definitions are left unchanged, so it is safe to run
+// on every start.
+func ensureSchema(ctx context.Context, c *client, b *SchemaBuilder) error {
+	var types struct {
+		FieldTypes []struct {
+			Name string `json:"name"`
+		} `json:"fieldTypes"`
+	}
+	if err := c.get(ctx, "/schema/fieldtypes", nil, &types); err != nil {
+		return fmt.Errorf("failed to read schema field types: %w", err)
+	}
+	var fields struct {
+		Fields []struct {
+			Name string `json:"name"`
+		} `json:"fields"`
+	}
+	if err := c.get(ctx, "/schema/fields", nil, &fields); err != nil {
+		return fmt.Errorf("failed to read schema fields: %w", err)
+	}
+	var copies struct {
+		CopyFields []schemaCopyField `json:"copyFields"`
+	}
+	if err := c.get(ctx, "/schema/copyfields", nil, &copies); err != nil {
+		return fmt.Errorf("failed to read schema copy fields: %w", err)
+	}
+
+	existing := make(map[string]bool)
+	for _, t := range types.FieldTypes {
+		existing["type:"+t.Name] = true
+	}
+	for _, f := range fields.Fields {
+		existing["field:"+f.Name] = true
+	}
+	for _, cf := range copies.CopyFields {
+		existing["copy:"+cf.Source+">"+cf.Dest] = true
+	}
+
+	var update schemaUpdate
+	for _, t := range b.buildFieldTypes() {
+		if !existing["type:"+t.Name] {
+			update.AddFieldType = append(update.AddFieldType, t)
+		}
+	}
+	for _, f := range b.buildFields() {
+		if !existing["field:"+f.Name] {
+			update.AddField = append(update.AddField, f)
+		}
+	}
+	for _, cf := range b.buildCopyFields() {
+		if !existing["copy:"+cf.Source+">"+cf.Dest] {
+			update.AddCopyField = append(update.AddCopyField, cf)
+		}
+	}
+
+	if update.isEmpty() {
+		slog.Info("Solr schema is up to date", "collection", c.config.Collection)
+		return nil
+	}
+	if err := c.postJSON(ctx, "/schema", nil, update, nil); err != nil {
+		return fmt.Errorf("failed to update schema: %w", err)
+	}
+
+	slog.Info("Solr schema updated",
+		"collection", c.config.Collection,
+		"field_types", len(update.AddFieldType),
+		"fields", len(update.AddField),
+		"copy_fields", len(update.AddCopyField))
+	return nil
+}
@@ -0,0 +1,327 @@
+package solr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+)
+
+// Searcher runs full-text queries through the select handler of the collection. Queries
+// compile to edismax (match, multi_match, phrase, query_string leaves), the bool query
+// parser (bool, query_string groups) and the standard parser (boolean expressions) over the
+// per-language copies of the text fields, and pages are traversed with cursorMark.
+type Searcher struct {
+	client *client
+}
+
+func NewSearcher(config ClientConfig) (*Searcher, error) {
+	c, err := newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solr client: %w", err)
+	}
+	return &Searcher{client: c}, nil
+}
+
+// SearchStringQuery implements storage.FtsSearcher interface
+func (s *Searcher) SearchStringQuery(ctx context.Context, query *dquery.String, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing solr query_string search",
+		"query", query.Query,
+		"language", query.GetLanguage(),
+		"operator", query.GetDefaultOperator(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	parser := token.NewQueryStringParser(
+		token.WithDefaultOperator(query.GetDefaultOperator()),
+		token.WithFields(dquery.QueryStringFields...),
+	)
+	root, err := parser.Parse(query.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query_string: %w", err)
+	}
+
+	c := newCompiler(query.GetLanguage())
+	q, err := c.queryString(root)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.StringType, c.query(q), baseOpts)
+}
+
+// SearchField implements storage.FtsSearcher interface
+func (s *Searcher) SearchField(ctx context.Context, query *dquery.Match, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing solr match search",
+		"query", query.Query,
+		"field", query.Field,
+		"operator", query.GetOperator(),
+		"fuzziness", query.Fuzziness,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := newCompiler(query.GetLanguage())
+	q, err := c.match(query.Field, query.Query, query.GetOperator().IsAnd(), query.GetFuzziness())
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.MatchType, c.query(q), baseOpts)
+}
+
+// SearchFields implements storage.FtsSearcher interface
+func (s *Searcher) SearchFields(ctx context.Context, query *dquery.MultiMatch, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing solr multi_match search",
+		"query", query.Query,
+		"fields", query.Fields,
+		"operator", query.GetOperator(),
+		"fuzziness", query.Fuzziness,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := newCompiler(query.GetLanguage())
+	q, err := c.multiMatch(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.MultiMatchType, c.query(q), baseOpts)
+}
+
+// SearchPhrase implements storage.FtsSearcher interface
+func (s *Searcher) SearchPhrase(ctx context.Context, query *dquery.Phrase, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing solr phrase search",
+		"query", query.Query,
+		"fields", query.Fields,
+		"slop", query.GetSlop(),
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := newCompiler(query.GetLanguage())
+	q, err := c.phrase(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.PhraseType, c.query(q), baseOpts)
+}
+
+// SearchBoolean implements storage.FtsSearcher interface
+func (s *Searcher) SearchBoolean(ctx context.Context, query *dquery.Boolean, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing solr boolean search",
+		"expression", query.Expression,
+		"language", query.GetLanguage(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	root, err := token.NewBooleanParser().Parse(query.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse boolean expression: %w", err)
+	}
+
+	c := newCompiler(query.GetLanguage())
+	q, err := c.boolean(root)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.BooleanType, c.query(q), baseOpts)
+}
+
+// SearchBool implements storage.FtsSearcher interface
+func (s *Searcher) SearchBool(ctx context.Context, query *dquery.Bool, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	slog.Info("Executing solr bool search",
+		"must", len(query.Must),
+		"should", len(query.Should),
+		"must_not", len(query.MustNot),
+		"filter", len(query.Filter),
+		"minimum_should_match", query.GetMinimumShouldMatch(),
+		"has_cursor", baseOpts.Cursor != nil,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", baseOpts.Size)
+
+	c := newCompiler(query.GetLanguage())
+	q, err := c.bool(query)
+	if err != nil {
+		return nil, err
+	}
+	return s.execute(ctx, dquery.BoolType, c.query(q), baseOpts)
+}
+
+// searchDoc is a returned document with its score
+type searchDoc struct {
+	ArticleDocument
+	Score float64 `json:"score"`
+}
+
+// searchResponse is the part of a select response the searcher reads
+type searchResponse struct {
+	Response struct {
+		NumFound int64       `json:"numFound"`
+		MaxScore *float64    `json:"maxScore"`
+		Docs     []searchDoc `json:"docs"`
+	} `json:"response"`
+	NextCursorMark string                         `json:"nextCursorMark"`
+	Highlighting   map[string]map[string][]string `json:"highlighting"`
+	Facets         map[string]json.RawMessage     `json:"facets"`
+	Debug          struct {
+		Explain map[string]explanation `json:"explain"`
+	} `json:"debug"`
+}
+
+// execute runs a compiled query with filters in filter queries, sorted by the requested sort
+// (see buildSort) and paginated with cursorMark. Rescoring boosts q, so max scores, the score
+// statistics, sorting and cursors all see the rescored score. A page reads size documents:
+// numFound tells whether more follow, and the next cursor carries nextCursorMark.
+func (s *Searcher) execute(ctx context.Context, kind dquery.Kind, q solrQuery, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	params, err := buildSearchParams(q, baseOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("solr query components",
+		"kind", kind,
+		"q", params.Get("q"),
+		"fq", params["fq"],
+		"sort", params.Get("sort"))
+
+	var res searchResponse
+	if err := s.client.postForm(ctx, "/select", params, &res); err != nil {
+		slog.Error("Solr query failed", "error", err, "kind", kind, "cursor", baseOpts.Cursor != nil)
+		return nil, fmt.Errorf("failed to execute %s search: %w", kind, err)
+	}
+
+	return s.mapSearchResponse(kind, &res, baseOpts)
+}
+
+// buildSearchParams builds the select parameters of a scoring query: the compiled query
+// (rescored), filter queries, facets (with the score statistics when the normalization needs
+// them), highlighting, explanations, sort and cursorMark.
+func buildSearchParams(q solrQuery, baseOpts *dquery.BaseOptions) (url.Values, error) {
+	mark, err := cursorMark(baseOpts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	for k, v := range q.params {
+		params[k] = v
+	}
+	params.Set("q", withRescoring(q.q, params, baseOpts.Rescoring))
+	params["fq"] = buildFilterQueries(baseOpts.Filters)
+	params.Set("fl", strings.Join(storedFields, ",")+",score")
+	params.Set("rows", strconv.Itoa(baseOpts.Size))
+	params.Set("sort", buildSort(baseOpts.Sort))
+	params.Set("cursorMark", mark)
+
+	facets, err := buildFacets(baseOpts.Aggregations, baseOpts.Normalization.NeedsDistribution())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build aggregations: %w", err)
+	}
+	if facets != nil {
+		body, err := json.Marshal(facets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode aggregations: %w", err)
+		}
+		params.Set("json.facet", string(body))
+	}
+
+	buildHighlight(params, baseOpts.Highlight, q.words)
+
+	if baseOpts.Explain {
+		params.Set("debug", "results")
+		params.Set("debug.explain.structured", "true")
+	}
+
+	return params, nil
+}
+
+// mapSearchResponse maps the documents and facets of a select response to a page:
+// normalized scores, highlights, explanations, rescoring breakdowns and the next cursor.
+func (s *Searcher) mapSearchResponse(kind dquery.Kind, res *searchResponse, baseOpts *dquery.BaseOptions) (*storage.SearchResult, error) {
+	cursor := baseOpts.Cursor
+	total := res.Response.NumFound
+	maxScore := dquery.CalcSafeScore(res.Response.MaxScore)
+
+	aggregations, err := mapAggregations(baseOpts.Aggregations, res.Facets)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := mapScoreStats(res.Facets, total, maxScore)
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]dto.ArticleSearchResult, 0, len(res.Response.Docs))
+	rawScores := make([]float64, 0, len(res.Response.Docs))
+	for _, doc := range res.Response.Docs {
+		article, err := doc.toDTO()
+		if err != nil {
+			return nil, fmt.Errorf("failed to map search results to types: %w", err)
+		}
+		searchResult := dto.ArticleSearchResult{
+			Article: article,
+			Score:   utils.RoundFloat64(doc.Score, dquery.ScoreDecimalPlaces),
+		}
+		if highlight, ok := res.Highlighting[doc.ID]; ok {
+			searchResult.Highlight = mapHighlight(highlight)
+		}
+		if e, ok := res.Debug.Explain[doc.ID]; ok {
+			searchResult.Explanation = mapExplanation(e)
+		}
+		articles = append(articles, searchResult)
+		rawScores = append(rawScores, doc.Score)
+	}
+
+	slog.Info("Solr search results fetched",
+		"kind", kind,
+		"total_matches", total,
+		"returned_count", len(articles),
+		"max_score", maxScore)
+
+	offset := storage.PageOffset(cursor)
+	hasMore := offset+int64(len(articles)) < total
+	storage.NormalizeScores(baseOpts.Normalization, articles, rawScores, stats, offset)
+	for i := range articles {
+		storage.ExplainRescoring(baseOpts.Rescoring, &articles[i], rawScores[i])
+	}
+
+	var nextCursor *dquery.Cursor
+	if hasMore && len(articles) > 0 {
+		nextCursor = storage.NextCursor(baseOpts.Sort, articles[len(articles)-1].Article, rawScores[len(rawScores)-1])
+		nextCursor.Offset = offset + int64(len(articles))
+		nextCursor.Mark = res.NextCursorMark
+	}
+
+	var pageMaxScore float64
+	if len(rawScores) > 0 {
+		pageMaxScore = utils.RoundFloat64(slices.Max(rawScores), dquery.ScoreDecimalPlaces)
+	}
+
+	return &storage.SearchResult{
+		Hits:         articles,
+		NextCursor:   nextCursor,
+		HasMore:      hasMore,
+		MaxScore:     utils.RoundFloat64(maxScore, dquery.ScoreDecimalPlaces),
+		PageMaxScore: pageMaxScore,
+		TotalMatches: total,
+		Aggregations: aggregations,
+	}, nil
+}
+
+// Compile-time interface assertions
+var _ storage.FtsSearcher = (*Searcher)(nil)
@@ -0,0 +1,221 @@
+package solr
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+)
+
+// selectResponse builds a select response with one document per id, scored in order
+func selectResponse(numFound int64, nextCursorMark string, ids ...uuid.UUID) map[string]any {
+	docs := make([]map[string]any, 0, len(ids))
+	for i, id := range ids {
+		docs = append(docs, map[string]any{
+			"id":           id.String(),
+			"title":        "Climate summit",
+			"source_name":  "BBC",
+			"published_at": "2024-03-01T11:00:00Z",
+			"score":        2.5 - float64(i),
+		})
+	}
+	return map[string]any{
+		"response":       map[string]any{"numFound": numFound, "maxScore": 2.5, "docs": docs},
+		"nextCursorMark": nextCursorMark,
+	}
+}
+
+func TestSearcher_SearchFields_CursorMarkPagination(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	solr, cfg := newFakeSolr(t, map[string]any{"/select": selectResponse(5, "AoE/BDE=", a, b)})
+	searcher, err := NewSearcher(cfg)
+	if err != nil {
+		t.Fatalf("NewSearcher: %v", err)
+	}
+
+	query := &dquery.MultiMatch{
+		Query:  "climate summit",
+		Fields: []dquery.MultiMatchField{{Name: "title", Weight: 3}, {Name: "content", Weight: 1}},
+	}
+	res, err := searcher.SearchFields(context.Background(), query, &dquery.BaseOptions{Size: 2})
+	if err != nil {
+		t.Fatalf("SearchFields: %v", err)
+	}
+
+	reqs := solr.received("/select")
+	if len(reqs) != 1 {
+		t.Fatalf("select requests = %d, want 1", len(reqs))
+	}
+	params := reqs[0].params
+	if got := expand(params.Get("q"), params); got != "{!edismax qf='title_en^3 content_en' mm=100% v=(climate summit)}" {
+		t.Errorf("q = %s", got)
+	}
+	for name, want := range map[string]string{"cursorMark": "*", "rows": "2", "sort": "score desc,id desc"} {
+		if got := params.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	if len(res.Hits) != 2 || res.Hits[0].Article.ID != a || res.Hits[1].Article.ID != b {
+		t.Fatalf("hits = %+v, want a then b", res.Hits)
+	}
+	if res.Hits[0].Score != 2.5 || res.MaxScore != 2.5 || res.TotalMatches != 5 {
+		t.Errorf("score = %v, max = %v, total = %d", res.Hits[0].Score, res.MaxScore, res.TotalMatches)
+	}
+	if !res.HasMore || res.NextCursor == nil {
+		t.Fatalf("has_more = %v, cursor = %v, want another page", res.HasMore, res.NextCursor)
+	}
+	if res.NextCursor.Mark != "AoE/BDE=" || res.NextCursor.Offset != 2 || res.NextCursor.ID != b {
+		t.Errorf("next cursor = %+v", res.NextCursor)
+	}
+
+	// the cursor survives its encoding and continues the traversal
+	encoded, err := res.NextCursor.Encode()
+	if err != nil {
+		t.Fatalf("encode cursor: %v", err)
+	}
+	cursor, err := dquery.DecodeCursor(encoded)
+	if err != nil {
+		t.Fatalf("decode cursor: %v", err)
+	}
+	solr.respond("/select", selectResponse(3, "AoE/BDF=", uuid.New()))
+	res, err = searcher.SearchFields(context.Background(), query, &dquery.BaseOptions{Size: 2, Cursor: cursor})
+	if err != nil {
+		t.Fatalf("SearchFields page 2: %v", err)
+	}
+	if got := solr.received("/select")[1].params.Get("cursorMark"); got != "AoE/BDE=" {
+		t.Errorf("page 2 cursorMark = %q, want the mark of page 1", got)
+	}
+	if res.HasMore {
+		t.Errorf("page 2 has_more = true, want false on the last hit")
+	}
+}
+
+func TestSearcher_CursorWithoutMark(t *testing.T) {
+	_, cfg := newFakeSolr(t, nil)
+	searcher, err := NewSearcher(cfg)
+	if err != nil {
+		t.Fatalf("NewSearcher: %v", err)
+	}
+
+	cursor := &dquery.Cursor{Score: 1.2, ID: uuid.New()}
+	_, err = searcher.SearchField(context.Background(), &dquery.Match{Field: "title", Query: "climate"}, &dquery.BaseOptions{Size: 10, Cursor: cursor})
+	if err == nil {
+		t.Fatal("expected an error for a cursor without a cursor mark")
+	}
+}
+
+func TestBuildSearchParams(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	highlight, err := dquery.NewHighlight([]string{"title"})
+	if err != nil {
+		t.Fatalf("NewHighlight: %v", err)
+	}
+	c := newCompiler(dquery.LanguageEnglish)
+	q, err := c.match("title", "climate", true, "")
+	if err != nil {
+		t.Fatalf("match: %v", err)
+	}
+
+	params, err := buildSearchParams(c.query(q), &dquery.BaseOptions{
+		Size: 10,
+		Filters: &dquery.Filters{
+			PublishedAt: &dquery.DateRange{Gte: &since},
+			Terms:       map[dquery.FilterField][]string{dquery.FilterSourceName: {"BBC", `The "Times"`}},
+		},
+		Aggregations: []dquery.Aggregation{{Name: "sources", Kind: dquery.TermsAggregation, Field: "source_name", Size: 5}},
+		Highlight:    highlight,
+		Sort:         dquery.Sort{{Field: dquery.SortPublishedAt, Order: dquery.SortDesc}, {Field: dquery.SortRelevance, Order: dquery.SortDesc}},
+		Rescoring:    &dquery.Rescoring{SourceWeights: map[string]float64{"BBC": 2}},
+	})
+	if err != nil {
+		t.Fatalf("buildSearchParams: %v", err)
+	}
+
+	wantFq := []string{
+		`published_at:["2024-01-01T00:00:00Z" TO *]`,
+		`source_name_str:("BBC" OR "The \"Times\"")`,
+	}
+	if got := params["fq"]; strings.Join(got, "\n") != strings.Join(wantFq, "\n") {
+		t.Errorf("fq = %q, want %q", got, wantFq)
+	}
+	if got, want := params.Get("sort"), "published_at desc,score desc,id desc"; got != want {
+		t.Errorf("sort = %q, want %q", got, want)
+	}
+	if got, want := params.Get("q"), "{!boost b=$rb v=$rq}"; got != want {
+		t.Errorf("q = %q, want %q", got, want)
+	}
+	if got, want := params.Get("rb"), "product(if(termfreq(source_name_str,$rs1),2,1))"; got != want {
+		t.Errorf("rb = %q, want %q", got, want)
+	}
+	if got := params.Get("rs1"); got != "BBC" {
+		t.Errorf("rs1 = %q, want BBC", got)
+	}
+	if got := params.Get("hl.fl"); got != "title" || params.Get("hlq") != "climate" {
+		t.Errorf("hl.fl = %q, hlq = %q", got, params.Get("hlq"))
+	}
+
+	var facets map[string]map[string]any
+	if err := json.Unmarshal([]byte(params.Get("json.facet")), &facets); err != nil {
+		t.Fatalf("decode json.facet: %v", err)
+	}
+	if got := facets["sources"]["field"]; got != "source_name_str" {
+		t.Errorf("sources facet field = %v, want source_name_str", got)
+	}
+}
+
+func TestBuildSearchParams_DateHistogramUnsupported(t *testing.T) {
+	_, err := buildSearchParams(solrQuery{q: matchAll}, &dquery.BaseOptions{
+		Size:         10,
+		Aggregations: []dquery.Aggregation{{Name: "per_month", Kind: dquery.DateHistogramAggregation, Field: "published_at", Interval: dquery.IntervalMonth}},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a date_histogram aggregation")
+	}
+}
+
+func TestSearcher_MapsHighlightsAndFacets(t *testing.T) {
+	a := uuid.New()
+	response := selectResponse(1, "AoE/BDE=", a)
+	response["highlighting"] = map[string]any{a.String(): map[string][]string{"title": {"<em>Climate</em> summit"}, "content": {}}}
+	response["facets"] = map[string]any{
+		"count":   1,
+		"sources": map[string]any{"buckets": []map[string]any{{"val": "BBC", "count": 1}}},
+	}
+	_, cfg := newFakeSolr(t, map[string]any{"/select": response})
+	searcher, err := NewSearcher(cfg)
+	if err != nil {
+		t.Fatalf("NewSearcher: %v", err)
+	}
+
+	highlight, err := dquery.NewHighlight(nil)
+	if err != nil {
+		t.Fatalf("NewHighlight: %v", err)
+	}
+	res, err := searcher.SearchField(context.Background(), &dquery.Match{Field: "title", Query: "climate"}, &dquery.BaseOptions{
+		Size:         10,
+		Highlight:    highlight,
+		Aggregations: []dquery.Aggregation{{Name: "sources", Kind: dquery.TermsAggregation, Field: "source_name", Size: 5}},
+	})
+	if err != nil {
+		t.Fatalf("SearchField: %v", err)
+	}
+
+	if len(res.Hits) != 1 {
+		t.Fatalf("hits = %d, want 1", len(res.Hits))
+	}
+	if got := res.Hits[0].Highlight; len(got) != 1 || got["title"][0] != "<em>Climate</em> summit" {
+		t.Errorf("highlight = %v, want the title fragment only", got)
+	}
+	if res.HasMore || res.NextCursor != nil {
+		t.Errorf("has_more = %v, want a single page", res.HasMore)
+	}
+	buckets := res.Aggregations["sources"].Buckets
+	if len(buckets) != 1 || buckets[0] != (dquery.Bucket{Key: "BBC", DocCount: 1}) {
+		t.Errorf("buckets = %v", buckets)
+	}
+}
@@ -0,0 +1,130 @@
+package solr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/api/dto"
+	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	"github.com/DjordjeVuckovic/news-hunter/internal/ranking"
+	"github.com/DjordjeVuckovic/news-hunter/internal/storage"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/DjordjeVuckovic/news-hunter/pkg/utils"
+)
+
+// defaultThreshold is the distance ceiling applied when no threshold is set,
+// matching the PG semantic searcher.
+const defaultThreshold = 0.7
+
+// SemanticSearcher runs kNN queries ({!knn}) on the dense vector embedding field
+type SemanticSearcher struct {
+	client   *client
+	embedder *embedding.Embedder
+	model    string
+}
+
+func NewSemanticSearcher(config ClientConfig, embedder *embedding.Embedder, model string) (*SemanticSearcher, error) {
+	c, err := newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solr client: %w", err)
+	}
+	return &SemanticSearcher{
+		client:   c,
+		embedder: embedder,
+		model:    model,
+	}, nil
+}
+
+func (s *SemanticSearcher) SearchSemantic(ctx context.Context, query *dquery.Semantic, baseOpts *dquery.BaseOptions) (*storage.VectorSearchResult, error) {
+	vec, err := s.embedder.EmbedQuery(ctx, query.Query)
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+
+	size := baseOpts.Size
+	threshold := query.Threshold
+	if threshold == 0 {
+		threshold = defaultThreshold
+	}
+
+	slog.Info("Executing solr semantic kNN search",
+		"query", query.Query,
+		"model", s.model,
+		"k", size,
+		"has_filters", !baseOpts.Filters.IsEmpty(),
+		"size", size)
+
+	// Filter queries pre-filter the kNN search, so k neighbours are still returned when a
+	// filter is set.
+	params := url.Values{
+		"q":    {fmt.Sprintf("{!knn f=embedding topK=%d v=$vec}", size)},
+		"vec":  {formatVector(vec.Embedding)},
+		"fq":   buildFilterQueries(baseOpts.Filters),
+		"fl":   {strings.Join(storedFields, ",") + ",score"},
+		"rows": {strconv.Itoa(size)},
+	}
+
+	var res searchResponse
+	if err := s.client.postForm(ctx, "/select", params, &res); err != nil {
+		slog.Error("Solr kNN query failed", "error", err, "query", query.Query)
+		return nil, fmt.Errorf("failed to execute semantic search: %w", err)
+	}
+
+	// kNN has no similarity floor, so the distance threshold cuts the ranked neighbours at a
+	// minimum cosine similarity (1 - distance)
+	hits, similarities, err := mapSemanticHits(res.Response.Docs, 1-threshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map semantic search results: %w", err)
+	}
+	// kNN returns a single page, so the page is the match set
+	storage.NormalizeScores(baseOpts.Normalization, hits, similarities, ranking.StatsOf(similarities), 0)
+
+	slog.Info("Solr semantic search results fetched",
+		"total_matches", res.Response.NumFound,
+		"returned_count", len(hits))
+
+	// kNN returns a single page (parity with PG): no cursor follow-up.
+	return &storage.VectorSearchResult{
+		Hits:       hits,
+		NextCursor: nil,
+		HasMore:    false,
+	}, nil
+}
+
+// mapSemanticHits maps kNN documents to articles scored by cosine similarity, dropping those
+// below minSimilarity; the score of a cosine kNN search is (1 + similarity) / 2
+func mapSemanticHits(docs []searchDoc, minSimilarity float64) ([]dto.ArticleSearchResult, []float64, error) {
+	articles := make([]dto.ArticleSearchResult, 0, len(docs))
+	similarities := make([]float64, 0, len(docs))
+	for _, doc := range docs {
+		similarity := 2*doc.Score - 1
+		if similarity < minSimilarity {
+			continue
+		}
+		article, err := doc.toDTO()
+		if err != nil {
+			return nil, nil, err
+		}
+		articles = append(articles, dto.ArticleSearchResult{
+			Article: article,
+			Score:   utils.RoundFloat64(similarity, dquery.ScoreDecimalPlaces),
+		})
+		similarities = append(similarities, similarity)
+	}
+	return articles, similarities, nil
+}
+
+// formatVector renders a vector in the [v1,v2,...] form of the knn query parser
+func formatVector(vec []float32) string {
+	values := make([]string, len(vec))
+	for i, v := range vec {
+		values[i] = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return "[" + strings.Join(values, ",") + "]"
+}
+
+var _ storage.SemanticSearcher = (*SemanticSearcher)(nil)
@@ -0,0 +1,99 @@
+package solr
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/embedding"
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+	"github.com/google/uuid"
+)
+
+// staticEmbedder is an embedding.Client returning a fixed vector.
+type staticEmbedder struct {
+	vec []float32
+}
+
+func (s staticEmbedder) Generate(_ context.Context, _ embedding.Request) (*embedding.Response, error) {
+	return &embedding.Response{Embedding: s.vec}, nil
+}
+
+func (s staticEmbedder) GenerateBatch(_ context.Context, _ embedding.BatchRequest) (*embedding.BatchResponse, error) {
+	return &embedding.BatchResponse{Embeddings: [][]float32{s.vec}}, nil
+}
+
+func TestSemanticSearcher_KnnWithThreshold(t *testing.T) {
+	near, far := uuid.New(), uuid.New()
+	response := selectResponse(2, "", near, far)
+	docs := response["response"].(map[string]any)["docs"].([]map[string]any)
+	docs[0]["score"] = 0.95 // similarity 0.9
+	docs[1]["score"] = 0.6  // similarity 0.2, below 1 - 0.7
+	solr, cfg := newFakeSolr(t, map[string]any{"/select": response})
+
+	embedder := embedding.NewEmbedder(staticEmbedder{vec: []float32{0.5, -0.25}})
+	searcher, err := NewSemanticSearcher(cfg, embedder, embedding.DefaultModel)
+	if err != nil {
+		t.Fatalf("NewSemanticSearcher: %v", err)
+	}
+
+	res, err := searcher.SearchSemantic(context.Background(), dquery.NewSemantic("climate"), &dquery.BaseOptions{
+		Size:    5,
+		Filters: &dquery.Filters{Terms: map[dquery.FilterField][]string{dquery.FilterLanguage: {"english"}}},
+	})
+	if err != nil {
+		t.Fatalf("SearchSemantic: %v", err)
+	}
+
+	params := solr.received("/select")[0].params
+	if got, want := params.Get("q"), "{!knn f=embedding topK=5 v=$vec}"; got != want {
+		t.Errorf("q = %q, want %q", got, want)
+	}
+	if got, want := params.Get("vec"), "[0.5,-0.25]"; got != want {
+		t.Errorf("vec = %q, want %q", got, want)
+	}
+	if got := params["fq"]; len(got) != 1 || !strings.HasPrefix(got[0], "language:") {
+		t.Errorf("fq = %q, want the language filter", got)
+	}
+
+	if len(res.Hits) != 1 || res.Hits[0].Article.ID != near {
+		t.Fatalf("hits = %+v, want only the near article", res.Hits)
+	}
+	if got := res.Hits[0].Score; got != 0.9 {
+		t.Errorf("score = %v, want cosine similarity 0.9", got)
+	}
+}
+
+func TestEmbedder_SaveBulk_AtomicUpdates(t *testing.T) {
+	solr, cfg := newFakeSolr(t, fullSchema())
+	embedder, err := NewEmbedder(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewEmbedder: %v", err)
+	}
+
+	id := uuid.New()
+	vecs := []*embedding.Vec{{ID: id, Model: embedding.DefaultModel, Embedding: []float32{0.1, 0.2}}}
+	if err := embedder.SaveBulk(context.Background(), vecs); err != nil {
+		t.Fatalf("SaveBulk: %v", err)
+	}
+
+	updates := solr.received("/update")
+	if len(updates) != 1 {
+		t.Fatalf("update requests = %d, want 1", len(updates))
+	}
+	if got := updates[0].params.Get("failOnVersionConflicts"); got != "false" {
+		t.Errorf("failOnVersionConflicts = %q, want false (orphans are skipped)", got)
+	}
+	var docs []map[string]any
+	if err := json.Unmarshal(updates[0].body, &docs); err != nil {
+		t.Fatalf("decode update: %v", err)
+	}
+	doc := docs[0]
+	if doc["id"] != id.String() || doc["_version_"] != float64(1) {
+		t.Errorf("update = %v, want the article id with _version_ 1", doc)
+	}
+	if set, ok := doc["embedding"].(map[string]any)["set"].([]any); !ok || len(set) != 2 {
+		t.Errorf("embedding = %v, want an atomic set of the vector", doc["embedding"])
+	}
+}
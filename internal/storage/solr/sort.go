@@ -0,0 +1,52 @@
+package solr
+
+import (
+	"fmt"
+	"strings"
+
+	dquery "github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// firstCursorMark starts a cursorMark traversal
+const firstCursorMark = "*"
+
+// sortFields maps sort fields to sortable fields of the schema (see SchemaBuilder.buildFields).
+var sortFields = map[dquery.SortField]string{
+	dquery.SortPublishedAt: "published_at",
+	dquery.SortCreatedAt:   "created_at",
+	dquery.SortSourceName:  "source_name_str",
+}
+
+// buildSort translates the sort to the sort parameter ending with the id tiebreaker, which
+// cursorMark requires. Missing dates sort as the epoch, before any real date; missing source
+// names sort last in both directions (sortMissingLast of the _default string type).
+//
+//	published_at:desc,relevance:desc → "published_at desc,score desc,id desc"
+func buildSort(sort dquery.Sort) string {
+	if len(sort) == 0 {
+		sort = dquery.DefaultSort
+	}
+
+	keys := make([]string, 0, len(sort)+1)
+	for _, k := range sort {
+		field := "score"
+		if k.Field != dquery.SortRelevance {
+			field = sortFields[k.Field]
+		}
+		keys = append(keys, field+" "+string(k.Order))
+	}
+	return strings.Join(append(keys, "id desc"), ",")
+}
+
+// cursorMark returns the cursorMark of a page: the mark of the cursor, or the start of the
+// traversal on the first page. Cursors of other backends carry sort values, not a mark, and
+// cannot continue a Solr traversal.
+func cursorMark(cursor *dquery.Cursor) (string, error) {
+	if cursor == nil {
+		return firstCursorMark, nil
+	}
+	if cursor.Mark == "" {
+		return "", fmt.Errorf("cursor has no Solr cursor mark")
+	}
+	return cursor.Mark, nil
+}
@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/query"
+)
+
+// Suggester provides spelling corrections ("did you mean") for query text.
+// size caps the number of corrections per misspelled term.
+type Suggester interface {
+	Suggest(ctx context.Context, text string, size int) (*query.Suggest, error)
+}
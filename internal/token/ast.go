@@ -0,0 +1,93 @@
+package token
+
+// Node is a node of a parsed, backend-neutral query AST.
+// Storage backends compile the tree to their native query language.
+type Node interface {
+	node()
+}
+
+// Occur is how a clause takes part in the match of its group
+// (Lucene / Elasticsearch bool semantics)
+type Occur int
+
+const (
+	// Should: optional; contributes to the score. A group without Must clauses
+	// matches when at least one Should clause matches.
+	Should Occur = iota
+	// Must: required; contributes to the score
+	Must
+	// MustNot: prohibited; does not score
+	MustNot
+)
+
+func (o Occur) String() string {
+	switch o {
+	case Should:
+		return "should"
+	case Must:
+		return "must"
+	case MustNot:
+		return "must_not"
+	default:
+		return "unknown"
+	}
+}
+
+// Term matches a single term, or any term starting with Value when Prefix is set.
+// Field is empty when the term is not field-scoped (the backend's default fields).
+type Term struct {
+	Field  string
+	Value  string
+	Prefix bool
+	Boost  float64
+}
+
+// Phrase matches the words of Value adjacent and in order
+type Phrase struct {
+	Field string
+	Value string
+	Boost float64
+}
+
+// Group is a parenthesized list of clauses. The root of a parsed query is a Group.
+type Group struct {
+	Clauses []Clause
+	Boost   float64
+}
+
+// Clause is a node with its occurrence in the enclosing group
+type Clause struct {
+	Occur Occur
+	Node  Node
+}
+
+// And matches when every operand matches (boolean expressions)
+type And struct {
+	Operands []Node
+}
+
+// Or matches when at least one operand matches (boolean expressions)
+type Or struct {
+	Operands []Node
+}
+
+// Not matches when its operand does not match (boolean expressions)
+type Not struct {
+	Operand Node
+}
+
+// Near matches when its operands (terms or phrases) occur in either order with at most
+// Distance words between them (boolean expressions: "climate" NEAR/5 "summit")
+type Near struct {
+	Left     Node
+	Right    Node
+	Distance int
+}
+
+func (*Term) node()   {}
+func (*Phrase) node() {}
+func (*Group) node()  {}
+func (*And) node()    {}
+func (*Or) node()     {}
+func (*Not) node()    {}
+func (*Near) node()   {}
@@ -2,12 +2,17 @@ package token
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
 )
 
+// MaxNearDistance caps N of NEAR/N. PostgreSQL expands NEAR/N to 2(N+1) distance
+// alternatives, so the cap keeps the tsquery small.
+const MaxNearDistance = 20
+
 type BoolTokenizer struct {
 	input []rune
 	pos   int
@@ -18,10 +23,12 @@ func NewBoolTokenizer() *BoolTokenizer {
 }
 
 // Tokenize converts the input string into a slice of Tokens.
+// Pos of each token is its character offset in the input.
 // Example: Input: `(apple AND "banana split") OR NOT cherry`
 func (t *BoolTokenizer) Tokenize(input string) []Token {
-	t.input = []rune(strings.Trim(input, " "))
+	t.input = []rune(input)
 	t.pos = 0
+	t.skipWhitespace()
 
 	var tokens []Token
 
@@ -29,10 +36,10 @@ func (t *BoolTokenizer) Tokenize(input string) []Token {
 		ch := t.input[t.pos]
 		switch {
 		case ch == '(':
-			tokens = append(tokens, Token{Type: LPAREN, Value: "("})
+			tokens = append(tokens, Token{Type: LPAREN, Value: "(", Pos: t.pos})
 			t.pos++
 		case ch == ')':
-			tokens = append(tokens, Token{Type: RPAREN, Value: ")"})
+			tokens = append(tokens, Token{Type: RPAREN, Value: ")", Pos: t.pos})
 			t.pos++
 		case ch == '"':
 			tokens = append(tokens, t.readQuoted())
@@ -44,7 +51,7 @@ func (t *BoolTokenizer) Tokenize(input string) []Token {
 		t.skipWhitespace()
 	}
 
-	tokens = append(tokens, Token{Type: EOF})
+	tokens = append(tokens, Token{Type: EOF, Pos: t.pos})
 	return tokens
 }
 
@@ -64,17 +71,33 @@ func (t *BoolTokenizer) readWord() Token {
 
 	switch strings.ToUpper(word) {
 	case "AND":
-		return Token{Type: AND, Value: word}
+		return Token{Type: AND, Value: word, Pos: start}
 	case "OR":
-		return Token{Type: OR, Value: word}
+		return Token{Type: OR, Value: word, Pos: start}
 	case "NOT":
-		return Token{Type: NOT, Value: word}
+		return Token{Type: NOT, Value: word, Pos: start}
+	case "NEAR":
+		if t.pos < len(t.input) && t.input[t.pos] == '/' {
+			return t.readNear(start)
+		}
+		return Token{Type: WORD, Value: word, Pos: start}
 	default:
-		return Token{Type: WORD, Value: word}
+		return Token{Type: WORD, Value: word, Pos: start}
+	}
+}
+
+// readNear reads the distance of a NEAR/N operator; the value is the whole literal
+// ("NEAR/5"), the distance is checked by Validate
+func (t *BoolTokenizer) readNear(start int) Token {
+	t.pos++ // skip '/'
+	for t.pos < len(t.input) && unicode.IsDigit(t.input[t.pos]) {
+		t.pos++
 	}
+	return Token{Type: NEAR, Value: string(t.input[start:t.pos]), Pos: start}
 }
 
 func (t *BoolTokenizer) readQuoted() Token {
+	quote := t.pos
 	t.pos++ // skip opening quote
 	start := t.pos
 	for t.pos < len(t.input) && t.input[t.pos] != '"' {
@@ -84,7 +107,7 @@ func (t *BoolTokenizer) readQuoted() Token {
 	if t.pos < len(t.input) {
 		t.pos++ // skip closing quote
 	}
-	return Token{Type: WORD, Value: value}
+	return Token{Type: WORD, Value: value, Pos: quote}
 }
 
 func isWordChar(ch rune) bool {
@@ -125,6 +148,13 @@ func (t *BoolTokenizer) Validate(tokens []Token) error {
 			if i+1 >= len(tokens) || (tokens[i+1].Type != WORD && tokens[i+1].Type != LPAREN && tokens[i+1].Type != NOT) {
 				return apperr.NewValidation("NOT must be followed by a term or group")
 			}
+		case NEAR:
+			if _, err := NearDistance(tok); err != nil {
+				return err
+			}
+			if i == 0 || tokens[i-1].Type != WORD || tokens[i+1].Type != WORD {
+				return apperr.NewValidation(fmt.Sprintf("%s operands must be terms or phrases", tok.Value))
+			}
 		default:
 			return apperr.NewValidation(fmt.Sprintf("invalid token: %s", tok.Value))
 		}
@@ -140,3 +170,14 @@ func (t *BoolTokenizer) Validate(tokens []Token) error {
 
 	return nil
 }
+
+// NearDistance returns the distance of a NEAR/N token: the maximum number of words
+// between its operands
+func NearDistance(tok Token) (int, error) {
+	_, n, _ := strings.Cut(tok.Value, "/")
+	distance, err := strconv.Atoi(n)
+	if err != nil || distance < 0 || distance > MaxNearDistance {
+		return 0, apperr.NewValidation(fmt.Sprintf("%s: distance must be a number between 0 and %d", tok.Value, MaxNearDistance))
+	}
+	return distance, nil
+}
@@ -162,6 +162,25 @@ func TestBoolTokenizer_Tokenize(t *testing.T) {
 				{Type: EOF},
 			},
 		},
+		{
+			name:  "NEAR operator with distance",
+			input: `"climate" NEAR/5 "carbon tax"`,
+			expected: []Token{
+				{Type: WORD, Value: "climate"},
+				{Type: NEAR, Value: "NEAR/5"},
+				{Type: WORD, Value: "carbon tax"},
+				{Type: EOF},
+			},
+		},
+		{
+			name:  "near without distance is a word",
+			input: "near miss",
+			expected: []Token{
+				{Type: WORD, Value: "near"},
+				{Type: WORD, Value: "miss"},
+				{Type: EOF},
+			},
+		},
 		{
 			name:  "words with underscores and digits",
 			input: "field_1 AND test_value2",
@@ -227,6 +246,7 @@ func TestValidateTokens(t *testing.T) {
 		{name: "valid nested parens", input: "(climate OR weather) AND change"},
 		{name: "valid double NOT", input: "NOT NOT climate"},
 		{name: "valid complex", input: "(climate OR weather) AND (change OR warming) AND NOT politics"},
+		{name: "valid NEAR", input: `"climate" NEAR/5 "summit" AND NOT politics`},
 
 		{
 			name:    "empty expression",
@@ -273,6 +293,26 @@ func TestValidateTokens(t *testing.T) {
 			input:   "climate NOT",
 			wantErr: "NOT must be followed by a term or group",
 		},
+		{
+			name:    "NEAR without distance",
+			input:   "climate NEAR/ summit",
+			wantErr: "NEAR/: distance must be a number between 0 and 20",
+		},
+		{
+			name:    "NEAR distance too large",
+			input:   "climate near/21 summit",
+			wantErr: "near/21: distance must be a number between 0 and 20",
+		},
+		{
+			name:    "NEAR on a group",
+			input:   "(climate OR weather) NEAR/3 summit",
+			wantErr: "NEAR/3 operands must be terms or phrases",
+		},
+		{
+			name:    "trailing NEAR",
+			input:   "climate NEAR/3",
+			wantErr: "NEAR/3 operands must be terms or phrases",
+		},
 		{
 			name:    "NOT before AND",
 			input:   "climate NOT AND change",
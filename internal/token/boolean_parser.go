@@ -0,0 +1,176 @@
+package token
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BooleanParser parses boolean expressions (see BoolTokenizer) into an AST of And, Or,
+// Not, Term and Phrase nodes. Storage backends compile the AST, so every backend reads
+// an expression the same way.
+//
+// Precedence, from strongest: NEAR/N, NOT, AND, OR. Adjacent operands are AND-ed:
+//
+//	climate change OR NOT politics  →  (climate AND change) OR (NOT politics)
+//	NOT "climate" NEAR/5 summit     →  NOT (NEAR/5(climate, summit))
+//
+// Nested operators of the same kind are flattened: (a AND b) AND c → AND(a, b, c).
+// A parser is not safe for concurrent use.
+type BooleanParser struct {
+	tokenizer *BoolTokenizer
+}
+
+func NewBooleanParser() *BooleanParser {
+	return &BooleanParser{
+		tokenizer: NewBoolTokenizer(),
+	}
+}
+
+// Parse validates and parses the expression.
+// Invalid expressions are returned as validation errors.
+func (p *BooleanParser) Parse(expression string) (Node, error) {
+	tokens := p.tokenizer.Tokenize(expression)
+	if err := p.tokenizer.Validate(tokens); err != nil {
+		return nil, err
+	}
+
+	s := &parseState{tokens: tokens}
+	node, err := s.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := s.peek(); tok.Type != EOF {
+		return nil, SyntaxError(tok.Pos, fmt.Sprintf("unexpected %s", tok.Value))
+	}
+	return node, nil
+}
+
+// parseOr parses and_expr (OR and_expr)*
+func (s *parseState) parseOr() (Node, error) {
+	var operands []Node
+	for {
+		node, err := s.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = appendFlat[*Or](operands, node, func(o *Or) []Node { return o.Operands })
+
+		if s.peek().Type != OR {
+			break
+		}
+		s.next()
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &Or{Operands: operands}, nil
+}
+
+// parseAnd parses unary ([AND] unary)*; an operand following another one without an
+// operator is AND-ed
+func (s *parseState) parseAnd() (Node, error) {
+	var operands []Node
+	for {
+		node, err := s.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = appendFlat[*And](operands, node, func(a *And) []Node { return a.Operands })
+
+		tok := s.peek()
+		if tok.Type == AND {
+			s.next()
+			continue
+		}
+		if tok.Type != WORD && tok.Type != LPAREN && tok.Type != NOT {
+			break
+		}
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &And{Operands: operands}, nil
+}
+
+// parseUnary parses NOT unary | term | phrase | ( or_expr )
+func (s *parseState) parseUnary() (Node, error) {
+	tok := s.next()
+	switch tok.Type {
+	case NOT:
+		operand, err := s.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Operand: operand}, nil
+	case LPAREN:
+		node, err := s.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := s.next(); closing.Type != RPAREN {
+			return nil, SyntaxError(tok.Pos, "unclosed '('")
+		}
+		return node, nil
+	case WORD:
+		node, err := wordNode(tok)
+		if err != nil {
+			return nil, err
+		}
+		return s.parseNear(node)
+	case EOF:
+		return nil, SyntaxError(tok.Pos, "expression ends after an operator")
+	default:
+		return nil, SyntaxError(tok.Pos, fmt.Sprintf("unexpected %s", tok.Value))
+	}
+}
+
+// parseNear parses the optional "NEAR/N term" following a term or phrase
+func (s *parseState) parseNear(left Node) (Node, error) {
+	near := s.peek()
+	if near.Type != NEAR {
+		return left, nil
+	}
+	s.next()
+
+	distance, err := NearDistance(near)
+	if err != nil {
+		return nil, err
+	}
+	operand := s.next()
+	if operand.Type != WORD {
+		return nil, SyntaxError(near.Pos, fmt.Sprintf("%s operands must be terms or phrases", near.Value))
+	}
+	right, err := wordNode(operand)
+	if err != nil {
+		return nil, err
+	}
+	if next := s.peek(); next.Type == NEAR {
+		return nil, SyntaxError(next.Pos, fmt.Sprintf("%s cannot follow another proximity operator; combine them with AND", next.Value))
+	}
+
+	return &Near{Left: left, Right: right, Distance: distance}, nil
+}
+
+// wordNode turns a word into a Term, or into a Phrase when it is a quoted text of several
+// words. Phrase words are separated by single spaces, so every backend sees the same words.
+func wordNode(tok Token) (Node, error) {
+	words := strings.FieldsFunc(tok.Value, func(r rune) bool { return !isWordChar(r) })
+	switch {
+	case len(words) == 0:
+		return nil, SyntaxError(tok.Pos, "quoted phrase must contain a search term")
+	case len(words) == 1:
+		return &Term{Value: words[0], Boost: 1}, nil
+	default:
+		return &Phrase{Value: strings.Join(words, " "), Boost: 1}, nil
+	}
+}
+
+// appendFlat appends node to operands, inlining the operands of a node of the same kind
+func appendFlat[T Node](operands []Node, node Node, children func(T) []Node) []Node {
+	if same, ok := node.(T); ok {
+		return append(operands, children(same)...)
+	}
+	return append(operands, node)
+}
@@ -0,0 +1,120 @@
+package token_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+	"github.com/DjordjeVuckovic/news-hunter/internal/token/tokentest"
+)
+
+func TestBooleanParser_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "single term", input: "climate", expected: "climate"},
+		{name: "AND", input: "climate AND change", expected: "AND(climate, change)"},
+		{name: "implicit AND", input: "climate change", expected: "AND(climate, change)"},
+		{name: "AND binds tighter than OR", input: "a OR b AND c", expected: "OR(a, AND(b, c))"},
+		{name: "implicit AND binds tighter than OR", input: "a b OR c", expected: "OR(AND(a, b), c)"},
+		{name: "NOT binds tighter than AND", input: "NOT a AND b", expected: "AND(NOT(a), b)"},
+		{name: "NOT of a group", input: "NOT (a OR b)", expected: "NOT(OR(a, b))"},
+		{name: "double NOT", input: "NOT NOT a", expected: "NOT(NOT(a))"},
+		{name: "parentheses override precedence", input: "(a OR b) AND c", expected: "AND(OR(a, b), c)"},
+		{name: "same operators are flattened", input: "(a AND b) AND (c d)", expected: "AND(a, b, c, d)"},
+		{name: "ORs are flattened", input: "a OR (b OR c)", expected: "OR(a, b, c)"},
+		{name: "quoted phrase", input: `"climate change" OR energy`, expected: `OR("climate change", energy)`},
+		{name: "quoted single word is a term", input: `"climate"`, expected: "climate"},
+		{name: "phrase words are normalized", input: `"climate-change  policy"`, expected: `"climate change policy"`},
+		{name: "case insensitive operators", input: "a and b or not c", expected: "OR(AND(a, b), NOT(c))"},
+		{name: "NEAR", input: `"climate" NEAR/5 "summit"`, expected: "NEAR/5(climate, summit)"},
+		{name: "NEAR with phrase operand", input: `"carbon tax" near/0 reform`, expected: `NEAR/0("carbon tax", reform)`},
+		{name: "NEAR binds tighter than NOT and AND", input: "NOT a NEAR/2 b c", expected: "AND(NOT(NEAR/2(a, b)), c)"},
+	}
+
+	parser := token.NewBooleanParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := tokentest.Format(node); got != tt.expected {
+				t.Errorf("Parse() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBooleanParser_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "empty", input: "", wantErr: "expression must contain at least one search term"},
+		{name: "unclosed paren", input: "(a AND b", wantErr: "unbalanced parentheses: 1 unclosed"},
+		{name: "leading operator", input: "OR a", wantErr: "expression cannot start with OR"},
+		{name: "trailing AND", input: "climate AND", wantErr: "expression ends after an operator at position 11"},
+		{name: "trailing OR in group", input: "(climate OR) energy", wantErr: "unexpected ) at position 11"},
+		{name: "phrase without words", input: `climate "?!"`, wantErr: "quoted phrase must contain a search term at position 8"},
+		{name: "chained NEAR", input: "a NEAR/2 b NEAR/3 c", wantErr: "NEAR/3 cannot follow another proximity operator; combine them with AND at position 11"},
+	}
+
+	parser := token.NewBooleanParser()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.Parse(tt.input)
+			if err == nil {
+				t.Fatalf("expected error %q, got nil", tt.wantErr)
+			}
+			var ve *apperr.ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("expected *apperr.ValidationError, got %T", err)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+// Every generated expression parses, and wrapping it in parentheses or a double
+// negation does not change what it matches
+func TestBooleanParser_RandomExpressions(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	parser := token.NewBooleanParser()
+
+	for i := 0; i < 500; i++ {
+		expression := tokentest.RandomExpression(r, 4)
+		node, err := parser.Parse(expression)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", expression, err)
+		}
+
+		grouped, err := parser.Parse("(" + expression + ")")
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", "("+expression+")", err)
+		}
+		if tokentest.Format(grouped) != tokentest.Format(node) {
+			t.Fatalf("parentheses changed the tree of %q: %s vs %s", expression, tokentest.Format(grouped), tokentest.Format(node))
+		}
+
+		negated, err := parser.Parse("NOT NOT (" + expression + ")")
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", "NOT NOT ("+expression+")", err)
+		}
+		for j := 0; j < 8; j++ {
+			matches := tokentest.RandomMatch(r)
+			if tokentest.Eval(negated, matches) != tokentest.Eval(node, matches) {
+				t.Fatalf("double negation of %q matches differently for %v", expression, matches)
+			}
+		}
+	}
+}
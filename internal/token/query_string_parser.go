@@ -0,0 +1,250 @@
+package token
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+)
+
+const (
+	// MaxQueryStringDepth caps the nesting of groups (the whole query is depth 0)
+	MaxQueryStringDepth = 5
+	// MaxQueryStringClauses caps the number of terms and phrases in one query
+	MaxQueryStringClauses = 64
+)
+
+// QueryStringParser parses query_string syntax (see QueryStringTokenizer) into a Group.
+//
+// Clauses without + or - take their occurrence from the default operator:
+// Should for OR, Must for AND. A field scope on a group applies to every term and
+// phrase inside it that is not scoped itself. A parser is not safe for concurrent use.
+type QueryStringParser struct {
+	tokenizer    *QueryStringTokenizer
+	defaultOccur Occur
+	fields       map[string]bool
+}
+
+type QueryStringOption func(p *QueryStringParser)
+
+func NewQueryStringParser(opts ...QueryStringOption) *QueryStringParser {
+	p := &QueryStringParser{
+		tokenizer:    NewQueryStringTokenizer(),
+		defaultOccur: Should,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithDefaultOperator sets the occurrence of clauses without + or -
+func WithDefaultOperator(op operator.Operator) QueryStringOption {
+	return func(p *QueryStringParser) {
+		if op.IsAnd() {
+			p.defaultOccur = Must
+		} else {
+			p.defaultOccur = Should
+		}
+	}
+}
+
+// WithFields restricts field scopes to the given fields; any field is accepted by default
+func WithFields(fields ...string) QueryStringOption {
+	return func(p *QueryStringParser) {
+		p.fields = make(map[string]bool, len(fields))
+		for _, f := range fields {
+			p.fields[f] = true
+		}
+	}
+}
+
+// Parse parses the input into the root group of the query.
+// Invalid syntax is returned as a validation error with the character position.
+func (p *QueryStringParser) Parse(input string) (*Group, error) {
+	tokens, err := p.tokenizer.Tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, SyntaxError(0, "query must contain at least one search term")
+	}
+
+	s := &parseState{parser: p, tokens: tokens}
+	clauses, err := s.parseClauses(0, "", -1)
+	if err != nil {
+		return nil, err
+	}
+	return &Group{Clauses: clauses, Boost: 1}, nil
+}
+
+// parseState is the cursor over the tokens of one Parse call
+type parseState struct {
+	parser *QueryStringParser
+	tokens []Token
+	i      int
+	leaves int
+}
+
+func (s *parseState) peek() Token {
+	return s.tokens[s.i]
+}
+
+func (s *parseState) next() Token {
+	tok := s.tokens[s.i]
+	if tok.Type != EOF {
+		s.i++
+	}
+	return tok
+}
+
+// parseClauses parses clauses up to the end of the group opened at lparen
+// (-1 for the root, which ends at EOF).
+func (s *parseState) parseClauses(depth int, field string, lparen int) ([]Clause, error) {
+	var clauses []Clause
+	for {
+		tok := s.peek()
+		switch {
+		case tok.Type == EOF && lparen >= 0:
+			return nil, SyntaxError(lparen, "unclosed '('")
+		case tok.Type == EOF:
+			return clauses, nil
+		case tok.Type == RPAREN && lparen < 0:
+			return nil, SyntaxError(tok.Pos, "unexpected ')'")
+		case tok.Type == RPAREN:
+			return clauses, nil
+		}
+
+		clause, err := s.parseClause(depth, field)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+}
+
+// parseClause parses [+|-] [field:] (term | prefix | phrase | group) [^boost]
+func (s *parseState) parseClause(depth int, field string) (Clause, error) {
+	clause := Clause{Occur: s.parser.defaultOccur}
+
+	if tok := s.peek(); tok.Type == PLUS || tok.Type == MINUS {
+		s.next()
+		clause.Occur = Must
+		if tok.Type == MINUS {
+			clause.Occur = MustNot
+		}
+		if !startsOperand(s.peek().Type) {
+			return Clause{}, SyntaxError(tok.Pos, fmt.Sprintf("'%s' must be followed by a term, phrase or group", tok.Value))
+		}
+	}
+
+	if tok := s.peek(); tok.Type == FIELD {
+		s.next()
+		if s.parser.fields != nil && !s.parser.fields[tok.Value] {
+			return Clause{}, SyntaxError(tok.Pos, fmt.Sprintf("unknown field '%s'", tok.Value))
+		}
+		field = tok.Value
+		if next := s.peek().Type; next == FIELD || !startsOperand(next) {
+			return Clause{}, SyntaxError(tok.Pos, fmt.Sprintf("field '%s' must be followed by a term, phrase or group", tok.Value))
+		}
+	}
+
+	var boost *float64
+	tok := s.next()
+	switch tok.Type {
+	case WORD, PREFIX:
+		if err := s.countLeaf(tok); err != nil {
+			return Clause{}, err
+		}
+		term := &Term{Field: field, Value: tok.Value, Prefix: tok.Type == PREFIX, Boost: 1}
+		clause.Node, boost = term, &term.Boost
+	case PHRASE:
+		if err := s.countLeaf(tok); err != nil {
+			return Clause{}, err
+		}
+		phrase := &Phrase{Field: field, Value: tok.Value, Boost: 1}
+		clause.Node, boost = phrase, &phrase.Boost
+	case LPAREN:
+		if depth+1 > MaxQueryStringDepth {
+			return Clause{}, SyntaxError(tok.Pos, fmt.Sprintf("groups nested deeper than %d", MaxQueryStringDepth))
+		}
+		clauses, err := s.parseClauses(depth+1, field, tok.Pos)
+		if err != nil {
+			return Clause{}, err
+		}
+		if len(clauses) == 0 {
+			return Clause{}, SyntaxError(tok.Pos, "empty group")
+		}
+		s.next() // skip ')'
+		group := &Group{Clauses: clauses, Boost: 1}
+		clause.Node, boost = group, &group.Boost
+	case BOOST:
+		return Clause{}, SyntaxError(tok.Pos, "boost '^' must follow a term, phrase or group")
+	default:
+		return Clause{}, SyntaxError(tok.Pos, fmt.Sprintf("unexpected '%s'", tok.Value))
+	}
+
+	if tok := s.peek(); tok.Type == BOOST {
+		s.next()
+		// The tokenizer only emits positive numbers
+		*boost, _ = strconv.ParseFloat(tok.Value, 64)
+	}
+
+	return clause, nil
+}
+
+func (s *parseState) countLeaf(tok Token) error {
+	s.leaves++
+	if s.leaves > MaxQueryStringClauses {
+		return SyntaxError(tok.Pos, fmt.Sprintf("query has more than %d terms", MaxQueryStringClauses))
+	}
+	return nil
+}
+
+// startsOperand reports whether a token of type t can start a term, phrase or group
+func startsOperand(t Type) bool {
+	return t == WORD || t == PREFIX || t == PHRASE || t == LPAREN || t == FIELD
+}
+
+// String renders the group back to query_string syntax with explicit occurrences
+// and boosts, e.g. "+title:climat* -(coal oil)^2". Used in logs and tests.
+func (g *Group) String() string {
+	parts := make([]string, 0, len(g.Clauses))
+	for _, c := range g.Clauses {
+		var b strings.Builder
+		switch c.Occur {
+		case Must:
+			b.WriteString("+")
+		case MustNot:
+			b.WriteString("-")
+		}
+
+		var boost float64
+		switch n := c.Node.(type) {
+		case *Term:
+			if n.Field != "" {
+				b.WriteString(n.Field + ":")
+			}
+			b.WriteString(n.Value)
+			if n.Prefix {
+				b.WriteString("*")
+			}
+			boost = n.Boost
+		case *Phrase:
+			if n.Field != "" {
+				b.WriteString(n.Field + ":")
+			}
+			b.WriteString(strconv.Quote(n.Value))
+			boost = n.Boost
+		case *Group:
+			b.WriteString("(" + n.String() + ")")
+			boost = n.Boost
+		}
+		if boost != 1 {
+			b.WriteString("^" + strconv.FormatFloat(boost, 'g', -1, 64))
+		}
+		parts = append(parts, b.String())
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,253 @@
+package token
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+	"github.com/DjordjeVuckovic/news-hunter/internal/types/operator"
+)
+
+func TestQueryStringTokenizer_Tokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Token
+	}{
+		{
+			name:  "terms",
+			input: "climate change",
+			expected: []Token{
+				{Type: WORD, Value: "climate", Pos: 0},
+				{Type: WORD, Value: "change", Pos: 8},
+				{Type: EOF, Pos: 14},
+			},
+		},
+		{
+			name:  "field scoped phrase",
+			input: `title:"climate change"`,
+			expected: []Token{
+				{Type: FIELD, Value: "title", Pos: 0},
+				{Type: PHRASE, Value: "climate change", Pos: 6},
+				{Type: EOF, Pos: 22},
+			},
+		},
+		{
+			name:  "prefix and boost",
+			input: "energ* solar^2.5",
+			expected: []Token{
+				{Type: PREFIX, Value: "energ", Pos: 0},
+				{Type: WORD, Value: "solar", Pos: 7},
+				{Type: BOOST, Value: "2.5", Pos: 12},
+				{Type: EOF, Pos: 16},
+			},
+		},
+		{
+			name:  "required, prohibited and grouping",
+			input: "+climate -(coal oil)",
+			expected: []Token{
+				{Type: PLUS, Value: "+", Pos: 0},
+				{Type: WORD, Value: "climate", Pos: 1},
+				{Type: MINUS, Value: "-", Pos: 9},
+				{Type: LPAREN, Value: "(", Pos: 10},
+				{Type: WORD, Value: "coal", Pos: 11},
+				{Type: WORD, Value: "oil", Pos: 16},
+				{Type: RPAREN, Value: ")", Pos: 19},
+				{Type: EOF, Pos: 20},
+			},
+		},
+		{
+			name:  "hyphen inside a term is not an operator",
+			input: "covid-19",
+			expected: []Token{
+				{Type: WORD, Value: "covid-19", Pos: 0},
+				{Type: EOF, Pos: 8},
+			},
+		},
+		{
+			name:  "escaped reserved characters",
+			input: `c\+\+ a\:b "say \"hi\""`,
+			expected: []Token{
+				{Type: WORD, Value: "c++", Pos: 0},
+				{Type: WORD, Value: "a:b", Pos: 6},
+				{Type: PHRASE, Value: `say "hi"`, Pos: 11},
+				{Type: EOF, Pos: 23},
+			},
+		},
+		{
+			name:  "positions count characters, not bytes",
+			input: "čćž šđ",
+			expected: []Token{
+				{Type: WORD, Value: "čćž", Pos: 0},
+				{Type: WORD, Value: "šđ", Pos: 4},
+				{Type: EOF, Pos: 6},
+			},
+		},
+	}
+
+	tokenizer := NewQueryStringTokenizer()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := tokenizer.Tokenize(tt.input)
+			if err != nil {
+				t.Fatalf("Tokenize() error = %v", err)
+			}
+
+			if len(tokens) != len(tt.expected) {
+				t.Fatalf("expected %d tokens, got %d\nexpected: %v\ngot:      %v", len(tt.expected), len(tokens), tt.expected, tokens)
+			}
+			for i, tok := range tokens {
+				if tok != tt.expected[i] {
+					t.Errorf("token[%d]: expected %+v, got %+v", i, tt.expected[i], tok)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryStringParser_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     []QueryStringOption
+		expected string
+	}{
+		{
+			name:     "default operator OR",
+			input:    "climate change",
+			expected: "climate change",
+		},
+		{
+			name:     "default operator AND",
+			input:    "climate change",
+			opts:     []QueryStringOption{WithDefaultOperator(operator.And)},
+			expected: "+climate +change",
+		},
+		{
+			name:     "required and prohibited",
+			input:    "+climate -politics energy",
+			expected: "+climate -politics energy",
+		},
+		{
+			name:     "field scopes",
+			input:    `title:"climate change" author:smith`,
+			expected: `title:"climate change" author:smith`,
+		},
+		{
+			name:     "field scope applies to group members",
+			input:    "title:(solar content:wind)",
+			expected: "(title:solar content:wind)",
+		},
+		{
+			name:     "prefix and boosts",
+			input:    `energ* solar^2 "wind farm"^1.5 (coal oil)^0.5`,
+			expected: `energ* solar^2 "wind farm"^1.5 (coal oil)^0.5`,
+		},
+		{
+			name:     "nested groups with operators",
+			input:    "+(climate -(politics election)) -title:opinion",
+			expected: "+(climate -(politics election)) -title:opinion",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewQueryStringParser(append(tt.opts, WithFields("title", "content", "author"))...)
+			got, err := parser.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.String() != tt.expected {
+				t.Errorf("Parse() = %q, want %q", got.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestQueryStringParser_Nodes(t *testing.T) {
+	got, err := NewQueryStringParser().Parse(`+title:energ*^3 -"coal power"`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(got.Clauses) != 2 {
+		t.Fatalf("expected 2 clauses, got %d", len(got.Clauses))
+	}
+
+	term, ok := got.Clauses[0].Node.(*Term)
+	if !ok {
+		t.Fatalf("clause 0: expected *Term, got %T", got.Clauses[0].Node)
+	}
+	want := Term{Field: "title", Value: "energ", Prefix: true, Boost: 3}
+	if *term != want || got.Clauses[0].Occur != Must {
+		t.Errorf("clause 0 = %s %+v, want must %+v", got.Clauses[0].Occur, *term, want)
+	}
+
+	phrase, ok := got.Clauses[1].Node.(*Phrase)
+	if !ok {
+		t.Fatalf("clause 1: expected *Phrase, got %T", got.Clauses[1].Node)
+	}
+	if phrase.Value != "coal power" || phrase.Field != "" || got.Clauses[1].Occur != MustNot {
+		t.Errorf("clause 1 = %s %+v, want must_not unscoped \"coal power\"", got.Clauses[1].Occur, *phrase)
+	}
+}
+
+func TestQueryStringParser_SyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "empty", input: "   ", wantErr: "query must contain at least one search term at position 0"},
+		{name: "unclosed quote", input: `climate "change`, wantErr: "unclosed quote at position 8"},
+		{name: "empty phrase", input: `"  "`, wantErr: "empty phrase at position 0"},
+		{name: "unclosed group", input: "climate (solar wind", wantErr: "unclosed '(' at position 8"},
+		{name: "unexpected closing paren", input: "climate)", wantErr: "unexpected ')' at position 7"},
+		{name: "empty group", input: "climate ()", wantErr: "empty group at position 8"},
+		{name: "dangling plus", input: "climate +", wantErr: "'+' must be followed by a term, phrase or group at position 8"},
+		{name: "double operator", input: "+-climate", wantErr: "'+' must be followed by a term, phrase or group at position 0"},
+		{name: "field without value", input: "climate title:", wantErr: "field 'title' must be followed by a term, phrase or group at position 8"},
+		{name: "unknown field", input: "climate body:energy", wantErr: "unknown field 'body' at position 8"},
+		{name: "leading colon", input: ":climate", wantErr: "unexpected ':' without a field name at position 0"},
+		{name: "inner wildcard", input: "cl*mate", wantErr: "wildcard '*' is only supported at the end of a term at position 2"},
+		{name: "bare wildcard", input: "climate *", wantErr: "prefix query needs at least one character before '*' at position 8"},
+		{name: "boost without number", input: "solar^", wantErr: "boost '^' must be followed by a positive number at position 5"},
+		{name: "zero boost", input: "solar^0", wantErr: "boost '^' must be followed by a positive number at position 5"},
+		{name: "boost without operand", input: "^2 solar", wantErr: "boost '^' must follow a term, phrase or group at position 0"},
+		{name: "trailing escape", input: `solar\`, wantErr: "escape character '\\' at end of input at position 5"},
+		{name: "too deep", input: "((((((a))))))", wantErr: "groups nested deeper than 5 at position 5"},
+	}
+
+	parser := NewQueryStringParser(WithFields("title", "content"))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.Parse(tt.input)
+			if err == nil {
+				t.Fatalf("expected error %q, got nil", tt.wantErr)
+			}
+			var ve *apperr.ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("expected *apperr.ValidationError, got %T", err)
+			}
+			if err.Error() != tt.wantErr {
+				t.Errorf("expected error %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestQueryStringParser_MaxClauses(t *testing.T) {
+	input := ""
+	for i := 0; i <= MaxQueryStringClauses; i++ {
+		input += "a "
+	}
+
+	_, err := NewQueryStringParser().Parse(input)
+	if err == nil {
+		t.Fatal("expected an error for too many terms")
+	}
+	if want := "query has more than 64 terms at position 128"; err.Error() != want {
+		t.Errorf("expected error %q, got %q", want, err.Error())
+	}
+}
@@ -0,0 +1,189 @@
+package token
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/apperr"
+)
+
+// QueryStringTokenizer splits query_string input into tokens that carry their position.
+//
+// Syntax:
+//
+//	climate                   term
+//	energ*                    prefix
+//	"climate change"          phrase
+//	title:climate             field scope (also title:"..." and title:(...))
+//	solar^2                   boost (also "..."^1.5 and (...)^2)
+//	+climate -politics        required / prohibited
+//	(solar wind)              grouping
+//
+// A backslash escapes the next character, so reserved characters ( ) " : ^ * \ can be
+// searched for. + and - are operators only at the start of a term ("covid-19" is one term).
+type QueryStringTokenizer struct {
+	input []rune
+	pos   int
+}
+
+func NewQueryStringTokenizer() *QueryStringTokenizer {
+	return &QueryStringTokenizer{}
+}
+
+// Tokenize converts the input string into a slice of Tokens ending with EOF.
+// Lexical errors (unclosed quotes, misplaced wildcards, invalid boosts) are returned as
+// validation errors with the character position.
+// Example: Input: `title:"climate change" +energ* -coal^2`
+func (t *QueryStringTokenizer) Tokenize(input string) ([]Token, error) {
+	t.input = []rune(input)
+	t.pos = 0
+
+	var tokens []Token
+
+	for t.skipWhitespace(); t.pos < len(t.input); t.skipWhitespace() {
+		ch := t.input[t.pos]
+		switch ch {
+		case '(':
+			tokens = append(tokens, Token{Type: LPAREN, Value: "(", Pos: t.pos})
+			t.pos++
+		case ')':
+			tokens = append(tokens, Token{Type: RPAREN, Value: ")", Pos: t.pos})
+			t.pos++
+		case '+':
+			tokens = append(tokens, Token{Type: PLUS, Value: "+", Pos: t.pos})
+			t.pos++
+		case '-':
+			tokens = append(tokens, Token{Type: MINUS, Value: "-", Pos: t.pos})
+			t.pos++
+		case '"':
+			tok, err := t.readPhrase()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+		case '^':
+			tok, err := t.readBoost()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+		case ':':
+			return nil, SyntaxError(t.pos, "unexpected ':' without a field name")
+		default:
+			tok, err := t.readWord()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+
+	tokens = append(tokens, Token{Type: EOF, Pos: t.pos})
+	return tokens, nil
+}
+
+func (t *QueryStringTokenizer) skipWhitespace() {
+	for t.pos < len(t.input) && unicode.IsSpace(t.input[t.pos]) {
+		t.pos++
+	}
+}
+
+// readWord reads a term, a prefix term (trailing '*') or a field name (trailing ':')
+func (t *QueryStringTokenizer) readWord() (Token, error) {
+	start := t.pos
+	var b strings.Builder
+	prefix := false
+
+	for t.pos < len(t.input) && !t.atWordEnd(t.pos) {
+		ch := t.input[t.pos]
+		switch {
+		case ch == '\\':
+			if t.pos+1 >= len(t.input) {
+				return Token{}, SyntaxError(t.pos, "escape character '\\' at end of input")
+			}
+			b.WriteRune(t.input[t.pos+1])
+			t.pos += 2
+			continue
+		case ch == '*':
+			if b.Len() == 0 {
+				return Token{}, SyntaxError(t.pos, "prefix query needs at least one character before '*'")
+			}
+			if !t.atWordEnd(t.pos + 1) {
+				return Token{}, SyntaxError(t.pos, "wildcard '*' is only supported at the end of a term")
+			}
+			prefix = true
+		default:
+			b.WriteRune(ch)
+		}
+		t.pos++
+	}
+
+	if t.pos < len(t.input) && t.input[t.pos] == ':' {
+		if prefix {
+			return Token{}, SyntaxError(start, "field name cannot contain '*'")
+		}
+		t.pos++ // skip ':'
+		return Token{Type: FIELD, Value: b.String(), Pos: start}, nil
+	}
+	if prefix {
+		return Token{Type: PREFIX, Value: b.String(), Pos: start}, nil
+	}
+	return Token{Type: WORD, Value: b.String(), Pos: start}, nil
+}
+
+// atWordEnd reports whether the word ends before position i
+func (t *QueryStringTokenizer) atWordEnd(i int) bool {
+	if i >= len(t.input) {
+		return true
+	}
+	ch := t.input[i]
+	return unicode.IsSpace(ch) || strings.ContainsRune(`()":^`, ch)
+}
+
+func (t *QueryStringTokenizer) readPhrase() (Token, error) {
+	start := t.pos
+	t.pos++ // skip opening quote
+
+	var b strings.Builder
+	for t.pos < len(t.input) && t.input[t.pos] != '"' {
+		if t.input[t.pos] == '\\' && t.pos+1 < len(t.input) {
+			t.pos++
+		}
+		b.WriteRune(t.input[t.pos])
+		t.pos++
+	}
+	if t.pos >= len(t.input) {
+		return Token{}, SyntaxError(start, "unclosed quote")
+	}
+	t.pos++ // skip closing quote
+
+	if strings.TrimSpace(b.String()) == "" {
+		return Token{}, SyntaxError(start, "empty phrase")
+	}
+	return Token{Type: PHRASE, Value: b.String(), Pos: start}, nil
+}
+
+// readBoost reads '^' and the positive number following it
+func (t *QueryStringTokenizer) readBoost() (Token, error) {
+	start := t.pos
+	t.pos++ // skip '^'
+
+	numStart := t.pos
+	for t.pos < len(t.input) && (unicode.IsDigit(t.input[t.pos]) || t.input[t.pos] == '.') {
+		t.pos++
+	}
+	value := string(t.input[numStart:t.pos])
+
+	boost, err := strconv.ParseFloat(value, 64)
+	if err != nil || boost <= 0 {
+		return Token{}, SyntaxError(start, "boost '^' must be followed by a positive number")
+	}
+	return Token{Type: BOOST, Value: value, Pos: start}, nil
+}
+
+// SyntaxError returns a validation error for invalid query syntax at a character position
+func SyntaxError(pos int, msg string) error {
+	return apperr.NewValidation(fmt.Sprintf("%s at position %d", msg, pos))
+}
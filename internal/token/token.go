@@ -10,6 +10,13 @@ const (
 	NOT
 	LPAREN
 	RPAREN
+	PHRASE
+	PREFIX
+	FIELD
+	PLUS
+	MINUS
+	BOOST
+	NEAR
 )
 
 func (t Type) String() string {
@@ -28,13 +35,29 @@ func (t Type) String() string {
 		return "LPAREN"
 	case RPAREN:
 		return "RPAREN"
+	case PHRASE:
+		return "PHRASE"
+	case PREFIX:
+		return "PREFIX"
+	case FIELD:
+		return "FIELD"
+	case PLUS:
+		return "PLUS"
+	case MINUS:
+		return "MINUS"
+	case BOOST:
+		return "BOOST"
+	case NEAR:
+		return "NEAR"
 	default:
 		return "UNKNOWN"
 	}
 }
 
 // Token represents a lexical token with its type and literal value.
+// Pos is the offset of the token in the input, in characters (runes).
 type Token struct {
 	Type  Type
 	Value string
+	Pos   int
 }
@@ -0,0 +1,102 @@
+// Package tokentest provides random boolean expressions and a reference evaluator of the
+// token AST, for property tests asserting that storage backends compile expressions to
+// queries with the same logical structure.
+package tokentest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/DjordjeVuckovic/news-hunter/internal/token"
+)
+
+// Atoms are the terms and phrases random expressions are built from
+var Atoms = []string{"climate", "energy", "solar", "coal", `"carbon tax"`, `"wind farm"`}
+
+// RandomExpression returns a random valid boolean expression nested at most depth levels,
+// mixing AND, OR, NOT, implicit AND and parentheses.
+func RandomExpression(r *rand.Rand, depth int) string {
+	if depth <= 0 || r.Intn(4) == 0 {
+		return Atoms[r.Intn(len(Atoms))]
+	}
+	left := RandomExpression(r, depth-1)
+	switch r.Intn(5) {
+	case 0:
+		return left + " AND " + RandomExpression(r, depth-1)
+	case 1:
+		return left + " OR " + RandomExpression(r, depth-1)
+	case 2:
+		return left + " " + RandomExpression(r, depth-1)
+	case 3:
+		return "NOT " + left
+	default:
+		return "(" + left + ")"
+	}
+}
+
+// RandomMatch returns a random match outcome for every atom, keyed like Eval looks them up:
+// the term, or the phrase words separated by a space
+func RandomMatch(r *rand.Rand) map[string]bool {
+	matches := make(map[string]bool, len(Atoms))
+	for _, atom := range Atoms {
+		matches[strings.Trim(atom, `"`)] = r.Intn(2) == 0
+	}
+	return matches
+}
+
+// Eval evaluates the AST for a document in which exactly the atoms set in matches occur
+func Eval(node token.Node, matches map[string]bool) bool {
+	switch n := node.(type) {
+	case *token.Term:
+		return matches[n.Value]
+	case *token.Phrase:
+		return matches[n.Value]
+	case *token.And:
+		for _, operand := range n.Operands {
+			if !Eval(operand, matches) {
+				return false
+			}
+		}
+		return true
+	case *token.Or:
+		for _, operand := range n.Operands {
+			if Eval(operand, matches) {
+				return true
+			}
+		}
+		return false
+	case *token.Not:
+		return !Eval(n.Operand, matches)
+	default:
+		panic(fmt.Sprintf("tokentest: unsupported node %T", node))
+	}
+}
+
+// Format renders the AST as nested operators, e.g. AND(climate, OR("carbon tax", NOT(coal)))
+func Format(node token.Node) string {
+	switch n := node.(type) {
+	case *token.Term:
+		return n.Value
+	case *token.Phrase:
+		return `"` + n.Value + `"`
+	case *token.And:
+		return "AND(" + formatAll(n.Operands) + ")"
+	case *token.Or:
+		return "OR(" + formatAll(n.Operands) + ")"
+	case *token.Not:
+		return "NOT(" + Format(n.Operand) + ")"
+	case *token.Near:
+		return fmt.Sprintf("NEAR/%d(%s, %s)", n.Distance, Format(n.Left), Format(n.Right))
+	default:
+		return fmt.Sprintf("%T", node)
+	}
+}
+
+func formatAll(nodes []token.Node) string {
+	parts := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		parts = append(parts, Format(n))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -33,6 +33,12 @@ type ArticleMetadata struct {
 
 	// System metadata
 	ImportedAt time.Time `json:"importedAt,omitempty"`
+
+	// Language detection (set during ingest)
+	DetectedLanguage   string  `json:"detectedLanguage,omitempty"`
+	LanguageConfidence float64 `json:"languageConfidence,omitempty"`
+	// LanguageMismatch flags articles whose mapped language differs from the detected one
+	LanguageMismatch bool `json:"languageMismatch,omitempty"`
 }
 
 func (ar *Article) ContainsField(field string) bool {
@@ -0,0 +1,143 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AggregationKind identifies a facet type
+type AggregationKind string
+
+const (
+	// TermsAggregation: document count per distinct value of a keyword field
+	// ES: terms aggregation
+	// PG: GROUP BY over the matched set
+	TermsAggregation AggregationKind = "terms"
+
+	// DateHistogramAggregation: document count per calendar interval of a date field
+	// ES: date_histogram aggregation with calendar_interval
+	// PG: GROUP BY date_trunc(interval, field)
+	DateHistogramAggregation AggregationKind = "date_histogram"
+)
+
+// DateField identifies a date attribute usable in date histograms and date range filters
+type DateField string
+
+const (
+	DatePublishedAt DateField = "published_at"
+	DateCreatedAt   DateField = "created_at"
+)
+
+// SupportedDateFields lists the fields accepted by date histograms
+var SupportedDateFields = map[DateField]bool{
+	DatePublishedAt: true,
+	DateCreatedAt:   true,
+}
+
+// CalendarInterval is a date histogram bucket width; buckets are aligned to UTC calendar boundaries
+type CalendarInterval string
+
+const (
+	IntervalHour    CalendarInterval = "hour"
+	IntervalDay     CalendarInterval = "day"
+	IntervalWeek    CalendarInterval = "week"
+	IntervalMonth   CalendarInterval = "month"
+	IntervalQuarter CalendarInterval = "quarter"
+	IntervalYear    CalendarInterval = "year"
+)
+
+// SupportedIntervals lists the accepted calendar intervals
+var SupportedIntervals = map[CalendarInterval]bool{
+	IntervalHour:    true,
+	IntervalDay:     true,
+	IntervalWeek:    true,
+	IntervalMonth:   true,
+	IntervalQuarter: true,
+	IntervalYear:    true,
+}
+
+const (
+	DefaultTermsAggregationSize = 10
+	MaxTermsAggregationSize     = 100
+	DefaultCalendarInterval     = IntervalMonth
+)
+
+// Aggregation is a named facet computed over the full (filtered) match set, not just the returned page.
+//
+// Example: top 5 categories and articles per month
+//
+//	[]Aggregation{
+//	  {Name: "categories", Kind: TermsAggregation, Field: "category", Size: 5},
+//	  {Name: "per_month", Kind: DateHistogramAggregation, Field: "published_at", Interval: IntervalMonth},
+//	}
+type Aggregation struct {
+	// Name: key of the facet in the response
+	Name string `json:"name"`
+
+	Kind AggregationKind `json:"kind"`
+
+	// Field: a FilterField for terms, a DateField for date_histogram
+	Field string `json:"field"`
+
+	// Size: maximum number of buckets (terms only), ordered by count desc then key asc
+	Size int `json:"size,omitempty"`
+
+	// Interval: bucket width (date_histogram only)
+	Interval CalendarInterval `json:"interval,omitempty"`
+}
+
+// Validate checks the field and parameters against the aggregation kind
+func (a *Aggregation) Validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("aggregation name is required")
+	}
+	switch a.Kind {
+	case TermsAggregation:
+		if !SupportedFilterFields[FilterField(a.Field)] {
+			return fmt.Errorf("aggregation %s: unsupported terms field: %s", a.Name, a.Field)
+		}
+		if a.Size < 1 || a.Size > MaxTermsAggregationSize {
+			return fmt.Errorf("aggregation %s: size must be between 1 and %d", a.Name, MaxTermsAggregationSize)
+		}
+	case DateHistogramAggregation:
+		if !SupportedDateFields[DateField(a.Field)] {
+			return fmt.Errorf("aggregation %s: unsupported date_histogram field: %s", a.Name, a.Field)
+		}
+		if !SupportedIntervals[a.Interval] {
+			return fmt.Errorf("aggregation %s: unsupported interval: %s", a.Name, a.Interval)
+		}
+	default:
+		return fmt.Errorf("aggregation %s: unsupported kind: %s", a.Name, a.Kind)
+	}
+	return nil
+}
+
+// AggregationResult holds the buckets of one facet
+type AggregationResult struct {
+	Buckets []Bucket `json:"buckets"`
+}
+
+// Bucket is a facet value with its document count.
+// Date histogram keys are RFC3339 timestamps of the bucket start (UTC).
+type Bucket struct {
+	Key      string `json:"key"`
+	DocCount int64  `json:"doc_count"`
+}
+
+// EmptyAggregationResults returns an empty bucket list for every requested aggregation,
+// used when the match set is empty so that the response shape does not depend on hit count.
+func EmptyAggregationResults(aggs []Aggregation) map[string]AggregationResult {
+	if len(aggs) == 0 {
+		return nil
+	}
+	results := make(map[string]AggregationResult, len(aggs))
+	for _, agg := range aggs {
+		results[agg.Name] = AggregationResult{Buckets: []Bucket{}}
+	}
+	return results
+}
+
+// SortAggregations orders aggregations by name so generated queries are deterministic
+func SortAggregations(aggs []Aggregation) {
+	sort.Slice(aggs, func(i, j int) bool { return aggs[i].Name < aggs[j].Name })
+}
@@ -0,0 +1,56 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultAutocompleteSize is the default number of title completions
+	DefaultAutocompleteSize = 10
+	// MaxAutocompleteSize caps the number of title completions
+	MaxAutocompleteSize = 25
+	// AutocompleteMinPrefixLength is the shortest prefix completed; a single character
+	// prefix-matches a large share of the titles on every keystroke
+	AutocompleteMinPrefixLength = 2
+	// AutocompleteMaxPrefixLength caps the prefix length (type-ahead input, not full queries)
+	AutocompleteMaxPrefixLength = 100
+	// AutocompleteCandidateFactor is how many candidates backends fetch per requested
+	// completion, leaving room for duplicate titles (syndicated articles)
+	AutocompleteCandidateFactor = 4
+)
+
+// Completion is an article title completing a type-ahead prefix
+type Completion struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+	Score float64   `json:"score"`
+}
+
+// NormalizePrefix trims the prefix and collapses inner whitespace.
+// Trailing whitespace is significant to users ("climate " means the word is complete)
+// but not to the backends, which treat every word as a prefix.
+// Example: "  climate   ch " → "climate ch"
+func NormalizePrefix(prefix string) string {
+	return strings.Join(strings.Fields(prefix), " ")
+}
+
+// DistinctCompletions keeps the first (best) completion of every title, compared
+// case-insensitively, and returns at most size completions.
+func DistinctCompletions(completions []Completion, size int) []Completion {
+	seen := make(map[string]bool, len(completions))
+	distinct := make([]Completion, 0, min(len(completions), size))
+	for _, c := range completions {
+		key := strings.ToLower(NormalizePrefix(c.Title))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		distinct = append(distinct, c)
+		if len(distinct) == size {
+			break
+		}
+	}
+	return distinct
+}
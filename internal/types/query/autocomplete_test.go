@@ -0,0 +1,31 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizePrefix(t *testing.T) {
+	if got := NormalizePrefix("  climate \t  ch "); got != "climate ch" {
+		t.Errorf("NormalizePrefix() = %q, want %q", got, "climate ch")
+	}
+}
+
+func TestDistinctCompletions(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	completions := []Completion{
+		{ID: a, Title: "Climate summit opens", Score: 0.9},
+		{ID: b, Title: "climate  summit opens", Score: 0.8},
+		{ID: c, Title: "Climate change report", Score: 0.7},
+		{ID: uuid.New(), Title: "Climate bill passes", Score: 0.6},
+	}
+
+	got := DistinctCompletions(completions, 2)
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+	if got[0].ID != a || got[1].ID != c {
+		t.Errorf("DistinctCompletions() = %+v, want best of each title in order", got)
+	}
+}
@@ -1,6 +1,19 @@
 package query
 
+import "github.com/DjordjeVuckovic/news-hunter/internal/ranking"
+
 type BaseOptions struct {
-	Cursor *Cursor
-	Size   int
+	Cursor       *Cursor
+	Size         int
+	Filters      *Filters
+	Aggregations []Aggregation
+	Highlight    *Highlight
+	// Sort orders the hits; empty means relevance
+	Sort Sort
+	// Rescoring multiplies relevance scores by recency, source and field value factors
+	Rescoring *Rescoring
+	// Explain attaches a score explanation to every hit
+	Explain bool
+	// Normalization selects how raw scores map to ScoreNormalized; the zero value divides by the max score
+	Normalization ranking.Spec
 }
@@ -0,0 +1,145 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// MaxBoolDepth caps the nesting of bool queries (the top-level bool is depth 1)
+	MaxBoolDepth = 5
+	// MaxBoolClauses caps the number of leaf queries in one bool query tree
+	MaxBoolClauses = 64
+)
+
+// Clause is one query of a bool occurrence list.
+// Exactly one field is set.
+type Clause struct {
+	Match      *Match      `json:"match,omitempty"`
+	MultiMatch *MultiMatch `json:"multi_match,omitempty"`
+	Phrase     *Phrase     `json:"phrase,omitempty"`
+	Bool       *Bool       `json:"bool,omitempty"`
+}
+
+// Kind returns the query kind of the clause
+func (c Clause) Kind() Kind {
+	switch {
+	case c.Match != nil:
+		return MatchType
+	case c.MultiMatch != nil:
+		return MultiMatchType
+	case c.Phrase != nil:
+		return PhraseType
+	case c.Bool != nil:
+		return BoolType
+	default:
+		return ""
+	}
+}
+
+// Bool is a compound query (Elasticsearch bool query semantics).
+//
+//   - Must: every clause must match; clauses contribute to the score
+//   - Should: clauses contribute to the score; see MinimumShouldMatch
+//   - MustNot: no clause may match; no scoring
+//   - Filter: every clause must match; no scoring
+//
+// Example:
+//
+//	{
+//	  "must":     [{"phrase": {"query": "climate change", "fields": ["title"]}}],
+//	  "should":   [{"match": {"field": "content", "query": "emissions"}}],
+//	  "must_not": [{"match": {"field": "author", "query": "smith"}}]
+//	}
+type Bool struct {
+	Must    []Clause `json:"must,omitempty"`
+	Should  []Clause `json:"should,omitempty"`
+	MustNot []Clause `json:"must_not,omitempty"`
+	Filter  []Clause `json:"filter,omitempty"`
+
+	// MinimumShouldMatch: number of should clauses that must match.
+	// Default (0): 1 when the query has no must or filter clauses, otherwise 0
+	// (should clauses only boost the score).
+	MinimumShouldMatch int `json:"minimum_should_match,omitempty"`
+}
+
+// GetMinimumShouldMatch returns the effective number of should clauses that must match
+func (q *Bool) GetMinimumShouldMatch() int {
+	if q.MinimumShouldMatch > 0 {
+		return q.MinimumShouldMatch
+	}
+	if len(q.Should) > 0 && len(q.Must) == 0 && len(q.Filter) == 0 {
+		return 1
+	}
+	return 0
+}
+
+// GetLanguage returns the language of the first scoring leaf query (must, then should,
+// then filter), used where a backend needs a single language (e.g. highlighting).
+func (q *Bool) GetLanguage() Language {
+	for _, clauses := range [][]Clause{q.Must, q.Should, q.Filter} {
+		for _, c := range clauses {
+			switch {
+			case c.Match != nil:
+				return c.Match.GetLanguage()
+			case c.MultiMatch != nil:
+				return c.MultiMatch.GetLanguage()
+			case c.Phrase != nil:
+				return c.Phrase.GetLanguage()
+			case c.Bool != nil:
+				return c.Bool.GetLanguage()
+			}
+		}
+	}
+	return DefaultLanguage
+}
+
+// Validate checks the clause structure: at least one clause, exactly one query per
+// clause, minimum_should_match within the should list, and the depth/size limits.
+func (q *Bool) Validate() error {
+	leaves, err := q.validate(1)
+	if err != nil {
+		return err
+	}
+	if leaves > MaxBoolClauses {
+		return fmt.Errorf("bool query has %d clauses, maximum is %d", leaves, MaxBoolClauses)
+	}
+	return nil
+}
+
+func (q *Bool) validate(depth int) (int, error) {
+	if depth > MaxBoolDepth {
+		return 0, fmt.Errorf("bool query nesting exceeds maximum depth of %d", MaxBoolDepth)
+	}
+	if len(q.Must)+len(q.Should)+len(q.MustNot)+len(q.Filter) == 0 {
+		return 0, errors.New("bool query must have at least one clause")
+	}
+	if q.MinimumShouldMatch < 0 || q.MinimumShouldMatch > len(q.Should) {
+		return 0, fmt.Errorf("minimum_should_match must be between 0 and the number of should clauses (%d), got %d", len(q.Should), q.MinimumShouldMatch)
+	}
+
+	leaves := 0
+	for _, clauses := range [][]Clause{q.Must, q.Should, q.MustNot, q.Filter} {
+		for _, c := range clauses {
+			set := 0
+			for _, ok := range []bool{c.Match != nil, c.MultiMatch != nil, c.Phrase != nil, c.Bool != nil} {
+				if ok {
+					set++
+				}
+			}
+			if set != 1 {
+				return 0, errors.New("each bool clause must specify exactly one of: match, multi_match, phrase, bool")
+			}
+			if c.Bool == nil {
+				leaves++
+				continue
+			}
+			n, err := c.Bool.validate(depth + 1)
+			if err != nil {
+				return 0, err
+			}
+			leaves += n
+		}
+	}
+	return leaves, nil
+}
@@ -0,0 +1,94 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBool_GetMinimumShouldMatch(t *testing.T) {
+	match := Clause{Match: NewMatch("title", "climate")}
+
+	tests := []struct {
+		name string
+		q    Bool
+		want int
+	}{
+		{name: "only should", q: Bool{Should: []Clause{match, match}}, want: 1},
+		{name: "should with must", q: Bool{Must: []Clause{match}, Should: []Clause{match}}, want: 0},
+		{name: "should with filter", q: Bool{Filter: []Clause{match}, Should: []Clause{match}}, want: 0},
+		{name: "explicit", q: Bool{Must: []Clause{match}, Should: []Clause{match, match}, MinimumShouldMatch: 2}, want: 2},
+		{name: "no should", q: Bool{Must: []Clause{match}}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.GetMinimumShouldMatch(); got != tt.want {
+				t.Errorf("GetMinimumShouldMatch() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBool_GetLanguage(t *testing.T) {
+	serbian := Clause{Match: NewMatch("title", "izbori", WithMatchLanguage(LanguageSerbian))}
+	english := Clause{Match: NewMatch("title", "election")}
+
+	q := Bool{MustNot: []Clause{english}, Should: []Clause{{Bool: &Bool{Must: []Clause{serbian}}}}}
+	if got := q.GetLanguage(); got != LanguageSerbian {
+		t.Errorf("GetLanguage() = %q, want %q", got, LanguageSerbian)
+	}
+	if got := (&Bool{MustNot: []Clause{serbian}}).GetLanguage(); got != DefaultLanguage {
+		t.Errorf("GetLanguage() with only must_not = %q, want default", got)
+	}
+}
+
+func TestBool_Validate(t *testing.T) {
+	match := Clause{Match: NewMatch("title", "climate")}
+
+	nested := func(depth int) *Bool {
+		q := &Bool{Must: []Clause{match}}
+		for i := 1; i < depth; i++ {
+			q = &Bool{Must: []Clause{{Bool: q}}}
+		}
+		return q
+	}
+
+	tests := []struct {
+		name    string
+		q       *Bool
+		wantErr string
+	}{
+		{name: "valid", q: &Bool{Must: []Clause{match}, Should: []Clause{match}, MinimumShouldMatch: 1}},
+		{name: "valid nested at max depth", q: nested(MaxBoolDepth)},
+		{name: "empty", q: &Bool{}, wantErr: "at least one clause"},
+		{name: "empty clause", q: &Bool{Must: []Clause{{}}}, wantErr: "exactly one of"},
+		{name: "two queries in one clause", q: &Bool{Must: []Clause{{Match: match.Match, Bool: &Bool{Must: []Clause{match}}}}}, wantErr: "exactly one of"},
+		{name: "minimum_should_match above should count", q: &Bool{Should: []Clause{match}, MinimumShouldMatch: 2}, wantErr: "minimum_should_match"},
+		{name: "too deep", q: nested(MaxBoolDepth + 1), wantErr: "maximum depth"},
+		{name: "empty nested bool", q: &Bool{Must: []Clause{{Bool: &Bool{}}}}, wantErr: "at least one clause"},
+		{name: "too many clauses", q: &Bool{Should: repeatClause(match, MaxBoolClauses+1)}, wantErr: "maximum is"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.q.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func repeatClause(c Clause, n int) []Clause {
+	clauses := make([]Clause, n)
+	for i := range clauses {
+		clauses[i] = c
+	}
+	return clauses
+}
@@ -7,5 +7,6 @@ type Capabilities struct {
 	MultiMatch  bool `json:"multi_match"`
 	Phrase      bool `json:"phrase"`
 	Boolean     bool `json:"boolean"`
+	Bool        bool `json:"bool"`
 	Semantic    bool `json:"semantic"`
 }
@@ -4,29 +4,81 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// CursorVersion is the version of the cursor encoding written by Encode.
+// Version 1 cursors ({"s": score, "i": id}) predate sorting and decode as relevance cursors.
+const CursorVersion = 2
+
 // Cursor represents a position in a search result set
-// It contains the relevance score and ID of the last item
+// It contains the sort values and ID of the last item
 type Cursor struct {
-	Score float64   `json:"s"` // Raw score for pagination consistency
-	ID    uuid.UUID `json:"i"`
+	Score float64   // Raw score for pagination consistency
+	ID    uuid.UUID // Final tiebreaker of every sort
+	// Sort is the sort the cursor was issued for; a cursor only continues the same sort
+	Sort Sort
+	// Values holds the last item's value for each of Sort.FieldKeys(), in order
+	Values []SortValue
+	// Offset counts the hits before the next page, so rank normalization continues across pages
+	Offset int64
+	// Mark is an opaque position issued by the backend (Solr cursorMark), empty for the others
+	Mark string
 }
 
-// EncodeCursor converts a Cursor to a base64-encoded string
-func EncodeCursor(score float64, id uuid.UUID) (string, error) {
-	if id == uuid.Nil {
-		return "", fmt.Errorf("cursor ID cannot be nil")
-	}
+// SortValue is a typed value of a sort field: a timestamp or a keyword
+type SortValue struct {
+	Time    *time.Time `json:"t,omitempty"`
+	Keyword *string    `json:"k,omitempty"`
+}
+
+func TimeSortValue(t time.Time) SortValue {
+	t = t.UTC()
+	return SortValue{Time: &t}
+}
 
-	c := Cursor{
+func KeywordSortValue(s string) SortValue {
+	return SortValue{Keyword: &s}
+}
+
+// cursorJSON is the wire format of a cursor; Version is absent in version 1 cursors
+type cursorJSON struct {
+	Version int         `json:"v,omitempty"`
+	Sort    string      `json:"o,omitempty"`
+	Score   float64     `json:"s"`
+	Values  []SortValue `json:"k,omitempty"`
+	ID      uuid.UUID   `json:"i"`
+	Offset  int64       `json:"n,omitempty"`
+	Mark    string      `json:"m,omitempty"`
+}
+
+// EncodeCursor encodes a relevance cursor to a base64-encoded string
+func EncodeCursor(score float64, id uuid.UUID) (string, error) {
+	c := &Cursor{
 		Score: score,
 		ID:    id,
+		Sort:  DefaultSort,
+	}
+	return c.Encode()
+}
+
+// Encode converts the cursor to a base64-encoded string
+func (c *Cursor) Encode() (string, error) {
+	if c.ID == uuid.Nil {
+		return "", fmt.Errorf("cursor ID cannot be nil")
 	}
 
-	b, err := json.Marshal(c)
+	b, err := json.Marshal(cursorJSON{
+		Version: CursorVersion,
+		Sort:    c.Sort.String(),
+		Score:   c.Score,
+		Values:  c.Values,
+		ID:      c.ID,
+		Offset:  c.Offset,
+		Mark:    c.Mark,
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal cursor: %w", err)
 	}
@@ -34,7 +86,7 @@ func EncodeCursor(score float64, id uuid.UUID) (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
-// DecodeCursor parses a base64-encoded cursor string
+// DecodeCursor parses a base64-encoded cursor string of any supported version
 func DecodeCursor(s string) (*Cursor, error) {
 	if s == "" {
 		return nil, nil
@@ -45,16 +97,45 @@ func DecodeCursor(s string) (*Cursor, error) {
 		return nil, fmt.Errorf("failed to decode cursor: %w", err)
 	}
 
-	var c Cursor
-	if err := json.Unmarshal(b, &c); err != nil {
+	var raw cursorJSON
+	if err := json.Unmarshal(b, &raw); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal cursor: %w", err)
 	}
 
-	if c.ID == uuid.Nil {
+	if raw.ID == uuid.Nil {
 		return nil, fmt.Errorf("invalid cursor: ID cannot be nil")
 	}
 
-	return &c, nil
+	c := &Cursor{Score: raw.Score, ID: raw.ID, Sort: DefaultSort, Offset: raw.Offset, Mark: raw.Mark}
+	switch raw.Version {
+	case 0, 1:
+		return c, nil
+	case CursorVersion:
+	default:
+		return nil, fmt.Errorf("invalid cursor: unsupported version %d", raw.Version)
+	}
+
+	if c.Sort, err = ParseSort(raw.Sort); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	keys := c.Sort.FieldKeys()
+	if len(raw.Values) != len(keys) {
+		return nil, fmt.Errorf("invalid cursor: %d sort values for %d sort fields", len(raw.Values), len(keys))
+	}
+	for i, k := range keys {
+		v := raw.Values[i]
+		if (k.Field.IsTime() && v.Time == nil) || (!k.Field.IsTime() && v.Keyword == nil) {
+			return nil, fmt.Errorf("invalid cursor: missing %s value", k.Field)
+		}
+	}
+	c.Values = raw.Values
+
+	return c, nil
+}
+
+// Continues reports whether the cursor was issued for the given sort
+func (c *Cursor) Continues(sort Sort) bool {
+	return c.Sort.String() == sort.String()
 }
 
 // MustEncodeCursor is like EncodeCursor but panics on error
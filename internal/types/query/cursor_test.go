@@ -1,7 +1,9 @@
 package query
 
 import (
+	"encoding/base64"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -157,6 +159,80 @@ func TestCursorRoundtrip(t *testing.T) {
 	}
 }
 
+func TestCursorSortRoundtrip(t *testing.T) {
+	sort, err := ParseSort("published_at:desc,source_name,relevance")
+	if err != nil {
+		t.Fatalf("ParseSort() failed: %v", err)
+	}
+	published := time.Date(2024, 5, 1, 10, 30, 0, 123456000, time.UTC)
+	c := &Cursor{
+		Score:  1.25,
+		ID:     uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+		Sort:   sort,
+		Values: []SortValue{TimeSortValue(published), KeywordSortValue("Reuters")},
+		Offset: 20,
+		Mark:   "AoE/BDE=",
+	}
+
+	encoded, err := c.Encode()
+	if err != nil {
+		t.Fatalf("Encode() failed: %v", err)
+	}
+	decoded, err := DecodeCursor(encoded)
+	if err != nil {
+		t.Fatalf("DecodeCursor() failed: %v", err)
+	}
+
+	if !decoded.Continues(sort) || decoded.Continues(DefaultSort) {
+		t.Errorf("decoded sort = %s, want %s", decoded.Sort, sort)
+	}
+	if decoded.Score != c.Score || decoded.ID != c.ID || decoded.Offset != c.Offset || decoded.Mark != c.Mark {
+		t.Errorf("decoded score/id/offset/mark = %v/%v/%d/%q, want %v/%v/%d/%q", decoded.Score, decoded.ID, decoded.Offset, decoded.Mark, c.Score, c.ID, c.Offset, c.Mark)
+	}
+	if len(decoded.Values) != 2 || !decoded.Values[0].Time.Equal(published) || *decoded.Values[1].Keyword != "Reuters" {
+		t.Errorf("decoded values = %+v", decoded.Values)
+	}
+}
+
+func TestDecodeCursor_Versions(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.URLEncoding.EncodeToString([]byte(raw))
+	}
+	id := "123e4567-e89b-12d3-a456-426614174000"
+
+	tests := []struct {
+		name        string
+		raw         string
+		wantSort    string
+		errContains string
+	}{
+		{name: "version 1 is a relevance cursor", raw: `{"s":0.5,"i":"` + id + `"}`, wantSort: "relevance:desc"},
+		{name: "version 2", raw: `{"v":2,"o":"created_at:asc","s":0.5,"k":[{"t":"2024-05-01T00:00:00Z"}],"i":"` + id + `"}`, wantSort: "created_at:asc"},
+		{name: "unknown version", raw: `{"v":9,"s":0.5,"i":"` + id + `"}`, errContains: "unsupported version 9"},
+		{name: "missing sort values", raw: `{"v":2,"o":"published_at","s":0.5,"i":"` + id + `"}`, errContains: "0 sort values for 1 sort fields"},
+		{name: "mistyped sort value", raw: `{"v":2,"o":"source_name","s":0.5,"k":[{"t":"2024-05-01T00:00:00Z"}],"i":"` + id + `"}`, errContains: "missing source_name value"},
+		{name: "invalid sort", raw: `{"v":2,"o":"title","s":0.5,"i":"` + id + `"}`, errContains: "unsupported sort field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := DecodeCursor(encode(tt.raw))
+			if tt.errContains != "" {
+				if err == nil || !contains(err.Error(), tt.errContains) {
+					t.Fatalf("DecodeCursor() error = %v, should contain %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeCursor() failed: %v", err)
+			}
+			if got := decoded.Sort.String(); got != tt.wantSort {
+				t.Errorf("decoded sort = %s, want %s", got, tt.wantSort)
+			}
+		})
+	}
+}
+
 func TestMustEncodeCursor(t *testing.T) {
 	t.Run("valid input", func(t *testing.T) {
 		id := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
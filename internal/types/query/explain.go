@@ -0,0 +1,54 @@
+package query
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// Explanation is a node of a score explanation tree: the value a part of the query
+// contributes and how it is made up of its details. Both backends report the same shape:
+//
+// Elasticsearch: the Lucene explanation of the hit (search explain / _explain API),
+// with filter clauses dropped and the field and terms of weight(...) leaves extracted
+// PostgreSQL: the ts_rank of the query over the lexemes of each weight label (A/B/C/D),
+// the lexemes of the query found under the label and the rank normalization flags
+type Explanation struct {
+	Value       float64 `json:"value"`
+	Description string  `json:"description"`
+	// Field is the document field the node scores, when it scores a single field
+	Field string `json:"field,omitempty"`
+	// Terms are the analyzed query terms the node matched in Field
+	Terms   []string      `json:"terms,omitempty"`
+	Details []Explanation `json:"details,omitempty"`
+}
+
+// Round rounds the values of the node and its details to the given decimal places
+func (e *Explanation) Round(places int) {
+	p := math.Pow(10, float64(places))
+	e.Value = math.Round(e.Value*p) / p
+	for i := range e.Details {
+		e.Details[i].Round(places)
+	}
+}
+
+// Rescored explains a rescored score as the product of the text score explanation
+// and the rescoring factors of the breakdown
+func (b *ScoreBreakdown) Rescored(text Explanation) Explanation {
+	details := []Explanation{text}
+	if b.Recency != nil {
+		details = append(details, Explanation{Value: *b.Recency, Description: "recency decay on published_at", Field: "published_at"})
+	}
+	if b.Source != nil {
+		details = append(details, Explanation{Value: *b.Source, Description: "source weight", Field: "source_name"})
+	}
+	for _, field := range slices.Sorted(maps.Keys(b.FieldValues)) {
+		details = append(details, Explanation{
+			Value:       b.FieldValues[field],
+			Description: fmt.Sprintf("field value factor of %s", field),
+			Field:       field,
+		})
+	}
+	return Explanation{Value: b.Total, Description: "rescored score, product of:", Details: details}
+}
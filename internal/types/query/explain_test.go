@@ -0,0 +1,32 @@
+package query
+
+import "testing"
+
+func TestScoreBreakdown_Rescored(t *testing.T) {
+	recency, source := 0.5, 1.5
+	b := &ScoreBreakdown{
+		Text:        2,
+		Recency:     &recency,
+		Source:      &source,
+		FieldValues: map[string]float64{"language_confidence": 0.8},
+		Total:       1.2,
+	}
+
+	e := b.Rescored(Explanation{Value: b.Text, Description: "text"})
+	if e.Value != 1.2 || len(e.Details) != 4 {
+		t.Fatalf("got %+v, want the total with text, recency, source and field value details", e)
+	}
+	for i, field := range []string{"", "published_at", "source_name", "language_confidence"} {
+		if e.Details[i].Field != field {
+			t.Errorf("detail %d field = %q, want %q", i, e.Details[i].Field, field)
+		}
+	}
+}
+
+func TestExplanation_Round(t *testing.T) {
+	e := Explanation{Value: 1.234567, Details: []Explanation{{Value: 0.000049}}}
+	e.Round(ScoreDecimalPlaces)
+	if e.Value != 1.2346 || e.Details[0].Value != 0 {
+		t.Errorf("got %g and %g, want 1.2346 and 0", e.Value, e.Details[0].Value)
+	}
+}
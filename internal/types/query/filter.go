@@ -0,0 +1,120 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FilterField identifies an article attribute that can be used as a structured filter.
+// Filters are evaluated in non-scoring context: they narrow the match set but never
+// change the relevance score of a hit.
+type FilterField string
+
+const (
+	FilterLanguage   FilterField = "language"
+	FilterSourceID   FilterField = "source_id"
+	FilterSourceName FilterField = "source_name"
+	FilterCategory   FilterField = "category"
+	FilterAuthor     FilterField = "author"
+)
+
+// DateRange is an inclusive time range; a nil bound means the range is open on that side.
+type DateRange struct {
+	Gte *time.Time `json:"gte,omitempty"`
+	Lte *time.Time `json:"lte,omitempty"`
+}
+
+// IsEmpty reports whether the range has no bounds.
+func (r *DateRange) IsEmpty() bool {
+	return r == nil || (r.Gte == nil && r.Lte == nil)
+}
+
+// Filters is the backend-neutral structured filter model.
+// Every non-empty constraint must hold (AND); values within a term list are OR'ed.
+//
+// Elasticsearch: bool.filter clauses (range on date fields, terms on keyword fields)
+// PostgreSQL: WHERE predicates over articles columns and the metadata jsonb
+//
+// Example: published in the last 7 days, source=bbc, category=science
+//
+//	&Filters{
+//	  PublishedAt: &DateRange{Gte: &weekAgo},
+//	  Terms: map[FilterField][]string{
+//	    FilterSourceID: {"bbc"},
+//	    FilterCategory: {"science"},
+//	  },
+//	}
+type Filters struct {
+	// PublishedAt: range on Metadata.PublishedAt
+	PublishedAt *DateRange `json:"published_at,omitempty"`
+
+	// CreatedAt: range on Article.CreatedAt
+	CreatedAt *DateRange `json:"created_at,omitempty"`
+
+	// Terms: exact-value constraints keyed by filter field
+	Terms map[FilterField][]string `json:"terms,omitempty"`
+
+	// IDs: restricts matches to these articles; not a request filter, used to explain one article
+	IDs []uuid.UUID `json:"-"`
+}
+
+// SupportedFilterFields lists the fields accepted in Filters.Terms
+var SupportedFilterFields = map[FilterField]bool{
+	FilterLanguage:   true,
+	FilterSourceID:   true,
+	FilterSourceName: true,
+	FilterCategory:   true,
+	FilterAuthor:     true,
+}
+
+// IsEmpty reports whether no constraint is set
+func (f *Filters) IsEmpty() bool {
+	if f == nil {
+		return true
+	}
+	if !f.PublishedAt.IsEmpty() || !f.CreatedAt.IsEmpty() || len(f.IDs) > 0 {
+		return false
+	}
+	for _, values := range f.Terms {
+		if len(values) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks field names and range bounds
+func (f *Filters) Validate() error {
+	if f == nil {
+		return nil
+	}
+	for field := range f.Terms {
+		if !SupportedFilterFields[field] {
+			return fmt.Errorf("unsupported filter field: %s", field)
+		}
+	}
+	for name, r := range map[string]*DateRange{"published_at": f.PublishedAt, "created_at": f.CreatedAt} {
+		if r != nil && r.Gte != nil && r.Lte != nil && r.Gte.After(*r.Lte) {
+			return fmt.Errorf("invalid %s range: gte is after lte", name)
+		}
+	}
+	return nil
+}
+
+// TermFields returns the term filter fields with at least one value, in a stable order
+// so that generated queries are deterministic.
+func (f *Filters) TermFields() []FilterField {
+	if f == nil {
+		return nil
+	}
+	order := []FilterField{FilterLanguage, FilterSourceID, FilterSourceName, FilterCategory, FilterAuthor}
+	fields := make([]FilterField, 0, len(order))
+	for _, field := range order {
+		if len(f.Terms[field]) > 0 {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -1,5 +1,11 @@
 package query
 
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
 type Fuzziness string
 
 const (
@@ -16,3 +22,54 @@ var SupportedFuzziness = map[Fuzziness]bool{
 	Fuzziness1:    true,
 	Fuzziness2:    true,
 }
+
+// DefaultFuzzyMaxExpansions caps the number of variants a single term expands to
+// (Elasticsearch max_expansions default)
+const DefaultFuzzyMaxExpansions = 50
+
+// ParseFuzziness validates a fuzziness value ("AUTO" is case-insensitive).
+// Empty input means no fuzziness.
+func ParseFuzziness(s string) (Fuzziness, error) {
+	if s == "" {
+		return "", nil
+	}
+	f := Fuzziness(strings.ToUpper(strings.TrimSpace(s)))
+	if !SupportedFuzziness[f] {
+		return "", fmt.Errorf("unsupported fuzziness %q (expected AUTO, 0, 1 or 2)", s)
+	}
+	return f, nil
+}
+
+// MaxEdits returns the Levenshtein distance allowed for a term.
+// AUTO follows Elasticsearch semantics: terms of 1-2 characters must match exactly,
+// 3-5 characters allow one edit and longer terms allow two.
+func (f Fuzziness) MaxEdits(term string) int {
+	switch f {
+	case FuzzinessAuto:
+		switch n := utf8.RuneCountInString(term); {
+		case n < 3:
+			return 0
+		case n < 6:
+			return 1
+		default:
+			return 2
+		}
+	case Fuzziness1:
+		return 1
+	case Fuzziness2:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// IsEnabled reports whether the fuzziness allows any edits
+func (f Fuzziness) IsEnabled() bool {
+	return f == FuzzinessAuto || f == Fuzziness1 || f == Fuzziness2
+}
+
+// TermExpansion reports the indexed terms a fuzzy query term was expanded to
+type TermExpansion struct {
+	Term       string   `json:"term"`
+	Expansions []string `json:"expansions"`
+}
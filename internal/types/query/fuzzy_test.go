@@ -0,0 +1,51 @@
+package query
+
+import "testing"
+
+func TestFuzziness_MaxEdits(t *testing.T) {
+	tests := []struct {
+		fuzziness Fuzziness
+		term      string
+		want      int
+	}{
+		{FuzzinessAuto, "ai", 0},
+		{FuzzinessAuto, "war", 1},
+		{FuzzinessAuto, "clima", 1},
+		{FuzzinessAuto, "climat", 2},
+		{FuzzinessAuto, "đak", 1},
+		{Fuzziness0, "climat", 0},
+		{Fuzziness1, "ai", 1},
+		{Fuzziness2, "war", 2},
+		{"", "climat", 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.fuzziness.MaxEdits(tt.term); got != tt.want {
+			t.Errorf("Fuzziness(%q).MaxEdits(%q) = %d, want %d", tt.fuzziness, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestParseFuzziness(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Fuzziness
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: "auto", want: FuzzinessAuto},
+		{in: "2", want: Fuzziness2},
+		{in: "3", wantErr: true},
+		{in: "AUTO:3,6", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFuzziness(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseFuzziness(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseFuzziness(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
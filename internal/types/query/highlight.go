@@ -0,0 +1,114 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultHighlightFragmentSize      = 100
+	DefaultHighlightNumberOfFragments = 5
+	MaxHighlightFragmentSize          = 1000
+	MaxHighlightNumberOfFragments     = 20
+	DefaultHighlightPreTag            = "<em>"
+	DefaultHighlightPostTag           = "</em>"
+)
+
+// DefaultHighlightFields are highlighted when the request does not name any field
+var DefaultHighlightFields = []string{"title", "description", "content"}
+
+// SupportedHighlightFields lists the text fields that can be highlighted
+var SupportedHighlightFields = map[string]bool{
+	"title":       true,
+	"subtitle":    true,
+	"description": true,
+	"content":     true,
+}
+
+// Highlight requests matched-term snippets per field for every returned hit.
+// Only fields that contain a match produce fragments.
+//
+// Elasticsearch: highlight API (unified highlighter, require_field_match=false)
+// PostgreSQL: ts_headline with the same tsquery used for matching
+//
+// Example:
+//
+//	{"fields": ["title", "content"], "fragment_size": 150, "number_of_fragments": 3, "pre_tag": "<mark>", "post_tag": "</mark>"}
+type Highlight struct {
+	// Fields: text fields to highlight (default: title, description, content)
+	Fields []string `json:"fields"`
+
+	// FragmentSize: approximate fragment length in characters (default 100)
+	FragmentSize int `json:"fragment_size"`
+
+	// NumberOfFragments: maximum fragments per field (default 5)
+	NumberOfFragments int `json:"number_of_fragments"`
+
+	// PreTag/PostTag: markup wrapped around every matched term (default <em></em>)
+	PreTag  string `json:"pre_tag"`
+	PostTag string `json:"post_tag"`
+}
+
+type HighlightOption func(h *Highlight)
+
+// NewHighlight creates a highlight request with Elasticsearch-compatible defaults
+func NewHighlight(fields []string, opts ...HighlightOption) (*Highlight, error) {
+	if len(fields) == 0 {
+		fields = DefaultHighlightFields
+	}
+
+	h := &Highlight{
+		Fields:            fields,
+		FragmentSize:      DefaultHighlightFragmentSize,
+		NumberOfFragments: DefaultHighlightNumberOfFragments,
+		PreTag:            DefaultHighlightPreTag,
+		PostTag:           DefaultHighlightPostTag,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	for _, field := range h.Fields {
+		if !SupportedHighlightFields[field] {
+			return nil, fmt.Errorf("unsupported highlight field: %s", field)
+		}
+	}
+	if h.FragmentSize < 1 || h.FragmentSize > MaxHighlightFragmentSize {
+		return nil, fmt.Errorf("fragment_size must be between 1 and %d, got %d", MaxHighlightFragmentSize, h.FragmentSize)
+	}
+	if h.NumberOfFragments < 1 || h.NumberOfFragments > MaxHighlightNumberOfFragments {
+		return nil, fmt.Errorf("number_of_fragments must be between 1 and %d, got %d", MaxHighlightNumberOfFragments, h.NumberOfFragments)
+	}
+	if h.PreTag == "" || h.PostTag == "" {
+		return nil, fmt.Errorf("pre_tag and post_tag must not be empty")
+	}
+	// Tags are embedded as quoted ts_headline options in PostgreSQL
+	if strings.ContainsAny(h.PreTag+h.PostTag, `"`) {
+		return nil, fmt.Errorf("pre_tag and post_tag must not contain double quotes")
+	}
+
+	return h, nil
+}
+
+// WithHighlightFragmentSize sets the approximate fragment length in characters
+func WithHighlightFragmentSize(size int) HighlightOption {
+	return func(h *Highlight) {
+		h.FragmentSize = size
+	}
+}
+
+// WithHighlightNumberOfFragments sets the maximum number of fragments per field
+func WithHighlightNumberOfFragments(n int) HighlightOption {
+	return func(h *Highlight) {
+		h.NumberOfFragments = n
+	}
+}
+
+// WithHighlightTags sets the markup wrapped around matched terms
+func WithHighlightTags(pre, post string) HighlightOption {
+	return func(h *Highlight) {
+		h.PreTag = pre
+		h.PostTag = post
+	}
+}
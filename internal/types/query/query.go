@@ -36,6 +36,14 @@ const (
 
 	// HybridType: lexical FTS fused with vector similarity via RRF.
 	HybridType Kind = "hybrid"
+
+	// BoolType: Compound query combining match, multi_match, phrase and nested bool clauses
+	// ES: bool query with must/should/must_not/filter
+	// PG: composed tsquery predicates with summed ts_rank
+	BoolType Kind = "bool"
+
+	// SemanticType: kNN search over article embeddings (the semantic search endpoint)
+	SemanticType Kind = "semantic"
 )
 
 // Base is the top-level query container
@@ -48,6 +56,29 @@ type Base struct {
 	Boolean     *Boolean    `json:"boolean,omitempty"`
 	Phrase      *Phrase     `json:"phrase,omitempty"`
 	Hybrid      *Hybrid     `json:"hybrid,omitempty"`
+	Bool        *Bool       `json:"bool,omitempty"`
+}
+
+// GetLanguage returns the language of the query of its kind
+func (q *Base) GetLanguage() Language {
+	switch {
+	case q.QueryString != nil:
+		return q.QueryString.GetLanguage()
+	case q.Match != nil:
+		return q.Match.GetLanguage()
+	case q.MultiMatch != nil:
+		return q.MultiMatch.GetLanguage()
+	case q.Boolean != nil:
+		return q.Boolean.GetLanguage()
+	case q.Phrase != nil:
+		return q.Phrase.GetLanguage()
+	case q.Hybrid != nil:
+		return q.Hybrid.GetLanguage()
+	case q.Bool != nil:
+		return q.Bool.GetLanguage()
+	default:
+		return DefaultLanguage
+	}
 }
 
 // String represents a simple text-based search query
@@ -57,12 +88,15 @@ type Base struct {
 // This is the primary search API for end-user queries (e.g., search box input).
 // The application handles field selection, weighting, and query optimization.
 //
-// Inspired by Elasticsearch's query_string query.
+// Inspired by Elasticsearch's query_string query; parsed by token.QueryStringParser.
+// Terms without a field scope search DefaultFields.
 //
 // Examples:
 //
 //	"climate change"           → Multi-field text search with default operator
-//	"renewable energy"         → Analyzed and tokenized across configured fields
+//	title:"climate change"     → Phrase in the title only
+//	+energ* -coal solar^2      → Required prefix, prohibited term, boosted optional term
+//	author:smith (wind solar)  → Field scope and grouping
 type String struct {
 	// Query: The search text to query
 	Query string `json:"query" validate:"required,min=1"`
@@ -121,15 +155,15 @@ type Phrase struct {
 
 	// Slop: Maximum positions allowed between matching tokens
 	// 0 = exact phrase, 1 = one word between, etc.
-	// Maximum value: 3 (to limit PostgreSQL OR clause complexity)
+	// Maximum value: MaxPhraseSlop (10; PostgreSQL and pg_textsearch render one <k> alternative per distance)
 	// Default: 0 (exact phrase)
-	Slop int `json:"slop,omitempty" validate:"min=0,max=3"`
+	Slop int `json:"slop,omitempty" validate:"min=0,max=10"`
 
 	// Language: Prompt analysis language
 	Language Language `json:"language,omitempty"`
 }
 
-const MaxPhraseSlop = 3
+const MaxPhraseSlop = 10
 
 type PhraseOption func(q *Phrase)
 
@@ -205,6 +239,9 @@ var (
 		"content":     1.0,
 	}
 
+	// QueryStringFields are the fields a query_string term can be scoped to (title:climate)
+	QueryStringFields = []string{"title", "subtitle", "description", "content", "author"}
+
 	RecommendedFieldWeights = map[string]float64{
 		"title":       3.0,
 		"description": 2.0,
@@ -283,10 +320,15 @@ type Match struct {
 	// Fuzziness: Typo tolerance (general search concept)
 	// "AUTO", "0", "1", "2" - Levenshtein edit distance
 	// Elasticsearch: Native support via fuzziness parameter
-	// PostgreSQL: Ignored (would require pg_trgm extension)
+	// PostgreSQL: Query terms expanded to indexed lexemes within the edit distance (fuzzystrmatch)
 	Fuzziness string `json:"fuzziness,omitempty"`
 }
 
+// GetFuzziness returns the typed fuzziness (empty when not set)
+func (q *Match) GetFuzziness() Fuzziness {
+	return Fuzziness(q.Fuzziness)
+}
+
 // GetLanguage returns the language with default fallback
 func (q *Match) GetLanguage() Language {
 	if q.Language == "" {
@@ -391,6 +433,9 @@ type MultiMatch struct {
 	Operator operator.Operator `json:"operator,omitempty"`
 
 	MatchStrategy MultiMatchStrategy `json:"match_strategy,omitempty"`
+
+	// Fuzziness: Typo tolerance applied to every field, see Match.Fuzziness
+	Fuzziness string `json:"fuzziness,omitempty"`
 }
 type MultiMatchQueryOption func(q *MultiMatch)
 
@@ -429,6 +474,13 @@ func WithMultiMatchOperator(op operator.Operator) MultiMatchQueryOption {
 	}
 }
 
+// WithMultiMatchFuzziness sets the fuzziness for MultiMatch query
+func WithMultiMatchFuzziness(fuzziness string) MultiMatchQueryOption {
+	return func(q *MultiMatch) {
+		q.Fuzziness = fuzziness
+	}
+}
+
 func newMultiMatchNewFields(fields []string) []MultiMatchField {
 	parsedFields := make([]MultiMatchField, 0, len(fields))
 
@@ -459,6 +511,11 @@ func (q *MultiMatch) GetLanguage() Language {
 	return q.Language
 }
 
+// GetFuzziness returns the typed fuzziness (empty when not set)
+func (q *MultiMatch) GetFuzziness() Fuzziness {
+	return Fuzziness(q.Fuzziness)
+}
+
 func (q *MultiMatch) GetFields() []MultiMatchField {
 	return q.Fields
 }
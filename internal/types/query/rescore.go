@@ -0,0 +1,201 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DecayFunction is the shape of a recency decay
+type DecayFunction string
+
+const (
+	// DecayExp decays by a constant ratio per scale: decay^(distance/scale)
+	DecayExp DecayFunction = "exp"
+	// DecayGauss keeps recent articles near 1 and falls off faster later: decay^((distance/scale)^2)
+	DecayGauss DecayFunction = "gauss"
+)
+
+// DefaultDecay is the factor at scale distance from the origin
+const DefaultDecay = 0.5
+
+// ScoreField is a numeric field usable in a field value factor
+type ScoreField string
+
+const (
+	ScoreFieldLanguageConfidence ScoreField = "language_confidence"
+)
+
+// FactorModifier is applied to factor * field value
+type FactorModifier string
+
+const (
+	ModifierNone FactorModifier = "none"
+	// ModifierLog1p is the common logarithm of 1 + x, like Elasticsearch's log1p
+	ModifierLog1p FactorModifier = "log1p"
+	ModifierSqrt  FactorModifier = "sqrt"
+)
+
+// Rescoring multiplies the text relevance score of every hit by composite factors:
+// recency decay on published_at, per-source weights and field value factors.
+// score = text score × recency × source weight × field value factors
+//
+// Elasticsearch: function_score (score_mode and boost_mode multiply)
+// PostgreSQL: the ts_rank expression multiplied by the same factors computed in SQL
+//
+// Articles without a publication date are treated as published at the zero time (factor ≈ 0).
+// Sorting, cursors and max scores all use the rescored score.
+type Rescoring struct {
+	Recency *RecencyDecay
+	// SourceWeights multiplies the score of articles from the named sources; other sources keep 1
+	SourceWeights map[string]float64
+	// FieldValueFactors multiplies the score by a function of a numeric field, one per field
+	FieldValueFactors []FieldValueFactor
+	// Breakdown returns the factors of every hit (see ScoreBreakdown)
+	Breakdown bool
+}
+
+// RecencyDecay scores articles by the distance of their publication date from the origin:
+// 1 within offset, decay at offset + scale.
+type RecencyDecay struct {
+	Function DecayFunction
+	Origin   time.Time
+	Scale    time.Duration
+	Offset   time.Duration
+	Decay    float64
+}
+
+// FieldValueFactor scores articles by modifier(factor × value); a missing or zero value is replaced by Missing
+type FieldValueFactor struct {
+	Field    ScoreField
+	Factor   float64
+	Modifier FactorModifier
+	Missing  float64
+}
+
+// ScoreBreakdown lists the factors that make up a rescored hit score
+type ScoreBreakdown struct {
+	// Text is the relevance score of the query before rescoring
+	Text        float64            `json:"text"`
+	Recency     *float64           `json:"recency,omitempty"`
+	Source      *float64           `json:"source,omitempty"`
+	FieldValues map[string]float64 `json:"field_values,omitempty"`
+	Total       float64            `json:"total"`
+}
+
+// Validate checks the rescoring parameters
+func (r *Rescoring) Validate() error {
+	if r.Recency == nil && len(r.SourceWeights) == 0 && len(r.FieldValueFactors) == 0 {
+		return fmt.Errorf("rescoring must define recency, source_weights or field_value_factors")
+	}
+
+	if d := r.Recency; d != nil {
+		if d.Function != DecayExp && d.Function != DecayGauss {
+			return fmt.Errorf("unsupported decay function: %s (expected exp or gauss)", d.Function)
+		}
+		if d.Scale < time.Second {
+			return fmt.Errorf("recency scale must be at least 1s, got %s", d.Scale)
+		}
+		if d.Offset < 0 {
+			return fmt.Errorf("recency offset must not be negative, got %s", d.Offset)
+		}
+		if d.Decay <= 0 || d.Decay >= 1 {
+			return fmt.Errorf("recency decay must be between 0 and 1 (exclusive), got %g", d.Decay)
+		}
+	}
+
+	for source, weight := range r.SourceWeights {
+		if weight <= 0 {
+			return fmt.Errorf("source weight for %s must be positive, got %g", source, weight)
+		}
+	}
+
+	seen := make(map[ScoreField]bool, len(r.FieldValueFactors))
+	for _, f := range r.FieldValueFactors {
+		if f.Field != ScoreFieldLanguageConfidence {
+			return fmt.Errorf("unsupported field value factor field: %s (supported: language_confidence)", f.Field)
+		}
+		if seen[f.Field] {
+			return fmt.Errorf("duplicate field value factor field: %s", f.Field)
+		}
+		seen[f.Field] = true
+		switch f.Modifier {
+		case ModifierNone, ModifierLog1p, ModifierSqrt:
+		default:
+			return fmt.Errorf("unsupported modifier: %s (expected none, log1p or sqrt)", f.Modifier)
+		}
+		if f.Factor < 0 || f.Missing < 0 {
+			return fmt.Errorf("factor and missing of %s must not be negative", f.Field)
+		}
+	}
+	return nil
+}
+
+// Exponent returns the distance exponent of the decay: 1 for exp, 2 for gauss
+func (d *RecencyDecay) Exponent() float64 {
+	if d.Function == DecayGauss {
+		return 2
+	}
+	return 1
+}
+
+// Factor computes the decay for a publication date with the Elasticsearch decay formulas:
+// decay^((max(0, |published - origin| - offset) / scale)^exponent)
+func (d *RecencyDecay) Factor(published time.Time) float64 {
+	distance := math.Max(math.Abs(published.Sub(d.Origin).Seconds())-d.Offset.Seconds(), 0)
+	return math.Exp(math.Log(d.Decay) * math.Pow(distance/d.Scale.Seconds(), d.Exponent()))
+}
+
+// Apply computes modifier(factor × value); zero values count as missing, as they are not indexed
+func (f FieldValueFactor) Apply(value float64) float64 {
+	if value == 0 {
+		value = f.Missing
+	}
+	x := f.Factor * value
+	switch f.Modifier {
+	case ModifierLog1p:
+		return math.Log10(1 + x)
+	case ModifierSqrt:
+		return math.Sqrt(x)
+	default:
+		return x
+	}
+}
+
+// SourceWeight returns the weight of a source, 1 for sources without a weight
+func (r *Rescoring) SourceWeight(source string) float64 {
+	if w, ok := r.SourceWeights[source]; ok {
+		return w
+	}
+	return 1
+}
+
+// Explain breaks a rescored score down into its factors for an article with the given
+// publication date, source and field values. The text score is derived by dividing the
+// score by the product of the factors (0 when a factor is 0).
+func (r *Rescoring) Explain(score float64, published time.Time, source string, fieldValues map[ScoreField]float64) *ScoreBreakdown {
+	b := &ScoreBreakdown{Total: score}
+	product := 1.0
+	if r.Recency != nil {
+		recency := r.Recency.Factor(published)
+		b.Recency = &recency
+		product *= recency
+	}
+	if len(r.SourceWeights) > 0 {
+		weight := r.SourceWeight(source)
+		b.Source = &weight
+		product *= weight
+	}
+	if len(r.FieldValueFactors) > 0 {
+		b.FieldValues = make(map[string]float64, len(r.FieldValueFactors))
+		for _, f := range r.FieldValueFactors {
+			factor := f.Apply(fieldValues[f.Field])
+			b.FieldValues[string(f.Field)] = factor
+			product *= factor
+		}
+	}
+	if product > 0 {
+		b.Text = score / product
+	}
+	return b
+}
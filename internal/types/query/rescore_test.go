@@ -0,0 +1,84 @@
+package query
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRecencyDecay_Factor(t *testing.T) {
+	origin := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		decay     RecencyDecay
+		published time.Time
+		want      float64
+	}{
+		{name: "at origin", decay: RecencyDecay{Function: DecayExp, Scale: day, Decay: 0.5}, published: origin, want: 1},
+		{name: "exp at scale", decay: RecencyDecay{Function: DecayExp, Scale: day, Decay: 0.5}, published: origin.Add(-day), want: 0.5},
+		{name: "exp at twice the scale", decay: RecencyDecay{Function: DecayExp, Scale: day, Decay: 0.5}, published: origin.Add(-2 * day), want: 0.25},
+		{name: "gauss at scale", decay: RecencyDecay{Function: DecayGauss, Scale: day, Decay: 0.5}, published: origin.Add(-day), want: 0.5},
+		{name: "gauss at twice the scale", decay: RecencyDecay{Function: DecayGauss, Scale: day, Decay: 0.5}, published: origin.Add(-2 * day), want: 0.0625},
+		{name: "within offset", decay: RecencyDecay{Function: DecayGauss, Scale: day, Offset: day, Decay: 0.5}, published: origin.Add(-day), want: 1},
+		{name: "future dates decay too", decay: RecencyDecay{Function: DecayExp, Scale: day, Decay: 0.5}, published: origin.Add(day), want: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.decay.Origin = origin
+			if got := tt.decay.Factor(tt.published); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Factor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldValueFactor_Apply(t *testing.T) {
+	tests := []struct {
+		name   string
+		factor FieldValueFactor
+		value  float64
+		want   float64
+	}{
+		{name: "none", factor: FieldValueFactor{Factor: 2, Modifier: ModifierNone}, value: 0.5, want: 1},
+		{name: "sqrt", factor: FieldValueFactor{Factor: 1, Modifier: ModifierSqrt}, value: 0.25, want: 0.5},
+		{name: "log1p is base 10", factor: FieldValueFactor{Factor: 9, Modifier: ModifierLog1p}, value: 1, want: 1},
+		{name: "zero is missing", factor: FieldValueFactor{Factor: 1, Modifier: ModifierNone, Missing: 0.8}, value: 0, want: 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.factor.Apply(tt.value); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRescoring_Explain(t *testing.T) {
+	origin := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	r := &Rescoring{
+		Recency:       &RecencyDecay{Function: DecayExp, Origin: origin, Scale: 24 * time.Hour, Decay: 0.5},
+		SourceWeights: map[string]float64{"Reuters": 2},
+		FieldValueFactors: []FieldValueFactor{
+			{Field: ScoreFieldLanguageConfidence, Factor: 1, Modifier: ModifierNone, Missing: 1},
+		},
+	}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	b := r.Explain(1.5, origin.Add(-24*time.Hour), "Reuters", map[ScoreField]float64{ScoreFieldLanguageConfidence: 0.75})
+	if *b.Recency != 0.5 || *b.Source != 2 || b.FieldValues["language_confidence"] != 0.75 {
+		t.Errorf("factors = %v, %v, %v; want 0.5, 2, 0.75", *b.Recency, *b.Source, b.FieldValues)
+	}
+	if math.Abs(b.Text-2) > 1e-9 || b.Total != 1.5 {
+		t.Errorf("text = %v, total = %v; want 2 and 1.5", b.Text, b.Total)
+	}
+
+	if b := r.Explain(1, origin, "AP", nil); *b.Source != 1 || b.FieldValues["language_confidence"] != 1 {
+		t.Errorf("unweighted source and missing value = %v, %v; want 1 and 1", *b.Source, b.FieldValues)
+	}
+}
@@ -0,0 +1,139 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortField is a field search results can be ordered by
+type SortField string
+
+const (
+	// SortRelevance orders by the raw relevance score of the query
+	SortRelevance   SortField = "relevance"
+	SortPublishedAt SortField = "published_at"
+	SortCreatedAt   SortField = "created_at"
+	SortSourceName  SortField = "source_name"
+)
+
+// SortOrder is the direction of a sort key
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "asc"
+	SortDesc SortOrder = "desc"
+)
+
+// defaultSortOrders are used when a sort key does not name a direction:
+// best match first, newest first, sources alphabetically
+var defaultSortOrders = map[SortField]SortOrder{
+	SortRelevance:   SortDesc,
+	SortPublishedAt: SortDesc,
+	SortCreatedAt:   SortDesc,
+	SortSourceName:  SortAsc,
+}
+
+// IsTime reports whether the field holds timestamps (as opposed to keywords or scores)
+func (f SortField) IsTime() bool {
+	return f == SortPublishedAt || f == SortCreatedAt
+}
+
+// SortKey is one field of a sort with its direction
+type SortKey struct {
+	Field SortField
+	Order SortOrder
+}
+
+func (k SortKey) String() string {
+	return string(k.Field) + ":" + string(k.Order)
+}
+
+// Sort orders search results by its keys in turn; the article ID (descending) breaks
+// the remaining ties, so every sort is total and can be paginated with a Cursor.
+// An empty sort orders by relevance.
+//
+// Elasticsearch: sort + search_after, missing values sort as the smallest value
+// PostgreSQL: ORDER BY + keyset predicate, missing values coalesced to the zero value
+//
+// Example:
+//
+//	published_at:desc,relevance:desc → newest first, best match first within the same instant
+type Sort []SortKey
+
+// DefaultSort orders by relevance, best match first
+var DefaultSort = Sort{{Field: SortRelevance, Order: SortDesc}}
+
+// ParseSort parses comma-separated sort keys in "field[:asc|desc]" form,
+// e.g. "published_at:desc,relevance". An empty expression is the default sort.
+func ParseSort(expression string) (Sort, error) {
+	var keys []string
+	for _, part := range strings.Split(expression, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			keys = append(keys, part)
+		}
+	}
+	return NewSort(keys...)
+}
+
+// NewSort validates sort keys in "field[:asc|desc]" form. Without keys it returns the default sort.
+func NewSort(keys ...string) (Sort, error) {
+	if len(keys) == 0 {
+		return DefaultSort, nil
+	}
+
+	sort := make(Sort, 0, len(keys))
+	seen := make(map[SortField]bool, len(keys))
+	for _, key := range keys {
+		name, order, hasOrder := strings.Cut(strings.TrimSpace(key), ":")
+		field := SortField(strings.ToLower(strings.TrimSpace(name)))
+		defaultOrder, ok := defaultSortOrders[field]
+		if !ok {
+			return nil, fmt.Errorf("unsupported sort field: %s (supported: relevance, published_at, created_at, source_name)", name)
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("duplicate sort field: %s", field)
+		}
+		seen[field] = true
+
+		k := SortKey{Field: field, Order: defaultOrder}
+		if hasOrder {
+			k.Order = SortOrder(strings.ToLower(strings.TrimSpace(order)))
+			if k.Order != SortAsc && k.Order != SortDesc {
+				return nil, fmt.Errorf("invalid sort order for %s: %s (expected asc or desc)", field, order)
+			}
+		}
+		if field == SortRelevance && k.Order != SortDesc {
+			return nil, fmt.Errorf("relevance can only be sorted in descending order")
+		}
+		sort = append(sort, k)
+	}
+	return sort, nil
+}
+
+// IsRelevance reports whether the sort is the default relevance order
+func (s Sort) IsRelevance() bool {
+	return len(s) == 0 || (len(s) == 1 && s[0] == DefaultSort[0])
+}
+
+// FieldKeys returns the keys that are not relevance, in order: the keys a Cursor carries values for
+func (s Sort) FieldKeys() []SortKey {
+	keys := make([]SortKey, 0, len(s))
+	for _, k := range s {
+		if k.Field != SortRelevance {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// String renders the sort in its canonical form, e.g. "published_at:desc,relevance:desc"
+func (s Sort) String() string {
+	if len(s) == 0 {
+		return DefaultSort.String()
+	}
+	parts := make([]string, 0, len(s))
+	for _, k := range s {
+		parts = append(parts, k.String())
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,51 @@
+package query
+
+import "testing"
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name        string
+		expression  string
+		want        string
+		errContains string
+	}{
+		{name: "empty is relevance", expression: "", want: "relevance:desc"},
+		{name: "default orders", expression: "published_at, source_name", want: "published_at:desc,source_name:asc"},
+		{name: "explicit orders", expression: "created_at:ASC,relevance:desc", want: "created_at:asc,relevance:desc"},
+		{name: "unknown field", expression: "title", errContains: "unsupported sort field: title"},
+		{name: "invalid order", expression: "published_at:newest", errContains: "invalid sort order for published_at"},
+		{name: "duplicate field", expression: "created_at,created_at:asc", errContains: "duplicate sort field: created_at"},
+		{name: "ascending relevance", expression: "relevance:asc", errContains: "descending order"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sort, err := ParseSort(tt.expression)
+			if tt.errContains != "" {
+				if err == nil || !contains(err.Error(), tt.errContains) {
+					t.Fatalf("ParseSort() error = %v, should contain %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSort() error = %v", err)
+			}
+			if got := sort.String(); got != tt.want {
+				t.Errorf("ParseSort() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSort_IsRelevance(t *testing.T) {
+	if !Sort(nil).IsRelevance() || !DefaultSort.IsRelevance() {
+		t.Error("empty and default sorts should be relevance")
+	}
+	sort, _ := ParseSort("published_at")
+	if sort.IsRelevance() {
+		t.Error("published_at sort should not be relevance")
+	}
+	if keys := sort.FieldKeys(); len(keys) != 1 || keys[0].Field != SortPublishedAt {
+		t.Errorf("FieldKeys() = %v, want [published_at:desc]", keys)
+	}
+}
@@ -0,0 +1,90 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultSuggestSize is the default number of corrections per term
+	DefaultSuggestSize = 3
+	// MaxSuggestSize caps the number of corrections per term
+	MaxSuggestSize = 10
+	// SuggestMinWordLength is the minimum length of a term to be corrected
+	// (Elasticsearch term suggester min_word_length default)
+	SuggestMinWordLength = 4
+	// SuggestMaxHits is the hit count up to which search responses carry suggestions
+	SuggestMaxHits = 5
+)
+
+// SuggestOption is a candidate correction for a query term
+type SuggestOption struct {
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+	Freq  int64   `json:"freq,omitempty"`
+}
+
+// TermSuggestion holds the corrections for one misspelled query term, best first
+type TermSuggestion struct {
+	Term    string          `json:"term"`
+	Options []SuggestOption `json:"options"`
+}
+
+// Suggest is a "did you mean" result: the corrected query text and per-term corrections.
+// Text is empty when no term needs correcting.
+type Suggest struct {
+	Text  string           `json:"text,omitempty"`
+	Terms []TermSuggestion `json:"terms,omitempty"`
+}
+
+// IsEmpty reports whether the suggest result carries no correction
+func (s *Suggest) IsEmpty() bool {
+	return s == nil || len(s.Terms) == 0
+}
+
+// SuggestTerms splits query text into lower-cased words eligible for correction
+// Example: "Goverment, budgett 2024" → ["goverment", "budgett"]
+func SuggestTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		if utf8.RuneCountInString(w) >= SuggestMinWordLength && strings.IndexFunc(w, unicode.IsLetter) >= 0 {
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// NewSuggest builds a suggest result for text, correcting every word that has a
+// suggestion with its best option. Words without suggestions are kept as typed.
+func NewSuggest(text string, terms []TermSuggestion) *Suggest {
+	best := make(map[string]string, len(terms))
+	var kept []TermSuggestion
+	for _, t := range terms {
+		if len(t.Options) == 0 {
+			continue
+		}
+		best[t.Term] = t.Options[0].Text
+		kept = append(kept, t)
+	}
+	if len(kept) == 0 {
+		return &Suggest{}
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		if correction, ok := best[w]; ok {
+			words[i] = correction
+		}
+	}
+
+	return &Suggest{
+		Text:  strings.Join(words, " "),
+		Terms: kept,
+	}
+}
@@ -0,0 +1,33 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestTerms(t *testing.T) {
+	got := SuggestTerms("Goverment, budgett in 2024 ĐAKA")
+	want := []string{"goverment", "budgett", "đaka"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SuggestTerms() = %v, want %v", got, want)
+	}
+}
+
+func TestNewSuggest(t *testing.T) {
+	terms := []TermSuggestion{
+		{Term: "goverment", Options: []SuggestOption{{Text: "government", Score: 0.8}, {Text: "governments", Score: 0.6}}},
+		{Term: "budget"},
+	}
+
+	got := NewSuggest("Goverment budget plan", terms)
+	if got.Text != "government budget plan" {
+		t.Errorf("Text = %q, want %q", got.Text, "government budget plan")
+	}
+	if len(got.Terms) != 1 || got.Terms[0].Term != "goverment" {
+		t.Errorf("Terms = %v, want only the corrected term", got.Terms)
+	}
+
+	if empty := NewSuggest("budget", []TermSuggestion{{Term: "budget"}}); !empty.IsEmpty() || empty.Text != "" {
+		t.Errorf("NewSuggest() without options = %+v, want empty", empty)
+	}
+}